@@ -8,9 +8,12 @@ import (
 	"os"
 	"os/signal"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/yavurb/goyurback/internal/app"
+	auditDomain "github.com/yavurb/goyurback/internal/audit/domain"
+	authCLI "github.com/yavurb/goyurback/internal/auth/infrastructure/cli"
 )
 
 var (
@@ -24,7 +27,21 @@ func main() {
 	appCtx := app.NewAppContext()
 	defer appCtx.Connpool.Close()
 
+	if len(os.Args) > 1 && os.Args[1] == "keys" {
+		ctx := auditDomain.WithActor(context.Background(), &auditDomain.Actor{ID: auditDomain.CLIActor})
+
+		err := authCLI.RunKeysCommand(ctx, appCtx.NewAPIKeyUsecase(), os.Args[2:], os.Stdout)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			appCtx.Connpool.Close()
+			os.Exit(1)
+		}
+
+		return
+	}
+
 	app := appCtx.NewRouter()
+	postScheduler := appCtx.NewPostScheduler()
 
 	fmt.Printf(`
  ██████╗  ██████╗ ██╗   ██╗██╗   ██╗██████╗ ██████╗  █████╗  ██████╗██╗  ██╗
@@ -39,6 +56,33 @@ func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, os.Kill)
 	defer stop()
 
+	// The trackers run until the server has drained its requests, so the usage and clicks of the
+	// requests served during the shutdown are flushed too
+	trackersCtx, stopTrackers := context.WithCancel(context.Background())
+	defer stopTrackers()
+
+	var workers sync.WaitGroup
+
+	workers.Add(3)
+
+	go func() {
+		defer workers.Done()
+
+		postScheduler.Run(ctx)
+	}()
+
+	go func() {
+		defer workers.Done()
+
+		appCtx.APIKeyUsageTracker.Run(trackersCtx)
+	}()
+
+	go func() {
+		defer workers.Done()
+
+		appCtx.ChikitoClickTracker.Run(trackersCtx)
+	}()
+
 	go func() {
 		host := fmt.Sprintf("0.0.0.0:%s", appCtx.Settings.Port)
 		if err := app.Start(host); err != nil && err != http.ErrServerClosed {
@@ -52,6 +96,11 @@ func main() {
 	defer cancel()
 
 	if err := app.Shutdown(ctx); err != nil {
-		app.Logger.Fatal(err)
+		app.Logger.Error(err)
 	}
+
+	// Let the workers finish their current run, and the trackers their final flush, before the
+	// connection pool is closed
+	stopTrackers()
+	workers.Wait()
 }
@@ -2,10 +2,16 @@ package app
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"log"
+	"maps"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
@@ -14,6 +20,8 @@ import (
 
 	"github.com/yavurb/goyurback/internal/app/mods"
 	postApplication "github.com/yavurb/goyurback/internal/posts/application"
+	postDomain "github.com/yavurb/goyurback/internal/posts/domain"
+	postMarkdown "github.com/yavurb/goyurback/internal/posts/infrastructure/markdown"
 	postRepository "github.com/yavurb/goyurback/internal/posts/infrastructure/repository"
 	postUI "github.com/yavurb/goyurback/internal/posts/infrastructure/ui"
 
@@ -22,22 +30,76 @@ import (
 	projectUI "github.com/yavurb/goyurback/internal/projects/infrastructure/ui"
 
 	authApplication "github.com/yavurb/goyurback/internal/auth/application"
+	authDomain "github.com/yavurb/goyurback/internal/auth/domain"
 	authRepository "github.com/yavurb/goyurback/internal/auth/infrastructure/repository"
+	authSecrets "github.com/yavurb/goyurback/internal/auth/infrastructure/secrets"
 	authUI "github.com/yavurb/goyurback/internal/auth/infrastructure/ui"
 
 	chikitoApplication "github.com/yavurb/goyurback/internal/chikitos/application"
+	chikitoQRCode "github.com/yavurb/goyurback/internal/chikitos/infrastructure/qrcode"
 	chikitoRepository "github.com/yavurb/goyurback/internal/chikitos/infrastructure/repository"
 	chikitoUI "github.com/yavurb/goyurback/internal/chikitos/infrastructure/ui"
+
+	feedApplication "github.com/yavurb/goyurback/internal/feeds/application"
+	feedUI "github.com/yavurb/goyurback/internal/feeds/infrastructure/ui"
+
+	sitemapApplication "github.com/yavurb/goyurback/internal/sitemap/application"
+	sitemapUI "github.com/yavurb/goyurback/internal/sitemap/infrastructure/ui"
+
+	searchApplication "github.com/yavurb/goyurback/internal/search/application"
+	searchRepository "github.com/yavurb/goyurback/internal/search/infrastructure/repository"
+	searchUI "github.com/yavurb/goyurback/internal/search/infrastructure/ui"
+
+	auditApplication "github.com/yavurb/goyurback/internal/audit/application"
+	auditRepository "github.com/yavurb/goyurback/internal/audit/infrastructure/repository"
+	auditUI "github.com/yavurb/goyurback/internal/audit/infrastructure/ui"
+
+	ratelimitApplication "github.com/yavurb/goyurback/internal/ratelimit/application"
+	ratelimitDomain "github.com/yavurb/goyurback/internal/ratelimit/domain"
+	ratelimitRepository "github.com/yavurb/goyurback/internal/ratelimit/infrastructure/repository"
+	ratelimitUI "github.com/yavurb/goyurback/internal/ratelimit/infrastructure/ui"
 )
 
 type appContext struct {
 	Settings *appSetings
 	Connpool *pgxpool.Pool
-	ctx      context.Context
+	// APIKeyUsageTracker is shared by the router, which feeds it, and the worker that flushes it
+	APIKeyUsageTracker *authApplication.UsageTracker
+	// ChikitoClickTracker is shared by the router, which feeds it, and the worker that flushes it
+	ChikitoClickTracker *chikitoApplication.ClickTracker
+	// PostRenderer is shared by the router and the post scheduler, so posts are only rendered
+	// and cached once
+	PostRenderer postDomain.ContentRenderer
+	// APIKeySecretCipher encrypts the signing secrets of the keys. It is nil when API_KEY_SECRETS_KEY
+	// is not set, and keys are issued without secret
+	APIKeySecretCipher authDomain.SecretCipher
+	ctx                context.Context
 }
 type appSetings struct {
 	Port         string
 	DBConnString string
+	// TrustedProxies are the proxies whose X-Forwarded-For header gives the IP of the clients.
+	// The IP of the connection is used when there are none
+	TrustedProxies []*net.IPNet
+
+	PostSchedulerInterval     time.Duration
+	APIKeyUsageFlushInterval  time.Duration
+	APIKeySecretsKey          []byte
+	ChikitoClickFlushInterval time.Duration
+
+	// RateLimit is the default of requests per minute allowed to each client in a route group,
+	// and RateLimitGroups overrides it by group
+	RateLimit       int32
+	RateLimitGroups map[string]int32
+
+	SiteTitle string
+	SiteURL   string
+	// ChikitosURL is the public base URL of the short links, encoded in their QR codes. They are
+	// left out of the sitemap, and can't have QR codes, when empty
+	ChikitosURL string
+	// ChikitosFallbackURL is where the chikitos that no longer redirect send their visitors. They
+	// respond with 410 Gone when empty
+	ChikitosFallbackURL string
 }
 
 func NewAppContext() *appContext {
@@ -54,6 +116,28 @@ func NewAppContext() *appContext {
 	}
 
 	appCtx.Connpool = connpool
+	appCtx.APIKeyUsageTracker = authApplication.NewUsageTracker(
+		authRepository.NewAPIKeyRepo(connpool),
+		appCtx.Settings.APIKeyUsageFlushInterval,
+	)
+
+	appCtx.PostRenderer = postMarkdown.NewRenderer()
+
+	appCtx.ChikitoClickTracker = chikitoApplication.NewClickTracker(
+		chikitoRepository.NewRepo(connpool),
+		appCtx.Settings.ChikitoClickFlushInterval,
+	)
+
+	if appCtx.Settings.APIKeySecretsKey != nil {
+		cipher, err := authSecrets.NewCipher(appCtx.Settings.APIKeySecretsKey)
+		if err != nil {
+			log.Fatalf("Unable to create the API keys secret cipher: %v\n", err)
+		}
+
+		appCtx.APIKeySecretCipher = cipher
+	} else {
+		log.Println("API_KEY_SECRETS_KEY is not set, new API keys won't be able to sign requests")
+	}
 
 	return appCtx
 }
@@ -63,42 +147,81 @@ func (c *appContext) NewRouter() *echo.Echo {
 	e := echo.New()
 
 	e.HideBanner = true
+	e.IPExtractor = mods.NewIPExtractor(c.Settings.TrustedProxies)
 	e.Use(middleware.Recover())
-	e.Use(middleware.RateLimiter(middleware.NewRateLimiterMemoryStore(20))) // Limits request to 20req/s based on the client's IP
-	e.Use(middleware.Logger())                                              // Use a simple logger middleware
+	// Limits requests to 20req/s based on the client's IP, before the API keys are validated
+	e.Use(middleware.RateLimiter(middleware.NewRateLimiterMemoryStore(20)))
+	e.Use(middleware.Logger()) // Use a simple logger middleware
+	e.Use(auditUI.RequestID()) // Sets the X-Request-Id header recorded in the audit log
 
 	e.Validator = mods.NewAppValidator()
 
+	// Identifies the API key of the request, if any, from the x-api-key header or a GYB-HMAC
+	// signature. Routes are public unless their router requires a scope with authUI.RequireScope.
+	authAPIKeyUcase := c.NewAPIKeyUsecase()
+	e.Use(authUI.KeyAuth(authAPIKeyUcase))
+	e.Use(authUI.SignatureAuth(authAPIKeyUcase))
+	e.Use(auditUI.ActorMiddleware())
+
+	// Limits the requests of each API key, or IP for anonymous requests, per minute across all
+	// the instances
+	rateLimitRespository := ratelimitRepository.NewRepo(c.Connpool)
+	rateLimitUcase := ratelimitApplication.NewRateLimitUsecase(rateLimitRespository)
+	e.Use(ratelimitUI.Middleware(rateLimitUcase, &ratelimitDomain.Policy{
+		Groups:  c.Settings.RateLimitGroups,
+		Default: c.Settings.RateLimit,
+	}))
+
 	e.GET("/health", func(c echo.Context) error { return c.String(http.StatusOK, "Healthy!") })
 
 	postRespository := postRepository.NewRepo(c.Connpool)
-	postUcase := postApplication.NewPostUsecase(postRespository)
+	postUcase := postApplication.NewPostUsecase(postRespository, c.PostRenderer)
 	postUI.NewPostsRouter(e, postUcase)
 
+	feedUcase := feedApplication.NewFeedUsecase(postUcase, c.Settings.SiteTitle, c.Settings.SiteURL)
+	feedUI.NewFeedsRouter(e, feedUcase)
+
 	projectRespository := projectRepository.NewRepo(c.Connpool)
 	projectUcase := projectApplication.NewProjectUsecase(projectRespository)
 	projectUI.NewProjectsRouter(e, projectUcase)
 
 	chikitoRespository := chikitoRepository.NewRepo(c.Connpool)
-	chikitoUcase := chikitoApplication.NewChikitoUsecase(chikitoRespository)
-	chikitoUI.NewChikitosRouter(e, chikitoUcase)
+	chikitoQRRenderer := chikitoQRCode.NewRenderer()
+	chikitoUcase := chikitoApplication.NewChikitoUsecase(chikitoRespository, c.ChikitoClickTracker, chikitoQRRenderer, c.Settings.ChikitosURL)
+	chikitoUI.NewChikitosRouter(e, chikitoUcase, c.Settings.ChikitosFallbackURL)
 
-	authAPIKeyRespository := authRepository.NewAPIKeyRepo(c.Connpool)
-	authAPIKeyUcase := authApplication.NewAPIKeyUsecase(authAPIKeyRespository)
-	authUI.NewAuthRouter(e, authAPIKeyUcase)
+	sitemapUcase := sitemapApplication.NewSitemapUsecase(postUcase, projectUcase, chikitoUcase, c.Settings.SiteURL, c.Settings.ChikitosURL)
+	sitemapUI.NewSitemapRouter(e, sitemapUcase, c.Settings.SiteURL)
 
-	e.Use(middleware.KeyAuthWithConfig(middleware.KeyAuthConfig{
-		KeyLookup: "header:x-api-key",
-		Validator: func(auth string, c echo.Context) (bool, error) {
-			isValid, err := authAPIKeyUcase.ValidateAPIKey(c.Request().Context(), auth)
+	searchRespository := searchRepository.NewRepo(c.Connpool)
+	searchUcase := searchApplication.NewSearchUsecase(searchRespository)
+	searchUI.NewSearchRouter(e, searchUcase)
 
-			return isValid, err
-		},
-	}))
+	authUI.NewAuthRouter(e, authAPIKeyUcase)
+
+	auditRespository := auditRepository.NewRepo(c.Connpool)
+	auditUcase := auditApplication.NewAuditUsecase(auditRespository)
+	auditUI.NewAuditRouter(e, auditUcase)
 
 	return e
 }
 
+// NewAPIKeyUsecase creates the usecase behind the API keys routes and the `keys` command.
+func (c *appContext) NewAPIKeyUsecase() authDomain.APIKeyUsecase {
+	authAPIKeyRespository := authRepository.NewAPIKeyRepo(c.Connpool)
+
+	return authApplication.NewAPIKeyUsecase(authAPIKeyRespository, c.APIKeyUsageTracker, c.APIKeySecretCipher)
+}
+
+// NewPostScheduler creates the worker that publishes scheduled posts. It is meant to run
+// alongside the server.
+func (c *appContext) NewPostScheduler() *postApplication.Scheduler {
+	postRespository := postRepository.NewRepo(c.Connpool)
+	postUcase := postApplication.NewPostUsecase(postRespository, c.PostRenderer)
+
+	return postApplication.NewScheduler(postUcase, c.Settings.PostSchedulerInterval)
+}
+
 func (c *appContext) initAppSettings() {
 	goenv := "dev"
 
@@ -115,4 +238,111 @@ func (c *appContext) initAppSettings() {
 
 	c.Settings.Port = envs["PORT"]
 	c.Settings.DBConnString = envs["DB_URI"]
+	c.Settings.PostSchedulerInterval = time.Minute
+
+	if value, ok := envs["POST_SCHEDULER_INTERVAL"]; ok {
+		interval, err := time.ParseDuration(value)
+		if err != nil || interval <= 0 {
+			log.Fatalf("Invalid POST_SCHEDULER_INTERVAL `%s`. Use a positive duration like `30s` or `1m`", value)
+		}
+
+		c.Settings.PostSchedulerInterval = interval
+	}
+
+	c.Settings.APIKeyUsageFlushInterval = 30 * time.Second
+
+	if value, ok := envs["API_KEY_USAGE_FLUSH_INTERVAL"]; ok {
+		interval, err := time.ParseDuration(value)
+		if err != nil || interval <= 0 {
+			log.Fatalf("Invalid API_KEY_USAGE_FLUSH_INTERVAL `%s`. Use a positive duration like `30s` or `1m`", value)
+		}
+
+		c.Settings.APIKeyUsageFlushInterval = interval
+	}
+
+	if value, ok := envs["API_KEY_SECRETS_KEY"]; ok && value != "" {
+		key, err := base64.StdEncoding.DecodeString(value)
+		if err != nil || len(key) != authSecrets.KeySize {
+			log.Fatalf("Invalid API_KEY_SECRETS_KEY. Use %d random bytes encoded in base64, like the output of `openssl rand -base64 %d`", authSecrets.KeySize, authSecrets.KeySize)
+		}
+
+		c.Settings.APIKeySecretsKey = key
+	}
+
+	c.Settings.ChikitoClickFlushInterval = 10 * time.Second
+
+	if value, ok := envs["CHIKITO_CLICKS_FLUSH_INTERVAL"]; ok {
+		interval, err := time.ParseDuration(value)
+		if err != nil || interval <= 0 {
+			log.Fatalf("Invalid CHIKITO_CLICKS_FLUSH_INTERVAL `%s`. Use a positive duration like `10s` or `1m`", value)
+		}
+
+		c.Settings.ChikitoClickFlushInterval = interval
+	}
+
+	c.Settings.RateLimit = 60
+
+	if value, ok := envs["RATE_LIMIT"]; ok {
+		limit, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || limit < 0 {
+			log.Fatalf("Invalid RATE_LIMIT `%s`. Use the requests per minute allowed to each client, or 0 to disable the limit", value)
+		}
+
+		c.Settings.RateLimit = int32(limit)
+	}
+
+	// The health checks of the load balancer are not limited unless configured
+	c.Settings.RateLimitGroups = map[string]int32{"health": 0}
+
+	if value, ok := envs["RATE_LIMIT_GROUPS"]; ok {
+		limits, err := ratelimitDomain.ParseGroupLimits(value)
+		if err != nil {
+			log.Fatalf("Invalid RATE_LIMIT_GROUPS `%s`. Use the requests per minute of each route group, like `search=30,auth=10`", value)
+		}
+
+		maps.Copy(c.Settings.RateLimitGroups, limits)
+	}
+
+	if value, ok := envs["TRUSTED_PROXIES"]; ok {
+		proxies, err := mods.ParseTrustedProxies(value)
+		if err != nil {
+			log.Fatalf("Invalid TRUSTED_PROXIES `%s`. Use the IPs or CIDR ranges of the proxies, like `10.0.0.1,172.16.0.0/12`", value)
+		}
+
+		c.Settings.TrustedProxies = proxies
+	}
+
+	c.Settings.SiteTitle = "yurb.dev"
+	c.Settings.SiteURL = "https://yurb.dev"
+
+	if value, ok := envs["SITE_TITLE"]; ok {
+		c.Settings.SiteTitle = value
+	}
+
+	if value, ok := envs["SITE_URL"]; ok {
+		siteURL, err := url.Parse(value)
+		if err != nil || siteURL.Scheme == "" || siteURL.Host == "" {
+			log.Fatalf("Invalid SITE_URL `%s`. Use an absolute URL like `https://yurb.dev`", value)
+		}
+
+		c.Settings.SiteURL = value
+	}
+
+	if value, ok := envs["CHIKITOS_URL"]; ok && value != "" {
+		chikitosURL, err := url.Parse(value)
+		if err != nil || chikitosURL.Scheme == "" || chikitosURL.Host == "" {
+			log.Fatalf("Invalid CHIKITOS_URL `%s`. Use an absolute URL like `https://yurb.dev/chikitos`", value)
+		}
+
+		c.Settings.ChikitosURL = value
+	}
+
+	if value, ok := envs["CHIKITOS_FALLBACK_URL"]; ok && value != "" {
+		fallbackURL, err := url.Parse(value)
+		if err != nil || fallbackURL.Scheme == "" || fallbackURL.Host == "" {
+			log.Fatalf("Invalid CHIKITOS_FALLBACK_URL `%s`. Use an absolute URL like `https://yurb.dev/expired`", value)
+		}
+
+		c.Settings.ChikitosFallbackURL = value
+	}
 }
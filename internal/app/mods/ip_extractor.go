@@ -0,0 +1,66 @@
+package mods
+
+import (
+	"net"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// NewIPExtractor returns how the IP of the clients is found. Without trusted proxies it is the
+// IP of the connection. Otherwise it is read from the X-Forwarded-For header, skipping only the
+// addresses of the trusted proxies, so clients can't choose the IP they are seen with.
+func NewIPExtractor(trustedProxies []*net.IPNet) echo.IPExtractor {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect()
+	}
+
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+
+	for _, proxy := range trustedProxies {
+		options = append(options, echo.TrustIPRange(proxy))
+	}
+
+	return echo.ExtractIPFromXFFHeader(options...)
+}
+
+// ParseTrustedProxies parses a list of IPs and CIDR ranges like `10.0.0.1,172.16.0.0/12`.
+func ParseTrustedProxies(value string) ([]*net.IPNet, error) {
+	proxies := []*net.IPNet{}
+
+	for _, proxy := range strings.Split(value, ",") {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, &net.ParseError{Type: "IP address", Text: proxy}
+			}
+
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+
+			proxies = append(proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+
+			continue
+		}
+
+		_, ipNet, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, err
+		}
+
+		proxies = append(proxies, ipNet)
+	}
+
+	return proxies, nil
+}
@@ -0,0 +1,59 @@
+package mods
+
+import (
+	"net"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestIPExtractor(t *testing.T) {
+	proxies, err := ParseTrustedProxies("10.0.0.1, 172.16.0.0/12")
+	if err != nil {
+		t.Fatalf("Expected no error parsing the proxies. Got: %v", err)
+	}
+
+	cases := []struct {
+		name       string
+		proxies    []*net.IPNet
+		remoteAddr string
+		xff        string
+		want       string
+	}{
+		{name: "without proxies", remoteAddr: "203.0.113.7:4321", xff: "198.51.100.1", want: "203.0.113.7"},
+		{name: "from a trusted proxy", proxies: proxies, remoteAddr: "10.0.0.1:4321", xff: "198.51.100.1", want: "198.51.100.1"},
+		{name: "through several trusted proxies", proxies: proxies, remoteAddr: "10.0.0.1:4321", xff: "1.1.1.1, 198.51.100.1, 172.16.3.4", want: "198.51.100.1"},
+		{name: "from an untrusted client", proxies: proxies, remoteAddr: "203.0.113.7:4321", xff: "198.51.100.1", want: "203.0.113.7"},
+		{name: "from an untrusted private address", proxies: proxies, remoteAddr: "192.168.1.5:4321", xff: "198.51.100.1", want: "192.168.1.5"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = tc.remoteAddr
+			req.Header.Set(echo.HeaderXForwardedFor, tc.xff)
+
+			if got := NewIPExtractor(tc.proxies)(req); got != tc.want {
+				t.Errorf("Expected the IP to be %s. Got: %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	proxies, err := ParseTrustedProxies("10.0.0.1,2001:db8::/32,")
+	if err != nil {
+		t.Fatalf("Expected no error. Got: %v", err)
+	}
+
+	if len(proxies) != 2 || proxies[0].String() != "10.0.0.1/32" || proxies[1].String() != "2001:db8::/32" {
+		t.Errorf("Expected a single IP and a range. Got: %v", proxies)
+	}
+
+	for _, value := range []string{"10.0.0", "10.0.0.0/33", "proxy.local"} {
+		if _, err := ParseTrustedProxies(value); err == nil {
+			t.Errorf("Expected %q to be invalid", value)
+		}
+	}
+}
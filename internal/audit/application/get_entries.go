@@ -0,0 +1,56 @@
+package application
+
+import (
+	"context"
+	"log"
+
+	"github.com/yavurb/goyurback/internal/audit/domain"
+)
+
+func (uc *auditUsecase) GetEntries(ctx context.Context, filter *domain.EntryFilter) (*domain.EntryPage, error) {
+	if filter.EntityType != "" && !filter.EntityType.Valid() {
+		return nil, domain.ErrInvalidEntityType
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = domain.DefaultPageSize
+	}
+
+	if limit > domain.MaxPageSize {
+		limit = domain.MaxPageSize
+	}
+
+	query := &domain.EntryQuery{
+		EntityType: filter.EntityType,
+		EntityID:   filter.EntityID,
+		Limit:      limit + 1, // Fetch an extra entry to know if there is a next page
+	}
+
+	if filter.Cursor != "" {
+		beforeID, err := domain.DecodeCursor(filter.Cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		query.BeforeID = beforeID
+	}
+
+	entries, err := uc.repository.GetEntries(ctx, query)
+	if err != nil {
+		log.Printf("Error getting audit entries. Got: %v\n", err)
+
+		return nil, err
+	}
+
+	page := &domain.EntryPage{
+		Entries: entries,
+	}
+
+	if int32(len(entries)) > limit {
+		page.Entries = entries[:limit]
+		page.NextCursor = domain.EncodeCursor(page.Entries[limit-1].ID)
+	}
+
+	return page, nil
+}
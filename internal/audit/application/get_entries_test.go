@@ -0,0 +1,92 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/yavurb/goyurback/internal/audit/application/mocks"
+	"github.com/yavurb/goyurback/internal/audit/domain"
+)
+
+func TestGetEntries(t *testing.T) {
+	entries := []*domain.Entry{
+		{ID: 9, Actor: "ak_1qjrblb8pm90", Action: domain.ActionUpdate, EntityType: domain.EntityPosts, EntityID: "po_1"},
+		{ID: 5, Actor: "ak_1qjrblb8pm90", Action: domain.ActionCreate, EntityType: domain.EntityPosts, EntityID: "po_1"},
+		{ID: 2, Actor: "ak_0000000000aa", Action: domain.ActionCreate, EntityType: domain.EntityChikitos, EntityID: "ch_1"},
+	}
+
+	t.Run("it should return a next cursor when there are more entries", func(t *testing.T) {
+		var gotQuery *domain.EntryQuery
+
+		repo := &mocks.MockAuditRepository{
+			GetEntriesFn: func(ctx context.Context, query *domain.EntryQuery) ([]*domain.Entry, error) {
+				gotQuery = query
+
+				return entries[:query.Limit], nil
+			},
+		}
+
+		uc := NewAuditUsecase(repo)
+
+		page, err := uc.GetEntries(context.Background(), &domain.EntryFilter{EntityType: domain.EntityPosts, EntityID: "po_1", Limit: 1})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		if *gotQuery != (domain.EntryQuery{EntityType: domain.EntityPosts, EntityID: "po_1", Limit: 2}) {
+			t.Errorf("Expected the filter and a limit of 2, got: %+v", gotQuery)
+		}
+
+		if !reflect.DeepEqual(page.Entries, entries[:1]) {
+			t.Errorf("Expected entries to be %v, got: %v", entries[:1], page.Entries)
+		}
+
+		beforeID, err := domain.DecodeCursor(page.NextCursor)
+		if err != nil || beforeID != 9 {
+			t.Errorf("Expected the cursor to point to entry 9, got: %d, %v", beforeID, err)
+		}
+	})
+
+	t.Run("it should pass the decoded cursor to the repository", func(t *testing.T) {
+		var gotQuery *domain.EntryQuery
+
+		repo := &mocks.MockAuditRepository{
+			GetEntriesFn: func(ctx context.Context, query *domain.EntryQuery) ([]*domain.Entry, error) {
+				gotQuery = query
+
+				return entries[1:], nil
+			},
+		}
+
+		uc := NewAuditUsecase(repo)
+
+		page, err := uc.GetEntries(context.Background(), &domain.EntryFilter{Cursor: domain.EncodeCursor(9)})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		if gotQuery.BeforeID != 9 || gotQuery.Limit != domain.DefaultPageSize+1 {
+			t.Errorf("Expected entries before 9 with the default limit, got: %+v", gotQuery)
+		}
+
+		if page.NextCursor != "" {
+			t.Errorf("Expected no next cursor, got: %s", page.NextCursor)
+		}
+	})
+
+	t.Run("it should reject invalid filters", func(t *testing.T) {
+		uc := NewAuditUsecase(&mocks.MockAuditRepository{})
+
+		_, err := uc.GetEntries(context.Background(), &domain.EntryFilter{EntityType: "users"})
+		if !errors.Is(err, domain.ErrInvalidEntityType) {
+			t.Errorf("Expected error to be %v, got: %v", domain.ErrInvalidEntityType, err)
+		}
+
+		_, err = uc.GetEntries(context.Background(), &domain.EntryFilter{Cursor: "not a cursor"})
+		if !errors.Is(err, domain.ErrInvalidCursor) {
+			t.Errorf("Expected error to be %v, got: %v", domain.ErrInvalidCursor, err)
+		}
+	})
+}
@@ -0,0 +1,15 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/yavurb/goyurback/internal/audit/domain"
+)
+
+type MockAuditRepository struct {
+	GetEntriesFn func(ctx context.Context, query *domain.EntryQuery) ([]*domain.Entry, error)
+}
+
+func (m *MockAuditRepository) GetEntries(ctx context.Context, query *domain.EntryQuery) ([]*domain.Entry, error) {
+	return m.GetEntriesFn(ctx, query)
+}
@@ -0,0 +1,11 @@
+package application
+
+import "github.com/yavurb/goyurback/internal/audit/domain"
+
+type auditUsecase struct {
+	repository domain.AuditRepository
+}
+
+func NewAuditUsecase(repository domain.AuditRepository) domain.AuditUsecase {
+	return &auditUsecase{repository}
+}
@@ -0,0 +1,33 @@
+package domain
+
+import "context"
+
+// SystemActor is the actor of the changes made by the server itself, like publishing the
+// scheduled posts.
+const SystemActor = "system"
+
+// CLIActor is the actor of the changes made from the command line, like creating the first API
+// key of an environment.
+const CLIActor = "cli"
+
+// Actor identifies who made a request.
+type Actor struct {
+	ID        string // Public id of the API key
+	RequestID string
+	IP        string
+}
+
+type actorKey struct{}
+
+func WithActor(ctx context.Context, actor *Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor of the request, or SystemActor when there is none.
+func ActorFromContext(ctx context.Context) *Actor {
+	if actor, ok := ctx.Value(actorKey{}).(*Actor); ok {
+		return actor
+	}
+
+	return &Actor{ID: SystemActor}
+}
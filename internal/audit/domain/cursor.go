@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"encoding/base64"
+	"strconv"
+)
+
+// EncodeCursor returns an opaque cursor pointing to the entry with the given id. Entries are
+// sorted by id, newest first.
+func EncodeCursor(id int32) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(int64(id), 10)))
+}
+
+func DecodeCursor(cursor string) (int32, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+
+	id, err := strconv.ParseInt(string(raw), 10, 32)
+	if err != nil || id <= 0 {
+		return 0, ErrInvalidCursor
+	}
+
+	return int32(id), nil
+}
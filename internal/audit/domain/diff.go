@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// FieldChange holds the values of a field before and after a change.
+type FieldChange struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+// NewDiff returns the fields that differ between the JSON representations of before and after,
+// as a JSON object of FieldChange. A nil before or after is treated as an empty object, and
+// fields that are null on both sides are left out.
+func NewDiff(before, after any) (json.RawMessage, error) {
+	oldFields, err := toFields(before)
+	if err != nil {
+		return nil, err
+	}
+
+	newFields, err := toFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := map[string]*FieldChange{}
+
+	for field, value := range newFields {
+		if old := oldFields[field]; !reflect.DeepEqual(old, value) {
+			diff[field] = &FieldChange{Old: old, New: value}
+		}
+	}
+
+	for field, old := range oldFields {
+		if _, ok := newFields[field]; !ok && old != nil {
+			diff[field] = &FieldChange{Old: old}
+		}
+	}
+
+	return json.Marshal(diff)
+}
+
+func toFields(value any) (map[string]any, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]any{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewDiff(t *testing.T) {
+	type entity struct {
+		Name string
+		Tags []string
+		Live bool
+	}
+
+	cases := []struct {
+		name   string
+		before any
+		after  any
+		want   string
+	}{
+		{
+			name:  "creation",
+			after: &entity{Name: "goyurback", Tags: []string{"go"}},
+			want:  `{"Live":{"old":null,"new":false},"Name":{"old":null,"new":"goyurback"},"Tags":{"old":null,"new":["go"]}}`,
+		},
+		{
+			name:   "update",
+			before: &entity{Name: "goyurback", Tags: []string{"go"}},
+			after:  &entity{Name: "goyurback", Tags: []string{"go", "echo"}, Live: true},
+			want:   `{"Live":{"old":false,"new":true},"Tags":{"old":["go"],"new":["go","echo"]}}`,
+		},
+		{
+			name:   "deletion",
+			before: &entity{Name: "goyurback"},
+			want:   `{"Live":{"old":false,"new":null},"Name":{"old":"goyurback","new":null}}`,
+		},
+		{
+			name:   "no changes",
+			before: &entity{Name: "goyurback"},
+			after:  &entity{Name: "goyurback"},
+			want:   `{}`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := NewDiff(tc.before, tc.after)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			var gotValue, wantValue any
+			json.Unmarshal(got, &gotValue)
+			json.Unmarshal([]byte(tc.want), &wantValue)
+
+			if diff := cmp.Diff(wantValue, gotValue); diff != "" {
+				t.Errorf("NewDiff() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+type EntityType string
+
+const (
+	EntityPosts    EntityType = "posts"
+	EntityProjects EntityType = "projects"
+	EntityChikitos EntityType = "chikitos"
+	EntityAPIKeys  EntityType = "apikeys"
+)
+
+var EntityTypes = []EntityType{EntityPosts, EntityProjects, EntityChikitos, EntityAPIKeys}
+
+func (t EntityType) Valid() bool {
+	return slices.Contains(EntityTypes, t)
+}
+
+const (
+	DefaultPageSize int32 = 20
+	MaxPageSize     int32 = 100
+)
+
+// Entry is the record of a change made to an entity.
+type Entry struct {
+	CreatedAt  time.Time
+	Diff       json.RawMessage
+	Actor      string // Public id of the API key that made the change, SystemActor or CLIActor
+	RequestID  string
+	IP         string
+	EntityID   string // Public id of the changed entity
+	Action     Action
+	EntityType EntityType
+	ID         int32
+}
+
+// Change describes a mutation to record. Before is nil for creations and After is nil for
+// deletions.
+type Change struct {
+	Before     any
+	After      any
+	EntityID   string
+	Action     Action
+	EntityType EntityType
+}
+
+// EntryFilter holds the options accepted by the usecase. Cursor is the opaque value returned as
+// NextCursor by a previous page.
+type EntryFilter struct {
+	EntityType EntityType
+	EntityID   string
+	Cursor     string
+	Limit      int32
+}
+
+// EntryQuery is the decoded version of EntryFilter used by the repository.
+type EntryQuery struct {
+	EntityType EntityType
+	EntityID   string
+	BeforeID   int32
+	Limit      int32
+}
+
+type EntryPage struct {
+	Entries    []*Entry
+	NextCursor string
+}
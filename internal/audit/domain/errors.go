@@ -0,0 +1,6 @@
+package domain
+
+import "errors"
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+var ErrInvalidEntityType = errors.New("invalid entity type")
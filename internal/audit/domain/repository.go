@@ -0,0 +1,7 @@
+package domain
+
+import "context"
+
+type AuditRepository interface {
+	GetEntries(ctx context.Context, query *EntryQuery) ([]*Entry, error)
+}
@@ -0,0 +1,8 @@
+package domain
+
+import "context"
+
+type AuditUsecase interface {
+	// GetEntries lists the entries matching the filter, newest first.
+	GetEntries(ctx context.Context, filter *EntryFilter) (*EntryPage, error)
+}
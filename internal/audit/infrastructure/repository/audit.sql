@@ -0,0 +1,10 @@
+-- name: CreateAuditLog :exec
+INSERT INTO audit_logs (actor, action, entity_type, entity_id, request_id, ip, diff) VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: GetAuditLogs :many
+SELECT * FROM audit_logs
+WHERE (sqlc.narg(entity_type)::varchar IS NULL OR entity_type = sqlc.narg(entity_type))
+  AND (sqlc.narg(entity_id)::varchar IS NULL OR entity_id = sqlc.narg(entity_id))
+  AND (sqlc.narg(before_id)::integer IS NULL OR id < sqlc.narg(before_id))
+ORDER BY id DESC
+LIMIT sqlc.arg(page_size)::integer;
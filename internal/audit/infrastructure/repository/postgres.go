@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"log"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yavurb/goyurback/internal/audit/domain"
+	"github.com/yavurb/goyurback/internal/database/postgres"
+)
+
+type Repository struct {
+	db *postgres.Queries
+}
+
+func NewRepo(connpool *pgxpool.Pool) domain.AuditRepository {
+	db := postgres.New(connpool)
+
+	return &Repository{db}
+}
+
+// Record saves the change made by the actor of ctx. The other modules call it with the queries
+// of the transaction that makes the change, so the entry is only kept if the change is committed.
+func Record(ctx context.Context, db *postgres.Queries, change *domain.Change) error {
+	diff, err := domain.NewDiff(change.Before, change.After)
+	if err != nil {
+		log.Printf("Error computing audit diff: %v\n", err)
+
+		return err
+	}
+
+	actor := domain.ActorFromContext(ctx)
+
+	err = db.CreateAuditLog(ctx, postgres.CreateAuditLogParams{
+		Actor:      actor.ID,
+		Action:     string(change.Action),
+		EntityType: string(change.EntityType),
+		EntityID:   change.EntityID,
+		RequestID:  actor.RequestID,
+		Ip:         actor.IP,
+		Diff:       diff,
+	})
+	if err != nil {
+		log.Printf("DB Error recording audit log: %v\n", err)
+
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) GetEntries(ctx context.Context, query *domain.EntryQuery) ([]*domain.Entry, error) {
+	params := postgres.GetAuditLogsParams{
+		PageSize: query.Limit,
+	}
+
+	if query.EntityType != "" {
+		params.EntityType = pgtype.Text{String: string(query.EntityType), Valid: true}
+	}
+
+	if query.EntityID != "" {
+		params.EntityID = pgtype.Text{String: query.EntityID, Valid: true}
+	}
+
+	if query.BeforeID != 0 {
+		params.BeforeID = pgtype.Int4{Int32: query.BeforeID, Valid: true}
+	}
+
+	logs, err := r.db.GetAuditLogs(ctx, params)
+	if err != nil {
+		log.Printf("DB Error getting audit logs: %v\n", err)
+
+		return nil, err
+	}
+
+	entries := []*domain.Entry{}
+
+	for _, log_ := range logs {
+		entries = append(entries, &domain.Entry{
+			CreatedAt:  log_.CreatedAt.Time,
+			Diff:       log_.Diff,
+			Actor:      log_.Actor,
+			RequestID:  log_.RequestID,
+			IP:         log_.Ip,
+			EntityID:   log_.EntityID,
+			Action:     domain.Action(log_.Action),
+			EntityType: domain.EntityType(log_.EntityType),
+			ID:         log_.ID,
+		})
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/yavurb/goyurback/internal/audit/domain"
+)
+
+type EntriesParams struct {
+	EntityType string `query:"entity"`
+	EntityID   string `query:"id" validate:"max=64"`
+	Cursor     string `query:"cursor"`
+	Limit      int32  `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+type EntryOut struct {
+	CreatedAt  time.Time       `json:"created_at"`
+	Diff       json.RawMessage `json:"diff"`
+	Actor      string          `json:"actor"`
+	RequestID  string          `json:"request_id"`
+	IP         string          `json:"ip"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	ID         int32           `json:"id"`
+}
+
+type EntriesOut struct {
+	NextCursor string      `json:"next_cursor,omitempty"`
+	Data       []*EntryOut `json:"data"`
+}
+
+func toEntryOut(entry *domain.Entry) *EntryOut {
+	return &EntryOut{
+		CreatedAt:  entry.CreatedAt,
+		Diff:       entry.Diff,
+		Actor:      entry.Actor,
+		RequestID:  entry.RequestID,
+		IP:         entry.IP,
+		Action:     string(entry.Action),
+		EntityType: string(entry.EntityType),
+		EntityID:   entry.EntityID,
+		ID:         entry.ID,
+	}
+}
@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"regexp"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/yavurb/goyurback/internal/audit/domain"
+	authUI "github.com/yavurb/goyurback/internal/auth/infrastructure/ui"
+	"github.com/yavurb/goyurback/internal/pgk/ips"
+)
+
+// requestIDPattern matches the ids that fit in the request_id column of the audit log.
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
+// RequestID sets the X-Request-Id header recorded in the audit log. The id sent by the client is
+// kept so requests can be traced across services, unless it is not a short id of safe characters,
+// in which case a new one is generated.
+func RequestID() echo.MiddlewareFunc {
+	requestID := middleware.RequestID()
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		handler := requestID(next)
+
+		return func(c echo.Context) error {
+			header := c.Request().Header
+			if !requestIDPattern.MatchString(header.Get(echo.HeaderXRequestID)) {
+				header.Del(echo.HeaderXRequestID)
+			}
+
+			return handler(c)
+		}
+	}
+}
+
+// ActorMiddleware stores the actor of the request in its context, so the changes made by the
+// request are recorded with it. It must run after authUI.KeyAuth and RequestID.
+func ActorMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			apiKey, ok := authUI.APIKeyFromContext(c)
+			if !ok {
+				return next(c)
+			}
+
+			actor := &domain.Actor{
+				ID:        apiKey.PublicID,
+				RequestID: c.Response().Header().Get(echo.HeaderXRequestID),
+				IP:        ips.Normalize(c.RealIP()),
+			}
+
+			req := c.Request()
+			c.SetRequest(req.WithContext(domain.WithActor(req.Context(), actor)))
+
+			return next(c)
+		}
+	}
+}
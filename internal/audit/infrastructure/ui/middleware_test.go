@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+	"github.com/yavurb/goyurback/internal/audit/domain"
+	authDomain "github.com/yavurb/goyurback/internal/auth/domain"
+	authUI "github.com/yavurb/goyurback/internal/auth/infrastructure/ui"
+)
+
+func TestActorMiddleware(t *testing.T) {
+	newContext := func(apiKey *authDomain.APIKey) (echo.Context, *httptest.ResponseRecorder) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/posts", nil)
+		req.Header.Set(echo.HeaderXRealIP, "10.0.0.1")
+		rec := httptest.NewRecorder()
+		rec.Header().Set(echo.HeaderXRequestID, "req_1")
+
+		c := e.NewContext(req, rec)
+		if apiKey != nil {
+			c.Set(authUI.APIKeyContextKey, apiKey)
+		}
+
+		return c, rec
+	}
+
+	t.Run("it should store the actor of an authenticated request", func(t *testing.T) {
+		var got *domain.Actor
+
+		c, _ := newContext(&authDomain.APIKey{PublicID: "ak_1"})
+		err := ActorMiddleware()(func(c echo.Context) error {
+			got = domain.ActorFromContext(c.Request().Context())
+
+			return nil
+		})(c)
+		if err != nil {
+			t.Fatalf("Expected no errors. Got: %v", err)
+		}
+
+		want := &domain.Actor{ID: "ak_1", RequestID: "req_1", IP: "10.0.0.1"}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("ActorMiddleware() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("it should record the IP normalized", func(t *testing.T) {
+		var got *domain.Actor
+
+		c, _ := newContext(&authDomain.APIKey{PublicID: "ak_1"})
+		c.Request().Header.Set(echo.HeaderXRealIP, "::ffff:10.0.0.1")
+		err := ActorMiddleware()(func(c echo.Context) error {
+			got = domain.ActorFromContext(c.Request().Context())
+
+			return nil
+		})(c)
+		if err != nil {
+			t.Fatalf("Expected no errors. Got: %v", err)
+		}
+
+		if got.IP != "10.0.0.1" {
+			t.Errorf("Expected the IP to be 10.0.0.1. Got: %s", got.IP)
+		}
+	})
+
+	t.Run("it should leave anonymous requests to the system actor", func(t *testing.T) {
+		var got *domain.Actor
+
+		c, _ := newContext(nil)
+		err := ActorMiddleware()(func(c echo.Context) error {
+			got = domain.ActorFromContext(c.Request().Context())
+
+			return nil
+		})(c)
+		if err != nil {
+			t.Fatalf("Expected no errors. Got: %v", err)
+		}
+
+		if got.ID != domain.SystemActor {
+			t.Errorf("Expected the actor to be %s. Got: %s", domain.SystemActor, got.ID)
+		}
+	})
+}
+
+func TestRequestID(t *testing.T) {
+	requestID := func(clientID string) string {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/posts", nil)
+		if clientID != "" {
+			req.Header.Set(echo.HeaderXRequestID, clientID)
+		}
+		rec := httptest.NewRecorder()
+
+		err := RequestID()(func(c echo.Context) error { return nil })(e.NewContext(req, rec))
+		if err != nil {
+			t.Fatalf("Expected no errors. Got: %v", err)
+		}
+
+		return rec.Header().Get(echo.HeaderXRequestID)
+	}
+
+	t.Run("it should keep the id sent by the client", func(t *testing.T) {
+		if got := requestID("req_1"); got != "req_1" {
+			t.Errorf("Expected the request id to be req_1. Got: %s", got)
+		}
+	})
+
+	t.Run("it should generate an id when the client sends none", func(t *testing.T) {
+		if got := requestID(""); got == "" {
+			t.Error("Expected a request id to be generated")
+		}
+	})
+
+	t.Run("it should replace the ids that don't fit in the audit log", func(t *testing.T) {
+		for _, clientID := range []string{strings.Repeat("a", 65), "req 1", "<script>"} {
+			got := requestID(clientID)
+			if got == clientID || got == "" {
+				t.Errorf("Expected %q to be replaced. Got: %q", clientID, got)
+			}
+		}
+	})
+}
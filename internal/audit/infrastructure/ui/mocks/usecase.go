@@ -0,0 +1,15 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/yavurb/goyurback/internal/audit/domain"
+)
+
+type MockAuditUsecase struct {
+	GetEntriesFn func(ctx context.Context, filter *domain.EntryFilter) (*domain.EntryPage, error)
+}
+
+func (m *MockAuditUsecase) GetEntries(ctx context.Context, filter *domain.EntryFilter) (*domain.EntryPage, error) {
+	return m.GetEntriesFn(ctx, filter)
+}
@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yavurb/goyurback/internal/audit/domain"
+	authDomain "github.com/yavurb/goyurback/internal/auth/domain"
+	authUI "github.com/yavurb/goyurback/internal/auth/infrastructure/ui"
+)
+
+type auditRouterCtx struct {
+	auditUsecase domain.AuditUsecase
+}
+
+func NewAuditRouter(e *echo.Echo, auditUsecase domain.AuditUsecase) *auditRouterCtx {
+	routerCtx := &auditRouterCtx{
+		auditUsecase,
+	}
+
+	e.GET("/audit", routerCtx.getEntries, authUI.RequireScope(authDomain.ScopeKeysAdmin))
+
+	return routerCtx
+}
+
+func (ctx *auditRouterCtx) getEntries(c echo.Context) error {
+	var params EntriesParams
+
+	if err := c.Bind(&params); err != nil {
+		return HTTPError{
+			Message: "Invalid query params",
+		}.BadRequest()
+	}
+
+	if err := c.Validate(params); err != nil {
+		return HTTPError{
+			Message: "Invalid query params",
+		}.ErrUnprocessableEntity()
+	}
+
+	page, err := ctx.auditUsecase.GetEntries(c.Request().Context(), &domain.EntryFilter{
+		EntityType: domain.EntityType(params.EntityType),
+		EntityID:   params.EntityID,
+		Cursor:     params.Cursor,
+		Limit:      params.Limit,
+	})
+	if err != nil {
+		return handleErr(err)
+	}
+
+	entriesOut := []*EntryOut{}
+
+	for _, entry := range page.Entries {
+		entriesOut = append(entriesOut, toEntryOut(entry))
+	}
+
+	return c.JSON(http.StatusOK, &EntriesOut{
+		NextCursor: page.NextCursor,
+		Data:       entriesOut,
+	})
+}
+
+func handleErr(err error) error {
+	switch err {
+	case domain.ErrInvalidEntityType:
+		return HTTPError{
+			Message: "Invalid entity. Use one of posts, projects, chikitos or apikeys",
+		}.ErrUnprocessableEntity()
+	case domain.ErrInvalidCursor:
+		return HTTPError{
+			Message: "Invalid cursor",
+		}.BadRequest()
+	default:
+		return HTTPError{
+			Message: "Internal server error",
+		}.InternalServerError()
+	}
+}
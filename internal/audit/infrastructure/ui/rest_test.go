@@ -0,0 +1,142 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+	"github.com/yavurb/goyurback/internal/app/mods"
+	"github.com/yavurb/goyurback/internal/audit/domain"
+	"github.com/yavurb/goyurback/internal/audit/infrastructure/ui/mocks"
+)
+
+func TestGetEntries(t *testing.T) {
+	e := echo.New()
+	e.Validator = mods.NewAppValidator()
+
+	createdAt := time.Date(2024, 7, 15, 10, 30, 0, 0, time.UTC)
+
+	newContext := func(query string) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodGet, "/audit"+query, nil)
+		rec := httptest.NewRecorder()
+
+		return e.NewContext(req, rec), rec
+	}
+
+	t.Run("it should return the entries", func(t *testing.T) {
+		var gotFilter *domain.EntryFilter
+
+		uc := &mocks.MockAuditUsecase{
+			GetEntriesFn: func(ctx context.Context, filter *domain.EntryFilter) (*domain.EntryPage, error) {
+				gotFilter = filter
+
+				return &domain.EntryPage{
+					Entries: []*domain.Entry{
+						{
+							ID:         2,
+							Actor:      "ak_1",
+							Action:     domain.ActionUpdate,
+							EntityType: domain.EntityPosts,
+							EntityID:   "po_1",
+							RequestID:  "req_1",
+							IP:         "10.0.0.1",
+							Diff:       json.RawMessage(`{"title":{"new":"New","old":"Old"}}`),
+							CreatedAt:  createdAt,
+						},
+					},
+					NextCursor: "next",
+				}, nil
+			},
+		}
+
+		c, rec := newContext("?entity=posts&id=po_1&limit=1&cursor=abc")
+		h := NewAuditRouter(e, uc)
+
+		if err := h.getEntries(c); err != nil {
+			t.Fatalf("Expected no errors getting the entries. Got: %v", err)
+		}
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected status code to be %d. Got: %d", http.StatusOK, rec.Code)
+		}
+
+		wantFilter := &domain.EntryFilter{EntityType: domain.EntityPosts, EntityID: "po_1", Cursor: "abc", Limit: 1}
+		if diff := cmp.Diff(wantFilter, gotFilter); diff != "" {
+			t.Errorf("getEntries() filter mismatch (-want +got):\n%s", diff)
+		}
+
+		got := EntriesOut{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatalf("Error unmarshalling response: %s", err)
+		}
+
+		want := EntriesOut{
+			NextCursor: "next",
+			Data: []*EntryOut{
+				{
+					ID:         2,
+					Actor:      "ak_1",
+					Action:     "update",
+					EntityType: "posts",
+					EntityID:   "po_1",
+					RequestID:  "req_1",
+					IP:         "10.0.0.1",
+					Diff:       json.RawMessage(`{"title":{"new":"New","old":"Old"}}`),
+					CreatedAt:  createdAt,
+				},
+			},
+		}
+
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("getEntries() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("it should reject a limit out of range", func(t *testing.T) {
+		c, _ := newContext("?limit=500")
+		h := NewAuditRouter(e, &mocks.MockAuditUsecase{})
+
+		err := h.getEntries(c)
+
+		var httpErr *echo.HTTPError
+		if !errors.As(err, &httpErr) || httpErr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected an unprocessable entity error. Got: %v", err)
+		}
+	})
+
+	cases := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{name: "invalid entity", err: domain.ErrInvalidEntityType, wantCode: http.StatusUnprocessableEntity},
+		{name: "invalid cursor", err: domain.ErrInvalidCursor, wantCode: http.StatusBadRequest},
+		{name: "db error", err: errors.New("DB error"), wantCode: http.StatusInternalServerError},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			uc := &mocks.MockAuditUsecase{
+				GetEntriesFn: func(ctx context.Context, filter *domain.EntryFilter) (*domain.EntryPage, error) {
+					return nil, tc.err
+				},
+			}
+
+			c, _ := newContext("?entity=users")
+			h := NewAuditRouter(e, uc)
+
+			err := h.getEntries(c)
+
+			var httpErr *echo.HTTPError
+			if !errors.As(err, &httpErr) || httpErr.Code != tc.wantCode {
+				t.Errorf("Expected a %d error. Got: %v", tc.wantCode, err)
+			}
+		})
+	}
+}
@@ -4,31 +4,52 @@ import (
 	"context"
 	"crypto/sha512"
 	"encoding/hex"
+	"log"
 	"strings"
+	"time"
 
 	"github.com/yavurb/goyurback/internal/auth/domain"
 	"github.com/yavurb/goyurback/internal/pgk/ids"
+	"github.com/yavurb/goyurback/internal/pgk/rand"
 )
 
 const prefix = "sk"
 
-func (uc *apiKeyUsecase) CreateAPIKey(ctx context.Context, name string) (*domain.APIKey, error) {
-	keyString, err := ids.NewAPIKey()
+func (uc *apiKeyUsecase) CreateAPIKey(ctx context.Context, name string, scopes []domain.Scope, expiresAt time.Time, rateLimit int32) (*domain.APIKey, error) {
+	scopes, err := domain.NormalizeScopes(scopes)
 	if err != nil {
 		return nil, err
 	}
 
-	sha512Hash := sha512.New()
-	sha512Hash.Write([]byte(keyString))
-	sha := sha512Hash.Sum(nil)
-	hashedApikey := hex.EncodeToString(sha)
+	// Dates are stored without a time zone, in UTC
+	expiresAt = expiresAt.UTC()
 
-	keySalt := strings.Split(keyString, ".")[0]
-	hashedApikeyWithSalt := strings.Join([]string{keySalt, hashedApikey}, ".")
+	if !expiresAt.IsZero() && !expiresAt.After(time.Now().UTC()) {
+		return nil, domain.ErrInvalidExpiry
+	}
+
+	if rateLimit < 0 {
+		return nil, domain.ErrInvalidRateLimit
+	}
+
+	keyString, keyID, hashedApikey, err := newAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	secret, encryptedSecret, err := uc.newSigningSecret()
+	if err != nil {
+		return nil, err
+	}
 
 	apiKey := &domain.APIKeyCreate{
-		Key:  hashedApikeyWithSalt,
-		Name: name,
+		ExpiresAt:     expiresAt,
+		Key:           hashedApikey,
+		KeyID:         keyID,
+		Name:          name,
+		Scopes:        scopes,
+		SigningSecret: encryptedSecret,
+		RateLimit:     rateLimit,
 	}
 
 	createdKey, err := uc.repository.CreateAPIKey(ctx, apiKey)
@@ -36,8 +57,52 @@ func (uc *apiKeyUsecase) CreateAPIKey(ctx context.Context, name string) (*domain
 		return nil, err
 	}
 
-	keyString = strings.Join([]string{prefix, keyString}, "_")
 	createdKey.Key = keyString
+	createdKey.Secret = secret
 
 	return createdKey, nil
 }
+
+// newAPIKey returns the key handed to the client, its key id and the salted hash that gets stored.
+func newAPIKey() (string, string, string, error) {
+	keyString, err := ids.NewAPIKey()
+	if err != nil {
+		return "", "", "", err
+	}
+
+	keySalt := strings.Split(keyString, ".")[0]
+	hashedApikeyWithSalt := strings.Join([]string{keySalt, hashAPIKey(keyString)}, ".")
+
+	return strings.Join([]string{prefix, keyString}, "_"), keySalt, hashedApikeyWithSalt, nil
+}
+
+// newSigningSecret returns the signing secret handed to the client and its encrypted version.
+// Both are empty when no cipher is configured.
+func (uc *apiKeyUsecase) newSigningSecret() (string, []byte, error) {
+	if uc.cipher == nil {
+		return "", nil, nil
+	}
+
+	secret, err := rand.GenerateRandomString(32)
+	if err != nil {
+		return "", nil, err
+	}
+
+	encryptedSecret, err := uc.cipher.Encrypt([]byte(secret))
+	if err != nil {
+		log.Printf("Error encrypting signing secret. Got: %v\n", err)
+
+		return "", nil, err
+	}
+
+	return secret, encryptedSecret, nil
+}
+
+// hashAPIKey returns the hex encoded SHA-512 of a key without its prefix.
+func hashAPIKey(key string) string {
+	sha512Hash := sha512.New()
+	sha512Hash.Write([]byte(key))
+	sha := sha512Hash.Sum(nil)
+
+	return hex.EncodeToString(sha)
+}
@@ -2,9 +2,12 @@ package application
 
 import (
 	"context"
+	"errors"
 	"regexp"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/yavurb/goyurback/internal/auth/application/mocks"
 	"github.com/yavurb/goyurback/internal/auth/domain"
@@ -18,16 +21,17 @@ func TestCreateAPIKey(t *testing.T) {
 				PublicID: "test",
 				Key:      apiKey.Key,
 				Name:     apiKey.Name,
+				Scopes:   apiKey.Scopes,
 				Revoked:  false,
 			}, nil
 		},
 	}
 
-	uc := NewAPIKeyUsecase(repo)
+	uc := NewAPIKeyUsecase(repo, &mocks.MockUsageTracker{}, nil)
 
 	ctx := context.Background()
 
-	apikey, err := uc.CreateAPIKey(ctx, "test")
+	apikey, err := uc.CreateAPIKey(ctx, "test", []domain.Scope{domain.ScopePostsWrite}, time.Time{}, 0)
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -49,3 +53,166 @@ func TestCreateAPIKey(t *testing.T) {
 		t.Errorf("Expected key to match regex pattern xx_xxx+.xxxxxx+, got %s", apikey.Key)
 	}
 }
+
+func TestCreateAPIKeyScopes(t *testing.T) {
+	repo := &mocks.MockAuthRepository{
+		CreateAPIKeyFn: func(ctx context.Context, apiKey *domain.APIKeyCreate) (*domain.APIKey, error) {
+			return &domain.APIKey{
+				ID:       1,
+				PublicID: "test",
+				Key:      apiKey.Key,
+				Name:     apiKey.Name,
+				Scopes:   apiKey.Scopes,
+			}, nil
+		},
+	}
+
+	uc := NewAPIKeyUsecase(repo, &mocks.MockUsageTracker{}, nil)
+
+	t.Run("it should store the requested scopes sorted and without duplicates", func(t *testing.T) {
+		apikey, err := uc.CreateAPIKey(
+			context.Background(),
+			"test",
+			[]domain.Scope{domain.ScopeProjectsWrite, domain.ScopePostsWrite, domain.ScopeProjectsWrite},
+			time.Time{},
+			0,
+		)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		want := []domain.Scope{domain.ScopePostsWrite, domain.ScopeProjectsWrite}
+		if !slices.Equal(apikey.Scopes, want) {
+			t.Errorf("Expected scopes to be %v, got %v", want, apikey.Scopes)
+		}
+	})
+
+	t.Run("it should reject unknown scopes", func(t *testing.T) {
+		_, err := uc.CreateAPIKey(context.Background(), "test", []domain.Scope{"posts:delete"}, time.Time{}, 0)
+		if !errors.Is(err, domain.ErrInvalidScope) {
+			t.Errorf("Expected error to be %v, got %v", domain.ErrInvalidScope, err)
+		}
+	})
+}
+
+func TestCreateAPIKeyExpiry(t *testing.T) {
+	repo := &mocks.MockAuthRepository{
+		CreateAPIKeyFn: func(ctx context.Context, apiKey *domain.APIKeyCreate) (*domain.APIKey, error) {
+			return &domain.APIKey{
+				ID:        1,
+				PublicID:  "test",
+				Key:       apiKey.Key,
+				Name:      apiKey.Name,
+				Scopes:    apiKey.Scopes,
+				ExpiresAt: apiKey.ExpiresAt,
+			}, nil
+		},
+	}
+
+	uc := NewAPIKeyUsecase(repo, &mocks.MockUsageTracker{}, nil)
+	scopes := []domain.Scope{domain.ScopePostsWrite}
+
+	t.Run("it should store the expiry", func(t *testing.T) {
+		expiresAt := time.Now().Add(30 * 24 * time.Hour)
+
+		apikey, err := uc.CreateAPIKey(context.Background(), "test", scopes, expiresAt, 0)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if !apikey.ExpiresAt.Equal(expiresAt) {
+			t.Errorf("Expected the key to expire at %v, got %v", expiresAt, apikey.ExpiresAt)
+		}
+	})
+
+	t.Run("it should store the expiry in UTC", func(t *testing.T) {
+		expiresAt := time.Now().Add(30 * 24 * time.Hour).In(time.FixedZone("", -5*60*60))
+
+		apikey, err := uc.CreateAPIKey(context.Background(), "test", scopes, expiresAt, 0)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if apikey.ExpiresAt.Location() != time.UTC || !apikey.ExpiresAt.Equal(expiresAt) {
+			t.Errorf("Expected the key to expire at %v, got %v", expiresAt.UTC(), apikey.ExpiresAt)
+		}
+	})
+
+	t.Run("it should reject an expiry in the past", func(t *testing.T) {
+		_, err := uc.CreateAPIKey(context.Background(), "test", scopes, time.Now().Add(-time.Minute), 0)
+		if !errors.Is(err, domain.ErrInvalidExpiry) {
+			t.Errorf("Expected error to be %v, got %v", domain.ErrInvalidExpiry, err)
+		}
+	})
+}
+
+func TestCreateAPIKeyRateLimit(t *testing.T) {
+	repo := &mocks.MockAuthRepository{
+		CreateAPIKeyFn: func(ctx context.Context, apiKey *domain.APIKeyCreate) (*domain.APIKey, error) {
+			return &domain.APIKey{ID: 1, PublicID: "test", RateLimit: apiKey.RateLimit}, nil
+		},
+	}
+
+	uc := NewAPIKeyUsecase(repo, &mocks.MockUsageTracker{}, nil)
+	scopes := []domain.Scope{domain.ScopePostsWrite}
+
+	t.Run("it should store the rate limit", func(t *testing.T) {
+		apikey, err := uc.CreateAPIKey(context.Background(), "test", scopes, time.Time{}, 600)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if apikey.RateLimit != 600 {
+			t.Errorf("Expected the rate limit to be 600, got %d", apikey.RateLimit)
+		}
+	})
+
+	t.Run("it should reject a negative rate limit", func(t *testing.T) {
+		_, err := uc.CreateAPIKey(context.Background(), "test", scopes, time.Time{}, -1)
+		if !errors.Is(err, domain.ErrInvalidRateLimit) {
+			t.Errorf("Expected error to be %v, got %v", domain.ErrInvalidRateLimit, err)
+		}
+	})
+}
+
+func TestCreateAPIKeySigningSecret(t *testing.T) {
+	var stored *domain.APIKeyCreate
+
+	repo := &mocks.MockAuthRepository{
+		CreateAPIKeyFn: func(ctx context.Context, apiKey *domain.APIKeyCreate) (*domain.APIKey, error) {
+			stored = apiKey
+
+			return &domain.APIKey{ID: 1, PublicID: "test", SigningSecret: apiKey.SigningSecret}, nil
+		},
+	}
+
+	t.Run("it should issue an encrypted signing secret", func(t *testing.T) {
+		uc := NewAPIKeyUsecase(repo, &mocks.MockUsageTracker{}, &mocks.MockSecretCipher{})
+
+		apikey, err := uc.CreateAPIKey(context.Background(), "test", []domain.Scope{domain.ScopePostsWrite}, time.Time{}, 0)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if apikey.Secret == "" {
+			t.Fatalf("Expected the signing secret to be returned")
+		}
+
+		if want := "encrypted:" + apikey.Secret; string(stored.SigningSecret) != want {
+			t.Errorf("Expected the stored secret to be %q, got %q", want, stored.SigningSecret)
+		}
+	})
+
+	t.Run("it should issue keys without secret when there is no cipher", func(t *testing.T) {
+		uc := NewAPIKeyUsecase(repo, &mocks.MockUsageTracker{}, nil)
+
+		apikey, err := uc.CreateAPIKey(context.Background(), "test", []domain.Scope{domain.ScopePostsWrite}, time.Time{}, 0)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if apikey.Secret != "" || stored.SigningSecret != nil {
+			t.Errorf("Expected no signing secret, got %q", apikey.Secret)
+		}
+	})
+}
@@ -0,0 +1,30 @@
+package application
+
+import (
+	"context"
+	"log"
+
+	"github.com/yavurb/goyurback/internal/auth/domain"
+)
+
+func (uc *apiKeyUsecase) GetAPIKey(ctx context.Context, publicID string) (*domain.APIKey, error) {
+	apiKey, err := uc.repository.GetAPIKey(ctx, publicID)
+	if err != nil {
+		log.Printf("Error getting api key. Got: %v\n", err)
+
+		return nil, err
+	}
+
+	return apiKey, nil
+}
+
+func (uc *apiKeyUsecase) GetAPIKeys(ctx context.Context) ([]*domain.APIKey, error) {
+	apiKeys, err := uc.repository.GetAPIKeys(ctx)
+	if err != nil {
+		log.Printf("Error getting api keys. Got: %v\n", err)
+
+		return nil, err
+	}
+
+	return apiKeys, nil
+}
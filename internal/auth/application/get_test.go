@@ -0,0 +1,81 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yavurb/goyurback/internal/auth/application/mocks"
+	"github.com/yavurb/goyurback/internal/auth/domain"
+)
+
+func TestGetAPIKey(t *testing.T) {
+	want := &domain.APIKey{
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+		Name:      "testing_key",
+		Key:       apiKeyHash,
+		PublicID:  "ak_1qjrblb8pm90",
+		ID:        1,
+	}
+
+	t.Run("it should get an api key", func(t *testing.T) {
+		repo := &mocks.MockAuthRepository{
+			GetAPIKeyFn: func(ctx context.Context, publicID string) (*domain.APIKey, error) {
+				return want, nil
+			},
+		}
+
+		uc := NewAPIKeyUsecase(repo, &mocks.MockUsageTracker{}, nil)
+
+		got, err := uc.GetAPIKey(context.Background(), "ak_1qjrblb8pm90")
+		if err != nil {
+			t.Errorf("Expected no error, got: %v", err)
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Expected api key to be %v, got: %v", want, got)
+		}
+	})
+
+	t.Run("it should return a not found error", func(t *testing.T) {
+		repo := &mocks.MockAuthRepository{
+			GetAPIKeyFn: func(ctx context.Context, publicID string) (*domain.APIKey, error) {
+				return nil, domain.ErrAPIKeyNotFound
+			},
+		}
+
+		uc := NewAPIKeyUsecase(repo, &mocks.MockUsageTracker{}, nil)
+
+		_, err := uc.GetAPIKey(context.Background(), "ak_1qjrblb8pm90")
+		if !errors.Is(err, domain.ErrAPIKeyNotFound) {
+			t.Errorf("Expected error to be %v, got: %v", domain.ErrAPIKeyNotFound, err)
+		}
+	})
+}
+
+func TestGetAPIKeys(t *testing.T) {
+	want := []*domain.APIKey{
+		{Name: "testing_key", PublicID: "ak_1qjrblb8pm90", ID: 2},
+		{Name: "old_key", PublicID: "ak_0000000000aa", ID: 1, Revoked: true, RevokedAt: time.Now().UTC()},
+	}
+
+	repo := &mocks.MockAuthRepository{
+		GetAPIKeysFn: func(ctx context.Context) ([]*domain.APIKey, error) {
+			return want, nil
+		},
+	}
+
+	uc := NewAPIKeyUsecase(repo, &mocks.MockUsageTracker{}, nil)
+
+	got, err := uc.GetAPIKeys(context.Background())
+	if err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected api keys to be %v, got: %v", want, got)
+	}
+}
@@ -0,0 +1,59 @@
+package application
+
+import (
+	"sync"
+	"time"
+
+	"github.com/yavurb/goyurback/internal/auth/domain"
+)
+
+// keyCache keeps the keys that were validated recently, so they aren't read on every request.
+type keyCache struct {
+	ttl time.Duration
+
+	mu      sync.RWMutex
+	entries map[string]*cachedKey // By key id
+}
+
+type cachedKey struct {
+	cachedAt time.Time
+	apiKey   *domain.APIKey
+}
+
+func newKeyCache(ttl time.Duration) *keyCache {
+	return &keyCache{
+		ttl:     ttl,
+		entries: map[string]*cachedKey{},
+	}
+}
+
+func (c *keyCache) get(keyID string, now time.Time) (*domain.APIKey, bool) {
+	c.mu.RLock()
+	entry, ok := c.entries[keyID]
+	c.mu.RUnlock()
+
+	if !ok || now.Sub(entry.cachedAt) >= c.ttl {
+		return nil, false
+	}
+
+	return entry.apiKey, true
+}
+
+func (c *keyCache) set(apiKey *domain.APIKey, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[apiKey.KeyID] = &cachedKey{now, apiKey}
+}
+
+// delete removes the key with the given public id, so changes to it apply on the next request.
+func (c *keyCache) delete(publicID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for keyID, entry := range c.entries {
+		if entry.apiKey.PublicID == publicID {
+			delete(c.entries, keyID)
+		}
+	}
+}
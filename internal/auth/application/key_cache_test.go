@@ -0,0 +1,36 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yavurb/goyurback/internal/auth/domain"
+)
+
+func TestKeyCache(t *testing.T) {
+	now := time.Date(2024, 7, 14, 10, 30, 0, 0, time.UTC)
+	apiKey := &domain.APIKey{KeyID: "testsalt", PublicID: "ak_1qjrblb8pm90"}
+
+	t.Run("it should return keys until their ttl runs out", func(t *testing.T) {
+		cache := newKeyCache(time.Minute)
+		cache.set(apiKey, now)
+
+		if got, ok := cache.get("testsalt", now.Add(59*time.Second)); !ok || got != apiKey {
+			t.Errorf("Expected the cached key, got %v", got)
+		}
+
+		if _, ok := cache.get("testsalt", now.Add(time.Minute)); ok {
+			t.Errorf("Expected the key to be gone after its ttl")
+		}
+	})
+
+	t.Run("it should delete keys by public id", func(t *testing.T) {
+		cache := newKeyCache(time.Minute)
+		cache.set(apiKey, now)
+		cache.delete("ak_1qjrblb8pm90")
+
+		if _, ok := cache.get("testsalt", now); ok {
+			t.Errorf("Expected the key to be deleted")
+		}
+	})
+}
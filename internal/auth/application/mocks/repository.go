@@ -1,23 +1,73 @@
 package mocks
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"time"
 
 	"github.com/yavurb/goyurback/internal/auth/domain"
 )
 
 type MockAuthRepository struct {
-	CreateAPIKeyFn     func(ctx context.Context, apiKey *domain.APIKeyCreate) (*domain.APIKey, error)
-	GetAPIKeyByValueFn func(ctx context.Context, apiKey string) (*domain.APIKey, error)
-	RevokeAPIKeyFn     func(ctx context.Context, publicID string) error
+	CreateAPIKeyFn        func(ctx context.Context, apiKey *domain.APIKeyCreate) (*domain.APIKey, error)
+	GetAPIKeyFn           func(ctx context.Context, publicID string) (*domain.APIKey, error)
+	GetAPIKeysFn          func(ctx context.Context) ([]*domain.APIKey, error)
+	GetAPIKeyByKeyIDFn    func(ctx context.Context, keyID string) (*domain.APIKey, error)
+	RevokeAPIKeyFn        func(ctx context.Context, publicID string) error
+	RotateAPIKeyFn        func(ctx context.Context, publicID string, graceUntil time.Time, apiKey *domain.APIKeyCreate) (*domain.APIKey, error)
+	UpdateAPIKeysUsageFn  func(ctx context.Context, usages []*domain.APIKeyUsage) error
+	ClaimNonceFn          func(ctx context.Context, keyID, nonce string, now, expiresAt time.Time) (bool, error)
+	DeleteExpiredNoncesFn func(ctx context.Context, now time.Time) error
 }
 
+type MockUsageTracker struct {
+	Usages []*domain.APIKeyUsage
+}
+
+// MockSecretCipher "encrypts" by prefixing the plaintext with `encrypted:`.
+type MockSecretCipher struct{}
+
 func (m *MockAuthRepository) CreateAPIKey(ctx context.Context, apiKey *domain.APIKeyCreate) (*domain.APIKey, error) {
 	return m.CreateAPIKeyFn(ctx, apiKey)
 }
-func (m *MockAuthRepository) GetAPIKeyByValue(ctx context.Context, apiKey string) (*domain.APIKey, error) {
-	return m.GetAPIKeyByValueFn(ctx, apiKey)
+func (m *MockAuthRepository) GetAPIKey(ctx context.Context, publicID string) (*domain.APIKey, error) {
+	return m.GetAPIKeyFn(ctx, publicID)
+}
+func (m *MockAuthRepository) GetAPIKeys(ctx context.Context) ([]*domain.APIKey, error) {
+	return m.GetAPIKeysFn(ctx)
+}
+func (m *MockAuthRepository) GetAPIKeyByKeyID(ctx context.Context, keyID string) (*domain.APIKey, error) {
+	return m.GetAPIKeyByKeyIDFn(ctx, keyID)
 }
 func (m *MockAuthRepository) RevokeAPIKey(ctx context.Context, publicID string) error {
 	return m.RevokeAPIKeyFn(ctx, publicID)
 }
+func (m *MockAuthRepository) RotateAPIKey(ctx context.Context, publicID string, graceUntil time.Time, apiKey *domain.APIKeyCreate) (*domain.APIKey, error) {
+	return m.RotateAPIKeyFn(ctx, publicID, graceUntil, apiKey)
+}
+func (m *MockAuthRepository) UpdateAPIKeysUsage(ctx context.Context, usages []*domain.APIKeyUsage) error {
+	return m.UpdateAPIKeysUsageFn(ctx, usages)
+}
+func (m *MockAuthRepository) ClaimNonce(ctx context.Context, keyID, nonce string, now, expiresAt time.Time) (bool, error) {
+	return m.ClaimNonceFn(ctx, keyID, nonce, now, expiresAt)
+}
+func (m *MockAuthRepository) DeleteExpiredNonces(ctx context.Context, now time.Time) error {
+	return m.DeleteExpiredNoncesFn(ctx, now)
+}
+
+func (m *MockUsageTracker) Track(usage *domain.APIKeyUsage) {
+	m.Usages = append(m.Usages, usage)
+}
+
+func (m *MockSecretCipher) Encrypt(plaintext []byte) ([]byte, error) {
+	return append([]byte("encrypted:"), plaintext...), nil
+}
+func (m *MockSecretCipher) Decrypt(ciphertext []byte) ([]byte, error) {
+	plaintext, ok := bytes.CutPrefix(ciphertext, []byte("encrypted:"))
+	if !ok {
+		return nil, errors.New("invalid ciphertext")
+	}
+
+	return plaintext, nil
+}
@@ -0,0 +1,45 @@
+package application
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/yavurb/goyurback/internal/auth/domain"
+)
+
+// claimNonce uses up the nonce of a signed request. Nonces are stored in the database, so a
+// request can't be replayed against another instance.
+func (uc *apiKeyUsecase) claimNonce(ctx context.Context, request *domain.SignedRequest, now time.Time) error {
+	uc.pruneNonces(ctx, now)
+
+	claimed, err := uc.repository.ClaimNonce(ctx, request.KeyID, request.Nonce, now, now.Add(domain.SignatureNonceTTL))
+	if err != nil {
+		log.Printf("Error claiming signature nonce. Got: %v\n", err)
+
+		return err
+	}
+
+	if !claimed {
+		return domain.ErrSignatureReplayed
+	}
+
+	return nil
+}
+
+// pruneNonces deletes the expired nonces. It runs at most once per domain.SignatureNonceTTL on
+// each instance, and its errors are only logged as the nonces are deleted on the next run.
+func (uc *apiKeyUsecase) pruneNonces(ctx context.Context, now time.Time) {
+	uc.noncesMu.Lock()
+	if now.Before(uc.nextNoncePrune) {
+		uc.noncesMu.Unlock()
+
+		return
+	}
+	uc.nextNoncePrune = now.Add(domain.SignatureNonceTTL)
+	uc.noncesMu.Unlock()
+
+	if err := uc.repository.DeleteExpiredNonces(ctx, now); err != nil {
+		log.Printf("Error deleting expired signature nonces. Got: %v\n", err)
+	}
+}
@@ -0,0 +1,78 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yavurb/goyurback/internal/auth/application/mocks"
+	"github.com/yavurb/goyurback/internal/auth/domain"
+)
+
+func TestClaimNonce(t *testing.T) {
+	request := &domain.SignedRequest{KeyID: "testsalt", Nonce: "nonce"}
+
+	t.Run("it should keep the nonce for the whole replay window", func(t *testing.T) {
+		now := time.Now()
+		repo := &mocks.MockAuthRepository{
+			ClaimNonceFn: func(ctx context.Context, keyID, nonce string, claimedAt, expiresAt time.Time) (bool, error) {
+				if keyID != "testsalt" || nonce != "nonce" {
+					t.Errorf("Expected the nonce testsalt:nonce. Got: %s:%s", keyID, nonce)
+				}
+
+				if want := now.Add(2 * domain.SignatureReplayWindow); !expiresAt.Equal(want) {
+					t.Errorf("Expected the nonce to expire at %v. Got: %v", want, expiresAt)
+				}
+
+				return true, nil
+			},
+			DeleteExpiredNoncesFn: func(ctx context.Context, now time.Time) error { return nil },
+		}
+		uc := NewAPIKeyUsecase(repo, &mocks.MockUsageTracker{}, nil).(*apiKeyUsecase)
+
+		if err := uc.claimNonce(context.Background(), request, now); err != nil {
+			t.Errorf("Expected no error. Got: %v", err)
+		}
+	})
+
+	t.Run("it should reject a used nonce", func(t *testing.T) {
+		repo := &mocks.MockAuthRepository{
+			ClaimNonceFn: func(ctx context.Context, keyID, nonce string, now, expiresAt time.Time) (bool, error) {
+				return false, nil
+			},
+			DeleteExpiredNoncesFn: func(ctx context.Context, now time.Time) error { return nil },
+		}
+		uc := NewAPIKeyUsecase(repo, &mocks.MockUsageTracker{}, nil).(*apiKeyUsecase)
+
+		if err := uc.claimNonce(context.Background(), request, time.Now()); !errors.Is(err, domain.ErrSignatureReplayed) {
+			t.Errorf("Expected error to be %v. Got: %v", domain.ErrSignatureReplayed, err)
+		}
+	})
+
+	t.Run("it should delete the expired nonces at most once per TTL", func(t *testing.T) {
+		prunes := 0
+		repo := &mocks.MockAuthRepository{
+			ClaimNonceFn: func(ctx context.Context, keyID, nonce string, now, expiresAt time.Time) (bool, error) {
+				return true, nil
+			},
+			DeleteExpiredNoncesFn: func(ctx context.Context, now time.Time) error {
+				prunes++
+
+				return errors.New("DB error")
+			},
+		}
+		uc := NewAPIKeyUsecase(repo, &mocks.MockUsageTracker{}, nil).(*apiKeyUsecase)
+
+		now := time.Now()
+		for _, at := range []time.Time{now, now.Add(time.Minute), now.Add(domain.SignatureNonceTTL)} {
+			if err := uc.claimNonce(context.Background(), request, at); err != nil {
+				t.Errorf("Expected no error. Got: %v", err)
+			}
+		}
+
+		if prunes != 2 {
+			t.Errorf("Expected the nonces to be pruned twice. Got: %d", prunes)
+		}
+	})
+}
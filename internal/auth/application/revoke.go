@@ -1,12 +1,19 @@
 package application
 
-import "context"
+import (
+	"context"
+	"log"
+)
 
 func (uc *apiKeyUsecase) RevokeAPIKey(ctx context.Context, publicID string) error {
 	err := uc.repository.RevokeAPIKey(ctx, publicID)
 	if err != nil {
+		log.Printf("Error revoking api key. Got: %v\n", err)
+
 		return err
 	}
 
+	uc.cache.delete(publicID)
+
 	return nil
 }
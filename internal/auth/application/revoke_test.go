@@ -2,9 +2,11 @@ package application
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/yavurb/goyurback/internal/auth/application/mocks"
+	"github.com/yavurb/goyurback/internal/auth/domain"
 )
 
 func TestRevokeAPIKey(t *testing.T) {
@@ -14,7 +16,7 @@ func TestRevokeAPIKey(t *testing.T) {
 		},
 	}
 
-	uc := NewAPIKeyUsecase(repo)
+	uc := NewAPIKeyUsecase(repo, &mocks.MockUsageTracker{}, nil)
 	ctx := context.Background()
 
 	err := uc.RevokeAPIKey(ctx, "random-id")
@@ -22,3 +24,18 @@ func TestRevokeAPIKey(t *testing.T) {
 		t.Errorf("Expected no error, got: %v", err)
 	}
 }
+
+func TestRevokeAPIKeyNotFound(t *testing.T) {
+	repo := &mocks.MockAuthRepository{
+		RevokeAPIKeyFn: func(ctx context.Context, publicID string) error {
+			return domain.ErrAPIKeyNotFound
+		},
+	}
+
+	uc := NewAPIKeyUsecase(repo, &mocks.MockUsageTracker{}, nil)
+
+	err := uc.RevokeAPIKey(context.Background(), "random-id")
+	if !errors.Is(err, domain.ErrAPIKeyNotFound) {
+		t.Errorf("Expected error to be %v, got: %v", domain.ErrAPIKeyNotFound, err)
+	}
+}
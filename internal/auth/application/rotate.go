@@ -0,0 +1,73 @@
+package application
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/yavurb/goyurback/internal/auth/domain"
+)
+
+func (uc *apiKeyUsecase) RotateAPIKey(ctx context.Context, publicID string, gracePeriod time.Duration, expiresAt time.Time) (*domain.APIKey, error) {
+	if gracePeriod < 0 {
+		return nil, domain.ErrInvalidGracePeriod
+	}
+
+	// Dates are stored without a time zone, in UTC
+	now := time.Now().UTC()
+	expiresAt = expiresAt.UTC()
+
+	if !expiresAt.IsZero() && !expiresAt.After(now) {
+		return nil, domain.ErrInvalidExpiry
+	}
+
+	apiKey, err := uc.repository.GetAPIKey(ctx, publicID)
+	if err != nil {
+		log.Printf("Error getting api key to rotate. Got: %v\n", err)
+
+		return nil, err
+	}
+
+	if apiKey.Revoked {
+		return nil, domain.ErrAPIKeyRevoked
+	}
+
+	if apiKey.Expired(now) {
+		return nil, domain.ErrAPIKeyExpired
+	}
+
+	keyString, keyID, hashedApikey, err := newAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	secret, encryptedSecret, err := uc.newSigningSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	replacement := &domain.APIKeyCreate{
+		ExpiresAt:     expiresAt,
+		Key:           hashedApikey,
+		KeyID:         keyID,
+		Name:          apiKey.Name,
+		Scopes:        apiKey.Scopes,
+		SigningSecret: encryptedSecret,
+		RateLimit:     apiKey.RateLimit,
+	}
+
+	rotatedKey, err := uc.repository.RotateAPIKey(ctx, publicID, now.Add(gracePeriod), replacement)
+	if err != nil {
+		log.Printf("Error rotating api key. Got: %v\n", err)
+
+		return nil, err
+	}
+
+	// The old key now expires at the end of the grace period
+	uc.cache.delete(publicID)
+
+	rotatedKey.Key = keyString
+	rotatedKey.Secret = secret
+
+	return rotatedKey, nil
+}
@@ -0,0 +1,133 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yavurb/goyurback/internal/auth/application/mocks"
+	"github.com/yavurb/goyurback/internal/auth/domain"
+)
+
+func TestRotateAPIKey(t *testing.T) {
+	newRepo := func(stored *domain.APIKey) (*mocks.MockAuthRepository, *time.Time) {
+		var gotGraceUntil time.Time
+
+		return &mocks.MockAuthRepository{
+			GetAPIKeyFn: func(ctx context.Context, publicID string) (*domain.APIKey, error) {
+				if publicID != stored.PublicID {
+					return nil, domain.ErrAPIKeyNotFound
+				}
+
+				return stored, nil
+			},
+			RotateAPIKeyFn: func(ctx context.Context, publicID string, graceUntil time.Time, apiKey *domain.APIKeyCreate) (*domain.APIKey, error) {
+				gotGraceUntil = graceUntil
+
+				return &domain.APIKey{
+					ID:        2,
+					PublicID:  "ak_replacement",
+					Key:       apiKey.Key,
+					Name:      apiKey.Name,
+					Scopes:    apiKey.Scopes,
+					ExpiresAt: apiKey.ExpiresAt,
+				}, nil
+			},
+		}, &gotGraceUntil
+	}
+
+	stored := &domain.APIKey{
+		ID:       1,
+		PublicID: "ak_1qjrblb8pm90",
+		Name:     "ci_pipeline",
+		Scopes:   []domain.Scope{domain.ScopePostsWrite},
+	}
+
+	t.Run("it should issue a replacement and keep the old key working for the grace period", func(t *testing.T) {
+		repo, gotGraceUntil := newRepo(stored)
+		uc := NewAPIKeyUsecase(repo, &mocks.MockUsageTracker{}, nil)
+
+		before := time.Now()
+
+		rotated, err := uc.RotateAPIKey(context.Background(), "ak_1qjrblb8pm90", time.Hour, time.Time{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if rotated.PublicID != "ak_replacement" || rotated.Name != "ci_pipeline" {
+			t.Errorf("Expected the replacement key, got %+v", rotated)
+		}
+
+		if len(rotated.Scopes) != 1 || rotated.Scopes[0] != domain.ScopePostsWrite {
+			t.Errorf("Expected the replacement to keep the scopes, got %v", rotated.Scopes)
+		}
+
+		if !strings.HasPrefix(rotated.Key, "sk_") {
+			t.Errorf("Expected the plain key to be returned, got %s", rotated.Key)
+		}
+
+		if gotGraceUntil.Before(before.Add(time.Hour)) || gotGraceUntil.After(time.Now().Add(time.Hour)) {
+			t.Errorf("Expected the old key to expire in an hour, got %v", *gotGraceUntil)
+		}
+	})
+
+	t.Run("it should pass the dates in UTC", func(t *testing.T) {
+		repo, gotGraceUntil := newRepo(stored)
+		uc := NewAPIKeyUsecase(repo, &mocks.MockUsageTracker{}, nil)
+
+		expiresAt := time.Now().Add(24 * time.Hour).In(time.FixedZone("", -5*60*60))
+
+		rotated, err := uc.RotateAPIKey(context.Background(), "ak_1qjrblb8pm90", time.Hour, expiresAt)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if rotated.ExpiresAt.Location() != time.UTC || !rotated.ExpiresAt.Equal(expiresAt) {
+			t.Errorf("Expected the replacement to expire at %v, got %v", expiresAt.UTC(), rotated.ExpiresAt)
+		}
+
+		if gotGraceUntil.Location() != time.UTC {
+			t.Errorf("Expected the grace period to end in UTC, got %v", *gotGraceUntil)
+		}
+	})
+
+	t.Run("it should not rotate revoked or expired keys", func(t *testing.T) {
+		revoked := *stored
+		revoked.Revoked = true
+
+		expired := *stored
+		expired.ExpiresAt = time.Now().Add(-time.Minute)
+
+		for want, key := range map[error]*domain.APIKey{domain.ErrAPIKeyRevoked: &revoked, domain.ErrAPIKeyExpired: &expired} {
+			repo, _ := newRepo(key)
+			uc := NewAPIKeyUsecase(repo, &mocks.MockUsageTracker{}, nil)
+
+			_, err := uc.RotateAPIKey(context.Background(), "ak_1qjrblb8pm90", time.Hour, time.Time{})
+			if !errors.Is(err, want) {
+				t.Errorf("Expected error to be %v, got %v", want, err)
+			}
+		}
+	})
+
+	t.Run("it should reject a negative grace period", func(t *testing.T) {
+		repo, _ := newRepo(stored)
+		uc := NewAPIKeyUsecase(repo, &mocks.MockUsageTracker{}, nil)
+
+		_, err := uc.RotateAPIKey(context.Background(), "ak_1qjrblb8pm90", -time.Hour, time.Time{})
+		if !errors.Is(err, domain.ErrInvalidGracePeriod) {
+			t.Errorf("Expected error to be %v, got %v", domain.ErrInvalidGracePeriod, err)
+		}
+	})
+
+	t.Run("it should return not found for unknown keys", func(t *testing.T) {
+		repo, _ := newRepo(stored)
+		uc := NewAPIKeyUsecase(repo, &mocks.MockUsageTracker{}, nil)
+
+		_, err := uc.RotateAPIKey(context.Background(), "ak_unknown", time.Hour, time.Time{})
+		if !errors.Is(err, domain.ErrAPIKeyNotFound) {
+			t.Errorf("Expected error to be %v, got %v", domain.ErrAPIKeyNotFound, err)
+		}
+	})
+}
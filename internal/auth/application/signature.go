@@ -0,0 +1,54 @@
+package application
+
+import (
+	"context"
+	"crypto/hmac"
+	"log"
+	"time"
+
+	"github.com/yavurb/goyurback/internal/auth/domain"
+)
+
+func (uc *apiKeyUsecase) ValidateSignature(ctx context.Context, request *domain.SignedRequest, ip string) (*domain.APIKey, error) {
+	now := time.Now().UTC()
+
+	if skew := now.Sub(request.Timestamp).Abs(); skew > domain.SignatureReplayWindow {
+		return nil, domain.ErrSignatureExpired
+	}
+
+	storedKey, cached, err := uc.lookupKey(ctx, request.KeyID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if !storedKey.CanSign() || uc.cipher == nil {
+		return nil, domain.ErrSigningUnsupported
+	}
+
+	secret, err := uc.cipher.Decrypt(storedKey.SigningSecret)
+	if err != nil {
+		log.Printf("Error decrypting signing secret. Got: %v\n", err)
+
+		return nil, err
+	}
+
+	// The body is read last, so only the clients of a signing key can make the server read it
+	if err := request.HashBody(); err != nil {
+		return nil, err
+	}
+
+	if !hmac.Equal(request.Signature, request.Sign(secret)) {
+		return nil, domain.ErrSignatureInvalid
+	}
+
+	// Only signed requests use up their nonce, so a forged request can't block a legitimate one
+	if err := uc.claimNonce(ctx, request, now); err != nil {
+		return nil, err
+	}
+
+	if err := uc.accept(storedKey, cached, ip, now); err != nil {
+		return nil, err
+	}
+
+	return storedKey, nil
+}
@@ -0,0 +1,248 @@
+package application
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yavurb/goyurback/internal/auth/application/mocks"
+	"github.com/yavurb/goyurback/internal/auth/domain"
+)
+
+func TestValidateSignature(t *testing.T) {
+	const secret = "signing-secret"
+
+	repo := func(storedKey *domain.APIKey) *mocks.MockAuthRepository {
+		nonces := map[string]bool{}
+
+		return &mocks.MockAuthRepository{
+			GetAPIKeyByKeyIDFn: func(ctx context.Context, keyID string) (*domain.APIKey, error) {
+				if keyID != storedKey.KeyID {
+					return nil, domain.ErrAPIKeyNotFound
+				}
+
+				return storedKey, nil
+			},
+			ClaimNonceFn: func(ctx context.Context, keyID, nonce string, now, expiresAt time.Time) (bool, error) {
+				if nonces[keyID+":"+nonce] {
+					return false, nil
+				}
+
+				nonces[keyID+":"+nonce] = true
+
+				return true, nil
+			},
+			DeleteExpiredNoncesFn: func(ctx context.Context, now time.Time) error { return nil },
+		}
+	}
+
+	newKey := func() *domain.APIKey {
+		return &domain.APIKey{
+			KeyID:         "testsalt",
+			PublicID:      "ak_1qjrblb8pm90",
+			SigningSecret: []byte("encrypted:" + secret),
+			ID:            1,
+		}
+	}
+
+	signedRequest := func(nonce string, timestamp time.Time) *domain.SignedRequest {
+		request := &domain.SignedRequest{
+			Timestamp: timestamp,
+			KeyID:     "testsalt",
+			Nonce:     nonce,
+			Method:    "POST",
+			Path:      "/posts",
+			Body:      strings.NewReader(`{"title":"Signed"}`),
+		}
+		bodyHash := sha256.Sum256([]byte(`{"title":"Signed"}`))
+		request.BodyHash = bodyHash[:]
+		request.Signature = request.Sign([]byte(secret))
+		request.BodyHash = nil
+
+		return request
+	}
+
+	t.Run("it should accept a signed request once", func(t *testing.T) {
+		tracker := &mocks.MockUsageTracker{}
+		uc := NewAPIKeyUsecase(repo(newKey()), tracker, &mocks.MockSecretCipher{})
+		request := signedRequest("nonce", time.Now())
+
+		apiKey, err := uc.ValidateSignature(context.Background(), request, "127.0.0.1")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if apiKey.PublicID != "ak_1qjrblb8pm90" {
+			t.Errorf("Expected the key ak_1qjrblb8pm90, got %s", apiKey.PublicID)
+		}
+
+		if len(tracker.Usages) != 1 || tracker.Usages[0].IP != "127.0.0.1" {
+			t.Errorf("Expected the usage to be tracked, got %v", tracker.Usages)
+		}
+
+		replayed := signedRequest("nonce", request.Timestamp)
+		if _, err := uc.ValidateSignature(context.Background(), replayed, "127.0.0.1"); !errors.Is(err, domain.ErrSignatureReplayed) {
+			t.Errorf("Expected error to be %v, got: %v", domain.ErrSignatureReplayed, err)
+		}
+	})
+
+	t.Run("it should not use up the nonce of a bad signature", func(t *testing.T) {
+		uc := NewAPIKeyUsecase(repo(newKey()), &mocks.MockUsageTracker{}, &mocks.MockSecretCipher{})
+
+		forged := signedRequest("nonce", time.Now())
+		forged.Signature = []byte("forged")
+
+		if _, err := uc.ValidateSignature(context.Background(), forged, "127.0.0.1"); !errors.Is(err, domain.ErrSignatureInvalid) {
+			t.Errorf("Expected error to be %v, got: %v", domain.ErrSignatureInvalid, err)
+		}
+
+		if _, err := uc.ValidateSignature(context.Background(), signedRequest("nonce", time.Now()), "127.0.0.1"); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("it should not read the body before the key is known", func(t *testing.T) {
+		uc := NewAPIKeyUsecase(repo(newKey()), &mocks.MockUsageTracker{}, &mocks.MockSecretCipher{})
+
+		request := signedRequest("nonce", time.Now())
+		request.KeyID = "unknown"
+		request.Body = readerFunc(func(p []byte) (int, error) {
+			t.Error("Expected the body not to be read")
+
+			return 0, io.EOF
+		})
+
+		if _, err := uc.ValidateSignature(context.Background(), request, "127.0.0.1"); !errors.Is(err, domain.ErrAPIKeyInvalid) {
+			t.Errorf("Expected error to be %v, got: %v", domain.ErrAPIKeyInvalid, err)
+		}
+	})
+
+	t.Run("it should reject a tampered body", func(t *testing.T) {
+		uc := NewAPIKeyUsecase(repo(newKey()), &mocks.MockUsageTracker{}, &mocks.MockSecretCipher{})
+
+		request := signedRequest("nonce", time.Now())
+		request.Body = strings.NewReader(`{"title":"Tampered"}`)
+
+		if _, err := uc.ValidateSignature(context.Background(), request, "127.0.0.1"); !errors.Is(err, domain.ErrSignatureInvalid) {
+			t.Errorf("Expected error to be %v, got: %v", domain.ErrSignatureInvalid, err)
+		}
+	})
+
+	t.Run("it should reject a tampered request", func(t *testing.T) {
+		uc := NewAPIKeyUsecase(repo(newKey()), &mocks.MockUsageTracker{}, &mocks.MockSecretCipher{})
+
+		request := signedRequest("nonce", time.Now())
+		request.Path = "/projects"
+
+		if _, err := uc.ValidateSignature(context.Background(), request, "127.0.0.1"); !errors.Is(err, domain.ErrSignatureInvalid) {
+			t.Errorf("Expected error to be %v, got: %v", domain.ErrSignatureInvalid, err)
+		}
+	})
+
+	cases := []struct {
+		name    string
+		key     func() *domain.APIKey
+		request *domain.SignedRequest
+		cipher  domain.SecretCipher
+		wantErr error
+	}{
+		{
+			name:    "old timestamp",
+			key:     newKey,
+			request: signedRequest("nonce", time.Now().Add(-domain.SignatureReplayWindow-time.Second)),
+			cipher:  &mocks.MockSecretCipher{},
+			wantErr: domain.ErrSignatureExpired,
+		},
+		{
+			name:    "future timestamp",
+			key:     newKey,
+			request: signedRequest("nonce", time.Now().Add(domain.SignatureReplayWindow+time.Minute)),
+			cipher:  &mocks.MockSecretCipher{},
+			wantErr: domain.ErrSignatureExpired,
+		},
+		{
+			name: "unknown key",
+			key:  newKey,
+			request: func() *domain.SignedRequest {
+				request := signedRequest("nonce", time.Now())
+				request.KeyID = "unknown"
+
+				return request
+			}(),
+			cipher:  &mocks.MockSecretCipher{},
+			wantErr: domain.ErrAPIKeyInvalid,
+		},
+		{
+			name: "key without secret",
+			key: func() *domain.APIKey {
+				key := newKey()
+				key.SigningSecret = nil
+
+				return key
+			},
+			request: signedRequest("nonce", time.Now()),
+			cipher:  &mocks.MockSecretCipher{},
+			wantErr: domain.ErrSigningUnsupported,
+		},
+		{
+			name:    "no cipher",
+			key:     newKey,
+			request: signedRequest("nonce", time.Now()),
+			wantErr: domain.ErrSigningUnsupported,
+		},
+		{
+			name: "revoked key",
+			key: func() *domain.APIKey {
+				key := newKey()
+				key.Revoked = true
+
+				return key
+			},
+			request: signedRequest("nonce", time.Now()),
+			cipher:  &mocks.MockSecretCipher{},
+			wantErr: domain.ErrAPIKeyRevoked,
+		},
+		{
+			name: "expired key",
+			key: func() *domain.APIKey {
+				key := newKey()
+				key.ExpiresAt = time.Now().Add(-time.Minute)
+
+				return key
+			},
+			request: signedRequest("nonce", time.Now()),
+			cipher:  &mocks.MockSecretCipher{},
+			wantErr: domain.ErrAPIKeyExpired,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tracker := &mocks.MockUsageTracker{}
+			uc := NewAPIKeyUsecase(repo(tc.key()), tracker, tc.cipher)
+
+			apiKey, err := uc.ValidateSignature(context.Background(), tc.request, "127.0.0.1")
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("Expected error to be %v, got: %v", tc.wantErr, err)
+			}
+
+			if apiKey != nil {
+				t.Errorf("Expected no key, got %v", apiKey)
+			}
+
+			if len(tracker.Usages) != 0 {
+				t.Errorf("Expected the usage of a rejected request not to be tracked, got: %v", tracker.Usages)
+			}
+		})
+	}
+}
+
+type readerFunc func(p []byte) (int, error)
+
+func (f readerFunc) Read(p []byte) (int, error) {
+	return f(p)
+}
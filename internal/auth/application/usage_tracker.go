@@ -0,0 +1,110 @@
+package application
+
+import (
+	"cmp"
+	"context"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/yavurb/goyurback/internal/auth/domain"
+)
+
+// UsageTracker batches the usage of the API keys and writes it periodically, so validating a
+// key never waits on a write.
+type UsageTracker struct {
+	repository domain.APIKeyRepository
+	interval   time.Duration
+
+	mu      sync.Mutex
+	pending map[int32]*domain.APIKeyUsage
+}
+
+func NewUsageTracker(repository domain.APIKeyRepository, interval time.Duration) *UsageTracker {
+	return &UsageTracker{
+		repository: repository,
+		interval:   interval,
+		pending:    map[int32]*domain.APIKeyUsage{},
+	}
+}
+
+// Track keeps the latest usage of each key until the next flush.
+func (t *UsageTracker) Track(usage *domain.APIKeyUsage) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if last, ok := t.pending[usage.ID]; ok && last.UsedAt.After(usage.UsedAt) {
+		return
+	}
+
+	t.pending[usage.ID] = usage
+}
+
+// Run blocks until ctx is done. The usage still pending at that point is written before
+// returning, so it isn't lost on shutdown.
+func (t *UsageTracker) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			t.Flush(flushCtx)
+
+			return
+		case <-ticker.C:
+			t.Flush(ctx)
+		}
+	}
+}
+
+// Flush writes the pending usage. When the batch fails each usage is written on its own, so a
+// bad one can't hold back the others, and the usage that still fails is dropped. It is kept for
+// the next flush only when every write failed, as the database is likely unreachable then.
+func (t *UsageTracker) Flush(ctx context.Context) error {
+	t.mu.Lock()
+	usages := []*domain.APIKeyUsage{}
+	for _, usage := range t.pending {
+		usages = append(usages, usage)
+	}
+	t.pending = map[int32]*domain.APIKeyUsage{}
+	t.mu.Unlock()
+
+	if len(usages) == 0 {
+		return nil
+	}
+
+	slices.SortFunc(usages, func(a, b *domain.APIKeyUsage) int { return cmp.Compare(a.ID, b.ID) })
+
+	err := t.repository.UpdateAPIKeysUsage(ctx, usages)
+	if err == nil {
+		return nil
+	}
+
+	log.Printf("Error updating api keys usage, writing them one by one. Got: %v\n", err)
+
+	failed := []*domain.APIKeyUsage{}
+	for _, usage := range usages {
+		if err := t.repository.UpdateAPIKeysUsage(ctx, []*domain.APIKeyUsage{usage}); err != nil {
+			failed = append(failed, usage)
+		}
+	}
+
+	if len(failed) == len(usages) {
+		for _, usage := range usages {
+			t.Track(usage)
+		}
+
+		return err
+	}
+
+	for _, usage := range failed {
+		log.Printf("Error updating the usage of api key %d, dropping it. Got: %v\n", usage.ID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/yavurb/goyurback/internal/auth/application/mocks"
+	"github.com/yavurb/goyurback/internal/auth/domain"
+)
+
+func TestUsageTracker(t *testing.T) {
+	usedAt := time.Date(2024, 7, 14, 10, 30, 0, 0, time.UTC)
+
+	t.Run("it should write the latest usage of each key in one batch", func(t *testing.T) {
+		var batches [][]*domain.APIKeyUsage
+
+		repo := &mocks.MockAuthRepository{
+			UpdateAPIKeysUsageFn: func(ctx context.Context, usages []*domain.APIKeyUsage) error {
+				batches = append(batches, usages)
+
+				return nil
+			},
+		}
+
+		tracker := NewUsageTracker(repo, time.Minute)
+		tracker.Track(&domain.APIKeyUsage{ID: 2, IP: "10.0.0.1", UsedAt: usedAt})
+		tracker.Track(&domain.APIKeyUsage{ID: 1, IP: "10.0.0.2", UsedAt: usedAt.Add(time.Second)})
+		tracker.Track(&domain.APIKeyUsage{ID: 1, IP: "10.0.0.3", UsedAt: usedAt})
+
+		if err := tracker.Flush(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		want := [][]*domain.APIKeyUsage{{
+			{ID: 1, IP: "10.0.0.2", UsedAt: usedAt.Add(time.Second)},
+			{ID: 2, IP: "10.0.0.1", UsedAt: usedAt},
+		}}
+		if diff := cmp.Diff(want, batches); diff != "" {
+			t.Errorf("Flush() mismatch (-want +got):\n%s", diff)
+		}
+
+		if err := tracker.Flush(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if len(batches) != 1 {
+			t.Errorf("Expected nothing to be written when there is no new usage, got %d batches", len(batches))
+		}
+	})
+
+	t.Run("it should keep the usage for the next flush when the write fails", func(t *testing.T) {
+		fail := true
+		var written []*domain.APIKeyUsage
+
+		repo := &mocks.MockAuthRepository{
+			UpdateAPIKeysUsageFn: func(ctx context.Context, usages []*domain.APIKeyUsage) error {
+				if fail {
+					return errors.New("DB error")
+				}
+
+				written = usages
+
+				return nil
+			},
+		}
+
+		tracker := NewUsageTracker(repo, time.Minute)
+		tracker.Track(&domain.APIKeyUsage{ID: 1, IP: "10.0.0.1", UsedAt: usedAt})
+
+		if err := tracker.Flush(context.Background()); err == nil {
+			t.Fatalf("Expected an error")
+		}
+
+		fail = false
+
+		if err := tracker.Flush(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if len(written) != 1 || written[0].ID != 1 {
+			t.Errorf("Expected the usage to be written on the next flush, got %v", written)
+		}
+	})
+
+	t.Run("it should drop the usage that can't be written on its own", func(t *testing.T) {
+		var written []*domain.APIKeyUsage
+
+		repo := &mocks.MockAuthRepository{
+			UpdateAPIKeysUsageFn: func(ctx context.Context, usages []*domain.APIKeyUsage) error {
+				for _, usage := range usages {
+					if usage.ID == 2 {
+						return errors.New("DB error")
+					}
+				}
+
+				written = append(written, usages...)
+
+				return nil
+			},
+		}
+
+		tracker := NewUsageTracker(repo, time.Minute)
+		tracker.Track(&domain.APIKeyUsage{ID: 1, IP: "10.0.0.1", UsedAt: usedAt})
+		tracker.Track(&domain.APIKeyUsage{ID: 2, IP: "10.0.0.2", UsedAt: usedAt})
+		tracker.Track(&domain.APIKeyUsage{ID: 3, IP: "10.0.0.3", UsedAt: usedAt})
+
+		if err := tracker.Flush(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if len(written) != 2 || written[0].ID != 1 || written[1].ID != 3 {
+			t.Errorf("Expected the other usages to be written, got %v", written)
+		}
+
+		written = nil
+
+		if err := tracker.Flush(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if len(written) != 0 {
+			t.Errorf("Expected the failed usage to be dropped, got %v", written)
+		}
+	})
+
+	t.Run("it should write the pending usage on shutdown", func(t *testing.T) {
+		var written []*domain.APIKeyUsage
+
+		repo := &mocks.MockAuthRepository{
+			UpdateAPIKeysUsageFn: func(ctx context.Context, usages []*domain.APIKeyUsage) error {
+				written = usages
+
+				return nil
+			},
+		}
+
+		tracker := NewUsageTracker(repo, time.Hour)
+		tracker.Track(&domain.APIKeyUsage{ID: 1, IP: "10.0.0.1", UsedAt: usedAt})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		tracker.Run(ctx)
+
+		if len(written) != 1 {
+			t.Errorf("Expected the pending usage to be written, got %v", written)
+		}
+	})
+}
@@ -1,11 +1,29 @@
 package application
 
-import "github.com/yavurb/goyurback/internal/auth/domain"
+import (
+	"sync"
+	"time"
+
+	"github.com/yavurb/goyurback/internal/auth/domain"
+)
 
 type apiKeyUsecase struct {
-	repository domain.APIKeyRepository
+	repository   domain.APIKeyRepository
+	usageTracker domain.APIKeyUsageTracker
+	cipher       domain.SecretCipher
+	cache        *keyCache
+
+	noncesMu       sync.Mutex
+	nextNoncePrune time.Time
 }
 
-func NewAPIKeyUsecase(repository domain.APIKeyRepository) domain.APIKeyUsecase {
-	return &apiKeyUsecase{repository}
+// NewAPIKeyUsecase creates the API keys usecase. Keys are issued without a signing secret, and
+// can't sign requests, when cipher is nil.
+func NewAPIKeyUsecase(repository domain.APIKeyRepository, usageTracker domain.APIKeyUsageTracker, cipher domain.SecretCipher) domain.APIKeyUsecase {
+	return &apiKeyUsecase{
+		repository:   repository,
+		usageTracker: usageTracker,
+		cipher:       cipher,
+		cache:        newKeyCache(domain.ValidatedKeyTTL),
+	}
 }
@@ -2,49 +2,86 @@ package application
 
 import (
 	"context"
-	"crypto/sha512"
-	"encoding/hex"
+	"crypto/subtle"
 	"errors"
 	"log"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/yavurb/goyurback/internal/auth/domain"
+	"github.com/yavurb/goyurback/internal/pgk/ips"
 )
 
-func (uc *apiKeyUsecase) ValidateAPIKey(ctx context.Context, key string) (bool, error) {
-	match, err := regexp.MatchString("^[a-z]{2}_[a-zA-Z0-9]+\\.[a-zA-Z0-9]+$", key)
+var keyFormat = regexp.MustCompile(`^[a-z]{2}_[a-zA-Z0-9]+\.[a-zA-Z0-9]+$`)
+
+func (uc *apiKeyUsecase) ValidateAPIKey(ctx context.Context, key, ip string) (*domain.APIKey, error) {
+	if !keyFormat.MatchString(key) {
+		return nil, domain.ErrAPIKeyInvalid
+	}
+
+	apiKey := strings.Split(key, "_")[1] // remove prefix
+	apiKeySalt := strings.Split(apiKey, ".")[0]
+	hashedApikey := hashAPIKey(apiKey)
+
+	now := time.Now().UTC()
+
+	storedKey, cached, err := uc.lookupKey(ctx, apiKeySalt, now)
 	if err != nil {
-		log.Printf("Error matching api key: %v\n", err)
+		return nil, err
+	}
 
-		return false, err
+	_, storedHash, _ := strings.Cut(storedKey.Key, ".")
+	if subtle.ConstantTimeCompare([]byte(storedHash), []byte(hashedApikey)) != 1 {
+		return nil, domain.ErrAPIKeyInvalid
 	}
 
-	if !match {
-		return false, nil
+	if err := uc.accept(storedKey, cached, ip, now); err != nil {
+		return nil, err
 	}
 
-	apiKey := strings.Split(key, "_")[1] // remove prefix
-	apiKeySalt := strings.Split(apiKey, ".")[0]
+	return storedKey, nil
+}
 
-	sha512Hash := sha512.New()
-	sha512Hash.Write([]byte(apiKey))
-	sha := sha512Hash.Sum(nil)
-	hashedApikey := hex.EncodeToString(sha)
+// lookupKey returns the key with the given key id, from the cache when it was validated recently.
+func (uc *apiKeyUsecase) lookupKey(ctx context.Context, keyID string, now time.Time) (*domain.APIKey, bool, error) {
+	if storedKey, ok := uc.cache.get(keyID, now); ok {
+		return storedKey, true, nil
+	}
 
-	_, err = uc.repository.GetAPIKeyByValue(
-		ctx,
-		strings.Join([]string{apiKeySalt, hashedApikey}, "."),
-	)
+	storedKey, err := uc.repository.GetAPIKeyByKeyID(ctx, keyID)
 	if err != nil {
 		if errors.Is(err, domain.ErrAPIKeyNotFound) {
-			return false, nil
+			return nil, false, domain.ErrAPIKeyInvalid
 		}
 
-		log.Printf("Error getting api key by value: %v\n", err)
+		log.Printf("Error getting api key by key id: %v\n", err)
+
+		return nil, false, err
+	}
+
+	return storedKey, false, nil
+}
+
+// accept checks that an authenticated key can still be used, then caches it and records its usage.
+func (uc *apiKeyUsecase) accept(storedKey *domain.APIKey, cached bool, ip string, now time.Time) error {
+	if storedKey.Revoked {
+		return domain.ErrAPIKeyRevoked
+	}
 
-		return false, err
+	if storedKey.Expired(now) {
+		return domain.ErrAPIKeyExpired
 	}
 
-	return true, nil
+	if !cached {
+		uc.cache.set(storedKey, now)
+	}
+
+	uc.usageTracker.Track(&domain.APIKeyUsage{
+		UsedAt: now,
+		IP:     ips.Normalize(ip),
+		ID:     storedKey.ID,
+	})
+
+	return nil
 }
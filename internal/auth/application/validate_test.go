@@ -2,6 +2,7 @@ package application
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -15,10 +16,87 @@ const (
 )
 
 func TestValidate(t *testing.T) {
-	t.Run("it should return true", func(t *testing.T) {
+	storedKey := func(revoked bool, expiresAt time.Time) *mocks.MockAuthRepository {
+		return &mocks.MockAuthRepository{
+			GetAPIKeyByKeyIDFn: func(ctx context.Context, keyID string) (*domain.APIKey, error) {
+				key := &domain.APIKey{
+					CreatedAt: time.Now().UTC(),
+					UpdatedAt: time.Now().UTC(),
+					ExpiresAt: expiresAt,
+					Name:      "testing_key",
+					Key:       apiKeyHash,
+					KeyID:     "testsalt",
+					PublicID:  "ak_1qjrblb8pm90",
+					ID:        1,
+					Revoked:   revoked,
+				}
+
+				if revoked {
+					key.RevokedAt = time.Now().UTC()
+				}
+
+				return key, nil
+			},
+		}
+	}
+
+	t.Run("it should reject a revoked key", func(t *testing.T) {
+		tracker := &mocks.MockUsageTracker{}
+		uc := NewAPIKeyUsecase(storedKey(true, time.Time{}), tracker, nil)
+
+		key, err := uc.ValidateAPIKey(context.Background(), apiKey, "127.0.0.1")
+		if !errors.Is(err, domain.ErrAPIKeyRevoked) {
+			t.Errorf("Expected error to be %v, got: %v", domain.ErrAPIKeyRevoked, err)
+		}
+
+		if key != nil {
+			t.Errorf("Expected a revoked key to be invalid")
+		}
+
+		if len(tracker.Usages) != 0 {
+			t.Errorf("Expected the usage of a rejected key not to be tracked, got: %v", tracker.Usages)
+		}
+	})
+
+	t.Run("it should reject a revoked key even if it has not expired", func(t *testing.T) {
+		uc := NewAPIKeyUsecase(storedKey(true, time.Now().Add(time.Hour)), &mocks.MockUsageTracker{}, nil)
+
+		_, err := uc.ValidateAPIKey(context.Background(), apiKey, "127.0.0.1")
+		if !errors.Is(err, domain.ErrAPIKeyRevoked) {
+			t.Errorf("Expected error to be %v, got: %v", domain.ErrAPIKeyRevoked, err)
+		}
+	})
+
+	t.Run("it should reject an expired key", func(t *testing.T) {
+		uc := NewAPIKeyUsecase(storedKey(false, time.Now().Add(-time.Minute)), &mocks.MockUsageTracker{}, nil)
+
+		key, err := uc.ValidateAPIKey(context.Background(), apiKey, "127.0.0.1")
+		if !errors.Is(err, domain.ErrAPIKeyExpired) {
+			t.Errorf("Expected error to be %v, got: %v", domain.ErrAPIKeyExpired, err)
+		}
+
+		if key != nil {
+			t.Errorf("Expected an expired key to be invalid")
+		}
+	})
+
+	t.Run("it should accept a key that has not expired yet", func(t *testing.T) {
+		uc := NewAPIKeyUsecase(storedKey(false, time.Now().Add(time.Hour)), &mocks.MockUsageTracker{}, nil)
+
+		key, err := uc.ValidateAPIKey(context.Background(), apiKey, "127.0.0.1")
+		if err != nil {
+			t.Errorf("Expected no error, got: %v", err)
+		}
+
+		if key == nil {
+			t.Errorf("Expected the key to be valid")
+		}
+	})
+
+	t.Run("it should return the stored key", func(t *testing.T) {
 		repo := &mocks.MockAuthRepository{
-			GetAPIKeyByValueFn: func(ctx context.Context, apiKey string) (*domain.APIKey, error) {
-				if apiKey != apiKeyHash {
+			GetAPIKeyByKeyIDFn: func(ctx context.Context, keyID string) (*domain.APIKey, error) {
+				if keyID != "testsalt" {
 					return nil, domain.ErrAPIKeyNotFound
 				}
 
@@ -27,30 +105,41 @@ func TestValidate(t *testing.T) {
 					UpdatedAt: time.Now().UTC(),
 					Name:      "testing_key",
 					Key:       apiKeyHash,
+					KeyID:     "testsalt",
 					PublicID:  "ak_1qjrblb8pm90",
 					ID:        1,
 					Revoked:   false,
+					Scopes:    []domain.Scope{domain.ScopePostsWrite},
 				}, nil
 			},
 		}
 
-		uc := NewAPIKeyUsecase(repo)
+		tracker := &mocks.MockUsageTracker{}
+		uc := NewAPIKeyUsecase(repo, tracker, nil)
 		ctx := context.Background()
 
-		valid, err := uc.ValidateAPIKey(ctx, apiKey)
+		key, err := uc.ValidateAPIKey(ctx, apiKey, "127.0.0.1")
 		if err != nil {
 			t.Errorf("Expected no error, got: %v", err)
 		}
 
-		if !valid {
-			t.Errorf("Expected valid to be true, got: %v", valid)
+		if key == nil || key.PublicID != "ak_1qjrblb8pm90" {
+			t.Fatalf("Expected the stored key to be returned, got: %v", key)
+		}
+
+		if !key.HasScope(domain.ScopePostsWrite) {
+			t.Errorf("Expected the key to keep its scopes, got: %v", key.Scopes)
+		}
+
+		if len(tracker.Usages) != 1 || tracker.Usages[0].ID != 1 || tracker.Usages[0].IP != "127.0.0.1" {
+			t.Errorf("Expected the usage of the key to be tracked, got: %v", tracker.Usages)
 		}
 	})
 
-	t.Run("it should return false", func(t *testing.T) {
+	t.Run("it should reject an unknown key", func(t *testing.T) {
 		repo := &mocks.MockAuthRepository{
-			GetAPIKeyByValueFn: func(ctx context.Context, apiKey string) (*domain.APIKey, error) {
-				if apiKey != apiKeyHash {
+			GetAPIKeyByKeyIDFn: func(ctx context.Context, keyID string) (*domain.APIKey, error) {
+				if keyID != "testsalt" {
 					return nil, domain.ErrAPIKeyNotFound
 				}
 
@@ -59,6 +148,7 @@ func TestValidate(t *testing.T) {
 					UpdatedAt: time.Now().UTC(),
 					Name:      "testing_key",
 					Key:       apiKeyHash,
+					KeyID:     "testsalt",
 					PublicID:  "ak_1qjrblb8pm90",
 					ID:        1,
 					Revoked:   false,
@@ -66,31 +156,117 @@ func TestValidate(t *testing.T) {
 			},
 		}
 
-		uc := NewAPIKeyUsecase(repo)
+		uc := NewAPIKeyUsecase(repo, &mocks.MockUsageTracker{}, nil)
 		ctx := context.Background()
 
-		valid, err := uc.ValidateAPIKey(ctx, "sk_invalid.key")
-		if err != nil {
-			t.Errorf("Expected no error, got: %v", err)
+		key, err := uc.ValidateAPIKey(ctx, "sk_invalid.key", "127.0.0.1")
+		if !errors.Is(err, domain.ErrAPIKeyInvalid) {
+			t.Errorf("Expected error to be %v, got: %v", domain.ErrAPIKeyInvalid, err)
 		}
 
-		if valid == true {
-			t.Errorf("Expected valid to be true, got: %v", valid)
+		if key != nil {
+			t.Errorf("Expected no key, got: %v", key)
 		}
 	})
 
-	t.Run("it should return false if the key does not follow the key format sk_salt.hash", func(t *testing.T) {
+	t.Run("it should reject a key that does not follow the key format sk_salt.hash", func(t *testing.T) {
 		repo := &mocks.MockAuthRepository{}
-		uc := NewAPIKeyUsecase(repo)
+		uc := NewAPIKeyUsecase(repo, &mocks.MockUsageTracker{}, nil)
 		ctx := context.Background()
 
-		valid, err := uc.ValidateAPIKey(ctx, "invalid-key")
-		if err != nil {
-			t.Errorf("Expected no error, got: %v", err)
+		key, err := uc.ValidateAPIKey(ctx, "invalid-key", "127.0.0.1")
+		if !errors.Is(err, domain.ErrAPIKeyInvalid) {
+			t.Errorf("Expected error to be %v, got: %v", domain.ErrAPIKeyInvalid, err)
+		}
+
+		if key != nil {
+			t.Errorf("Expected no key, got: %v", key)
+		}
+	})
+	t.Run("it should reject a key with a known key id but a wrong secret", func(t *testing.T) {
+		uc := NewAPIKeyUsecase(storedKey(false, time.Time{}), &mocks.MockUsageTracker{}, nil)
+
+		key, err := uc.ValidateAPIKey(context.Background(), "sk_testsalt.wrongkey", "127.0.0.1")
+		if !errors.Is(err, domain.ErrAPIKeyInvalid) {
+			t.Errorf("Expected error to be %v, got: %v", domain.ErrAPIKeyInvalid, err)
+		}
+
+		if key != nil {
+			t.Errorf("Expected no key, got: %v", key)
+		}
+	})
+}
+
+func TestValidateCache(t *testing.T) {
+	newRepo := func(lookups *int) *mocks.MockAuthRepository {
+		return &mocks.MockAuthRepository{
+			GetAPIKeyByKeyIDFn: func(ctx context.Context, keyID string) (*domain.APIKey, error) {
+				*lookups++
+
+				return &domain.APIKey{
+					Name:     "testing_key",
+					Key:      apiKeyHash,
+					KeyID:    "testsalt",
+					PublicID: "ak_1qjrblb8pm90",
+					ID:       1,
+				}, nil
+			},
+			RevokeAPIKeyFn: func(ctx context.Context, publicID string) error {
+				return nil
+			},
+		}
+	}
+
+	t.Run("it should read a validated key once", func(t *testing.T) {
+		var lookups int
+
+		uc := NewAPIKeyUsecase(newRepo(&lookups), &mocks.MockUsageTracker{}, nil)
+
+		for range 3 {
+			if _, err := uc.ValidateAPIKey(context.Background(), apiKey, "127.0.0.1"); err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+		}
+
+		if lookups != 1 {
+			t.Errorf("Expected the key to be read once, got %d reads", lookups)
+		}
+	})
+
+	t.Run("it should check the secret of cached keys", func(t *testing.T) {
+		var lookups int
+
+		uc := NewAPIKeyUsecase(newRepo(&lookups), &mocks.MockUsageTracker{}, nil)
+
+		if _, err := uc.ValidateAPIKey(context.Background(), apiKey, "127.0.0.1"); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		_, err := uc.ValidateAPIKey(context.Background(), "sk_testsalt.wrongkey", "127.0.0.1")
+		if !errors.Is(err, domain.ErrAPIKeyInvalid) {
+			t.Errorf("Expected error to be %v, got: %v", domain.ErrAPIKeyInvalid, err)
+		}
+	})
+
+	t.Run("it should read the key again after it is revoked", func(t *testing.T) {
+		var lookups int
+
+		uc := NewAPIKeyUsecase(newRepo(&lookups), &mocks.MockUsageTracker{}, nil)
+
+		if _, err := uc.ValidateAPIKey(context.Background(), apiKey, "127.0.0.1"); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		if err := uc.RevokeAPIKey(context.Background(), "ak_1qjrblb8pm90"); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		if _, err := uc.ValidateAPIKey(context.Background(), apiKey, "127.0.0.1"); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
 		}
 
-		if valid == true {
-			t.Errorf("Expected valid to be true, got: %v", valid)
+		if lookups != 2 {
+			t.Errorf("Expected the key to be read again after revoking it, got %d reads", lookups)
 		}
 	})
 }
@@ -1,20 +1,74 @@
 package domain
 
-import "time"
+import (
+	"slices"
+	"time"
+)
+
+// DefaultRotationGracePeriod is how long a rotated key keeps working when no grace period is given.
+const DefaultRotationGracePeriod = 24 * time.Hour
+
+// ValidatedKeyTTL is how long a validated key is kept in memory before it is read again. Keys
+// revoked through another instance keep working on this one for at most this long.
+const ValidatedKeyTTL = 30 * time.Second
 
 type APIKey struct {
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	RevokedAt time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	RevokedAt  time.Time
+	ExpiresAt  time.Time // Zero when the key never expires
+	LastUsedAt time.Time // Zero when the key has never been used
+
+	Name          string
+	Key           string // Salted hash of the key, as `<key id>.<hash>`
+	KeyID         string // Salt of the key, used to look it up
+	Secret        string // Signing secret handed to the client, only set when the key is issued
+	PublicID      string
+	LastUsedIP    string
+	Scopes        []Scope
+	SigningSecret []byte // Encrypted. Empty for keys issued while no SecretCipher was configured
+	RateLimit     int32  // Requests per minute. Zero to use the limits of the route groups
+	ID            int32
+	Revoked       bool
+}
+
+// CanSign reports whether the key can authenticate signed requests.
+func (k APIKey) CanSign() bool {
+	return len(k.SigningSecret) > 0
+}
+
+func (k APIKey) Expired(now time.Time) bool {
+	return !k.ExpiresAt.IsZero() && !k.ExpiresAt.After(now)
+}
 
-	Name     string
-	Key      string
-	PublicID string
-	ID       int32
-	Revoked  bool
+func (k APIKey) HasScope(scope Scope) bool {
+	return slices.Contains(k.Scopes, scope)
+}
+
+// HasAnyScope reports whether the key has at least one of the scopes.
+func (k APIKey) HasAnyScope(scopes ...Scope) bool {
+	return slices.ContainsFunc(scopes, k.HasScope)
 }
 
 type APIKeyCreate struct {
-	Name string
-	Key  string
+	ExpiresAt time.Time
+
+	Name          string
+	Key           string
+	KeyID         string
+	Scopes        []Scope
+	SigningSecret []byte // Encrypted
+	RateLimit     int32
+}
+
+// APIKeyUsage records when and from where a key was last used.
+type APIKeyUsage struct {
+	UsedAt time.Time
+	IP     string
+	ID     int32
+}
+
+// APIKeyUsageTracker collects the usage of the keys off the request path.
+type APIKeyUsageTracker interface {
+	Track(usage *APIKeyUsage)
 }
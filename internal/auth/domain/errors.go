@@ -2,5 +2,15 @@ package domain
 
 import "errors"
 
-var ErrAPIKeyNotFound = errors.New("post not found")
+var ErrAPIKeyNotFound = errors.New("api key not found")
 var ErrAPIKeyInvalid = errors.New("invalid api key")
+var ErrAPIKeyRevoked = errors.New("api key has been revoked")
+var ErrAPIKeyExpired = errors.New("api key has expired")
+var ErrInvalidScope = errors.New("invalid api key scope")
+var ErrInvalidExpiry = errors.New("api key expiry must be in the future")
+var ErrInvalidGracePeriod = errors.New("api key grace period can't be negative")
+var ErrInvalidRateLimit = errors.New("api key rate limit can't be negative")
+var ErrSignatureInvalid = errors.New("invalid request signature")
+var ErrSignatureExpired = errors.New("request signature timestamp is outside the replay window")
+var ErrSignatureReplayed = errors.New("request signature nonce has already been used")
+var ErrSigningUnsupported = errors.New("api key can't sign requests")
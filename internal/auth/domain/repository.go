@@ -1,9 +1,22 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type APIKeyRepository interface {
 	CreateAPIKey(ctx context.Context, apiKey *APIKeyCreate) (*APIKey, error)
-	GetAPIKeyByValue(ctx context.Context, apiKey string) (*APIKey, error)
+	GetAPIKey(ctx context.Context, publicID string) (*APIKey, error)
+	GetAPIKeys(ctx context.Context) ([]*APIKey, error)
+	GetAPIKeyByKeyID(ctx context.Context, keyID string) (*APIKey, error)
 	RevokeAPIKey(ctx context.Context, publicID string) error
+	// RotateAPIKey makes the key expire at graceUntil, unless it expires earlier, and creates
+	// its replacement in the same transaction.
+	RotateAPIKey(ctx context.Context, publicID string, graceUntil time.Time, apiKey *APIKeyCreate) (*APIKey, error)
+	UpdateAPIKeysUsage(ctx context.Context, usages []*APIKeyUsage) error
+	// ClaimNonce stores the nonce of a signed request until expiresAt and reports whether it
+	// was unused, or its previous use has expired. Nonces are shared by all the instances.
+	ClaimNonce(ctx context.Context, keyID, nonce string, now, expiresAt time.Time) (bool, error)
+	DeleteExpiredNonces(ctx context.Context, now time.Time) error
 }
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"slices"
+)
+
+// Scope is a permission granted to an API key.
+type Scope string
+
+const (
+	// ScopePostsRead allows reading the posts that are not published, and the revisions of all
+	// of them. Published posts are public.
+	ScopePostsRead     Scope = "posts:read"
+	ScopePostsWrite    Scope = "posts:write"
+	ScopeProjectsWrite Scope = "projects:write"
+	ScopeChikitosWrite Scope = "chikitos:write"
+	ScopeKeysAdmin     Scope = "keys:admin"
+)
+
+var Scopes = []Scope{ScopePostsRead, ScopePostsWrite, ScopeProjectsWrite, ScopeChikitosWrite, ScopeKeysAdmin}
+
+func (s Scope) Valid() bool {
+	return slices.Contains(Scopes, s)
+}
+
+// NormalizeScopes sorts and removes duplicated scopes. It returns ErrInvalidScope if any of
+// them is unknown.
+func NormalizeScopes(scopes []Scope) ([]Scope, error) {
+	normalized := []Scope{}
+
+	for _, scope := range scopes {
+		if !scope.Valid() {
+			return nil, ErrInvalidScope
+		}
+
+		if !slices.Contains(normalized, scope) {
+			normalized = append(normalized, scope)
+		}
+	}
+
+	slices.Sort(normalized)
+
+	return normalized, nil
+}
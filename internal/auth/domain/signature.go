@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureScheme is the scheme of the Authorization header of signed requests:
+//
+//	Authorization: GYB-HMAC key_id=<key id>, timestamp=<unix seconds>, nonce=<nonce>, signature=<hex>
+//
+// The signature is computed by SignedRequest.Sign with the signing secret of the key.
+const SignatureScheme = "GYB-HMAC"
+
+// SignatureReplayWindow is how far the timestamp of a signed request can be from the server
+// clock.
+const SignatureReplayWindow = 5 * time.Minute
+
+// SignatureNonceTTL is how long the nonces are remembered, which covers the whole window.
+const SignatureNonceTTL = 2 * SignatureReplayWindow
+
+const (
+	SignatureMaxBodySize    = 1 << 20 // In bytes
+	SignatureMaxNonceLength = 64
+)
+
+// SignedRequest holds the parts of a request covered by its signature.
+type SignedRequest struct {
+	Timestamp time.Time
+	KeyID     string
+	Nonce     string
+	Method    string
+	Path      string    // Path and query of the request
+	Body      io.Reader // Only read once the key of the request is known
+	BodyHash  []byte    // SHA-256 of the body, set by HashBody
+	Signature []byte
+}
+
+// HashBody reads the body of the request and sets its hash.
+func (r *SignedRequest) HashBody() error {
+	hash := sha256.New()
+	if _, err := io.Copy(hash, r.Body); err != nil {
+		return err
+	}
+
+	r.BodyHash = hash.Sum(nil)
+
+	return nil
+}
+
+// StringToSign returns the canonical form of the request, one part per line: method, path,
+// timestamp, nonce and the hex encoded body hash.
+func (r SignedRequest) StringToSign() string {
+	return strings.Join([]string{
+		strings.ToUpper(r.Method),
+		r.Path,
+		strconv.FormatInt(r.Timestamp.Unix(), 10),
+		r.Nonce,
+		hex.EncodeToString(r.BodyHash),
+	}, "\n")
+}
+
+// Sign returns the HMAC-SHA256 of the request with the given secret.
+func (r SignedRequest) Sign(secret []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(r.StringToSign()))
+
+	return mac.Sum(nil)
+}
+
+// SecretCipher encrypts the signing secrets of the keys at rest.
+type SecretCipher interface {
+	Encrypt(plaintext []byte) ([]byte, error)
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
@@ -1,9 +1,26 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type APIKeyUsecase interface {
-	CreateAPIKey(ctx context.Context, name string) (*APIKey, error)
+	// CreateAPIKey creates a key with the given scopes. A zero expiresAt creates a key that
+	// never expires, and a zero rateLimit keeps the limits of the route groups.
+	CreateAPIKey(ctx context.Context, name string, scopes []Scope, expiresAt time.Time, rateLimit int32) (*APIKey, error)
+	GetAPIKey(ctx context.Context, publicID string) (*APIKey, error)
+	GetAPIKeys(ctx context.Context) ([]*APIKey, error)
 	RevokeAPIKey(ctx context.Context, publicID string) error
-	ValidateAPIKey(ctx context.Context, key string) (bool, error)
+	// RotateAPIKey issues a replacement with the same name and scopes. The old key keeps
+	// working for gracePeriod so clients can switch without downtime.
+	RotateAPIKey(ctx context.Context, publicID string, gracePeriod time.Duration, expiresAt time.Time) (*APIKey, error)
+	// ValidateAPIKey returns the stored key matching the given value and records its usage
+	// from ip. It fails with ErrAPIKeyInvalid, ErrAPIKeyRevoked or ErrAPIKeyExpired when the
+	// key can't be used.
+	ValidateAPIKey(ctx context.Context, key, ip string) (*APIKey, error)
+	// ValidateSignature returns the key that signed the request and records its usage from ip.
+	// Besides the errors of ValidateAPIKey, it fails with ErrSignatureInvalid, ErrSignatureExpired,
+	// ErrSignatureReplayed or ErrSigningUnsupported.
+	ValidateSignature(ctx context.Context, request *SignedRequest, ip string) (*APIKey, error)
 }
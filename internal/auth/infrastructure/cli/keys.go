@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode/utf8"
+
+	"github.com/yavurb/goyurback/internal/auth/domain"
+)
+
+const keysUsage = `Usage: goyurback keys <command> [flags]
+
+Commands:
+  create --name <name> --scopes <scope,...> [--expires-in <duration>] [--rate-limit <requests per minute>]
+  list
+  revoke <key id>
+
+Scopes: posts:read, posts:write, projects:write, chikitos:write, keys:admin`
+
+var ErrUsage = errors.New(keysUsage)
+
+// RunKeysCommand manages the API keys from the command line, which is the only way to create
+// the first key of an environment.
+func RunKeysCommand(ctx context.Context, apiKeyUsecase domain.APIKeyUsecase, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return ErrUsage
+	}
+
+	switch args[0] {
+	case "create":
+		return createKey(ctx, apiKeyUsecase, args[1:], out)
+	case "list":
+		return listKeys(ctx, apiKeyUsecase, out)
+	case "revoke":
+		return revokeKey(ctx, apiKeyUsecase, args[1:], out)
+	default:
+		return ErrUsage
+	}
+}
+
+func createKey(ctx context.Context, apiKeyUsecase domain.APIKeyUsecase, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("keys create", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	name := fs.String("name", "", "name of the key")
+	scopes := fs.String("scopes", "", "comma separated scopes of the key")
+	expiresIn := fs.Duration("expires-in", 0, "how long the key is valid for, it never expires by default")
+	rateLimit := fs.Int("rate-limit", 0, "requests per minute allowed to the key, it uses the limits of the route groups by default")
+
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w\n\n%s", err, keysUsage)
+	}
+
+	if *name == "" || *scopes == "" {
+		return fmt.Errorf("--name and --scopes are required\n\n%s", keysUsage)
+	}
+
+	// Same limits as the name of the keys created through the API
+	if length := utf8.RuneCountInString(*name); length < 5 || length > 64 {
+		return fmt.Errorf("--name must be between 5 and 64 characters")
+	}
+
+	if *expiresIn < 0 {
+		return fmt.Errorf("--expires-in can't be negative")
+	}
+
+	if *rateLimit < 0 || *rateLimit > math.MaxInt32 {
+		return fmt.Errorf("--rate-limit must be between 0 and %d", math.MaxInt32)
+	}
+
+	var expiresAt time.Time
+	if *expiresIn > 0 {
+		expiresAt = time.Now().UTC().Add(*expiresIn)
+	}
+
+	apiKey, err := apiKeyUsecase.CreateAPIKey(ctx, *name, toScopes(*scopes), expiresAt, int32(*rateLimit))
+	if err != nil {
+		return fmt.Errorf("error creating the key: %w", err)
+	}
+
+	fmt.Fprintf(out, "Created key %s (%s)\n", apiKey.PublicID, apiKey.Name)
+	fmt.Fprintf(out, "Scopes:  %s\n", formatScopes(apiKey.Scopes))
+	fmt.Fprintf(out, "Expires: %s\n", formatTime(apiKey.ExpiresAt, "never"))
+	fmt.Fprintf(out, "Limit:   %s\n", formatRateLimit(apiKey.RateLimit))
+	fmt.Fprintf(out, "Key:     %s\n", apiKey.Key)
+
+	if apiKey.Secret != "" {
+		fmt.Fprintf(out, "Secret:  %s\n", apiKey.Secret)
+	}
+
+	fmt.Fprintln(out, "\nStore the key and its signing secret now, they can't be shown again.")
+
+	return nil
+}
+
+func listKeys(ctx context.Context, apiKeyUsecase domain.APIKeyUsecase, out io.Writer) error {
+	apiKeys, err := apiKeyUsecase.GetAPIKeys(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing the keys: %w", err)
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "ID\tNAME\tSCOPES\tCREATED\tEXPIRES\tLAST USED\tREVOKED")
+
+	for _, apiKey := range apiKeys {
+		fmt.Fprintf(
+			w,
+			"%s\t%s\t%s\t%s\t%s\t%s\t%t\n",
+			apiKey.PublicID,
+			apiKey.Name,
+			formatScopes(apiKey.Scopes),
+			formatTime(apiKey.CreatedAt, "-"),
+			formatTime(apiKey.ExpiresAt, "never"),
+			formatTime(apiKey.LastUsedAt, "never"),
+			apiKey.Revoked,
+		)
+	}
+
+	return w.Flush()
+}
+
+func revokeKey(ctx context.Context, apiKeyUsecase domain.APIKeyUsecase, args []string, out io.Writer) error {
+	if len(args) != 1 {
+		return fmt.Errorf("revoke takes the id of the key\n\n%s", keysUsage)
+	}
+
+	if err := apiKeyUsecase.RevokeAPIKey(ctx, args[0]); err != nil {
+		return fmt.Errorf("error revoking the key: %w", err)
+	}
+
+	fmt.Fprintf(out, "Revoked key %s\n", args[0])
+
+	return nil
+}
+
+func toScopes(scopesIn string) []domain.Scope {
+	scopes := []domain.Scope{}
+
+	for _, scope := range strings.Split(scopesIn, ",") {
+		scopes = append(scopes, domain.Scope(strings.TrimSpace(scope)))
+	}
+
+	return scopes
+}
+
+func formatScopes(scopes []domain.Scope) string {
+	scopesOut := []string{}
+
+	for _, scope := range scopes {
+		scopesOut = append(scopesOut, string(scope))
+	}
+
+	return strings.Join(scopesOut, ",")
+}
+
+func formatTime(t time.Time, zero string) string {
+	if t.IsZero() {
+		return zero
+	}
+
+	return t.Format(time.RFC3339)
+}
+
+func formatRateLimit(rateLimit int32) string {
+	if rateLimit == 0 {
+		return "default"
+	}
+
+	return fmt.Sprintf("%d requests per minute", rateLimit)
+}
@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/yavurb/goyurback/internal/auth/domain"
+	"github.com/yavurb/goyurback/internal/auth/infrastructure/ui/mocks"
+)
+
+func TestRunKeysCommand(t *testing.T) {
+	createdAt := time.Date(2024, 7, 14, 10, 30, 0, 0, time.UTC)
+
+	t.Run("it should create a key and print it once", func(t *testing.T) {
+		var gotName string
+		var gotScopes []domain.Scope
+		var gotExpiresAt time.Time
+
+		uc := &mocks.MockAPIKeyUsecase{
+			CreateAPIKeyFn: func(ctx context.Context, name string, scopes []domain.Scope, expiresAt time.Time, rateLimit int32) (*domain.APIKey, error) {
+				gotName, gotScopes, gotExpiresAt = name, scopes, expiresAt
+
+				return &domain.APIKey{PublicID: "ak_1qjrblb8pm90", Name: name, Key: "sk_salt.key", Scopes: scopes, CreatedAt: createdAt}, nil
+			},
+		}
+
+		var out bytes.Buffer
+
+		args := []string{"create", "--name", "bootstrap", "--scopes", "keys:admin, posts:write", "--expires-in", "720h"}
+		if err := RunKeysCommand(context.Background(), uc, args, &out); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if gotName != "bootstrap" {
+			t.Errorf("Expected the key to be named bootstrap, got %s", gotName)
+		}
+
+		if diff := cmp.Diff([]domain.Scope{domain.ScopeKeysAdmin, domain.ScopePostsWrite}, gotScopes); diff != "" {
+			t.Errorf("CreateAPIKey() scopes mismatch (-want +got):\n%s", diff)
+		}
+
+		if until := time.Until(gotExpiresAt); until < 719*time.Hour || until > 720*time.Hour || gotExpiresAt.Location() != time.UTC {
+			t.Errorf("Expected the key to expire in 720h, got %v", gotExpiresAt)
+		}
+
+		if !strings.Contains(out.String(), "Key:     sk_salt.key\n") {
+			t.Errorf("Expected the key to be printed, got:\n%s", out.String())
+		}
+	})
+
+	t.Run("it should require a name and scopes", func(t *testing.T) {
+		uc := &mocks.MockAPIKeyUsecase{}
+
+		for _, args := range [][]string{{"create"}, {"create", "--name", "bootstrap"}, {"create", "--unknown"}} {
+			if err := RunKeysCommand(context.Background(), uc, args, &bytes.Buffer{}); err == nil {
+				t.Errorf("Expected %v to fail", args)
+			}
+		}
+	})
+
+	t.Run("it should reject names the API rejects", func(t *testing.T) {
+		uc := &mocks.MockAPIKeyUsecase{}
+
+		for _, name := range []string{"ci", strings.Repeat("n", 65)} {
+			args := []string{"create", "--name", name, "--scopes", "posts:write"}
+			if err := RunKeysCommand(context.Background(), uc, args, &bytes.Buffer{}); err == nil {
+				t.Errorf("Expected the name %q to be rejected", name)
+			}
+		}
+	})
+
+	t.Run("it should list the keys", func(t *testing.T) {
+		uc := &mocks.MockAPIKeyUsecase{
+			GetAPIKeysFn: func(ctx context.Context) ([]*domain.APIKey, error) {
+				return []*domain.APIKey{
+					{PublicID: "ak_1qjrblb8pm90", Name: "bootstrap", Scopes: []domain.Scope{domain.ScopeKeysAdmin}, CreatedAt: createdAt, LastUsedAt: createdAt},
+				}, nil
+			},
+		}
+
+		var out bytes.Buffer
+
+		if err := RunKeysCommand(context.Background(), uc, []string{"list"}, &out); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		want := "ID               NAME       SCOPES      CREATED               EXPIRES  LAST USED             REVOKED\n" +
+			"ak_1qjrblb8pm90  bootstrap  keys:admin  2024-07-14T10:30:00Z  never    2024-07-14T10:30:00Z  false\n"
+		if diff := cmp.Diff(want, out.String()); diff != "" {
+			t.Errorf("list mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("it should revoke a key", func(t *testing.T) {
+		uc := &mocks.MockAPIKeyUsecase{
+			RevokeAPIKeyFn: func(ctx context.Context, publicID string) error {
+				if publicID != "ak_1qjrblb8pm90" {
+					return domain.ErrAPIKeyNotFound
+				}
+
+				return nil
+			},
+		}
+
+		if err := RunKeysCommand(context.Background(), uc, []string{"revoke", "ak_1qjrblb8pm90"}, &bytes.Buffer{}); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+
+		err := RunKeysCommand(context.Background(), uc, []string{"revoke", "ak_unknown"}, &bytes.Buffer{})
+		if !errors.Is(err, domain.ErrAPIKeyNotFound) {
+			t.Errorf("Expected error to be %v, got %v", domain.ErrAPIKeyNotFound, err)
+		}
+	})
+
+	t.Run("it should print the usage for unknown commands", func(t *testing.T) {
+		for _, args := range [][]string{{}, {"rotate"}} {
+			err := RunKeysCommand(context.Background(), &mocks.MockAPIKeyUsecase{}, args, &bytes.Buffer{})
+			if !errors.Is(err, ErrUsage) {
+				t.Errorf("Expected %v to print the usage, got %v", args, err)
+			}
+		}
+	})
+}
@@ -1,8 +1,35 @@
 -- name: CreateAPIKey :one
-INSERT INTO apikeys (public_id, name, key) VALUES ($1, $2, $3) RETURNING *;
+INSERT INTO apikeys (public_id, name, key, key_id, scopes, expires_at, signing_secret, rate_limit) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING *;
 
--- name: RevokeAPIKey :exec
-UPDATE apikeys SET revoked_at = now(), updated_at = now(), revoked = true WHERE public_id = $1 RETURNING *;
+-- name: RevokeAPIKey :one
+UPDATE apikeys SET revoked_at = COALESCE(revoked_at, now()), updated_at = now(), revoked = true WHERE public_id = $1 RETURNING *;
 
--- name: GetAPIKeyByValue :one
-SELECT * from apikeys WHERE key = $1;
+-- name: ExpireAPIKey :one
+UPDATE apikeys SET expires_at = LEAST(COALESCE(expires_at, @expires_at::TIMESTAMP), @expires_at::TIMESTAMP), updated_at = now() WHERE public_id = @public_id RETURNING *;
+
+-- name: UpdateAPIKeysUsage :exec
+UPDATE apikeys SET last_used_at = usage.used_at, last_used_ip = NULLIF(usage.ip, '')
+FROM (
+  SELECT unnest(@ids::INTEGER[]) AS id, unnest(@used_at::TIMESTAMP[]) AS used_at, unnest(@ips::VARCHAR[]) AS ip
+) AS usage
+WHERE apikeys.id = usage.id AND (apikeys.last_used_at IS NULL OR apikeys.last_used_at < usage.used_at);
+
+-- name: GetAPIKeyByKeyID :one
+SELECT * FROM apikeys WHERE key_id = $1;
+
+-- name: GetAPIKey :one
+SELECT * FROM apikeys WHERE public_id = $1;
+
+-- name: GetAPIKeyForUpdate :one
+SELECT * FROM apikeys WHERE public_id = $1 FOR UPDATE;
+
+-- name: GetAPIKeys :many
+SELECT * FROM apikeys ORDER BY created_at DESC, id DESC;
+
+-- name: ClaimSignatureNonce :execrows
+INSERT INTO signature_nonces (key_id, nonce, expires_at) VALUES (@key_id, @nonce, @expires_at)
+ON CONFLICT (key_id, nonce) DO UPDATE SET expires_at = EXCLUDED.expires_at
+WHERE signature_nonces.expires_at <= @now::TIMESTAMP;
+
+-- name: DeleteExpiredSignatureNonces :exec
+DELETE FROM signature_nonces WHERE expires_at <= $1;
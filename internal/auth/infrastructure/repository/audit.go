@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	auditDomain "github.com/yavurb/goyurback/internal/audit/domain"
+	auditRepository "github.com/yavurb/goyurback/internal/audit/infrastructure/repository"
+	"github.com/yavurb/goyurback/internal/database/postgres"
+)
+
+// apiKeySnapshot is the state of an API key recorded in the audit log. The hashed key and its
+// id are left out.
+type apiKeySnapshot struct {
+	RevokedAt *time.Time `json:"revoked_at"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	RateLimit *int32     `json:"rate_limit"`
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	Revoked   bool       `json:"revoked"`
+}
+
+func newAPIKeySnapshot(apiKey *postgres.Apikey) *apiKeySnapshot {
+	snapshot := &apiKeySnapshot{
+		RevokedAt: timestampOrNil(apiKey.RevokedAt),
+		ExpiresAt: timestampOrNil(apiKey.ExpiresAt),
+		Name:      apiKey.Name,
+		Scopes:    apiKey.Scopes,
+		Revoked:   apiKey.Revoked,
+	}
+
+	if apiKey.RateLimit.Valid {
+		snapshot.RateLimit = &apiKey.RateLimit.Int32
+	}
+
+	return snapshot
+}
+
+func recordChange(ctx context.Context, db *postgres.Queries, action auditDomain.Action, publicID string, before, after any) error {
+	return auditRepository.Record(ctx, db, &auditDomain.Change{
+		Before:     before,
+		After:      after,
+		EntityID:   publicID,
+		Action:     action,
+		EntityType: auditDomain.EntityAPIKeys,
+	})
+}
+
+func timestampOrNil(timestamp pgtype.Timestamp) *time.Time {
+	if !timestamp.Valid {
+		return nil
+	}
+
+	return &timestamp.Time
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"errors"
 	"log"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
+	auditDomain "github.com/yavurb/goyurback/internal/audit/domain"
 	"github.com/yavurb/goyurback/internal/auth/domain"
 	"github.com/yavurb/goyurback/internal/database/postgres"
 	"github.com/yavurb/goyurback/internal/pgk/ids"
@@ -15,80 +18,299 @@ import (
 const prefix = "ak"
 
 type Repository struct {
-	db *postgres.Queries
+	db       *postgres.Queries
+	connpool *pgxpool.Pool
 }
 
 func NewAPIKeyRepo(connpool *pgxpool.Pool) domain.APIKeyRepository {
 	db := postgres.New(connpool)
 
 	return &Repository{
-		db: db,
+		db:       db,
+		connpool: connpool,
 	}
 }
 
 func (r *Repository) CreateAPIKey(ctx context.Context, apiKey *domain.APIKeyCreate) (*domain.APIKey, error) {
-	id, err := ids.NewPublicID(prefix) // TODO: handle errors and validate if the id already exists
+	tx, err := r.connpool.Begin(ctx)
+	if err != nil {
+		log.Printf("DB Error starting transaction: %v\n", err)
+
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	apiKeyCreated, err := createAPIKey(ctx, r.db.WithTx(tx), apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Printf("DB Error committing APIKey creation: %v\n", err)
+
+		return nil, err
+	}
+
+	return apiKeyCreated, nil
+}
+
+func (r *Repository) RotateAPIKey(ctx context.Context, publicID string, graceUntil time.Time, apiKey *domain.APIKeyCreate) (*domain.APIKey, error) {
+	tx, err := r.connpool.Begin(ctx)
+	if err != nil {
+		log.Printf("DB Error starting transaction: %v\n", err)
+
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := r.db.WithTx(tx)
+
+	current, err := getAPIKeyForUpdate(ctx, qtx, publicID)
 	if err != nil {
 		return nil, err
 	}
 
-	apiKey_, err := r.db.CreateAPIKey(ctx, postgres.CreateAPIKeyParams{
-		PublicID: id,
-		Key:      apiKey.Key,
-		Name:     apiKey.Name,
+	expired, err := qtx.ExpireAPIKey(ctx, postgres.ExpireAPIKeyParams{
+		ExpiresAt: pgtype.Timestamp{Time: graceUntil, Valid: true},
+		PublicID:  publicID,
 	})
-	// TODO: print log
 	if err != nil {
+		log.Printf("DB Error expiring rotated key: %v\n", err)
+
 		return nil, err
 	}
 
-	newApiKey := &domain.APIKey{
-		ID:        apiKey_.ID,
-		PublicID:  apiKey_.PublicID,
-		Name:      apiKey_.Name,
-		Key:       apiKey_.Key,
-		Revoked:   apiKey_.Revoked,
-		CreatedAt: apiKey_.CreatedAt.Time,
-		UpdatedAt: apiKey_.UpdatedAt.Time,
-		RevokedAt: apiKey_.RevokedAt.Time,
+	if err := recordChange(ctx, qtx, auditDomain.ActionUpdate, publicID, newAPIKeySnapshot(&current), newAPIKeySnapshot(&expired)); err != nil {
+		return nil, err
 	}
 
-	return newApiKey, nil
+	rotatedKey, err := createAPIKey(ctx, qtx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Printf("DB Error committing key rotation: %v\n", err)
+
+		return nil, err
+	}
+
+	return rotatedKey, nil
 }
 
-func (r *Repository) GetAPIKeyByValue(ctx context.Context, key string) (*domain.APIKey, error) {
-	apiKey_, err := r.db.GetAPIKeyByValue(ctx, key)
+func (r *Repository) UpdateAPIKeysUsage(ctx context.Context, usages []*domain.APIKeyUsage) error {
+	params := postgres.UpdateAPIKeysUsageParams{}
+
+	for _, usage := range usages {
+		params.Ids = append(params.Ids, usage.ID)
+		params.UsedAt = append(params.UsedAt, pgtype.Timestamp{Time: usage.UsedAt, Valid: true})
+		params.Ips = append(params.Ips, usage.IP)
+	}
+
+	if err := r.db.UpdateAPIKeysUsage(ctx, params); err != nil {
+		log.Printf("DB Error updating APIKeys usage: %v\n", err)
+
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) ClaimNonce(ctx context.Context, keyID, nonce string, now, expiresAt time.Time) (bool, error) {
+	claimed, err := r.db.ClaimSignatureNonce(ctx, postgres.ClaimSignatureNonceParams{
+		KeyID:     keyID,
+		Nonce:     nonce,
+		ExpiresAt: pgtype.Timestamp{Time: expiresAt, Valid: true},
+		Now:       pgtype.Timestamp{Time: now, Valid: true},
+	})
 	if err != nil {
-		log.Printf("Error getting APIKey. Got: %v", err)
+		log.Printf("DB Error claiming signature nonce: %v\n", err)
+
+		return false, err
+	}
+
+	return claimed == 1, nil
+}
+
+func (r *Repository) DeleteExpiredNonces(ctx context.Context, now time.Time) error {
+	if err := r.db.DeleteExpiredSignatureNonces(ctx, pgtype.Timestamp{Time: now, Valid: true}); err != nil {
+		log.Printf("DB Error deleting expired signature nonces: %v\n", err)
+
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) GetAPIKey(ctx context.Context, publicID string) (*domain.APIKey, error) {
+	apiKey_, err := r.db.GetAPIKey(ctx, publicID)
+	if err != nil {
+		log.Printf("DB Error getting APIKey: %v\n", err)
 
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrAPIKeyNotFound
 		}
 
-		log.Panicf("DB Error obtaining APIKey: %v", err)
+		return nil, err
+	}
+
+	return toDomainAPIKey(apiKey_), nil
+}
+
+func (r *Repository) GetAPIKeys(ctx context.Context) ([]*domain.APIKey, error) {
+	apiKeys_, err := r.db.GetAPIKeys(ctx)
+	if err != nil {
+		log.Printf("DB Error getting APIKeys: %v\n", err)
+
+		return nil, err
 	}
 
-	apiKey := &domain.APIKey{
-		ID:        apiKey_.ID,
-		PublicID:  apiKey_.PublicID,
-		Name:      apiKey_.Name,
-		Key:       apiKey_.Key,
-		Revoked:   apiKey_.Revoked,
-		CreatedAt: apiKey_.CreatedAt.Time,
-		UpdatedAt: apiKey_.UpdatedAt.Time,
-		RevokedAt: apiKey_.RevokedAt.Time,
+	apiKeys := []*domain.APIKey{}
+
+	for _, apiKey_ := range apiKeys_ {
+		apiKeys = append(apiKeys, toDomainAPIKey(apiKey_))
 	}
 
-	return apiKey, nil
+	return apiKeys, nil
+}
+
+func (r *Repository) GetAPIKeyByKeyID(ctx context.Context, keyID string) (*domain.APIKey, error) {
+	apiKey_, err := r.db.GetAPIKeyByKeyID(ctx, keyID)
+	if err != nil {
+		log.Printf("Error getting APIKey. Got: %v", err)
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrAPIKeyNotFound
+		}
+
+		return nil, err
+	}
+
+	return toDomainAPIKey(apiKey_), nil
 }
 
 func (r *Repository) RevokeAPIKey(ctx context.Context, publicID string) error {
-	err := r.db.RevokeAPIKey(ctx, publicID)
+	tx, err := r.connpool.Begin(ctx)
+	if err != nil {
+		log.Printf("DB Error starting transaction: %v\n", err)
+
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := r.db.WithTx(tx)
+
+	current, err := getAPIKeyForUpdate(ctx, qtx, publicID)
+	if err != nil {
+		return err
+	}
+
+	revoked, err := qtx.RevokeAPIKey(ctx, publicID)
 	if err != nil {
 		log.Printf("DB Error revoking key: %v\n", err)
 
 		return err
 	}
 
+	if err := recordChange(ctx, qtx, auditDomain.ActionUpdate, publicID, newAPIKeySnapshot(&current), newAPIKeySnapshot(&revoked)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Printf("DB Error committing key revocation: %v\n", err)
+
+		return err
+	}
+
 	return nil
 }
+
+func getAPIKeyForUpdate(ctx context.Context, db *postgres.Queries, publicID string) (postgres.Apikey, error) {
+	apiKey_, err := db.GetAPIKeyForUpdate(ctx, publicID)
+	if err != nil {
+		log.Printf("DB Error getting APIKey to update: %v\n", err)
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apiKey_, domain.ErrAPIKeyNotFound
+		}
+
+		return apiKey_, err
+	}
+
+	return apiKey_, nil
+}
+
+func createAPIKey(ctx context.Context, db *postgres.Queries, apiKey *domain.APIKeyCreate) (*domain.APIKey, error) {
+	id, err := ids.NewPublicID(prefix) // TODO: handle errors and validate if the id already exists
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey_, err := db.CreateAPIKey(ctx, postgres.CreateAPIKeyParams{
+		PublicID:      id,
+		Key:           apiKey.Key,
+		KeyID:         apiKey.KeyID,
+		Name:          apiKey.Name,
+		Scopes:        fromDomainScopes(apiKey.Scopes),
+		SigningSecret: apiKey.SigningSecret,
+		RateLimit: pgtype.Int4{
+			Valid: apiKey.RateLimit != 0,
+			Int32: apiKey.RateLimit,
+		},
+		ExpiresAt: pgtype.Timestamp{
+			Valid: !apiKey.ExpiresAt.IsZero(),
+			Time:  apiKey.ExpiresAt,
+		},
+	})
+	if err != nil {
+		log.Printf("DB Error creating APIKey: %v\n", err)
+
+		return nil, err
+	}
+
+	if err := recordChange(ctx, db, auditDomain.ActionCreate, apiKey_.PublicID, nil, newAPIKeySnapshot(&apiKey_)); err != nil {
+		return nil, err
+	}
+
+	return toDomainAPIKey(apiKey_), nil
+}
+
+func toDomainAPIKey(apiKey_ postgres.Apikey) *domain.APIKey {
+	return &domain.APIKey{
+		ID:            apiKey_.ID,
+		PublicID:      apiKey_.PublicID,
+		Name:          apiKey_.Name,
+		Key:           apiKey_.Key,
+		KeyID:         apiKey_.KeyID,
+		Revoked:       apiKey_.Revoked,
+		CreatedAt:     apiKey_.CreatedAt.Time,
+		UpdatedAt:     apiKey_.UpdatedAt.Time,
+		RevokedAt:     apiKey_.RevokedAt.Time,
+		ExpiresAt:     apiKey_.ExpiresAt.Time,
+		LastUsedAt:    apiKey_.LastUsedAt.Time,
+		LastUsedIP:    apiKey_.LastUsedIp.String,
+		Scopes:        toDomainScopes(apiKey_.Scopes),
+		SigningSecret: apiKey_.SigningSecret,
+		RateLimit:     apiKey_.RateLimit.Int32,
+	}
+}
+
+func toDomainScopes(scopes_ []string) []domain.Scope {
+	scopes := []domain.Scope{}
+
+	for _, scope := range scopes_ {
+		scopes = append(scopes, domain.Scope(scope))
+	}
+
+	return scopes
+}
+
+func fromDomainScopes(scopes []domain.Scope) []string {
+	scopes_ := []string{}
+
+	for _, scope := range scopes {
+		scopes_ = append(scopes_, string(scope))
+	}
+
+	return scopes_
+}
@@ -0,0 +1,63 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+
+	"github.com/yavurb/goyurback/internal/auth/domain"
+)
+
+// KeySize is the size of the keys accepted by NewCipher, which selects AES-256.
+const KeySize = 32
+
+var ErrInvalidKey = errors.New("secrets key must be 32 bytes long")
+var ErrInvalidCiphertext = errors.New("invalid ciphertext")
+
+type aesCipher struct {
+	aead cipher.AEAD
+}
+
+// NewCipher returns a domain.SecretCipher that encrypts with AES-256-GCM. Each ciphertext
+// starts with the random nonce used to seal it.
+func NewCipher(key []byte) (domain.SecretCipher, error) {
+	if len(key) != KeySize {
+		return nil, ErrInvalidKey
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &aesCipher{aead}, nil
+}
+
+func (c *aesCipher) Encrypt(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return c.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func (c *aesCipher) Decrypt(ciphertext []byte) ([]byte, error) {
+	nonceSize := c.aead.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return nil, ErrInvalidCiphertext
+	}
+
+	plaintext, err := c.aead.Open(nil, ciphertext[:nonceSize], ciphertext[nonceSize:], nil)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+
+	return plaintext, nil
+}
@@ -0,0 +1,68 @@
+package secrets
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestCipher(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, KeySize)
+
+	t.Run("it should decrypt what it encrypts", func(t *testing.T) {
+		c, err := NewCipher(key)
+		if err != nil {
+			t.Fatalf("Expected no errors creating the cipher. Got: %v", err)
+		}
+
+		ciphertext, err := c.Encrypt([]byte("signing secret"))
+		if err != nil {
+			t.Fatalf("Expected no errors encrypting. Got: %v", err)
+		}
+
+		if bytes.Contains(ciphertext, []byte("signing secret")) {
+			t.Errorf("Expected the ciphertext not to contain the plaintext")
+		}
+
+		plaintext, err := c.Decrypt(ciphertext)
+		if err != nil {
+			t.Fatalf("Expected no errors decrypting. Got: %v", err)
+		}
+
+		if string(plaintext) != "signing secret" {
+			t.Errorf("Expected plaintext to be %q. Got: %q", "signing secret", plaintext)
+		}
+	})
+
+	t.Run("it should use a new nonce for every encryption", func(t *testing.T) {
+		c, _ := NewCipher(key)
+
+		first, _ := c.Encrypt([]byte("signing secret"))
+		second, _ := c.Encrypt([]byte("signing secret"))
+
+		if bytes.Equal(first, second) {
+			t.Errorf("Expected the ciphertexts to differ")
+		}
+	})
+
+	t.Run("it should reject a ciphertext sealed with another key", func(t *testing.T) {
+		c, _ := NewCipher(key)
+		other, _ := NewCipher(bytes.Repeat([]byte{8}, KeySize))
+
+		ciphertext, _ := other.Encrypt([]byte("signing secret"))
+
+		if _, err := c.Decrypt(ciphertext); !errors.Is(err, ErrInvalidCiphertext) {
+			t.Errorf("Expected error to be %v. Got: %v", ErrInvalidCiphertext, err)
+		}
+
+		if _, err := c.Decrypt([]byte("short")); !errors.Is(err, ErrInvalidCiphertext) {
+			t.Errorf("Expected error to be %v. Got: %v", ErrInvalidCiphertext, err)
+		}
+	})
+
+	t.Run("it should reject keys of the wrong size", func(t *testing.T) {
+		if _, err := NewCipher([]byte("short")); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Expected error to be %v. Got: %v", ErrInvalidKey, err)
+		}
+	})
+}
@@ -1,14 +1,135 @@
 package ui
 
-import "time"
+import (
+	"time"
+
+	"github.com/yavurb/goyurback/internal/auth/domain"
+)
 
 type APIKeyIn struct {
-	Name string `json:"name" validate:"required,min=5,max=64"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	Name      string     `json:"name" validate:"required,min=5,max=64"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1,dive,required"`
+	RateLimit int32      `json:"rate_limit" validate:"min=0"` // Requests per minute, zero for the default limits
 }
 
 type APIKeyOut struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Key       string    `json:"key"`
-	CreatedAt time.Time `json:"created_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	ExpiresAt     *time.Time `json:"expires_at"`
+	ID            string     `json:"id"`
+	Name          string     `json:"name"`
+	Key           string     `json:"key"`
+	SigningSecret string     `json:"signing_secret,omitempty"` // Left out when the server has no secrets key
+	RateLimit     *int32     `json:"rate_limit"`
+	Scopes        []string   `json:"scopes"`
+}
+
+// RotateAPIKeyIn configures the replacement of a key. GracePeriod is a duration like `1h30m`
+// and defaults to domain.DefaultRotationGracePeriod.
+type RotateAPIKeyIn struct {
+	ExpiresAt   *time.Time `json:"expires_at"`
+	PublicID    string     `param:"publicID" validate:"required"`
+	GracePeriod string     `json:"grace_period"`
+}
+
+type APIKeyParams struct {
+	PublicID string `param:"publicID" validate:"required"`
+}
+
+// APIKeyMetadataOut describes a key without exposing its value or hash.
+type APIKeyMetadataOut struct {
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	LastUsedIP *string    `json:"last_used_ip"`
+	RateLimit  *int32     `json:"rate_limit"`
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	Revoked    bool       `json:"revoked"`
+}
+
+type APIKeysOut struct {
+	Data []*APIKeyMetadataOut `json:"data"`
+}
+
+func toAPIKeyMetadataOut(apiKey *domain.APIKey) *APIKeyMetadataOut {
+	apiKeyOut := &APIKeyMetadataOut{
+		CreatedAt: apiKey.CreatedAt,
+		UpdatedAt: apiKey.UpdatedAt,
+		ID:        apiKey.PublicID,
+		Name:      apiKey.Name,
+		Scopes:    toScopesOut(apiKey.Scopes),
+		Revoked:   apiKey.Revoked,
+	}
+
+	if !apiKey.RevokedAt.IsZero() {
+		apiKeyOut.RevokedAt = &apiKey.RevokedAt
+	}
+
+	if !apiKey.ExpiresAt.IsZero() {
+		apiKeyOut.ExpiresAt = &apiKey.ExpiresAt
+	}
+
+	if !apiKey.LastUsedAt.IsZero() {
+		apiKeyOut.LastUsedAt = &apiKey.LastUsedAt
+		apiKeyOut.LastUsedIP = &apiKey.LastUsedIP
+	}
+
+	if apiKey.RateLimit != 0 {
+		apiKeyOut.RateLimit = &apiKey.RateLimit
+	}
+
+	return apiKeyOut
+}
+
+func toAPIKeyOut(apiKey *domain.APIKey) *APIKeyOut {
+	apiKeyOut := &APIKeyOut{
+		CreatedAt:     apiKey.CreatedAt,
+		ID:            apiKey.PublicID,
+		Name:          apiKey.Name,
+		Key:           apiKey.Key,
+		Scopes:        toScopesOut(apiKey.Scopes),
+		SigningSecret: apiKey.Secret,
+	}
+
+	if !apiKey.ExpiresAt.IsZero() {
+		apiKeyOut.ExpiresAt = &apiKey.ExpiresAt
+	}
+
+	if apiKey.RateLimit != 0 {
+		apiKeyOut.RateLimit = &apiKey.RateLimit
+	}
+
+	return apiKeyOut
+}
+
+func fromExpiresAtIn(expiresAt *time.Time) time.Time {
+	if expiresAt == nil {
+		return time.Time{}
+	}
+
+	return *expiresAt
+}
+
+func toScopesOut(scopes []domain.Scope) []string {
+	scopesOut := []string{}
+
+	for _, scope := range scopes {
+		scopesOut = append(scopesOut, string(scope))
+	}
+
+	return scopesOut
+}
+
+func fromScopesIn(scopesIn []string) []domain.Scope {
+	scopes := []domain.Scope{}
+
+	for _, scope := range scopesIn {
+		scopes = append(scopes, domain.Scope(scope))
+	}
+
+	return scopes
 }
@@ -35,6 +35,10 @@ func (e HTTPError) Conflict() error {
 	return echo.NewHTTPError(http.StatusConflict, e.Message)
 }
 
+func (e HTTPError) RequestEntityTooLarge() error {
+	return echo.NewHTTPError(http.StatusRequestEntityTooLarge, e.Message)
+}
+
 func (e HTTPError) ErrUnprocessableEntity() error {
 	err := echo.ErrUnprocessableEntity
 
@@ -0,0 +1,186 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/yavurb/goyurback/internal/auth/domain"
+)
+
+var errMalformedSignature = errors.New("malformed signature")
+
+// APIKeyContextKey is the key under which the authenticated API key is stored in the request context.
+const APIKeyContextKey = "apiKey"
+
+// KeyAuth identifies the API key sent in the x-api-key header. Requests without a key go
+// through anonymously, so every router decides which of its routes need one with RequireScope.
+// Requests with a bad key are rejected even on public routes so clients notice it.
+func KeyAuth(apiKeyUsecase domain.APIKeyUsecase) echo.MiddlewareFunc {
+	return middleware.KeyAuthWithConfig(middleware.KeyAuthConfig{
+		KeyLookup:              "header:x-api-key",
+		Validator:              KeyAuthValidator(apiKeyUsecase),
+		ErrorHandler:           KeyAuthErrorHandler,
+		ContinueOnIgnoredError: true,
+	})
+}
+
+// KeyAuthValidator validates the keys received by the KeyAuth middleware and stores the
+// authenticated key in the request context.
+func KeyAuthValidator(apiKeyUsecase domain.APIKeyUsecase) middleware.KeyAuthValidator {
+	return func(key string, c echo.Context) (bool, error) {
+		apiKey, err := apiKeyUsecase.ValidateAPIKey(c.Request().Context(), key, c.RealIP())
+		if err != nil {
+			return false, err
+		}
+
+		c.Set(APIKeyContextKey, apiKey)
+
+		return true, nil
+	}
+}
+
+// SignatureAuth identifies the API key of requests signed with a domain.SignatureScheme
+// Authorization header. It sits beside KeyAuth: requests without a signature go through, and
+// requests with a bad one are rejected.
+func SignatureAuth(apiKeyUsecase domain.APIKeyUsecase) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			scheme, params, ok := strings.Cut(c.Request().Header.Get(echo.HeaderAuthorization), " ")
+			if !ok || !strings.EqualFold(scheme, domain.SignatureScheme) {
+				return next(c)
+			}
+
+			req := c.Request()
+
+			request, err := parseSignedRequest(req, params)
+			if err != nil {
+				return HTTPError{Message: fmt.Sprintf("Malformed %s authorization header", domain.SignatureScheme)}.Unauthorized()
+			}
+
+			// The body is kept as it is hashed, so the handler can read it again
+			var body bytes.Buffer
+			request.Body = io.TeeReader(http.MaxBytesReader(c.Response(), req.Body, domain.SignatureMaxBodySize), &body)
+
+			apiKey, err := apiKeyUsecase.ValidateSignature(req.Context(), request, c.RealIP())
+			if err != nil {
+				return KeyAuthErrorHandler(err, c)
+			}
+
+			req.Body = io.NopCloser(&body)
+			c.Set(APIKeyContextKey, apiKey)
+
+			return next(c)
+		}
+	}
+}
+
+// parseSignedRequest reads the parameters of the Authorization header. The body is left to the
+// usecase, which only reads it once the key is known.
+func parseSignedRequest(req *http.Request, params string) (*domain.SignedRequest, error) {
+	values := map[string]string{}
+
+	for _, param := range strings.Split(params, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok {
+			return nil, errMalformedSignature
+		}
+
+		values[name] = value
+	}
+
+	timestamp, err := strconv.ParseInt(values["timestamp"], 10, 64)
+	if err != nil {
+		return nil, errMalformedSignature
+	}
+
+	signature, err := hex.DecodeString(values["signature"])
+	if err != nil || len(signature) == 0 || values["key_id"] == "" || values["nonce"] == "" ||
+		len(values["nonce"]) > domain.SignatureMaxNonceLength {
+		return nil, errMalformedSignature
+	}
+
+	return &domain.SignedRequest{
+		Timestamp: time.Unix(timestamp, 0),
+		KeyID:     values["key_id"],
+		Nonce:     values["nonce"],
+		Method:    req.Method,
+		Path:      req.URL.RequestURI(),
+		Signature: signature,
+	}, nil
+}
+
+// KeyAuthErrorHandler turns the errors of the KeyAuth and SignatureAuth middlewares into
+// responses that tell the client why it was rejected. A missing key is not an error, the
+// request is anonymous.
+func KeyAuthErrorHandler(err error, c echo.Context) error {
+	var (
+		missingErr  *middleware.ErrKeyAuthMissing
+		tooLargeErr *http.MaxBytesError
+	)
+
+	switch {
+	case errors.As(err, &missingErr):
+		return nil
+	case errors.As(err, &tooLargeErr):
+		return HTTPError{Message: fmt.Sprintf("Signed request bodies can't be larger than %d bytes", tooLargeErr.Limit)}.RequestEntityTooLarge()
+	case errors.Is(err, domain.ErrAPIKeyInvalid):
+		return HTTPError{Message: "Invalid API key"}.Unauthorized()
+	case errors.Is(err, domain.ErrAPIKeyRevoked):
+		return HTTPError{Message: "API key has been revoked"}.Unauthorized()
+	case errors.Is(err, domain.ErrAPIKeyExpired):
+		return HTTPError{Message: "API key has expired"}.Unauthorized()
+	case errors.Is(err, domain.ErrSignatureInvalid):
+		return HTTPError{Message: "Invalid request signature"}.Unauthorized()
+	case errors.Is(err, domain.ErrSignatureExpired):
+		return HTTPError{Message: "Request timestamp is outside the allowed window"}.Unauthorized()
+	case errors.Is(err, domain.ErrSignatureReplayed):
+		return HTTPError{Message: "Request nonce has already been used"}.Unauthorized()
+	case errors.Is(err, domain.ErrSigningUnsupported):
+		return HTTPError{Message: "API key can't sign requests"}.Unauthorized()
+	default:
+		log.Printf("Error validating API key. Got: %v\n", err)
+
+		return HTTPError{Message: "Internal server error"}.InternalServerError()
+	}
+}
+
+// APIKeyFromContext returns the API key that authenticated the request, if any.
+func APIKeyFromContext(c echo.Context) (*domain.APIKey, bool) {
+	apiKey, ok := c.Get(APIKeyContextKey).(*domain.APIKey)
+
+	return apiKey, ok
+}
+
+// RequireScope marks a route as private. It only lets through requests authenticated by
+// KeyAuth with a key that has any of the given scopes.
+func RequireScope(scopes ...domain.Scope) echo.MiddlewareFunc {
+	names := make([]string, len(scopes))
+	for i, scope := range scopes {
+		names[i] = string(scope)
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			apiKey, ok := APIKeyFromContext(c)
+			if !ok {
+				return HTTPError{Message: "Missing API key"}.Unauthorized()
+			}
+
+			if !apiKey.HasAnyScope(scopes...) {
+				return HTTPError{Message: fmt.Sprintf("API key is missing the %s scope", strings.Join(names, " or "))}.Forbidden()
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,339 @@
+package ui
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yavurb/goyurback/internal/auth/domain"
+	"github.com/yavurb/goyurback/internal/auth/infrastructure/ui/mocks"
+)
+
+func TestKeyAuth(t *testing.T) {
+	newServer := func(validateErr error) *echo.Echo {
+		e := echo.New()
+		uc := &mocks.MockAPIKeyUsecase{
+			ValidateAPIKeyFn: func(ctx context.Context, key, ip string) (*domain.APIKey, error) {
+				if validateErr != nil {
+					return nil, validateErr
+				}
+
+				return &domain.APIKey{PublicID: "ak_test", Scopes: []domain.Scope{domain.ScopePostsWrite}}, nil
+			},
+		}
+
+		e.Use(KeyAuth(uc))
+		e.GET("/public", func(c echo.Context) error { return c.NoContent(http.StatusNoContent) })
+		e.POST("/private", func(c echo.Context) error {
+			if apiKey, ok := APIKeyFromContext(c); !ok || apiKey.PublicID != "ak_test" {
+				t.Errorf("Expected the authenticated key to be stored in the context. Got: %v", apiKey)
+			}
+
+			return c.NoContent(http.StatusNoContent)
+		}, RequireScope(domain.ScopePostsWrite))
+
+		return e
+	}
+
+	cases := []struct {
+		name        string
+		method      string
+		path        string
+		key         string
+		validateErr error
+		wantCode    int
+		wantMessage string
+	}{
+		{name: "public route without key", method: http.MethodGet, path: "/public", wantCode: http.StatusNoContent},
+		{name: "public route with valid key", method: http.MethodGet, path: "/public", key: "sk_salt.key", wantCode: http.StatusNoContent},
+		{name: "public route with unknown key", method: http.MethodGet, path: "/public", key: "sk_salt.key", validateErr: domain.ErrAPIKeyInvalid, wantCode: http.StatusUnauthorized, wantMessage: "Invalid API key"},
+		{name: "private route with valid key", method: http.MethodPost, path: "/private", key: "sk_salt.key", wantCode: http.StatusNoContent},
+		{name: "private route without key", method: http.MethodPost, path: "/private", wantCode: http.StatusUnauthorized, wantMessage: "Missing API key"},
+		{name: "unknown key", method: http.MethodPost, path: "/private", key: "sk_salt.key", validateErr: domain.ErrAPIKeyInvalid, wantCode: http.StatusUnauthorized, wantMessage: "Invalid API key"},
+		{name: "revoked key", method: http.MethodPost, path: "/private", key: "sk_salt.key", validateErr: domain.ErrAPIKeyRevoked, wantCode: http.StatusUnauthorized, wantMessage: "API key has been revoked"},
+		{name: "expired key", method: http.MethodPost, path: "/private", key: "sk_salt.key", validateErr: domain.ErrAPIKeyExpired, wantCode: http.StatusUnauthorized, wantMessage: "API key has expired"},
+		{name: "db error", method: http.MethodPost, path: "/private", key: "sk_salt.key", validateErr: errors.New("DB error"), wantCode: http.StatusInternalServerError, wantMessage: "Internal server error"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			if tc.key != "" {
+				req.Header.Set("x-api-key", tc.key)
+			}
+
+			rec := httptest.NewRecorder()
+
+			newServer(tc.validateErr).ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantCode {
+				t.Errorf("Expected status code to be %d. Got: %d", tc.wantCode, rec.Code)
+			}
+
+			if tc.wantMessage == "" {
+				return
+			}
+
+			got := map[string]string{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("Error unmarshalling response: %s", err)
+			}
+
+			if got["message"] != tc.wantMessage {
+				t.Errorf("Expected message to be %q. Got: %q", tc.wantMessage, got["message"])
+			}
+		})
+	}
+}
+
+func TestSignatureAuth(t *testing.T) {
+	const secret = "signing-secret"
+
+	newServer := func(validateErr error) *echo.Echo {
+		e := echo.New()
+		uc := &mocks.MockAPIKeyUsecase{
+			ValidateSignatureFn: func(ctx context.Context, request *domain.SignedRequest, ip string) (*domain.APIKey, error) {
+				if validateErr != nil {
+					return nil, validateErr
+				}
+
+				if err := request.HashBody(); err != nil {
+					return nil, err
+				}
+
+				if !hmac.Equal(request.Signature, request.Sign([]byte(secret))) {
+					return nil, domain.ErrSignatureInvalid
+				}
+
+				return &domain.APIKey{PublicID: "ak_test", Scopes: []domain.Scope{domain.ScopePostsWrite}}, nil
+			},
+		}
+
+		e.Use(SignatureAuth(uc))
+		e.GET("/public", func(c echo.Context) error { return c.NoContent(http.StatusNoContent) })
+		e.POST("/private", func(c echo.Context) error {
+			body, _ := io.ReadAll(c.Request().Body)
+			if string(body) != `{"title":"Signed"}` {
+				t.Errorf("Expected the body to reach the handler. Got: %q", body)
+			}
+
+			return c.NoContent(http.StatusNoContent)
+		}, RequireScope(domain.ScopePostsWrite))
+
+		return e
+	}
+
+	sign := func(method, path, body string) string {
+		bodyHash := sha256.Sum256([]byte(body))
+		request := domain.SignedRequest{
+			Timestamp: time.Unix(1721000000, 0),
+			Nonce:     "nonce",
+			Method:    method,
+			Path:      path,
+			BodyHash:  bodyHash[:],
+		}
+
+		return fmt.Sprintf("GYB-HMAC key_id=testsalt, timestamp=1721000000, nonce=nonce, signature=%x", request.Sign([]byte(secret)))
+	}
+
+	cases := []struct {
+		name          string
+		method        string
+		path          string
+		authorization string
+		validateErr   error
+		wantCode      int
+		wantMessage   string
+	}{
+		{name: "public route without signature", method: http.MethodGet, path: "/public", wantCode: http.StatusNoContent},
+		{name: "other authorization scheme", method: http.MethodGet, path: "/public", authorization: "Bearer token", wantCode: http.StatusNoContent},
+		{name: "signed request", method: http.MethodPost, path: "/private?draft=true", authorization: sign(http.MethodPost, "/private?draft=true", `{"title":"Signed"}`), wantCode: http.StatusNoContent},
+		{name: "signature of another path", method: http.MethodPost, path: "/private", authorization: sign(http.MethodPost, "/public", `{"title":"Signed"}`), wantCode: http.StatusUnauthorized, wantMessage: "Invalid request signature"},
+		{name: "signature of another body", method: http.MethodPost, path: "/private", authorization: sign(http.MethodPost, "/private", `{}`), wantCode: http.StatusUnauthorized, wantMessage: "Invalid request signature"},
+		{name: "missing nonce", method: http.MethodPost, path: "/private", authorization: "GYB-HMAC key_id=testsalt, timestamp=1721000000, signature=00", wantCode: http.StatusUnauthorized, wantMessage: "Malformed GYB-HMAC authorization header"},
+		{name: "invalid signature encoding", method: http.MethodPost, path: "/private", authorization: "GYB-HMAC key_id=testsalt, timestamp=1721000000, nonce=nonce, signature=xyz", wantCode: http.StatusUnauthorized, wantMessage: "Malformed GYB-HMAC authorization header"},
+		{name: "long nonce", method: http.MethodPost, path: "/private", authorization: "GYB-HMAC key_id=testsalt, timestamp=1721000000, nonce=" + strings.Repeat("n", domain.SignatureMaxNonceLength+1) + ", signature=00", wantCode: http.StatusUnauthorized, wantMessage: "Malformed GYB-HMAC authorization header"},
+		{name: "replayed request", method: http.MethodPost, path: "/private", authorization: sign(http.MethodPost, "/private", `{"title":"Signed"}`), validateErr: domain.ErrSignatureReplayed, wantCode: http.StatusUnauthorized, wantMessage: "Request nonce has already been used"},
+		{name: "old request", method: http.MethodPost, path: "/private", authorization: sign(http.MethodPost, "/private", `{"title":"Signed"}`), validateErr: domain.ErrSignatureExpired, wantCode: http.StatusUnauthorized, wantMessage: "Request timestamp is outside the allowed window"},
+		{name: "key without secret", method: http.MethodPost, path: "/private", authorization: sign(http.MethodPost, "/private", `{"title":"Signed"}`), validateErr: domain.ErrSigningUnsupported, wantCode: http.StatusUnauthorized, wantMessage: "API key can't sign requests"},
+		{name: "revoked key", method: http.MethodPost, path: "/private", authorization: sign(http.MethodPost, "/private", `{"title":"Signed"}`), validateErr: domain.ErrAPIKeyRevoked, wantCode: http.StatusUnauthorized, wantMessage: "API key has been revoked"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(`{"title":"Signed"}`))
+			if tc.authorization != "" {
+				req.Header.Set(echo.HeaderAuthorization, tc.authorization)
+			}
+
+			rec := httptest.NewRecorder()
+
+			newServer(tc.validateErr).ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantCode {
+				t.Errorf("Expected status code to be %d. Got: %d", tc.wantCode, rec.Code)
+			}
+
+			if tc.wantMessage == "" {
+				return
+			}
+
+			got := map[string]string{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("Error unmarshalling response: %s", err)
+			}
+
+			if got["message"] != tc.wantMessage {
+				t.Errorf("Expected message to be %q. Got: %q", tc.wantMessage, got["message"])
+			}
+		})
+	}
+}
+
+func TestSignatureAuthBodyLimit(t *testing.T) {
+	e := echo.New()
+	uc := &mocks.MockAPIKeyUsecase{
+		ValidateSignatureFn: func(ctx context.Context, request *domain.SignedRequest, ip string) (*domain.APIKey, error) {
+			if err := request.HashBody(); err != nil {
+				return nil, err
+			}
+
+			return &domain.APIKey{PublicID: "ak_test"}, nil
+		},
+	}
+
+	e.Use(SignatureAuth(uc))
+	e.POST("/private", func(c echo.Context) error {
+		t.Error("Expected the request not to reach the handler")
+
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/private", strings.NewReader(strings.Repeat("a", domain.SignatureMaxBodySize+1)))
+	req.Header.Set(echo.HeaderAuthorization, "GYB-HMAC key_id=testsalt, timestamp=1721000000, nonce=nonce, signature=00")
+	rec := httptest.NewRecorder()
+
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected status code to be %d. Got: %d", http.StatusRequestEntityTooLarge, rec.Code)
+	}
+}
+
+func TestRequireScope(t *testing.T) {
+	cases := []struct {
+		name        string
+		apiKey      *domain.APIKey
+		wantCode    int
+		wantMessage string
+	}{
+		{
+			name:     "key with the scope",
+			apiKey:   &domain.APIKey{Scopes: []domain.Scope{domain.ScopePostsWrite, domain.ScopeProjectsWrite}},
+			wantCode: http.StatusNoContent,
+		},
+		{
+			name:        "key without the scope",
+			apiKey:      &domain.APIKey{Scopes: []domain.Scope{domain.ScopeProjectsWrite}},
+			wantCode:    http.StatusForbidden,
+			wantMessage: "API key is missing the posts:write scope",
+		},
+		{
+			name:        "missing key",
+			wantCode:    http.StatusUnauthorized,
+			wantMessage: "Missing API key",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			if tc.apiKey != nil {
+				c.Set(APIKeyContextKey, tc.apiKey)
+			}
+
+			handler := RequireScope(domain.ScopePostsWrite)(func(c echo.Context) error {
+				return c.NoContent(http.StatusNoContent)
+			})
+
+			err := handler(c)
+			if tc.wantMessage == "" {
+				if err != nil {
+					t.Fatalf("Expected no error. Got: %v", err)
+				}
+
+				if rec.Code != tc.wantCode {
+					t.Errorf("Expected status code to be %d. Got: %d", tc.wantCode, rec.Code)
+				}
+
+				return
+			}
+
+			var httpErr *echo.HTTPError
+			if !errors.As(err, &httpErr) {
+				t.Fatalf("Expected an HTTP error. Got: %v", err)
+			}
+
+			if httpErr.Code != tc.wantCode {
+				t.Errorf("Expected status code to be %d. Got: %d", tc.wantCode, httpErr.Code)
+			}
+
+			if got := httpErr.Message; got != tc.wantMessage {
+				t.Errorf("Expected message to be %q. Got: %v", tc.wantMessage, got)
+			}
+		})
+	}
+
+	t.Run("key with any of the scopes", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.Set(APIKeyContextKey, &domain.APIKey{Scopes: []domain.Scope{domain.ScopePostsWrite}})
+
+		handler := RequireScope(domain.ScopePostsRead, domain.ScopePostsWrite)(func(c echo.Context) error {
+			return c.NoContent(http.StatusNoContent)
+		})
+
+		if err := handler(c); err != nil {
+			t.Errorf("Expected the key to be let through. Got: %v", err)
+		}
+	})
+
+	t.Run("key without any of the scopes", func(t *testing.T) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.Set(APIKeyContextKey, &domain.APIKey{Scopes: []domain.Scope{domain.ScopeProjectsWrite}})
+
+		handler := RequireScope(domain.ScopePostsRead, domain.ScopePostsWrite)(func(c echo.Context) error {
+			return c.NoContent(http.StatusNoContent)
+		})
+
+		var httpErr *echo.HTTPError
+		if err := handler(c); !errors.As(err, &httpErr) || httpErr.Code != http.StatusForbidden {
+			t.Fatalf("Expected a 403 error. Got: %v", err)
+		}
+
+		if want := "API key is missing the posts:read or posts:write scope"; httpErr.Message != want {
+			t.Errorf("Expected message to be %q. Got: %q", want, httpErr.Message)
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/yavurb/goyurback/internal/auth/domain"
+)
+
+type MockAPIKeyUsecase struct {
+	CreateAPIKeyFn   func(ctx context.Context, name string, scopes []domain.Scope, expiresAt time.Time, rateLimit int32) (*domain.APIKey, error)
+	GetAPIKeyFn      func(ctx context.Context, publicID string) (*domain.APIKey, error)
+	GetAPIKeysFn     func(ctx context.Context) ([]*domain.APIKey, error)
+	RevokeAPIKeyFn   func(ctx context.Context, publicID string) error
+	RotateAPIKeyFn   func(ctx context.Context, publicID string, gracePeriod time.Duration, expiresAt time.Time) (*domain.APIKey, error)
+	ValidateAPIKeyFn func(ctx context.Context, key, ip string) (*domain.APIKey, error)
+
+	ValidateSignatureFn func(ctx context.Context, request *domain.SignedRequest, ip string) (*domain.APIKey, error)
+}
+
+func (m *MockAPIKeyUsecase) CreateAPIKey(ctx context.Context, name string, scopes []domain.Scope, expiresAt time.Time, rateLimit int32) (*domain.APIKey, error) {
+	return m.CreateAPIKeyFn(ctx, name, scopes, expiresAt, rateLimit)
+}
+
+func (m *MockAPIKeyUsecase) GetAPIKey(ctx context.Context, publicID string) (*domain.APIKey, error) {
+	return m.GetAPIKeyFn(ctx, publicID)
+}
+
+func (m *MockAPIKeyUsecase) GetAPIKeys(ctx context.Context) ([]*domain.APIKey, error) {
+	return m.GetAPIKeysFn(ctx)
+}
+
+func (m *MockAPIKeyUsecase) RevokeAPIKey(ctx context.Context, publicID string) error {
+	return m.RevokeAPIKeyFn(ctx, publicID)
+}
+
+func (m *MockAPIKeyUsecase) RotateAPIKey(ctx context.Context, publicID string, gracePeriod time.Duration, expiresAt time.Time) (*domain.APIKey, error) {
+	return m.RotateAPIKeyFn(ctx, publicID, gracePeriod, expiresAt)
+}
+
+func (m *MockAPIKeyUsecase) ValidateAPIKey(ctx context.Context, key, ip string) (*domain.APIKey, error) {
+	return m.ValidateAPIKeyFn(ctx, key, ip)
+}
+
+func (m *MockAPIKeyUsecase) ValidateSignature(ctx context.Context, request *domain.SignedRequest, ip string) (*domain.APIKey, error) {
+	return m.ValidateSignatureFn(ctx, request, ip)
+}
@@ -1,7 +1,9 @@
 package ui
 
 import (
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/yavurb/goyurback/internal/auth/domain"
@@ -11,14 +13,21 @@ type authRouterCtx struct {
 	apiKeyUsecase domain.APIKeyUsecase
 }
 
-func NewAuthRouter(e *echo.Echo, apiKeyUsecase domain.APIKeyUsecase) {
+func NewAuthRouter(e *echo.Echo, apiKeyUsecase domain.APIKeyUsecase) *authRouterCtx {
 	routerGroup := e.Group("/auth")
 	routerCtx := &authRouterCtx{
 		apiKeyUsecase,
 	}
 
-	routerGroup.POST("/keys", routerCtx.CreateAPIKey)
-	routerGroup.DELETE("/keys/:publicID", routerCtx.RevokeAPIKey)
+	isAdmin := RequireScope(domain.ScopeKeysAdmin)
+
+	routerGroup.POST("/keys", routerCtx.CreateAPIKey, isAdmin)
+	routerGroup.GET("/keys", routerCtx.GetAPIKeys, isAdmin)
+	routerGroup.GET("/keys/:publicID", routerCtx.GetAPIKey, isAdmin)
+	routerGroup.DELETE("/keys/:publicID", routerCtx.RevokeAPIKey, isAdmin)
+	routerGroup.POST("/keys/:publicID/rotate", routerCtx.RotateAPIKey, isAdmin)
+
+	return routerCtx
 }
 
 func (ctx *authRouterCtx) CreateAPIKey(c echo.Context) error {
@@ -28,23 +37,124 @@ func (ctx *authRouterCtx) CreateAPIKey(c echo.Context) error {
 		return HTTPError{Message: "Invalid request"}.ErrUnprocessableEntity() // TODO: Change to BadRequest()
 	}
 
-	// TODO: Validate the request body
+	if err := c.Validate(apikey); err != nil {
+		return HTTPError{Message: err.Error()}.ErrUnprocessableEntity()
+	}
 
-	apiKey, err := ctx.apiKeyUsecase.CreateAPIKey(c.Request().Context(), apikey.Name)
+	apiKey, err := ctx.apiKeyUsecase.CreateAPIKey(
+		c.Request().Context(),
+		apikey.Name,
+		fromScopesIn(apikey.Scopes),
+		fromExpiresAtIn(apikey.ExpiresAt),
+		apikey.RateLimit,
+	)
 	if err != nil {
+		if errors.Is(err, domain.ErrInvalidScope) || errors.Is(err, domain.ErrInvalidExpiry) || errors.Is(err, domain.ErrInvalidRateLimit) {
+			return HTTPError{Message: err.Error()}.ErrUnprocessableEntity()
+		}
+
 		return HTTPError{Message: "Failed to create API key"}.InternalServerError()
 	}
 
-	apikeyOut := &APIKeyOut{
-		ID:        apiKey.PublicID,
-		Name:      apiKey.Name,
-		Key:       apiKey.Key,
-		CreatedAt: apiKey.CreatedAt,
+	return c.JSON(http.StatusCreated, toAPIKeyOut(apiKey))
+}
+
+func (ctx *authRouterCtx) RotateAPIKey(c echo.Context) error {
+	var rotation RotateAPIKeyIn
+
+	if err := c.Bind(&rotation); err != nil {
+		return HTTPError{Message: "Invalid request body"}.BadRequest()
+	}
+
+	if err := c.Validate(rotation); err != nil {
+		return HTTPError{Message: "Invalid params"}.ErrUnprocessableEntity()
+	}
+
+	gracePeriod := domain.DefaultRotationGracePeriod
+
+	if rotation.GracePeriod != "" {
+		var err error
+
+		gracePeriod, err = time.ParseDuration(rotation.GracePeriod)
+		if err != nil {
+			return HTTPError{Message: "Invalid grace period. Use a duration like `1h` or `30m`"}.ErrUnprocessableEntity()
+		}
+	}
+
+	apiKey, err := ctx.apiKeyUsecase.RotateAPIKey(
+		c.Request().Context(),
+		rotation.PublicID,
+		gracePeriod,
+		fromExpiresAtIn(rotation.ExpiresAt),
+	)
+	if err != nil {
+		return handleErr(err)
+	}
+
+	return c.JSON(http.StatusCreated, toAPIKeyOut(apiKey))
+}
+
+func (ctx *authRouterCtx) GetAPIKeys(c echo.Context) error {
+	apiKeys, err := ctx.apiKeyUsecase.GetAPIKeys(c.Request().Context())
+	if err != nil {
+		return handleErr(err)
+	}
+
+	apiKeysOut := []*APIKeyMetadataOut{}
+
+	for _, apiKey := range apiKeys {
+		apiKeysOut = append(apiKeysOut, toAPIKeyMetadataOut(apiKey))
+	}
+
+	return c.JSON(http.StatusOK, &APIKeysOut{Data: apiKeysOut})
+}
+
+func (ctx *authRouterCtx) GetAPIKey(c echo.Context) error {
+	var params APIKeyParams
+
+	if err := c.Bind(&params); err != nil {
+		return HTTPError{Message: "Invalid params"}.BadRequest()
+	}
+
+	if err := c.Validate(params); err != nil {
+		return HTTPError{Message: "Invalid params"}.ErrUnprocessableEntity()
+	}
+
+	apiKey, err := ctx.apiKeyUsecase.GetAPIKey(c.Request().Context(), params.PublicID)
+	if err != nil {
+		return handleErr(err)
 	}
 
-	return c.JSON(http.StatusCreated, apikeyOut)
+	return c.JSON(http.StatusOK, toAPIKeyMetadataOut(apiKey))
 }
 
-func (r *authRouterCtx) RevokeAPIKey(c echo.Context) error {
-	return nil
+func (ctx *authRouterCtx) RevokeAPIKey(c echo.Context) error {
+	var params APIKeyParams
+
+	if err := c.Bind(&params); err != nil {
+		return HTTPError{Message: "Invalid params"}.BadRequest()
+	}
+
+	if err := c.Validate(params); err != nil {
+		return HTTPError{Message: "Invalid params"}.ErrUnprocessableEntity()
+	}
+
+	if err := ctx.apiKeyUsecase.RevokeAPIKey(c.Request().Context(), params.PublicID); err != nil {
+		return handleErr(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func handleErr(err error) error {
+	switch err {
+	case domain.ErrAPIKeyNotFound:
+		return HTTPError{Message: "API key not found"}.NotFound()
+	case domain.ErrAPIKeyRevoked, domain.ErrAPIKeyExpired:
+		return HTTPError{Message: "Revoked or expired API keys can't be rotated"}.Conflict()
+	case domain.ErrInvalidExpiry, domain.ErrInvalidGracePeriod:
+		return HTTPError{Message: err.Error()}.ErrUnprocessableEntity()
+	default:
+		return HTTPError{Message: "Internal server error"}.InternalServerError()
+	}
 }
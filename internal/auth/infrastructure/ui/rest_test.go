@@ -0,0 +1,327 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+	"github.com/yavurb/goyurback/internal/app/mods"
+	"github.com/yavurb/goyurback/internal/auth/domain"
+	"github.com/yavurb/goyurback/internal/auth/infrastructure/ui/mocks"
+)
+
+func TestAPIKeys(t *testing.T) {
+	e := echo.New()
+	e.Validator = mods.NewAppValidator()
+
+	createdAt := time.Date(2024, 7, 14, 10, 30, 0, 0, time.UTC)
+	revokedAt := createdAt.Add(24 * time.Hour)
+	apiKeys := []*domain.APIKey{
+		{ID: 2, PublicID: "ak_1qjrblb8pm90", Name: "testing_key", Key: "salt.hash", Scopes: []domain.Scope{domain.ScopePostsWrite}, LastUsedAt: revokedAt, LastUsedIP: "10.0.0.1", RateLimit: 600, CreatedAt: createdAt, UpdatedAt: createdAt},
+		{ID: 1, PublicID: "ak_0000000000aa", Name: "leaked_key", Key: "salt.hash", Revoked: true, RevokedAt: revokedAt, CreatedAt: createdAt, UpdatedAt: revokedAt},
+	}
+
+	newContext := func(method, path, publicID string) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(method, path, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if publicID != "" {
+			c.SetPath("/auth/keys/:publicID")
+			c.SetParamNames("publicID")
+			c.SetParamValues(publicID)
+		}
+
+		return c, rec
+	}
+
+	t.Run("it should create a key with the requested scopes", func(t *testing.T) {
+		var gotScopes []domain.Scope
+
+		uc := &mocks.MockAPIKeyUsecase{
+			CreateAPIKeyFn: func(ctx context.Context, name string, scopes []domain.Scope, expiresAt time.Time, rateLimit int32) (*domain.APIKey, error) {
+				gotScopes = scopes
+
+				return &domain.APIKey{PublicID: "ak_1qjrblb8pm90", Name: name, Key: "sk_salt.key", Scopes: scopes, CreatedAt: createdAt}, nil
+			},
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/auth/keys", strings.NewReader(`{"name":"ci_pipeline","scopes":["posts:write"]}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		h := NewAuthRouter(e, uc)
+
+		if err := h.CreateAPIKey(c); err != nil {
+			t.Fatalf("Expected no errors creating the key. Got: %v", err)
+		}
+
+		if diff := cmp.Diff([]domain.Scope{domain.ScopePostsWrite}, gotScopes); diff != "" {
+			t.Errorf("CreateAPIKey() scopes mismatch (-want +got):\n%s", diff)
+		}
+
+		got := APIKeyOut{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatalf("Error unmarshalling response: %s", err)
+		}
+
+		if diff := cmp.Diff([]string{"posts:write"}, got.Scopes); diff != "" {
+			t.Errorf("CreateAPIKey() response scopes mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("it should reject keys without valid scopes", func(t *testing.T) {
+		uc := &mocks.MockAPIKeyUsecase{
+			CreateAPIKeyFn: func(ctx context.Context, name string, scopes []domain.Scope, expiresAt time.Time, rateLimit int32) (*domain.APIKey, error) {
+				return nil, domain.ErrInvalidScope
+			},
+		}
+
+		h := NewAuthRouter(e, uc)
+
+		for _, body := range []string{`{"name":"ci_pipeline"}`, `{"name":"ci_pipeline","scopes":["posts:delete"]}`} {
+			req := httptest.NewRequest(http.MethodPost, "/auth/keys", strings.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			err := h.CreateAPIKey(c)
+
+			var httpErr *echo.HTTPError
+			if !errors.As(err, &httpErr) || httpErr.Code != http.StatusUnprocessableEntity {
+				t.Errorf("Expected %s to be rejected as unprocessable. Got: %v", body, err)
+			}
+		}
+	})
+
+	t.Run("it should list the keys without their values", func(t *testing.T) {
+		uc := &mocks.MockAPIKeyUsecase{
+			GetAPIKeysFn: func(ctx context.Context) ([]*domain.APIKey, error) {
+				return apiKeys, nil
+			},
+		}
+
+		c, rec := newContext(http.MethodGet, "/auth/keys", "")
+		h := NewAuthRouter(e, uc)
+
+		if err := h.GetAPIKeys(c); err != nil {
+			t.Fatalf("Expected no errors listing keys. Got: %v", err)
+		}
+
+		got := map[string]any{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatalf("Error unmarshalling response: %s", err)
+		}
+
+		want := map[string]any{
+			"data": []any{
+				map[string]any{
+					"id":           "ak_1qjrblb8pm90",
+					"name":         "testing_key",
+					"scopes":       []any{"posts:write"},
+					"revoked":      false,
+					"revoked_at":   nil,
+					"expires_at":   nil,
+					"last_used_at": "2024-07-15T10:30:00Z",
+					"last_used_ip": "10.0.0.1",
+					"rate_limit":   float64(600),
+					"created_at":   "2024-07-14T10:30:00Z",
+					"updated_at":   "2024-07-14T10:30:00Z",
+				},
+				map[string]any{
+					"id":           "ak_0000000000aa",
+					"name":         "leaked_key",
+					"scopes":       []any{},
+					"revoked":      true,
+					"revoked_at":   "2024-07-15T10:30:00Z",
+					"expires_at":   nil,
+					"last_used_at": nil,
+					"last_used_ip": nil,
+					"rate_limit":   nil,
+					"created_at":   "2024-07-14T10:30:00Z",
+					"updated_at":   "2024-07-15T10:30:00Z",
+				},
+			},
+		}
+
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("GetAPIKeys() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("it should get a key", func(t *testing.T) {
+		uc := &mocks.MockAPIKeyUsecase{
+			GetAPIKeyFn: func(ctx context.Context, publicID string) (*domain.APIKey, error) {
+				return apiKeys[1], nil
+			},
+		}
+
+		c, rec := newContext(http.MethodGet, "/auth/keys/ak_0000000000aa", "ak_0000000000aa")
+		h := NewAuthRouter(e, uc)
+
+		if err := h.GetAPIKey(c); err != nil {
+			t.Fatalf("Expected no errors getting the key. Got: %v", err)
+		}
+
+		got := APIKeyMetadataOut{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatalf("Error unmarshalling response: %s", err)
+		}
+
+		if got.ID != "ak_0000000000aa" || !got.Revoked || got.RevokedAt == nil || !got.RevokedAt.Equal(revokedAt) {
+			t.Errorf("Expected the revoked key. Got: %+v", got)
+		}
+	})
+
+	t.Run("it should revoke a key", func(t *testing.T) {
+		var gotPublicID string
+
+		uc := &mocks.MockAPIKeyUsecase{
+			RevokeAPIKeyFn: func(ctx context.Context, publicID string) error {
+				gotPublicID = publicID
+
+				return nil
+			},
+		}
+
+		c, rec := newContext(http.MethodDelete, "/auth/keys/ak_1qjrblb8pm90", "ak_1qjrblb8pm90")
+		h := NewAuthRouter(e, uc)
+
+		if err := h.RevokeAPIKey(c); err != nil {
+			t.Fatalf("Expected no errors revoking the key. Got: %v", err)
+		}
+
+		if rec.Code != http.StatusNoContent {
+			t.Errorf("Expected status code to be %d. Got: %d", http.StatusNoContent, rec.Code)
+		}
+
+		if gotPublicID != "ak_1qjrblb8pm90" {
+			t.Errorf("Expected ak_1qjrblb8pm90 to be revoked. Got: %s", gotPublicID)
+		}
+	})
+
+	t.Run("it should rotate a key", func(t *testing.T) {
+		cases := []struct {
+			name            string
+			body            string
+			wantGracePeriod time.Duration
+		}{
+			{name: "default grace period", body: ``, wantGracePeriod: domain.DefaultRotationGracePeriod},
+			{name: "custom grace period", body: `{"grace_period":"1h30m"}`, wantGracePeriod: 90 * time.Minute},
+			{name: "immediate cutover", body: `{"grace_period":"0s"}`, wantGracePeriod: 0},
+		}
+
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				var gotPublicID string
+				var gotGracePeriod time.Duration
+
+				uc := &mocks.MockAPIKeyUsecase{
+					RotateAPIKeyFn: func(ctx context.Context, publicID string, gracePeriod time.Duration, expiresAt time.Time) (*domain.APIKey, error) {
+						gotPublicID = publicID
+						gotGracePeriod = gracePeriod
+
+						return &domain.APIKey{PublicID: "ak_replacement", Name: "testing_key", Key: "sk_salt.key", CreatedAt: createdAt}, nil
+					},
+				}
+
+				req := httptest.NewRequest(http.MethodPost, "/auth/keys/ak_1qjrblb8pm90/rotate", strings.NewReader(tc.body))
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+				rec := httptest.NewRecorder()
+				c := e.NewContext(req, rec)
+				c.SetPath("/auth/keys/:publicID/rotate")
+				c.SetParamNames("publicID")
+				c.SetParamValues("ak_1qjrblb8pm90")
+				h := NewAuthRouter(e, uc)
+
+				if err := h.RotateAPIKey(c); err != nil {
+					t.Fatalf("Expected no errors rotating the key. Got: %v", err)
+				}
+
+				if rec.Code != http.StatusCreated {
+					t.Errorf("Expected status code to be %d. Got: %d", http.StatusCreated, rec.Code)
+				}
+
+				if gotPublicID != "ak_1qjrblb8pm90" || gotGracePeriod != tc.wantGracePeriod {
+					t.Errorf("Expected ak_1qjrblb8pm90 to be rotated with a %v grace period. Got: %s, %v", tc.wantGracePeriod, gotPublicID, gotGracePeriod)
+				}
+
+				got := APIKeyOut{}
+				if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+					t.Fatalf("Error unmarshalling response: %s", err)
+				}
+
+				if got.ID != "ak_replacement" || got.Key != "sk_salt.key" {
+					t.Errorf("Expected the replacement key. Got: %+v", got)
+				}
+			})
+		}
+	})
+
+	t.Run("it should not rotate revoked keys", func(t *testing.T) {
+		uc := &mocks.MockAPIKeyUsecase{
+			RotateAPIKeyFn: func(ctx context.Context, publicID string, gracePeriod time.Duration, expiresAt time.Time) (*domain.APIKey, error) {
+				return nil, domain.ErrAPIKeyRevoked
+			},
+		}
+
+		c, _ := newContext(http.MethodPost, "/auth/keys/ak_0000000000aa/rotate", "ak_0000000000aa")
+		h := NewAuthRouter(e, uc)
+
+		err := h.RotateAPIKey(c)
+
+		var httpErr *echo.HTTPError
+		if !errors.As(err, &httpErr) || httpErr.Code != http.StatusConflict {
+			t.Errorf("Expected a conflict error. Got: %v", err)
+		}
+	})
+
+	t.Run("it should reject invalid grace periods", func(t *testing.T) {
+		uc := &mocks.MockAPIKeyUsecase{}
+
+		req := httptest.NewRequest(http.MethodPost, "/auth/keys/ak_1qjrblb8pm90/rotate", strings.NewReader(`{"grace_period":"a day"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		c := e.NewContext(req, httptest.NewRecorder())
+		c.SetPath("/auth/keys/:publicID/rotate")
+		c.SetParamNames("publicID")
+		c.SetParamValues("ak_1qjrblb8pm90")
+		h := NewAuthRouter(e, uc)
+
+		err := h.RotateAPIKey(c)
+
+		var httpErr *echo.HTTPError
+		if !errors.As(err, &httpErr) || httpErr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected an unprocessable entity error. Got: %v", err)
+		}
+	})
+
+	t.Run("it should return not found for unknown keys", func(t *testing.T) {
+		uc := &mocks.MockAPIKeyUsecase{
+			GetAPIKeyFn: func(ctx context.Context, publicID string) (*domain.APIKey, error) {
+				return nil, domain.ErrAPIKeyNotFound
+			},
+			RevokeAPIKeyFn: func(ctx context.Context, publicID string) error {
+				return domain.ErrAPIKeyNotFound
+			},
+		}
+
+		h := NewAuthRouter(e, uc)
+
+		for name, handler := range map[string]echo.HandlerFunc{"GetAPIKey": h.GetAPIKey, "RevokeAPIKey": h.RevokeAPIKey} {
+			c, _ := newContext(http.MethodGet, "/auth/keys/ak_unknown", "ak_unknown")
+
+			err := handler(c)
+
+			var httpErr *echo.HTTPError
+			if !errors.As(err, &httpErr) || httpErr.Code != http.StatusNotFound {
+				t.Errorf("Expected %s to return a not found error. Got: %v", name, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,125 @@
+package application
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/yavurb/goyurback/internal/chikitos/domain"
+)
+
+const (
+	// clickBatchSize is the number of pending clicks that triggers a flush before the interval
+	clickBatchSize = 500
+	// maxPendingClicks bounds the memory used by the clicks while the database is unavailable.
+	// Newer clicks are dropped past it
+	maxPendingClicks = 20 * clickBatchSize
+)
+
+// ClickTracker buffers the clicks of the chikitos and writes them in batches, so redirecting
+// never waits on a write.
+type ClickTracker struct {
+	repository domain.ChikitoRepository
+	interval   time.Duration
+	full       chan struct{}
+
+	mu      sync.Mutex
+	pending []*domain.Click
+}
+
+func NewClickTracker(repository domain.ChikitoRepository, interval time.Duration) *ClickTracker {
+	return &ClickTracker{
+		repository: repository,
+		interval:   interval,
+		full:       make(chan struct{}, 1),
+	}
+}
+
+// Track keeps the click until the next flush, which is brought forward once a batch is pending.
+func (t *ClickTracker) Track(click *domain.Click) {
+	t.track([]*domain.Click{click})
+}
+
+func (t *ClickTracker) track(clicks []*domain.Click) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if dropped := len(t.pending) + len(clicks) - maxPendingClicks; dropped > 0 {
+		log.Printf("Too many pending clicks, dropping %d of them\n", dropped)
+
+		clicks = clicks[:max(len(clicks)-dropped, 0)]
+	}
+
+	t.pending = append(t.pending, clicks...)
+
+	if len(t.pending) >= clickBatchSize {
+		select {
+		case t.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Run blocks until ctx is done. The clicks still pending at that point are written before
+// returning, so they aren't lost on shutdown.
+func (t *ClickTracker) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			t.Flush(flushCtx)
+
+			return
+		case <-ticker.C:
+			t.Flush(ctx)
+		case <-t.full:
+			t.Flush(ctx)
+		}
+	}
+}
+
+// Flush writes the pending clicks. When the batch fails each click is written on its own, so a
+// bad one can't hold back the others, and the clicks that still fail are dropped. They are kept
+// for the next flush only when every write failed, as the database is likely unreachable then.
+func (t *ClickTracker) Flush(ctx context.Context) error {
+	t.mu.Lock()
+	clicks := t.pending
+	t.pending = nil
+	t.mu.Unlock()
+
+	if len(clicks) == 0 {
+		return nil
+	}
+
+	err := t.repository.CreateClicks(ctx, clicks)
+	if err == nil {
+		return nil
+	}
+
+	log.Printf("Error creating chikito clicks, writing them one by one. Got: %v\n", err)
+
+	failed := []*domain.Click{}
+	for _, click := range clicks {
+		if err := t.repository.CreateClicks(ctx, []*domain.Click{click}); err != nil {
+			failed = append(failed, click)
+		}
+	}
+
+	if len(failed) == len(clicks) {
+		t.track(clicks)
+
+		return err
+	}
+
+	for _, click := range failed {
+		log.Printf("Error creating a click of chikito %d, dropping it. Got: %v\n", click.ChikitoID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,180 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/yavurb/goyurback/internal/chikitos/application/mocks"
+	"github.com/yavurb/goyurback/internal/chikitos/domain"
+)
+
+func TestClickTracker(t *testing.T) {
+	clickedAt := time.Date(2024, 7, 14, 10, 30, 0, 0, time.UTC)
+
+	t.Run("it should write the pending clicks in one batch", func(t *testing.T) {
+		var batches [][]*domain.Click
+
+		repo := &mocks.MockChikitosRepository{
+			CreateClicksFn: func(ctx context.Context, clicks []*domain.Click) error {
+				batches = append(batches, clicks)
+
+				return nil
+			},
+		}
+
+		tracker := NewClickTracker(repo, time.Minute)
+		tracker.Track(&domain.Click{ChikitoID: 1, ClickedAt: clickedAt})
+		tracker.Track(&domain.Click{ChikitoID: 2, ClickedAt: clickedAt})
+
+		if err := tracker.Flush(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		want := [][]*domain.Click{{
+			{ChikitoID: 1, ClickedAt: clickedAt},
+			{ChikitoID: 2, ClickedAt: clickedAt},
+		}}
+		if diff := cmp.Diff(want, batches); diff != "" {
+			t.Errorf("Flush() mismatch (-want +got):\n%s", diff)
+		}
+
+		if err := tracker.Flush(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if len(batches) != 1 {
+			t.Errorf("Expected nothing to be written when there are no new clicks, got %d batches", len(batches))
+		}
+	})
+
+	t.Run("it should drop the clicks that can't be written on their own", func(t *testing.T) {
+		var written []*domain.Click
+
+		repo := &mocks.MockChikitosRepository{
+			CreateClicksFn: func(ctx context.Context, clicks []*domain.Click) error {
+				for _, click := range clicks {
+					if click.ChikitoID == 2 {
+						return errors.New("DB error")
+					}
+				}
+
+				written = append(written, clicks...)
+
+				return nil
+			},
+		}
+
+		tracker := NewClickTracker(repo, time.Minute)
+		tracker.Track(&domain.Click{ChikitoID: 1, ClickedAt: clickedAt})
+		tracker.Track(&domain.Click{ChikitoID: 2, ClickedAt: clickedAt})
+		tracker.Track(&domain.Click{ChikitoID: 3, ClickedAt: clickedAt})
+
+		if err := tracker.Flush(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		want := []*domain.Click{{ChikitoID: 1, ClickedAt: clickedAt}, {ChikitoID: 3, ClickedAt: clickedAt}}
+		if diff := cmp.Diff(want, written); diff != "" {
+			t.Errorf("Flush() mismatch (-want +got):\n%s", diff)
+		}
+
+		written = nil
+
+		if err := tracker.Flush(context.Background()); err != nil || len(written) != 0 {
+			t.Errorf("Expected the failed click to be dropped. Got %v written, error %v", written, err)
+		}
+	})
+
+	t.Run("it should keep the clicks for the next flush when the write fails", func(t *testing.T) {
+		fail := true
+		var written []*domain.Click
+
+		repo := &mocks.MockChikitosRepository{
+			CreateClicksFn: func(ctx context.Context, clicks []*domain.Click) error {
+				if fail {
+					return errors.New("DB error")
+				}
+
+				written = clicks
+
+				return nil
+			},
+		}
+
+		tracker := NewClickTracker(repo, time.Minute)
+		tracker.Track(&domain.Click{ChikitoID: 1, ClickedAt: clickedAt})
+
+		if err := tracker.Flush(context.Background()); err == nil {
+			t.Fatalf("Expected an error")
+		}
+
+		fail = false
+
+		if err := tracker.Flush(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if len(written) != 1 || written[0].ChikitoID != 1 {
+			t.Errorf("Expected the failed click to be written, got %v", written)
+		}
+	})
+
+	t.Run("it should drop the clicks past the limit", func(t *testing.T) {
+		var written []*domain.Click
+
+		repo := &mocks.MockChikitosRepository{
+			CreateClicksFn: func(ctx context.Context, clicks []*domain.Click) error {
+				written = clicks
+
+				return nil
+			},
+		}
+
+		tracker := NewClickTracker(repo, time.Minute)
+		for range maxPendingClicks + 10 {
+			tracker.Track(&domain.Click{ChikitoID: 1, ClickedAt: clickedAt})
+		}
+
+		if err := tracker.Flush(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if len(written) != maxPendingClicks {
+			t.Errorf("Expected %d clicks to be written, got %d", maxPendingClicks, len(written))
+		}
+	})
+
+	t.Run("it should flush once a batch is pending", func(t *testing.T) {
+		written := make(chan int, 1)
+
+		repo := &mocks.MockChikitosRepository{
+			CreateClicksFn: func(ctx context.Context, clicks []*domain.Click) error {
+				written <- len(clicks)
+
+				return nil
+			},
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		tracker := NewClickTracker(repo, time.Hour)
+		go tracker.Run(ctx)
+
+		for range clickBatchSize {
+			tracker.Track(&domain.Click{ChikitoID: 1, ClickedAt: clickedAt})
+		}
+
+		select {
+		case n := <-written:
+			if n < clickBatchSize {
+				t.Errorf("Expected at least %d clicks to be written, got %d", clickBatchSize, n)
+			}
+		case <-time.After(time.Second):
+			t.Error("Expected the clicks to be written before the interval")
+		}
+	})
+}
@@ -3,6 +3,7 @@ package application
 import (
 	"context"
 	"log"
+	"time"
 
 	"github.com/yavurb/goyurback/internal/chikitos/domain"
 	"github.com/yavurb/goyurback/internal/pgk/ids"
@@ -10,18 +11,38 @@ import (
 
 const prefix = "ch"
 
-func (uc *ChikitoUsecase) Create(ctx context.Context, url, description string) (*domain.Chikito, error) {
-	publicID, err := ids.NewPublicID(prefix)
-	if err != nil {
-		log.Printf("Error creating public id for chikito: %v\n", err)
+func (uc *ChikitoUsecase) Create(ctx context.Context, url, description, alias string, options *domain.ChikitoOptions) (*domain.Chikito, error) {
+	if options == nil {
+		options = &domain.ChikitoOptions{}
+	}
+
+	if err := validateLimits(options.ExpiresAt, options.MaxClicks); err != nil {
+		return nil, err
+	}
 
+	redirectCode := options.RedirectCode
+	if redirectCode == 0 {
+		redirectCode = domain.DefaultRedirectCode
+	}
+
+	if !domain.ValidRedirectCode(redirectCode) {
+		return nil, domain.ErrInvalidRedirectCode
+	}
+
+	publicID, err := newPublicID(alias)
+	if err != nil {
 		return nil, err
 	}
 
 	chikito := &domain.ChikitoCreate{
-		PublicID:    publicID,
-		URL:         url,
-		Description: description,
+		ExpiresAt:    options.ExpiresAt.UTC(), // Dates are stored without a time zone, in UTC
+		UTM:          options.UTM,
+		PublicID:     publicID,
+		URL:          url,
+		Description:  description,
+		MaxClicks:    options.MaxClicks,
+		RedirectCode: redirectCode,
+		ForwardQuery: options.ForwardQuery,
 	}
 
 	chikitoCreated, err := uc.repository.CreateChikito(ctx, chikito)
@@ -33,3 +54,31 @@ func (uc *ChikitoUsecase) Create(ctx context.Context, url, description string) (
 
 	return chikitoCreated, nil
 }
+
+func newPublicID(alias string) (string, error) {
+	if alias != "" {
+		return domain.NormalizeAlias(alias)
+	}
+
+	publicID, err := ids.NewPublicID(prefix)
+	if err != nil {
+		log.Printf("Error creating public id for chikito: %v\n", err)
+
+		return "", err
+	}
+
+	return publicID, nil
+}
+
+// validateLimits checks the expiry and max clicks given to a chikito. Zero values remove them.
+func validateLimits(expiresAt time.Time, maxClicks int32) error {
+	if !expiresAt.IsZero() && !expiresAt.After(time.Now().UTC()) {
+		return domain.ErrInvalidExpiry
+	}
+
+	if maxClicks < 0 {
+		return domain.ErrInvalidMaxClicks
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package application
 import (
 	"context"
 	"errors"
+	"net/http"
 	"regexp"
 	"testing"
 	"time"
@@ -32,9 +33,9 @@ func TestCreate(t *testing.T) {
 			}, nil
 		}
 
-		uc := NewChikitoUsecase(repo)
+		uc := NewChikitoUsecase(repo, nil, nil, "")
 
-		got, err := uc.Create(context.Background(), want.URL, want.Description)
+		got, err := uc.Create(context.Background(), want.URL, want.Description, "", nil)
 		if err != nil {
 			t.Errorf("Expected no error creating chikito, got %v", err)
 		}
@@ -50,6 +51,125 @@ func TestCreate(t *testing.T) {
 		}
 	})
 
+	t.Run("it should use the alias as the public id", func(t *testing.T) {
+		repo := &mocks.MockChikitosRepository{}
+
+		repo.CreateChikitoFn = func(ctx context.Context, chikito *domain.ChikitoCreate) (*domain.Chikito, error) {
+			return &domain.Chikito{
+				ID:          1,
+				PublicID:    chikito.PublicID,
+				URL:         chikito.URL,
+				Description: chikito.Description,
+			}, nil
+		}
+
+		uc := NewChikitoUsecase(repo, nil, nil, "")
+
+		got, err := uc.Create(context.Background(), "https://example.com", "some description", "Talk-GopherCon", nil)
+		if err != nil {
+			t.Fatalf("Expected no error creating chikito, got %v", err)
+		}
+
+		if got.PublicID != "talk-gophercon" {
+			t.Errorf("Expected PublicID to be talk-gophercon, got: %s", got.PublicID)
+		}
+	})
+
+	t.Run("it should reject an invalid alias", func(t *testing.T) {
+		repo := &mocks.MockChikitosRepository{}
+
+		repo.CreateChikitoFn = func(ctx context.Context, chikito *domain.ChikitoCreate) (*domain.Chikito, error) {
+			t.Error("Expected the chikito not to be created")
+
+			return nil, nil
+		}
+
+		uc := NewChikitoUsecase(repo, nil, nil, "")
+
+		_, err := uc.Create(context.Background(), "https://example.com", "some description", "health", nil)
+		if !errors.Is(err, domain.ErrReservedAlias) {
+			t.Errorf("Expected error to be ErrReservedAlias, got %v", err)
+		}
+	})
+
+	t.Run("it should create a chikito with limits", func(t *testing.T) {
+		expiresAt := time.Now().UTC().Add(time.Hour)
+
+		repo := &mocks.MockChikitosRepository{}
+		repo.CreateChikitoFn = func(ctx context.Context, chikito *domain.ChikitoCreate) (*domain.Chikito, error) {
+			if !chikito.ExpiresAt.Equal(expiresAt) || chikito.MaxClicks != 100 {
+				t.Errorf("Expected the limits to be saved, got %+v", chikito)
+			}
+
+			return &domain.Chikito{PublicID: chikito.PublicID, ExpiresAt: chikito.ExpiresAt, MaxClicks: chikito.MaxClicks}, nil
+		}
+
+		uc := NewChikitoUsecase(repo, nil, nil, "")
+
+		if _, err := uc.Create(context.Background(), "https://example.com", "some description", "", &domain.ChikitoOptions{ExpiresAt: expiresAt, MaxClicks: 100}); err != nil {
+			t.Errorf("Expected no error creating chikito, got %v", err)
+		}
+	})
+
+	t.Run("it should store the expiry in UTC", func(t *testing.T) {
+		expiresAt := time.Now().Add(time.Hour).In(time.FixedZone("", -5*60*60))
+
+		repo := &mocks.MockChikitosRepository{}
+		repo.CreateChikitoFn = func(ctx context.Context, chikito *domain.ChikitoCreate) (*domain.Chikito, error) {
+			if chikito.ExpiresAt.Location() != time.UTC || !chikito.ExpiresAt.Equal(expiresAt) {
+				t.Errorf("Expected the chikito to expire at %v, got %v", expiresAt.UTC(), chikito.ExpiresAt)
+			}
+
+			return &domain.Chikito{PublicID: chikito.PublicID, ExpiresAt: chikito.ExpiresAt}, nil
+		}
+
+		uc := NewChikitoUsecase(repo, nil, nil, "")
+
+		if _, err := uc.Create(context.Background(), "https://example.com", "some description", "", &domain.ChikitoOptions{ExpiresAt: expiresAt}); err != nil {
+			t.Errorf("Expected no error creating chikito, got %v", err)
+		}
+	})
+
+	t.Run("it should use a temporary redirect by default", func(t *testing.T) {
+		repo := &mocks.MockChikitosRepository{}
+		repo.CreateChikitoFn = func(ctx context.Context, chikito *domain.ChikitoCreate) (*domain.Chikito, error) {
+			if chikito.RedirectCode != http.StatusTemporaryRedirect {
+				t.Errorf("Expected a 307 redirect, got %d", chikito.RedirectCode)
+			}
+
+			return &domain.Chikito{PublicID: chikito.PublicID, RedirectCode: chikito.RedirectCode}, nil
+		}
+
+		uc := NewChikitoUsecase(repo, nil, nil, "")
+
+		if _, err := uc.Create(context.Background(), "https://example.com", "some description", "", nil); err != nil {
+			t.Errorf("Expected no error creating chikito, got %v", err)
+		}
+	})
+
+	t.Run("it should reject an invalid redirect code", func(t *testing.T) {
+		uc := NewChikitoUsecase(&mocks.MockChikitosRepository{}, nil, nil, "")
+
+		_, err := uc.Create(context.Background(), "https://example.com", "some description", "", &domain.ChikitoOptions{RedirectCode: http.StatusOK})
+		if !errors.Is(err, domain.ErrInvalidRedirectCode) {
+			t.Errorf("Expected error to be ErrInvalidRedirectCode, got %v", err)
+		}
+	})
+
+	t.Run("it should reject invalid limits", func(t *testing.T) {
+		uc := NewChikitoUsecase(&mocks.MockChikitosRepository{}, nil, nil, "")
+
+		_, err := uc.Create(context.Background(), "https://example.com", "some description", "", &domain.ChikitoOptions{ExpiresAt: time.Now().Add(-time.Hour)})
+		if !errors.Is(err, domain.ErrInvalidExpiry) {
+			t.Errorf("Expected error to be ErrInvalidExpiry, got %v", err)
+		}
+
+		_, err = uc.Create(context.Background(), "https://example.com", "some description", "", &domain.ChikitoOptions{MaxClicks: -1})
+		if !errors.Is(err, domain.ErrInvalidMaxClicks) {
+			t.Errorf("Expected error to be ErrInvalidMaxClicks, got %v", err)
+		}
+	})
+
 	t.Run("it should return an error when creating a chikito", func(t *testing.T) {
 		repo := &mocks.MockChikitosRepository{}
 
@@ -57,9 +177,9 @@ func TestCreate(t *testing.T) {
 			return nil, errors.New("DB Error")
 		}
 
-		uc := NewChikitoUsecase(repo)
+		uc := NewChikitoUsecase(repo, nil, nil, "")
 
-		_, err := uc.Create(context.Background(), "https://example.com", "some description")
+		_, err := uc.Create(context.Background(), "https://example.com", "some description", "", nil)
 		if err == nil {
 			t.Error("Expected error creating chikito, got nil")
 		}
@@ -0,0 +1,17 @@
+package application
+
+import (
+	"context"
+	"log"
+	"strings"
+)
+
+func (uc *ChikitoUsecase) Delete(ctx context.Context, id string) error {
+	if err := uc.repository.DeleteChikito(ctx, strings.ToLower(id)); err != nil {
+		log.Printf("Error deleting chikito. Got: %v\n", err)
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yavurb/goyurback/internal/chikitos/application/mocks"
+	"github.com/yavurb/goyurback/internal/chikitos/domain"
+)
+
+func TestDelete(t *testing.T) {
+	t.Run("it should delete the chikito", func(t *testing.T) {
+		var deleted string
+
+		repo := &mocks.MockChikitosRepository{}
+		repo.DeleteChikitoFn = func(ctx context.Context, id string) error {
+			deleted = id
+
+			return nil
+		}
+		uc := NewChikitoUsecase(repo, nil, nil, "")
+
+		if err := uc.Delete(context.Background(), "Talk-GopherCon"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if deleted != "talk-gophercon" {
+			t.Errorf("Expected talk-gophercon to be deleted, got %s", deleted)
+		}
+	})
+
+	t.Run("it should return a not found error", func(t *testing.T) {
+		repo := &mocks.MockChikitosRepository{}
+		repo.DeleteChikitoFn = func(ctx context.Context, id string) error {
+			return domain.ErrChikitoNotFound
+		}
+		uc := NewChikitoUsecase(repo, nil, nil, "")
+
+		if err := uc.Delete(context.Background(), "ch_12345"); !errors.Is(err, domain.ErrChikitoNotFound) {
+			t.Errorf("Expected ErrChikitoNotFound, got %v", err)
+		}
+	})
+}
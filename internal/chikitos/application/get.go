@@ -3,17 +3,33 @@ package application
 import (
 	"context"
 	"log"
+	"strings"
+	"time"
 
 	"github.com/yavurb/goyurback/internal/chikitos/domain"
 )
 
 func (uc *ChikitoUsecase) Get(ctx context.Context, id string) (*domain.Chikito, error) {
-	chikito, err := uc.repository.GetChikito(ctx, id)
+	chikito, err := uc.getChikito(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if chikito.Gone(time.Now().UTC()) {
+		return nil, domain.ErrChikitoGone
+	}
+
+	return chikito, nil
+}
+
+// getChikito returns the chikito even when it no longer redirects, for the routes managing it.
+func (uc *ChikitoUsecase) getChikito(ctx context.Context, id string) (*domain.Chikito, error) {
+	chikito, err := uc.repository.GetChikito(ctx, strings.ToLower(id))
 	if err != nil {
 		log.Printf("Unable to get chikito. Got: %v\n", err)
 
 		return nil, domain.ErrChikitoNotFound
 	}
 
-	return chikito, err
+	return chikito, nil
 }
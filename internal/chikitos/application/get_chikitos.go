@@ -0,0 +1,19 @@
+package application
+
+import (
+	"context"
+	"log"
+
+	"github.com/yavurb/goyurback/internal/chikitos/domain"
+)
+
+func (uc *ChikitoUsecase) GetChikitos(ctx context.Context) ([]*domain.Chikito, error) {
+	chikitos, err := uc.repository.GetChikitos(ctx)
+	if err != nil {
+		log.Printf("Unable to get chikitos. Got: %v\n", err)
+
+		return nil, err
+	}
+
+	return chikitos, nil
+}
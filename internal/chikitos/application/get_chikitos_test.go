@@ -0,0 +1,55 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/yavurb/goyurback/internal/chikitos/application/mocks"
+	"github.com/yavurb/goyurback/internal/chikitos/domain"
+)
+
+func TestGetChikitos(t *testing.T) {
+	t.Run("it should get all the chikitos", func(t *testing.T) {
+		want := []*domain.Chikito{
+			{
+				ID:          1,
+				PublicID:    "ch_12345",
+				URL:         "https://example.com/my_long_url",
+				Description: "My long URL description",
+				CreatedAt:   time.Now().UTC(),
+				UpdatedAt:   time.Now().UTC(),
+			},
+		}
+
+		repo := &mocks.MockChikitosRepository{}
+		repo.GetChikitosFn = func(ctx context.Context) ([]*domain.Chikito, error) {
+			return want, nil
+		}
+		uc := NewChikitoUsecase(repo, nil, nil, "")
+
+		got, err := uc.GetChikitos(context.Background())
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("Mismatch getting chikitos. (-want,+got):\n%v", diff)
+		}
+	})
+
+	t.Run("it should return the repository error", func(t *testing.T) {
+		repo := &mocks.MockChikitosRepository{}
+		repo.GetChikitosFn = func(ctx context.Context) ([]*domain.Chikito, error) {
+			return nil, errors.New("DB error")
+		}
+		uc := NewChikitoUsecase(repo, nil, nil, "")
+
+		_, err := uc.GetChikitos(context.Background())
+		if err == nil {
+			t.Errorf("Expected error, got nil")
+		}
+	})
+}
@@ -0,0 +1,37 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/yavurb/goyurback/internal/chikitos/domain"
+)
+
+func (uc *ChikitoUsecase) GetQRCode(ctx context.Context, id string, options *domain.QROptions) ([]byte, error) {
+	if uc.publicURL == "" || uc.qrRenderer == nil {
+		return nil, domain.ErrQRCodesDisabled
+	}
+
+	if options == nil {
+		options = domain.DefaultQROptions()
+	}
+
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	chikito, err := uc.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	qrCode, err := uc.qrRenderer.Render(fmt.Sprintf("%s/%s", uc.publicURL, chikito.PublicID), options)
+	if err != nil {
+		log.Printf("Error rendering the QR code of chikito %s. Got: %v\n", chikito.PublicID, err)
+
+		return nil, err
+	}
+
+	return qrCode, nil
+}
@@ -0,0 +1,82 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yavurb/goyurback/internal/chikitos/application/mocks"
+	"github.com/yavurb/goyurback/internal/chikitos/domain"
+)
+
+func TestGetQRCode(t *testing.T) {
+	repo := &mocks.MockChikitosRepository{}
+	repo.GetChikitoFn = func(ctx context.Context, id string) (*domain.Chikito, error) {
+		switch id {
+		case "talk-gophercon":
+			return &domain.Chikito{ID: 1, PublicID: id}, nil
+		case "ch_expired":
+			return &domain.Chikito{ID: 2, PublicID: id, ExpiresAt: time.Now().UTC().Add(-time.Hour)}, nil
+		default:
+			return nil, domain.ErrChikitoNotFound
+		}
+	}
+
+	renderer := &mocks.MockQRRenderer{}
+	renderer.RenderFn = func(content string, options *domain.QROptions) ([]byte, error) {
+		return []byte(content), nil
+	}
+
+	t.Run("it should encode the public URL of the chikito", func(t *testing.T) {
+		uc := NewChikitoUsecase(repo, nil, renderer, "https://chk.yurb.dev/")
+
+		got, err := uc.GetQRCode(context.Background(), "Talk-GopherCon", nil)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if want := "https://chk.yurb.dev/talk-gophercon"; string(got) != want {
+			t.Errorf("Expected the QR code to encode %q, got %q", want, got)
+		}
+	})
+
+	t.Run("it should reject invalid options", func(t *testing.T) {
+		uc := NewChikitoUsecase(repo, nil, renderer, "https://chk.yurb.dev")
+
+		options := domain.DefaultQROptions()
+		options.Size = domain.QRMaxSize + 1
+
+		_, err := uc.GetQRCode(context.Background(), "talk-gophercon", options)
+		if !errors.Is(err, domain.ErrInvalidQROptions) {
+			t.Errorf("Expected ErrInvalidQROptions, got %v", err)
+		}
+	})
+
+	t.Run("it should not render chikitos that no longer redirect", func(t *testing.T) {
+		uc := NewChikitoUsecase(repo, nil, renderer, "https://chk.yurb.dev")
+
+		_, err := uc.GetQRCode(context.Background(), "ch_expired", nil)
+		if !errors.Is(err, domain.ErrChikitoGone) {
+			t.Errorf("Expected ErrChikitoGone, got %v", err)
+		}
+	})
+
+	t.Run("it should return a not found error", func(t *testing.T) {
+		uc := NewChikitoUsecase(repo, nil, renderer, "https://chk.yurb.dev")
+
+		_, err := uc.GetQRCode(context.Background(), "ch_missing", nil)
+		if !errors.Is(err, domain.ErrChikitoNotFound) {
+			t.Errorf("Expected ErrChikitoNotFound, got %v", err)
+		}
+	})
+
+	t.Run("it should fail without the public URL", func(t *testing.T) {
+		uc := NewChikitoUsecase(repo, nil, renderer, "")
+
+		_, err := uc.GetQRCode(context.Background(), "talk-gophercon", nil)
+		if !errors.Is(err, domain.ErrQRCodesDisabled) {
+			t.Errorf("Expected ErrQRCodesDisabled, got %v", err)
+		}
+	})
+}
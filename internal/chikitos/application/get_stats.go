@@ -0,0 +1,58 @@
+package application
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/yavurb/goyurback/internal/chikitos/domain"
+)
+
+func (uc *ChikitoUsecase) GetStats(ctx context.Context, id string, days, hours int) (*domain.ClickStats, error) {
+	chikito, err := uc.getChikito(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if days <= 0 {
+		days = domain.DefaultStatsDays
+	}
+
+	if hours <= 0 {
+		hours = domain.DefaultStatsHours
+	}
+
+	now := time.Now().UTC()
+	today := now.Truncate(24 * time.Hour)
+	currentHour := now.Truncate(time.Hour)
+	dailySince := today.AddDate(0, 0, -(days - 1))
+	hourlySince := currentHour.Add(-time.Duration(hours-1) * time.Hour)
+
+	stats, err := uc.repository.GetClickStats(ctx, chikito.ID, dailySince, hourlySince)
+	if err != nil {
+		log.Printf("Error getting the stats of the chikito. Got: %v\n", err)
+
+		return nil, err
+	}
+
+	stats.Daily = fillSeries(stats.Daily, dailySince, today, func(t time.Time) time.Time { return t.AddDate(0, 0, 1) })
+	stats.Hourly = fillSeries(stats.Hourly, hourlySince, currentHour, func(t time.Time) time.Time { return t.Add(time.Hour) })
+
+	return stats, nil
+}
+
+// fillSeries returns a count for each step from since to until, with zero clicks where the
+// series has none.
+func fillSeries(series []domain.ClickCount, since, until time.Time, next func(time.Time) time.Time) []domain.ClickCount {
+	clicks := map[time.Time]int64{}
+	for _, count := range series {
+		clicks[count.Time.UTC()] = count.Clicks
+	}
+
+	filled := []domain.ClickCount{}
+	for t := since; !t.After(until); t = next(t) {
+		filled = append(filled, domain.ClickCount{Time: t, Clicks: clicks[t]})
+	}
+
+	return filled
+}
@@ -0,0 +1,85 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yavurb/goyurback/internal/chikitos/application/mocks"
+	"github.com/yavurb/goyurback/internal/chikitos/domain"
+)
+
+func TestGetStats(t *testing.T) {
+	t.Run("it should fill the days and hours without clicks", func(t *testing.T) {
+		var since, hourlyStart time.Time
+
+		repo := &mocks.MockChikitosRepository{}
+		repo.GetChikitoFn = func(ctx context.Context, id string) (*domain.Chikito, error) {
+			return &domain.Chikito{ID: 7, PublicID: id}, nil
+		}
+		repo.GetClickStatsFn = func(ctx context.Context, chikitoID int32, dailySince, hourlySince time.Time) (*domain.ClickStats, error) {
+			if chikitoID != 7 {
+				t.Errorf("Expected the stats of chikito 7, got %d", chikitoID)
+			}
+
+			since, hourlyStart = dailySince, hourlySince
+
+			return &domain.ClickStats{
+				Daily:  []domain.ClickCount{{Time: dailySince.AddDate(0, 0, 4), Clicks: 4}, {Time: dailySince.AddDate(0, 0, 6), Clicks: 1}},
+				Hourly: []domain.ClickCount{{Time: hourlySince, Clicks: 1}},
+				Total:  5,
+			}, nil
+		}
+		uc := NewChikitoUsecase(repo, nil, nil, "")
+
+		stats, err := uc.GetStats(context.Background(), "ch_12345", 7, 0)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if !since.Equal(since.Truncate(24*time.Hour)) || time.Since(since) > 7*24*time.Hour || time.Since(since) < 6*24*time.Hour {
+			t.Errorf("Expected the daily series to start at midnight 6 days ago, got %v", since)
+		}
+
+		wantDaily := []int64{0, 0, 0, 0, 4, 0, 1}
+		if len(stats.Daily) != len(wantDaily) {
+			t.Fatalf("Expected %d days, got %d", len(wantDaily), len(stats.Daily))
+		}
+
+		for i, count := range stats.Daily {
+			if !count.Time.Equal(since.AddDate(0, 0, i)) || count.Clicks != wantDaily[i] {
+				t.Errorf("Unexpected day %d: %+v", i, count)
+			}
+		}
+
+		if len(stats.Hourly) != domain.DefaultStatsHours {
+			t.Fatalf("Expected %d hours, got %d", domain.DefaultStatsHours, len(stats.Hourly))
+		}
+
+		if first := stats.Hourly[0]; !first.Time.Equal(hourlyStart) || first.Clicks != 1 {
+			t.Errorf("Unexpected first hour: %+v", first)
+		}
+
+		if last := stats.Hourly[len(stats.Hourly)-1]; !last.Time.Equal(hourlyStart.Add((domain.DefaultStatsHours-1)*time.Hour)) || last.Clicks != 0 {
+			t.Errorf("Unexpected last hour: %+v", last)
+		}
+
+		if stats.Total != 5 {
+			t.Errorf("Expected 5 clicks in total, got %d", stats.Total)
+		}
+	})
+
+	t.Run("it should return a not found error", func(t *testing.T) {
+		repo := &mocks.MockChikitosRepository{}
+		repo.GetChikitoFn = func(ctx context.Context, id string) (*domain.Chikito, error) {
+			return nil, domain.ErrChikitoNotFound
+		}
+		uc := NewChikitoUsecase(repo, nil, nil, "")
+
+		_, err := uc.GetStats(context.Background(), "ch_12345", 0, 0)
+		if !errors.Is(err, domain.ErrChikitoNotFound) {
+			t.Errorf("Expected ErrChikitoNotFound, got %v", err)
+		}
+	})
+}
@@ -26,7 +26,7 @@ func TestGet(t *testing.T) {
 		repo.GetChikitoFn = func(ctx context.Context, id string) (*domain.Chikito, error) {
 			return want, nil
 		}
-		uc := NewChikitoUsecase(repo)
+		uc := NewChikitoUsecase(repo, nil, nil, "")
 
 		got, err := uc.Get(context.Background(), "ch_12345")
 		if err != nil {
@@ -38,12 +38,42 @@ func TestGet(t *testing.T) {
 		}
 	})
 
+	t.Run("it should look up the chikito regardless of the case", func(t *testing.T) {
+		repo := &mocks.MockChikitosRepository{}
+		repo.GetChikitoFn = func(ctx context.Context, id string) (*domain.Chikito, error) {
+			if id != "talk-gophercon" {
+				return nil, domain.ErrChikitoNotFound
+			}
+
+			return &domain.Chikito{PublicID: id}, nil
+		}
+		uc := NewChikitoUsecase(repo, nil, nil, "")
+
+		_, err := uc.Get(context.Background(), "Talk-GopherCon")
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("it should return a gone error when the chikito no longer redirects", func(t *testing.T) {
+		repo := &mocks.MockChikitosRepository{}
+		repo.GetChikitoFn = func(ctx context.Context, id string) (*domain.Chikito, error) {
+			return &domain.Chikito{PublicID: id, ExpiresAt: time.Now().Add(-time.Minute)}, nil
+		}
+		uc := NewChikitoUsecase(repo, nil, nil, "")
+
+		_, err := uc.Get(context.Background(), "ch_12345")
+		if !errors.Is(err, domain.ErrChikitoGone) {
+			t.Errorf("Expected ErrChikitoGone error, got: %v", err)
+		}
+	})
+
 	t.Run("it should return a not found error", func(t *testing.T) {
 		repo := &mocks.MockChikitosRepository{}
 		repo.GetChikitoFn = func(ctx context.Context, id string) (*domain.Chikito, error) {
 			return nil, domain.ErrChikitoNotFound
 		}
-		uc := NewChikitoUsecase(repo)
+		uc := NewChikitoUsecase(repo, nil, nil, "")
 
 		_, err := uc.Get(context.Background(), "ch_12345")
 		if err == nil {
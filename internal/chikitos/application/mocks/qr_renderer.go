@@ -0,0 +1,11 @@
+package mocks
+
+import "github.com/yavurb/goyurback/internal/chikitos/domain"
+
+type MockQRRenderer struct {
+	RenderFn func(content string, options *domain.QROptions) ([]byte, error)
+}
+
+func (m *MockQRRenderer) Render(content string, options *domain.QROptions) ([]byte, error) {
+	return m.RenderFn(content, options)
+}
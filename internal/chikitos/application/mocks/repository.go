@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/yavurb/goyurback/internal/chikitos/domain"
 )
@@ -9,6 +10,12 @@ import (
 type MockChikitosRepository struct {
 	CreateChikitoFn func(ctx context.Context, chikito *domain.ChikitoCreate) (*domain.Chikito, error)
 	GetChikitoFn    func(ctx context.Context, id string) (*domain.Chikito, error)
+	GetChikitosFn   func(ctx context.Context) ([]*domain.Chikito, error)
+	UpdateChikitoFn func(ctx context.Context, chikito *domain.Chikito) (*domain.Chikito, error)
+	DeleteChikitoFn func(ctx context.Context, id string) error
+	ClaimClickFn    func(ctx context.Context, chikitoID int32) error
+	CreateClicksFn  func(ctx context.Context, clicks []*domain.Click) error
+	GetClickStatsFn func(ctx context.Context, chikitoID int32, dailySince, hourlySince time.Time) (*domain.ClickStats, error)
 }
 
 func (m *MockChikitosRepository) CreateChikito(ctx context.Context, chikito *domain.ChikitoCreate) (*domain.Chikito, error) {
@@ -18,3 +25,27 @@ func (m *MockChikitosRepository) CreateChikito(ctx context.Context, chikito *dom
 func (m *MockChikitosRepository) GetChikito(ctx context.Context, id string) (*domain.Chikito, error) {
 	return m.GetChikitoFn(ctx, id)
 }
+
+func (m *MockChikitosRepository) GetChikitos(ctx context.Context) ([]*domain.Chikito, error) {
+	return m.GetChikitosFn(ctx)
+}
+
+func (m *MockChikitosRepository) CreateClicks(ctx context.Context, clicks []*domain.Click) error {
+	return m.CreateClicksFn(ctx, clicks)
+}
+
+func (m *MockChikitosRepository) GetClickStats(ctx context.Context, chikitoID int32, dailySince, hourlySince time.Time) (*domain.ClickStats, error) {
+	return m.GetClickStatsFn(ctx, chikitoID, dailySince, hourlySince)
+}
+
+func (m *MockChikitosRepository) UpdateChikito(ctx context.Context, chikito *domain.Chikito) (*domain.Chikito, error) {
+	return m.UpdateChikitoFn(ctx, chikito)
+}
+
+func (m *MockChikitosRepository) DeleteChikito(ctx context.Context, id string) error {
+	return m.DeleteChikitoFn(ctx, id)
+}
+
+func (m *MockChikitosRepository) ClaimClick(ctx context.Context, chikitoID int32) error {
+	return m.ClaimClickFn(ctx, chikitoID)
+}
@@ -0,0 +1,77 @@
+package application
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/yavurb/goyurback/internal/chikitos/domain"
+)
+
+func (uc *ChikitoUsecase) Update(ctx context.Context, id string, update *domain.ChikitoUpdate) (*domain.Chikito, error) {
+	// Only the given limits are checked, so a chikito that already expired can still be
+	// updated, like to disable it, without extending it
+	var expiresAt time.Time
+	if update.ExpiresAt != nil {
+		expiresAt = *update.ExpiresAt
+	}
+
+	var maxClicks int32
+	if update.MaxClicks != nil {
+		maxClicks = *update.MaxClicks
+	}
+
+	if err := validateLimits(expiresAt, maxClicks); err != nil {
+		return nil, err
+	}
+
+	if update.RedirectCode != nil && !domain.ValidRedirectCode(*update.RedirectCode) {
+		return nil, domain.ErrInvalidRedirectCode
+	}
+
+	chikito, err := uc.getChikito(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.URL != nil {
+		chikito.URL = *update.URL
+	}
+
+	if update.Description != nil {
+		chikito.Description = *update.Description
+	}
+
+	if update.ExpiresAt != nil {
+		chikito.ExpiresAt = update.ExpiresAt.UTC() // Dates are stored without a time zone, in UTC
+	}
+
+	if update.MaxClicks != nil {
+		chikito.MaxClicks = *update.MaxClicks
+	}
+
+	if update.Disabled != nil {
+		chikito.Disabled = *update.Disabled
+	}
+
+	if update.RedirectCode != nil {
+		chikito.RedirectCode = *update.RedirectCode
+	}
+
+	if update.ForwardQuery != nil {
+		chikito.ForwardQuery = *update.ForwardQuery
+	}
+
+	if update.UTM != nil {
+		chikito.UTM = *update.UTM
+	}
+
+	chikitoUpdated, err := uc.repository.UpdateChikito(ctx, chikito)
+	if err != nil {
+		log.Printf("Error updating chikito. Got: %v\n", err)
+
+		return nil, err
+	}
+
+	return chikitoUpdated, nil
+}
@@ -0,0 +1,149 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/yavurb/goyurback/internal/chikitos/application/mocks"
+	"github.com/yavurb/goyurback/internal/chikitos/domain"
+)
+
+func TestUpdate(t *testing.T) {
+	expiresAt := time.Now().UTC().Add(24 * time.Hour)
+
+	t.Run("it should only change the given fields", func(t *testing.T) {
+		repo := &mocks.MockChikitosRepository{}
+		repo.GetChikitoFn = func(ctx context.Context, id string) (*domain.Chikito, error) {
+			return &domain.Chikito{
+				ID:          1,
+				PublicID:    id,
+				URL:         "https://example.com",
+				Description: "Registrations",
+				MaxClicks:   10,
+				Disabled:    true,
+			}, nil
+		}
+		repo.UpdateChikitoFn = func(ctx context.Context, chikito *domain.Chikito) (*domain.Chikito, error) {
+			return chikito, nil
+		}
+		uc := NewChikitoUsecase(repo, nil, nil, "")
+
+		url := "https://example.com/registrations"
+		disabled := false
+		redirectCode := int32(302)
+		utm := domain.UTM{Source: "newsletter"}
+
+		got, err := uc.Update(context.Background(), "ch_12345", &domain.ChikitoUpdate{
+			ExpiresAt:    &expiresAt,
+			UTM:          &utm,
+			URL:          &url,
+			RedirectCode: &redirectCode,
+			Disabled:     &disabled,
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		want := &domain.Chikito{
+			ExpiresAt:    expiresAt,
+			UTM:          domain.UTM{Source: "newsletter"},
+			ID:           1,
+			PublicID:     "ch_12345",
+			URL:          "https://example.com/registrations",
+			Description:  "Registrations",
+			MaxClicks:    10,
+			RedirectCode: 302,
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("Mismatch updating chikito (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("it should update a chikito that no longer redirects", func(t *testing.T) {
+		repo := &mocks.MockChikitosRepository{}
+		repo.GetChikitoFn = func(ctx context.Context, id string) (*domain.Chikito, error) {
+			return &domain.Chikito{PublicID: id, ExpiresAt: time.Now().Add(-time.Hour)}, nil
+		}
+		repo.UpdateChikitoFn = func(ctx context.Context, chikito *domain.Chikito) (*domain.Chikito, error) {
+			return chikito, nil
+		}
+		uc := NewChikitoUsecase(repo, nil, nil, "")
+
+		disabled := true
+
+		if _, err := uc.Update(context.Background(), "ch_12345", &domain.ChikitoUpdate{Disabled: &disabled}); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("it should store the expiry in UTC", func(t *testing.T) {
+		repo := &mocks.MockChikitosRepository{}
+		repo.GetChikitoFn = func(ctx context.Context, id string) (*domain.Chikito, error) {
+			return &domain.Chikito{PublicID: id}, nil
+		}
+		repo.UpdateChikitoFn = func(ctx context.Context, chikito *domain.Chikito) (*domain.Chikito, error) {
+			return chikito, nil
+		}
+		uc := NewChikitoUsecase(repo, nil, nil, "")
+
+		localExpiresAt := expiresAt.In(time.FixedZone("", -5*60*60))
+
+		got, err := uc.Update(context.Background(), "ch_12345", &domain.ChikitoUpdate{ExpiresAt: &localExpiresAt})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if got.ExpiresAt.Location() != time.UTC || !got.ExpiresAt.Equal(expiresAt) {
+			t.Errorf("Expected the chikito to expire at %v, got %v", expiresAt, got.ExpiresAt)
+		}
+	})
+
+	t.Run("it should reject an expiry in the past", func(t *testing.T) {
+		uc := NewChikitoUsecase(&mocks.MockChikitosRepository{}, nil, nil, "")
+
+		past := time.Now().Add(-time.Hour)
+
+		_, err := uc.Update(context.Background(), "ch_12345", &domain.ChikitoUpdate{ExpiresAt: &past})
+		if !errors.Is(err, domain.ErrInvalidExpiry) {
+			t.Errorf("Expected ErrInvalidExpiry, got %v", err)
+		}
+	})
+
+	t.Run("it should reject negative max clicks", func(t *testing.T) {
+		uc := NewChikitoUsecase(&mocks.MockChikitosRepository{}, nil, nil, "")
+
+		maxClicks := int32(-1)
+
+		_, err := uc.Update(context.Background(), "ch_12345", &domain.ChikitoUpdate{MaxClicks: &maxClicks})
+		if !errors.Is(err, domain.ErrInvalidMaxClicks) {
+			t.Errorf("Expected ErrInvalidMaxClicks, got %v", err)
+		}
+	})
+
+	t.Run("it should reject an invalid redirect code", func(t *testing.T) {
+		uc := NewChikitoUsecase(&mocks.MockChikitosRepository{}, nil, nil, "")
+
+		redirectCode := int32(303)
+
+		_, err := uc.Update(context.Background(), "ch_12345", &domain.ChikitoUpdate{RedirectCode: &redirectCode})
+		if !errors.Is(err, domain.ErrInvalidRedirectCode) {
+			t.Errorf("Expected ErrInvalidRedirectCode, got %v", err)
+		}
+	})
+
+	t.Run("it should return a not found error", func(t *testing.T) {
+		repo := &mocks.MockChikitosRepository{}
+		repo.GetChikitoFn = func(ctx context.Context, id string) (*domain.Chikito, error) {
+			return nil, domain.ErrChikitoNotFound
+		}
+		uc := NewChikitoUsecase(repo, nil, nil, "")
+
+		_, err := uc.Update(context.Background(), "ch_12345", &domain.ChikitoUpdate{})
+		if !errors.Is(err, domain.ErrChikitoNotFound) {
+			t.Errorf("Expected ErrChikitoNotFound, got %v", err)
+		}
+	})
+}
@@ -1,13 +1,30 @@
 package application
 
 import (
+	"strings"
+
 	"github.com/yavurb/goyurback/internal/chikitos/domain"
 )
 
 type ChikitoUsecase struct {
-	repository domain.ChikitoRepository
+	repository   domain.ChikitoRepository
+	clickTracker domain.ClickTracker
+	qrRenderer   domain.QRRenderer
+	publicURL    string
 }
 
-func NewChikitoUsecase(repository domain.ChikitoRepository) domain.ChikitoUsecase {
-	return &ChikitoUsecase{repository}
+// NewChikitoUsecase creates the chikitos usecase. Clicks are not tracked when clickTracker is nil,
+// and QR codes are not rendered when publicURL, the base URL of the short links, is empty.
+func NewChikitoUsecase(
+	repository domain.ChikitoRepository,
+	clickTracker domain.ClickTracker,
+	qrRenderer domain.QRRenderer,
+	publicURL string,
+) domain.ChikitoUsecase {
+	return &ChikitoUsecase{
+		repository:   repository,
+		clickTracker: clickTracker,
+		qrRenderer:   qrRenderer,
+		publicURL:    strings.TrimSuffix(publicURL, "/"),
+	}
 }
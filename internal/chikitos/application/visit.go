@@ -0,0 +1,29 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/yavurb/goyurback/internal/chikitos/domain"
+)
+
+func (uc *ChikitoUsecase) Visit(ctx context.Context, id string, visitor *domain.Visitor) (*domain.Chikito, error) {
+	chikito, err := uc.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// The clicks of the limited chikitos are counted before redirecting, so concurrent visits
+	// can't go past the limit
+	if chikito.MaxClicks > 0 {
+		if err := uc.repository.ClaimClick(ctx, chikito.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if uc.clickTracker != nil {
+		uc.clickTracker.Track(domain.NewClick(chikito.ID, visitor, time.Now().UTC()))
+	}
+
+	return chikito, nil
+}
@@ -0,0 +1,118 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yavurb/goyurback/internal/chikitos/application/mocks"
+	"github.com/yavurb/goyurback/internal/chikitos/domain"
+)
+
+type mockClickTracker struct {
+	clicks []*domain.Click
+}
+
+func (m *mockClickTracker) Track(click *domain.Click) {
+	m.clicks = append(m.clicks, click)
+}
+
+func TestVisit(t *testing.T) {
+	t.Run("it should track the click of the chikito", func(t *testing.T) {
+		repo := &mocks.MockChikitosRepository{}
+		repo.GetChikitoFn = func(ctx context.Context, id string) (*domain.Chikito, error) {
+			return &domain.Chikito{ID: 7, PublicID: id, URL: "https://example.com"}, nil
+		}
+		tracker := &mockClickTracker{}
+		uc := NewChikitoUsecase(repo, tracker, nil, "")
+
+		chikito, err := uc.Visit(context.Background(), "ch_12345", &domain.Visitor{
+			IP:        "203.0.113.42",
+			Referrer:  "https://t.co/abc",
+			UserAgent: "curl/8.5.0",
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if chikito.URL != "https://example.com" {
+			t.Errorf("Expected the chikito to be returned, got %+v", chikito)
+		}
+
+		if len(tracker.clicks) != 1 {
+			t.Fatalf("Expected one click to be tracked, got %d", len(tracker.clicks))
+		}
+
+		click := tracker.clicks[0]
+		if click.ChikitoID != 7 || click.ReferrerHost != "t.co" || click.UserAgentFamily != domain.UserAgentCurl {
+			t.Errorf("Unexpected click tracked: %+v", click)
+		}
+	})
+
+	t.Run("it should count the click of a chikito with max clicks", func(t *testing.T) {
+		var claimed int32
+
+		repo := &mocks.MockChikitosRepository{}
+		repo.GetChikitoFn = func(ctx context.Context, id string) (*domain.Chikito, error) {
+			return &domain.Chikito{ID: 7, PublicID: id, MaxClicks: 10, Clicks: 9}, nil
+		}
+		repo.ClaimClickFn = func(ctx context.Context, chikitoID int32) error {
+			claimed = chikitoID
+
+			return nil
+		}
+		tracker := &mockClickTracker{}
+		uc := NewChikitoUsecase(repo, tracker, nil, "")
+
+		if _, err := uc.Visit(context.Background(), "ch_12345", &domain.Visitor{}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if claimed != 7 {
+			t.Errorf("Expected the click of chikito 7 to be counted, got %d", claimed)
+		}
+
+		if len(tracker.clicks) != 1 {
+			t.Errorf("Expected one click to be tracked, got %d", len(tracker.clicks))
+		}
+	})
+
+	t.Run("it should return a gone error when another visit took the last click", func(t *testing.T) {
+		repo := &mocks.MockChikitosRepository{}
+		repo.GetChikitoFn = func(ctx context.Context, id string) (*domain.Chikito, error) {
+			return &domain.Chikito{ID: 7, PublicID: id, MaxClicks: 10, Clicks: 9}, nil
+		}
+		repo.ClaimClickFn = func(ctx context.Context, chikitoID int32) error {
+			return domain.ErrChikitoGone
+		}
+		tracker := &mockClickTracker{}
+		uc := NewChikitoUsecase(repo, tracker, nil, "")
+
+		_, err := uc.Visit(context.Background(), "ch_12345", &domain.Visitor{})
+		if !errors.Is(err, domain.ErrChikitoGone) {
+			t.Errorf("Expected ErrChikitoGone, got %v", err)
+		}
+
+		if len(tracker.clicks) != 0 {
+			t.Errorf("Expected no clicks to be tracked, got %d", len(tracker.clicks))
+		}
+	})
+
+	t.Run("it should not track the click of a missing chikito", func(t *testing.T) {
+		repo := &mocks.MockChikitosRepository{}
+		repo.GetChikitoFn = func(ctx context.Context, id string) (*domain.Chikito, error) {
+			return nil, domain.ErrChikitoNotFound
+		}
+		tracker := &mockClickTracker{}
+		uc := NewChikitoUsecase(repo, tracker, nil, "")
+
+		_, err := uc.Visit(context.Background(), "ch_12345", &domain.Visitor{})
+		if !errors.Is(err, domain.ErrChikitoNotFound) {
+			t.Errorf("Expected ErrChikitoNotFound, got %v", err)
+		}
+
+		if len(tracker.clicks) != 0 {
+			t.Errorf("Expected no clicks to be tracked, got %d", len(tracker.clicks))
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	AliasMinLength = 3
+	AliasMaxLength = 64
+)
+
+// aliasFormat allows lowercase letters, digits and single dashes between them. Generated public
+// ids contain an underscore, so aliases can't collide with them.
+var aliasFormat = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// ReservedAliases can't be used as aliases, as they name routes or could be mistaken for them.
+var ReservedAliases = []string{
+	"admin",
+	"api",
+	"auth",
+	"chikitos",
+	"delete",
+	"edit",
+	"health",
+	"new",
+	"posts",
+	"projects",
+	"qr",
+	"search",
+	"stats",
+}
+
+// NormalizeAlias lowercases the alias and checks that it can be used as the public id of a
+// chikito.
+func NormalizeAlias(alias string) (string, error) {
+	alias = strings.ToLower(strings.TrimSpace(alias))
+
+	if len(alias) < AliasMinLength || len(alias) > AliasMaxLength || !aliasFormat.MatchString(alias) {
+		return "", ErrInvalidAlias
+	}
+
+	if slices.Contains(ReservedAliases, alias) {
+		return "", ErrReservedAlias
+	}
+
+	return alias, nil
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeAlias(t *testing.T) {
+	cases := []struct {
+		alias   string
+		want    string
+		wantErr error
+	}{
+		{alias: "talk-gophercon", want: "talk-gophercon"},
+		{alias: " Talk-GopherCon ", want: "talk-gophercon"},
+		{alias: "go2024", want: "go2024"},
+		{alias: "go", wantErr: ErrInvalidAlias},
+		{alias: strings.Repeat("a", AliasMaxLength+1), wantErr: ErrInvalidAlias},
+		{alias: "talk_gophercon", wantErr: ErrInvalidAlias},
+		{alias: "talk--gophercon", wantErr: ErrInvalidAlias},
+		{alias: "-talk", wantErr: ErrInvalidAlias},
+		{alias: "talk/gophercon", wantErr: ErrInvalidAlias},
+		{alias: "café", wantErr: ErrInvalidAlias},
+		{alias: "Admin", wantErr: ErrReservedAlias},
+		{alias: "qr", wantErr: ErrInvalidAlias},
+		{alias: "stats", wantErr: ErrReservedAlias},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.alias, func(t *testing.T) {
+			got, err := NormalizeAlias(tc.alias)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Expected error to be %v. Got: %v", tc.wantErr, err)
+			}
+
+			if got != tc.want {
+				t.Errorf("Expected alias to be %q. Got: %q", tc.want, got)
+			}
+		})
+	}
+}
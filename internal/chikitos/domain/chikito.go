@@ -7,12 +7,19 @@ import (
 )
 
 type Chikito struct {
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	PublicID    string
-	URL         string
-	Description string
-	ID          int32
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	ExpiresAt    time.Time // Zero when the chikito never expires
+	UTM          UTM
+	PublicID     string
+	URL          string
+	Description  string
+	ID           int32
+	MaxClicks    int32 // Zero when the clicks are not limited
+	Clicks       int32 // Redirects counted against MaxClicks. Only counted when it is set
+	RedirectCode int32
+	Disabled     bool
+	ForwardQuery bool
 }
 
 func (c Chikito) Compare(c2 Chikito) bool {
@@ -22,8 +29,45 @@ func (c Chikito) Compare(c2 Chikito) bool {
 	return cmp.Equal(c, c2)
 }
 
+// Gone reports whether the chikito no longer redirects, as it is disabled, expired or has
+// reached its max clicks.
+func (c Chikito) Gone(now time.Time) bool {
+	expired := !c.ExpiresAt.IsZero() && !c.ExpiresAt.After(now)
+	outOfClicks := c.MaxClicks > 0 && c.Clicks >= c.MaxClicks
+
+	return c.Disabled || expired || outOfClicks
+}
+
 type ChikitoCreate struct {
-	PublicID    string
-	URL         string
-	Description string
+	ExpiresAt    time.Time
+	UTM          UTM
+	PublicID     string
+	URL          string
+	Description  string
+	MaxClicks    int32
+	RedirectCode int32
+	ForwardQuery bool
+}
+
+// ChikitoOptions are the optional settings of a new chikito. Zero values don't limit the
+// chikito, and a zero RedirectCode uses DefaultRedirectCode.
+type ChikitoOptions struct {
+	ExpiresAt    time.Time
+	UTM          UTM
+	MaxClicks    int32
+	RedirectCode int32
+	ForwardQuery bool
+}
+
+// ChikitoUpdate has the fields to change of a chikito, nil for the ones to keep. A zero
+// ExpiresAt removes the expiry, and a zero MaxClicks the limit of clicks.
+type ChikitoUpdate struct {
+	ExpiresAt    *time.Time
+	UTM          *UTM
+	URL          *string
+	Description  *string
+	MaxClicks    *int32
+	RedirectCode *int32
+	Disabled     *bool
+	ForwardQuery *bool
 }
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestChikitoGone(t *testing.T) {
+	now := time.Date(2024, 7, 14, 10, 30, 0, 0, time.UTC)
+
+	cases := []struct {
+		name    string
+		chikito Chikito
+		want    bool
+	}{
+		{name: "without limits", chikito: Chikito{}, want: false},
+		{name: "disabled", chikito: Chikito{Disabled: true}, want: true},
+		{name: "expiring later", chikito: Chikito{ExpiresAt: now.Add(time.Minute)}, want: false},
+		{name: "expired", chikito: Chikito{ExpiresAt: now}, want: true},
+		{name: "with clicks left", chikito: Chikito{MaxClicks: 10, Clicks: 9}, want: false},
+		{name: "out of clicks", chikito: Chikito{MaxClicks: 10, Clicks: 10}, want: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.chikito.Gone(now); got != tc.want {
+				t.Errorf("Gone() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultStatsDays  = 30
+	DefaultStatsHours = 48
+	// TopClickTotals is the number of referrers and user agents reported in the stats
+	TopClickTotals = 10
+)
+
+// User agent families of the clicks. Anything not recognized is recorded as UserAgentOther.
+const (
+	UserAgentBot     = "bot"
+	UserAgentChrome  = "chrome"
+	UserAgentCurl    = "curl"
+	UserAgentEdge    = "edge"
+	UserAgentFirefox = "firefox"
+	UserAgentOpera   = "opera"
+	UserAgentSafari  = "safari"
+	UserAgentOther   = "other"
+	UserAgentUnknown = "unknown"
+)
+
+// Visitor is the request behind a click, as received.
+type Visitor struct {
+	IP        string
+	Referrer  string
+	UserAgent string
+}
+
+// Click is a redirect to the URL of a chikito. It keeps only what is needed for the stats of the
+// chikito, nothing that identifies the visitor.
+type Click struct {
+	ClickedAt       time.Time
+	ReferrerHost    string
+	UserAgentFamily string
+	IPHash          string
+	ChikitoID       int32
+}
+
+func NewClick(chikitoID int32, visitor *Visitor, clickedAt time.Time) *Click {
+	return &Click{
+		ClickedAt:       clickedAt,
+		ReferrerHost:    referrerHost(visitor.Referrer),
+		UserAgentFamily: userAgentFamily(visitor.UserAgent),
+		IPHash:          hashIP(visitor.IP),
+		ChikitoID:       chikitoID,
+	}
+}
+
+// ClickTracker collects the clicks off the redirect path.
+type ClickTracker interface {
+	Track(click *Click)
+}
+
+// ClickCount is the number of clicks in the hour or day starting at Time.
+type ClickCount struct {
+	Time   time.Time
+	Clicks int64
+}
+
+// ClickTotal is the number of clicks from a referrer host or user agent family. An empty Name
+// stands for the clicks without referrer.
+type ClickTotal struct {
+	Name   string
+	Clicks int64
+}
+
+type ClickStats struct {
+	Referrers  []ClickTotal
+	UserAgents []ClickTotal
+	Daily      []ClickCount
+	Hourly     []ClickCount
+	Total      int64
+}
+
+func referrerHost(referrer string) string {
+	referrerURL, err := url.Parse(referrer)
+	if err != nil {
+		return ""
+	}
+
+	host := strings.ToLower(referrerURL.Hostname())
+	if len(host) > 255 {
+		return ""
+	}
+
+	return host
+}
+
+func userAgentFamily(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+
+	switch {
+	case ua == "":
+		return UserAgentUnknown
+	case strings.Contains(ua, "bot"), strings.Contains(ua, "crawler"), strings.Contains(ua, "spider"),
+		strings.Contains(ua, "facebookexternalhit"), strings.Contains(ua, "preview"):
+		return UserAgentBot
+	case strings.HasPrefix(ua, "curl/"):
+		return UserAgentCurl
+	case strings.Contains(ua, "edg/"), strings.Contains(ua, "edga/"), strings.Contains(ua, "edgios/"):
+		return UserAgentEdge
+	case strings.Contains(ua, "opr/"), strings.Contains(ua, "opera"):
+		return UserAgentOpera
+	case strings.Contains(ua, "firefox/"), strings.Contains(ua, "fxios/"):
+		return UserAgentFirefox
+	case strings.Contains(ua, "chrome/"), strings.Contains(ua, "crios/"), strings.Contains(ua, "chromium/"):
+		return UserAgentChrome
+	case strings.Contains(ua, "safari/"):
+		return UserAgentSafari
+	default:
+		return UserAgentOther
+	}
+}
+
+// hashIP truncates the IP to its /24, or /48 for IPv6, network and returns the first 16 hex
+// characters of its SHA-256 hash.
+func hashIP(ip string) string {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return ""
+	}
+
+	addr = addr.Unmap()
+
+	bits := 24
+	if addr.Is6() {
+		bits = 48
+	}
+
+	prefix, err := addr.Prefix(bits)
+	if err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(prefix.String()))
+
+	return hex.EncodeToString(sum[:])[:16]
+}
@@ -5,4 +5,12 @@ import "errors"
 var (
 	ErrChikitoNotFound       = errors.New("no chikito was found")
 	ErrPublicIDAlreadyExists = errors.New("public id already exists")
+	ErrInvalidAlias          = errors.New("alias must be 3 to 64 lowercase letters, digits or dashes")
+	ErrReservedAlias         = errors.New("alias is reserved")
+	ErrChikitoGone           = errors.New("chikito is expired, disabled or out of clicks")
+	ErrInvalidExpiry         = errors.New("expiry must be in the future")
+	ErrInvalidMaxClicks      = errors.New("max clicks must not be negative")
+	ErrInvalidRedirectCode   = errors.New("redirect code must be 301, 302, 307 or 308")
+	ErrInvalidQROptions      = errors.New("invalid QR code options")
+	ErrQRCodesDisabled       = errors.New("QR codes need the public URL of the chikitos")
 )
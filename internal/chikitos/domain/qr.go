@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"encoding/hex"
+	"image/color"
+	"strings"
+)
+
+type QRFormat string
+
+const (
+	QRFormatPNG QRFormat = "png"
+	QRFormatSVG QRFormat = "svg"
+)
+
+func (f QRFormat) ContentType() string {
+	if f == QRFormatSVG {
+		return "image/svg+xml"
+	}
+
+	return "image/png"
+}
+
+// QRLevel is the error correction level of a QR code. Higher levels can be read when more of
+// the code is damaged or covered, but need more modules.
+type QRLevel string
+
+const (
+	QRLevelLow      QRLevel = "L"
+	QRLevelMedium   QRLevel = "M"
+	QRLevelQuartile QRLevel = "Q"
+	QRLevelHigh     QRLevel = "H"
+)
+
+const (
+	QRMinSize = 64
+	QRMaxSize = 2048
+	// QRMaxMargin is in modules. Scanners expect a quiet zone of 4 modules around the code.
+	QRMaxMargin = 16
+
+	DefaultQRSize   = 512
+	DefaultQRMargin = 4
+)
+
+// QROptions describes how a QR code is rendered. Size is the width and height of the image in
+// pixels, and Margin the quiet zone around the code in modules.
+type QROptions struct {
+	Foreground color.RGBA
+	Background color.RGBA
+	Format     QRFormat
+	Level      QRLevel
+	Size       int
+	Margin     int
+}
+
+// DefaultQROptions returns the options of a black on white PNG that can be printed as is.
+func DefaultQROptions() *QROptions {
+	return &QROptions{
+		Foreground: color.RGBA{A: 0xff},
+		Background: color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+		Format:     QRFormatPNG,
+		Level:      QRLevelMedium,
+		Size:       DefaultQRSize,
+		Margin:     DefaultQRMargin,
+	}
+}
+
+func (o *QROptions) Validate() error {
+	if o.Format != QRFormatPNG && o.Format != QRFormatSVG {
+		return ErrInvalidQROptions
+	}
+
+	switch o.Level {
+	case QRLevelLow, QRLevelMedium, QRLevelQuartile, QRLevelHigh:
+	default:
+		return ErrInvalidQROptions
+	}
+
+	if o.Size < QRMinSize || o.Size > QRMaxSize || o.Margin < 0 || o.Margin > QRMaxMargin {
+		return ErrInvalidQROptions
+	}
+
+	return nil
+}
+
+// ParseHexColor parses colors like `1a2b3c` or `#1a2b3c`. The `#` is optional, as it has to be
+// escaped in query strings.
+func ParseHexColor(value string) (color.RGBA, error) {
+	value = strings.TrimPrefix(value, "#")
+
+	if len(value) != 6 {
+		return color.RGBA{}, ErrInvalidQROptions
+	}
+
+	rgb, err := hex.DecodeString(value)
+	if err != nil {
+		return color.RGBA{}, ErrInvalidQROptions
+	}
+
+	return color.RGBA{R: rgb[0], G: rgb[1], B: rgb[2], A: 0xff}, nil
+}
+
+// QRRenderer renders content as a QR code image.
+type QRRenderer interface {
+	Render(content string, options *QROptions) ([]byte, error)
+}
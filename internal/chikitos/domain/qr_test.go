@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"errors"
+	"image/color"
+	"testing"
+)
+
+func TestParseHexColor(t *testing.T) {
+	cases := []struct {
+		value   string
+		want    color.RGBA
+		wantErr error
+	}{
+		{value: "1a2b3c", want: color.RGBA{R: 0x1a, G: 0x2b, B: 0x3c, A: 0xff}},
+		{value: "#FFFFFF", want: color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}},
+		{value: "fff", wantErr: ErrInvalidQROptions},
+		{value: "zzzzzz", wantErr: ErrInvalidQROptions},
+		{value: "", wantErr: ErrInvalidQROptions},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.value, func(t *testing.T) {
+			got, err := ParseHexColor(tc.value)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Expected error to be %v. Got: %v", tc.wantErr, err)
+			}
+
+			if got != tc.want {
+				t.Errorf("Expected color to be %v. Got: %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestQROptionsValidate(t *testing.T) {
+	cases := []struct {
+		name   string
+		modify func(o *QROptions)
+		valid  bool
+	}{
+		{name: "defaults", modify: func(o *QROptions) {}, valid: true},
+		{name: "without margin", modify: func(o *QROptions) { o.Margin = 0 }, valid: true},
+		{name: "SVG with H level", modify: func(o *QROptions) { o.Format, o.Level = QRFormatSVG, QRLevelHigh }, valid: true},
+		{name: "unknown format", modify: func(o *QROptions) { o.Format = "gif" }},
+		{name: "unknown level", modify: func(o *QROptions) { o.Level = "X" }},
+		{name: "too small", modify: func(o *QROptions) { o.Size = QRMinSize - 1 }},
+		{name: "too large", modify: func(o *QROptions) { o.Size = QRMaxSize + 1 }},
+		{name: "negative margin", modify: func(o *QROptions) { o.Margin = -1 }},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			options := DefaultQROptions()
+			tc.modify(options)
+
+			if err := options.Validate(); (err == nil) != tc.valid {
+				t.Errorf("Expected valid to be %v. Got: %v", tc.valid, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+)
+
+// DefaultRedirectCode is a temporary redirect, so browsers don't cache the destination and
+// follow it when it changes.
+const DefaultRedirectCode int32 = http.StatusTemporaryRedirect
+
+var RedirectCodes = []int32{
+	http.StatusMovedPermanently,
+	http.StatusFound,
+	http.StatusTemporaryRedirect,
+	http.StatusPermanentRedirect,
+}
+
+func ValidRedirectCode(code int32) bool {
+	return slices.Contains(RedirectCodes, code)
+}
+
+// UTM has the UTM parameters added to the destination of a chikito. Empty ones are not added.
+type UTM struct {
+	Source   string `json:"source,omitempty"`
+	Medium   string `json:"medium,omitempty"`
+	Campaign string `json:"campaign,omitempty"`
+	Term     string `json:"term,omitempty"`
+	Content  string `json:"content,omitempty"`
+}
+
+func (u UTM) params() [][2]string {
+	return [][2]string{
+		{"utm_source", u.Source},
+		{"utm_medium", u.Medium},
+		{"utm_campaign", u.Campaign},
+		{"utm_term", u.Term},
+		{"utm_content", u.Content},
+	}
+}
+
+// Destination returns the URL to redirect a visit with the given query to. The parameters
+// already in the URL of the chikito are never overridden. The UTM parameters are added next,
+// then the query of the visit when the chikito forwards it.
+func (c Chikito) Destination(query url.Values) string {
+	destination, err := url.Parse(c.URL)
+	if err != nil {
+		return c.URL
+	}
+
+	params := destination.Query()
+	added := false
+
+	for _, param := range c.UTM.params() {
+		if param[1] != "" && !params.Has(param[0]) {
+			params.Set(param[0], param[1])
+			added = true
+		}
+	}
+
+	if c.ForwardQuery {
+		for key, values := range query {
+			if !params.Has(key) {
+				params[key] = values
+				added = true
+			}
+		}
+	}
+
+	// The URL is kept as given unless parameters were added
+	if !added {
+		return c.URL
+	}
+
+	destination.RawQuery = params.Encode()
+
+	return destination.String()
+}
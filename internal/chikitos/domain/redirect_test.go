@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestChikitoDestination(t *testing.T) {
+	query := url.Values{"ref": {"slides"}, "lang": {"es"}, "utm_source": {"twitter"}}
+
+	cases := []struct {
+		name    string
+		chikito Chikito
+		want    string
+	}{
+		{
+			name:    "without parameters to add",
+			chikito: Chikito{URL: "https://example.com/talk?b=2&a=1"},
+			want:    "https://example.com/talk?b=2&a=1",
+		},
+		{
+			name:    "with UTM parameters",
+			chikito: Chikito{URL: "https://example.com/talk#slides", UTM: UTM{Source: "chikitos", Medium: "qr"}},
+			want:    "https://example.com/talk?utm_medium=qr&utm_source=chikitos#slides",
+		},
+		{
+			name:    "keeping the parameters of the URL",
+			chikito: Chikito{URL: "https://example.com/talk?utm_source=newsletter", UTM: UTM{Source: "chikitos"}},
+			want:    "https://example.com/talk?utm_source=newsletter",
+		},
+		{
+			name:    "forwarding the query",
+			chikito: Chikito{URL: "https://example.com/talk?lang=en", ForwardQuery: true},
+			want:    "https://example.com/talk?lang=en&ref=slides&utm_source=twitter",
+		},
+		{
+			name:    "forwarding the query after the UTM parameters",
+			chikito: Chikito{URL: "https://example.com/talk", ForwardQuery: true, UTM: UTM{Source: "chikitos"}},
+			want:    "https://example.com/talk?lang=es&ref=slides&utm_source=chikitos",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.chikito.Destination(query); got != tc.want {
+				t.Errorf("Destination() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -1,8 +1,24 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type ChikitoRepository interface {
 	CreateChikito(ctx context.Context, chikito *ChikitoCreate) (*Chikito, error)
 	GetChikito(ctx context.Context, id string) (*Chikito, error)
+	GetChikitos(ctx context.Context) ([]*Chikito, error)
+	// UpdateChikito writes the url, description, expiry, max clicks and disabled state of the
+	// chikito with chikito.PublicID.
+	UpdateChikito(ctx context.Context, chikito *Chikito) (*Chikito, error)
+	DeleteChikito(ctx context.Context, id string) error
+	// ClaimClick counts a click of the chikito against its max clicks. It returns ErrChikitoGone
+	// when the chikito has reached them.
+	ClaimClick(ctx context.Context, chikitoID int32) error
+	// CreateClicks skips the clicks of the chikitos that no longer exist.
+	CreateClicks(ctx context.Context, clicks []*Click) error
+	// GetClickStats returns the clicks of the chikito. The series only have the days and hours
+	// with clicks since dailySince and hourlySince.
+	GetClickStats(ctx context.Context, chikitoID int32, dailySince, hourlySince time.Time) (*ClickStats, error)
 }
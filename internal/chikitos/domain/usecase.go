@@ -5,6 +5,22 @@ import (
 )
 
 type ChikitoUsecase interface {
-	Create(ctx context.Context, url, description string) (*Chikito, error)
+	// Create creates a chikito redirecting to url. Its public id is the alias, when given, or a
+	// random id otherwise. A nil options uses the defaults.
+	Create(ctx context.Context, url, description, alias string, options *ChikitoOptions) (*Chikito, error)
+	// Get returns the chikito with the given public id, regardless of its case. It returns
+	// ErrChikitoGone when the chikito no longer redirects.
 	Get(ctx context.Context, id string) (*Chikito, error)
+	Update(ctx context.Context, id string, update *ChikitoUpdate) (*Chikito, error)
+	Delete(ctx context.Context, id string) error
+	GetChikitos(ctx context.Context) ([]*Chikito, error)
+	// Visit returns the chikito like Get, and tracks the click of the visitor. The click is
+	// counted right away when the chikito has max clicks.
+	Visit(ctx context.Context, id string, visitor *Visitor) (*Chikito, error)
+	// GetStats returns the clicks of the chikito, with the series of the last days and hours.
+	// Zero days or hours use DefaultStatsDays and DefaultStatsHours.
+	GetStats(ctx context.Context, id string, days, hours int) (*ClickStats, error)
+	// GetQRCode renders a QR code with the public URL of the chikito. A nil options uses
+	// DefaultQROptions.
+	GetQRCode(ctx context.Context, id string, options *QROptions) ([]byte, error)
 }
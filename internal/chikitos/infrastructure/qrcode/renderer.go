@@ -0,0 +1,119 @@
+package qrcode
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+
+	"github.com/skip2/go-qrcode"
+	"github.com/yavurb/goyurback/internal/chikitos/domain"
+)
+
+var recoveryLevels = map[domain.QRLevel]qrcode.RecoveryLevel{
+	domain.QRLevelLow:      qrcode.Low,
+	domain.QRLevelMedium:   qrcode.Medium,
+	domain.QRLevelQuartile: qrcode.High,
+	domain.QRLevelHigh:     qrcode.Highest,
+}
+
+// Renderer renders QR codes as PNG or SVG images. The modules are encoded by go-qrcode, and
+// drawn here so the margin and colors can be chosen.
+type Renderer struct{}
+
+func NewRenderer() domain.QRRenderer {
+	return &Renderer{}
+}
+
+func (r *Renderer) Render(content string, options *domain.QROptions) ([]byte, error) {
+	level, ok := recoveryLevels[options.Level]
+	if !ok {
+		return nil, domain.ErrInvalidQROptions
+	}
+
+	code, err := qrcode.New(content, level)
+	if err != nil {
+		return nil, err
+	}
+
+	code.DisableBorder = true
+	modules := code.Bitmap()
+
+	if options.Format == domain.QRFormatSVG {
+		return renderSVG(modules, options), nil
+	}
+
+	return renderPNG(modules, options)
+}
+
+// renderPNG scales the modules by the largest whole number of pixels that fits in the size and
+// centers the code, as scaling by a fraction blurs the edges of the modules.
+func renderPNG(modules [][]bool, options *domain.QROptions) ([]byte, error) {
+	width := len(modules) + 2*options.Margin
+	scale := max(options.Size/width, 1)
+	size := max(options.Size, width)
+	offset := (size-width*scale)/2 + options.Margin*scale
+
+	palette := color.Palette{options.Background, options.Foreground}
+	img := image.NewPaletted(image.Rect(0, 0, size, size), palette)
+
+	for y, row := range modules {
+		for x, dark := range row {
+			if !dark {
+				continue
+			}
+
+			for py := 0; py < scale; py++ {
+				for px := 0; px < scale; px++ {
+					img.SetColorIndex(offset+x*scale+px, offset+y*scale+py, 1)
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// renderSVG draws the dark modules as a single path in a view box measured in modules, so the
+// image scales to any size without losing sharpness.
+func renderSVG(modules [][]bool, options *domain.QROptions) []byte {
+	width := len(modules) + 2*options.Margin
+
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		options.Size, options.Size, width, width)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="%s"/>`, width, width, hexColor(options.Background))
+	fmt.Fprintf(&buf, `<path fill="%s" d="`, hexColor(options.Foreground))
+
+	for y, row := range modules {
+		for x := 0; x < len(row); x++ {
+			if !row[x] {
+				continue
+			}
+
+			// Consecutive dark modules are drawn as a single rectangle
+			run := 1
+			for x+run < len(row) && row[x+run] {
+				run++
+			}
+
+			fmt.Fprintf(&buf, "M%d %dh%dv1h-%dz", x+options.Margin, y+options.Margin, run, run)
+			x += run - 1
+		}
+	}
+
+	buf.WriteString(`"/></svg>`)
+
+	return buf.Bytes()
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
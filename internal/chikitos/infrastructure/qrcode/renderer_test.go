@@ -0,0 +1,115 @@
+package qrcode
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/skip2/go-qrcode"
+	"github.com/yavurb/goyurback/internal/chikitos/domain"
+)
+
+const content = "https://chk.yurb.dev/talk-gophercon"
+
+// moduleCount returns the width in modules of the code of the content, without margin.
+func moduleCount(t *testing.T, level qrcode.RecoveryLevel) int {
+	t.Helper()
+
+	code, err := qrcode.New(content, level)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code.DisableBorder = true
+
+	return len(code.Bitmap())
+}
+
+func TestRender(t *testing.T) {
+	renderer := NewRenderer()
+
+	t.Run("it should render a PNG of the given size and colors", func(t *testing.T) {
+		options := domain.DefaultQROptions()
+		options.Size = 300
+		options.Foreground = color.RGBA{R: 0x1a, G: 0x2b, B: 0x3c, A: 0xff}
+		options.Background = color.RGBA{R: 0xf0, G: 0xe0, B: 0xd0, A: 0xff}
+
+		got, err := renderer.Render(content, options)
+		if err != nil {
+			t.Fatalf("Render() error = %v, want nil", err)
+		}
+
+		img, err := png.Decode(bytes.NewReader(got))
+		if err != nil {
+			t.Fatalf("Expected a valid PNG. Got: %v", err)
+		}
+
+		if bounds := img.Bounds(); bounds.Dx() != 300 || bounds.Dy() != 300 {
+			t.Fatalf("Expected a 300x300 image. Got: %dx%d", bounds.Dx(), bounds.Dy())
+		}
+
+		// The corners are in the margin, and the center of the top left finder pattern is dark
+		if got := color.RGBAModel.Convert(img.At(0, 0)); got != options.Background {
+			t.Errorf("Expected the margin to be %v. Got: %v", options.Background, got)
+		}
+
+		width := moduleCount(t, qrcode.Medium) + 2*options.Margin
+		scale := 300 / width
+		offset := (300-width*scale)/2 + options.Margin*scale
+		center := offset + 3*scale + scale/2
+		if got := color.RGBAModel.Convert(img.At(center, center)); got != options.Foreground {
+			t.Errorf("Expected the finder pattern to be %v. Got: %v", options.Foreground, got)
+		}
+	})
+
+	t.Run("it should render an SVG", func(t *testing.T) {
+		options := domain.DefaultQROptions()
+		options.Format = domain.QRFormatSVG
+		options.Margin = 0
+		options.Size = 128
+		options.Foreground = color.RGBA{R: 0x1a, G: 0x2b, B: 0x3c, A: 0xff}
+
+		got, err := renderer.Render(content, options)
+		if err != nil {
+			t.Fatalf("Render() error = %v, want nil", err)
+		}
+
+		// The top left finder pattern starts with a run of 7 dark modules
+		width := moduleCount(t, qrcode.Medium)
+		viewBox := fmt.Sprintf(`viewBox="0 0 %d %d"`, width, width)
+
+		svg := string(got)
+		for _, want := range []string{`width="128"`, viewBox, `fill="#ffffff"`, `fill="#1a2b3c"`, "M0 0h7v1h-7z"} {
+			if !strings.Contains(svg, want) {
+				t.Errorf("Expected the SVG to contain %q. Got: %s", want, svg)
+			}
+		}
+	})
+
+	t.Run("it should use the given error correction level", func(t *testing.T) {
+		levels := map[domain.QRLevel]qrcode.RecoveryLevel{
+			domain.QRLevelLow:  qrcode.Low,
+			domain.QRLevelHigh: qrcode.Highest,
+		}
+
+		for level, recoveryLevel := range levels {
+			options := domain.DefaultQROptions()
+			options.Format = domain.QRFormatSVG
+			options.Margin = 0
+			options.Level = level
+
+			got, err := renderer.Render(content, options)
+			if err != nil {
+				t.Fatalf("Render() error = %v, want nil", err)
+			}
+
+			width := moduleCount(t, recoveryLevel)
+			if viewBox := fmt.Sprintf(`viewBox="0 0 %d %d"`, width, width); !strings.Contains(string(got), viewBox) {
+				t.Errorf("Expected the %s level code to have %d modules", level, width)
+			}
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	auditDomain "github.com/yavurb/goyurback/internal/audit/domain"
+	auditRepository "github.com/yavurb/goyurback/internal/audit/infrastructure/repository"
+	"github.com/yavurb/goyurback/internal/database/postgres"
+)
+
+// chikitoSnapshot is the state of a chikito recorded in the audit log.
+type chikitoSnapshot struct {
+	ExpiresAt    *time.Time      `json:"expires_at"`
+	MaxClicks    *int32          `json:"max_clicks"`
+	UTM          json.RawMessage `json:"utm"`
+	URL          string          `json:"url"`
+	Description  string          `json:"description"`
+	RedirectCode int32           `json:"redirect_code"`
+	Disabled     bool            `json:"disabled"`
+	ForwardQuery bool            `json:"forward_query"`
+}
+
+func newChikitoSnapshot(chikito *postgres.Chikito) *chikitoSnapshot {
+	snapshot := &chikitoSnapshot{
+		UTM:          chikito.Utm,
+		URL:          chikito.Url,
+		Description:  chikito.Description,
+		RedirectCode: chikito.RedirectCode,
+		Disabled:     chikito.Disabled,
+		ForwardQuery: chikito.ForwardQuery,
+	}
+
+	if chikito.ExpiresAt.Valid {
+		snapshot.ExpiresAt = &chikito.ExpiresAt.Time
+	}
+
+	if chikito.MaxClicks.Valid {
+		snapshot.MaxClicks = &chikito.MaxClicks.Int32
+	}
+
+	return snapshot
+}
+
+func recordChange(ctx context.Context, db *postgres.Queries, action auditDomain.Action, publicID string, before, after any) error {
+	return auditRepository.Record(ctx, db, &auditDomain.Change{
+		Before:     before,
+		After:      after,
+		EntityID:   publicID,
+		Action:     action,
+		EntityType: auditDomain.EntityChikitos,
+	})
+}
@@ -1,5 +1,56 @@
 -- name: CreateChikito :one
-INSERT INTO chikitos (public_id, url, description) VALUES ($1, $2, $3) RETURNING *;
+INSERT INTO chikitos (public_id, url, description, expires_at, max_clicks, redirect_code, forward_query, utm) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING *;
 
 -- name: GetChikito :one
 SELECT * FROM chikitos WHERE public_id = $1;
+
+-- name: GetChikitos :many
+SELECT * FROM chikitos ORDER BY id;
+
+-- name: GetChikitoForUpdate :one
+SELECT * FROM chikitos WHERE public_id = $1 FOR UPDATE;
+
+-- name: UpdateChikito :one
+UPDATE chikitos SET url = $2, description = $3, expires_at = $4, max_clicks = $5, disabled = $6,
+  redirect_code = $7, forward_query = $8, utm = $9, updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteChikito :exec
+DELETE FROM chikitos WHERE id = $1;
+
+-- name: ClaimChikitoClick :one
+UPDATE chikitos SET clicks = clicks + 1 WHERE id = $1 AND (max_clicks IS NULL OR clicks < max_clicks) RETURNING clicks;
+
+-- name: CreateChikitoClicks :exec
+INSERT INTO chikito_clicks (chikito_id, clicked_at, referrer_host, user_agent_family, ip_hash)
+SELECT c.chikito_id, c.clicked_at, c.referrer_host, c.user_agent_family, c.ip_hash
+FROM unnest(@chikito_ids::INTEGER[], @clicked_at::TIMESTAMP[], @referrer_hosts::VARCHAR[], @user_agent_families::VARCHAR[], @ip_hashes::VARCHAR[])
+  AS c (chikito_id, clicked_at, referrer_host, user_agent_family, ip_hash)
+JOIN chikitos ON chikitos.id = c.chikito_id;
+
+-- name: CountChikitoClicks :one
+SELECT count(*) FROM chikito_clicks WHERE chikito_id = $1;
+
+-- name: GetChikitoClickSeries :many
+SELECT date_trunc(@bucket::TEXT, clicked_at)::TIMESTAMP AS time, count(*) AS clicks
+FROM chikito_clicks
+WHERE chikito_id = @chikito_id AND clicked_at >= @since::TIMESTAMP
+GROUP BY 1
+ORDER BY 1;
+
+-- name: GetChikitoClickReferrers :many
+SELECT referrer_host, count(*) AS clicks
+FROM chikito_clicks
+WHERE chikito_id = $1
+GROUP BY referrer_host
+ORDER BY clicks DESC, referrer_host
+LIMIT $2;
+
+-- name: GetChikitoClickUserAgents :many
+SELECT user_agent_family, count(*) AS clicks
+FROM chikito_clicks
+WHERE chikito_id = $1
+GROUP BY user_agent_family
+ORDER BY clicks DESC, user_agent_family
+LIMIT $2;
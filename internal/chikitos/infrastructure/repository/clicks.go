@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/yavurb/goyurback/internal/chikitos/domain"
+	"github.com/yavurb/goyurback/internal/database/postgres"
+)
+
+func (r *Repository) CreateClicks(ctx context.Context, clicks []*domain.Click) error {
+	params := postgres.CreateChikitoClicksParams{}
+
+	for _, click := range clicks {
+		params.ChikitoIds = append(params.ChikitoIds, click.ChikitoID)
+		params.ClickedAt = append(params.ClickedAt, pgtype.Timestamp{Time: click.ClickedAt, Valid: true})
+		params.ReferrerHosts = append(params.ReferrerHosts, click.ReferrerHost)
+		params.UserAgentFamilies = append(params.UserAgentFamilies, click.UserAgentFamily)
+		params.IpHashes = append(params.IpHashes, click.IPHash)
+	}
+
+	if err := r.db.CreateChikitoClicks(ctx, params); err != nil {
+		log.Printf("DB Error creating chikito clicks: %v\n", err)
+
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) GetClickStats(ctx context.Context, chikitoID int32, dailySince, hourlySince time.Time) (*domain.ClickStats, error) {
+	total, err := r.db.CountChikitoClicks(ctx, chikitoID)
+	if err != nil {
+		log.Printf("DB Error counting chikito clicks: %v\n", err)
+
+		return nil, err
+	}
+
+	daily, err := r.getClickSeries(ctx, chikitoID, "day", dailySince)
+	if err != nil {
+		return nil, err
+	}
+
+	hourly, err := r.getClickSeries(ctx, chikitoID, "hour", hourlySince)
+	if err != nil {
+		return nil, err
+	}
+
+	referrers_, err := r.db.GetChikitoClickReferrers(ctx, postgres.GetChikitoClickReferrersParams{
+		ChikitoID: chikitoID,
+		Limit:     domain.TopClickTotals,
+	})
+	if err != nil {
+		log.Printf("DB Error getting chikito click referrers: %v\n", err)
+
+		return nil, err
+	}
+
+	userAgents_, err := r.db.GetChikitoClickUserAgents(ctx, postgres.GetChikitoClickUserAgentsParams{
+		ChikitoID: chikitoID,
+		Limit:     domain.TopClickTotals,
+	})
+	if err != nil {
+		log.Printf("DB Error getting chikito click user agents: %v\n", err)
+
+		return nil, err
+	}
+
+	stats := &domain.ClickStats{
+		Referrers:  []domain.ClickTotal{},
+		UserAgents: []domain.ClickTotal{},
+		Daily:      daily,
+		Hourly:     hourly,
+		Total:      total,
+	}
+
+	for _, referrer := range referrers_ {
+		stats.Referrers = append(stats.Referrers, domain.ClickTotal{Name: referrer.ReferrerHost, Clicks: referrer.Clicks})
+	}
+
+	for _, userAgent := range userAgents_ {
+		stats.UserAgents = append(stats.UserAgents, domain.ClickTotal{Name: userAgent.UserAgentFamily, Clicks: userAgent.Clicks})
+	}
+
+	return stats, nil
+}
+
+func (r *Repository) getClickSeries(ctx context.Context, chikitoID int32, bucket string, since time.Time) ([]domain.ClickCount, error) {
+	series_, err := r.db.GetChikitoClickSeries(ctx, postgres.GetChikitoClickSeriesParams{
+		Bucket:    bucket,
+		ChikitoID: chikitoID,
+		Since:     pgtype.Timestamp{Time: since, Valid: true},
+	})
+	if err != nil {
+		log.Printf("DB Error getting chikito clicks by %s: %v\n", bucket, err)
+
+		return nil, err
+	}
+
+	series := []domain.ClickCount{}
+
+	for _, count := range series_ {
+		series = append(series, domain.ClickCount{Time: count.Time.Time, Clicks: count.Clicks})
+	}
+
+	return series, nil
+}
@@ -2,30 +2,54 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"log"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
+	auditDomain "github.com/yavurb/goyurback/internal/audit/domain"
 	"github.com/yavurb/goyurback/internal/chikitos/domain"
 	"github.com/yavurb/goyurback/internal/database/postgres"
 )
 
 type Repository struct {
-	db *postgres.Queries
+	db       *postgres.Queries
+	connpool *pgxpool.Pool
 }
 
 func NewRepo(connpool *pgxpool.Pool) domain.ChikitoRepository {
 	db := postgres.New(connpool)
-	return &Repository{db}
+	return &Repository{db, connpool}
 }
 
 func (r *Repository) CreateChikito(ctx context.Context, chikito *domain.ChikitoCreate) (*domain.Chikito, error) {
-	chikito_, err := r.db.CreateChikito(ctx, postgres.CreateChikitoParams{
-		PublicID:    chikito.PublicID,
-		Url:         chikito.URL,
-		Description: chikito.Description,
+	tx, err := r.connpool.Begin(ctx)
+	if err != nil {
+		log.Printf("DB Error starting transaction: %v\n", err)
+
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := r.db.WithTx(tx)
+
+	utm, err := json.Marshal(chikito.UTM)
+	if err != nil {
+		return nil, err
+	}
+
+	chikito_, err := qtx.CreateChikito(ctx, postgres.CreateChikitoParams{
+		PublicID:     chikito.PublicID,
+		Url:          chikito.URL,
+		Description:  chikito.Description,
+		ExpiresAt:    pgtype.Timestamp{Time: chikito.ExpiresAt, Valid: !chikito.ExpiresAt.IsZero()},
+		MaxClicks:    pgtype.Int4{Int32: chikito.MaxClicks, Valid: chikito.MaxClicks > 0},
+		RedirectCode: chikito.RedirectCode,
+		ForwardQuery: chikito.ForwardQuery,
+		Utm:          utm,
 	})
 	if err != nil {
 		log.Printf("DB Error creating chikito: %v\n", err)
@@ -40,16 +64,17 @@ func (r *Repository) CreateChikito(ctx context.Context, chikito *domain.ChikitoC
 		return nil, err
 	}
 
-	chikitoCreated := &domain.Chikito{
-		ID:          chikito_.ID,
-		PublicID:    chikito_.PublicID,
-		URL:         chikito_.Url,
-		Description: chikito_.Description,
-		CreatedAt:   chikito_.CreatedAt.Time,
-		UpdatedAt:   chikito_.UpdatedAt.Time,
+	if err := recordChange(ctx, qtx, auditDomain.ActionCreate, chikito_.PublicID, nil, newChikitoSnapshot(&chikito_)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Printf("DB Error committing chikito creation: %v\n", err)
+
+		return nil, err
 	}
 
-	return chikitoCreated, nil
+	return toDomainChikito(chikito_), nil
 }
 
 func (r *Repository) GetChikito(ctx context.Context, id string) (*domain.Chikito, error) {
@@ -64,14 +89,162 @@ func (r *Repository) GetChikito(ctx context.Context, id string) (*domain.Chikito
 		return nil, err
 	}
 
+	return toDomainChikito(chikito_), nil
+}
+
+func (r *Repository) GetChikitos(ctx context.Context) ([]*domain.Chikito, error) {
+	chikitos_, err := r.db.GetChikitos(ctx)
+	if err != nil {
+		log.Printf("DB Error getting chikitos: %v\n", err)
+
+		return nil, err
+	}
+
+	chikitos := []*domain.Chikito{}
+
+	for _, chikito_ := range chikitos_ {
+		chikitos = append(chikitos, toDomainChikito(chikito_))
+	}
+
+	return chikitos, nil
+}
+
+func (r *Repository) UpdateChikito(ctx context.Context, chikito *domain.Chikito) (*domain.Chikito, error) {
+	tx, err := r.connpool.Begin(ctx)
+	if err != nil {
+		log.Printf("DB Error starting transaction: %v\n", err)
+
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := r.db.WithTx(tx)
+
+	current, err := getChikitoForUpdate(ctx, qtx, chikito.PublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	utm, err := json.Marshal(chikito.UTM)
+	if err != nil {
+		return nil, err
+	}
+
+	chikito_, err := qtx.UpdateChikito(ctx, postgres.UpdateChikitoParams{
+		ID:           current.ID,
+		Url:          chikito.URL,
+		Description:  chikito.Description,
+		ExpiresAt:    pgtype.Timestamp{Time: chikito.ExpiresAt, Valid: !chikito.ExpiresAt.IsZero()},
+		MaxClicks:    pgtype.Int4{Int32: chikito.MaxClicks, Valid: chikito.MaxClicks > 0},
+		Disabled:     chikito.Disabled,
+		RedirectCode: chikito.RedirectCode,
+		ForwardQuery: chikito.ForwardQuery,
+		Utm:          utm,
+	})
+	if err != nil {
+		log.Printf("DB Error updating chikito: %v\n", err)
+
+		return nil, err
+	}
+
+	if err := recordChange(ctx, qtx, auditDomain.ActionUpdate, chikito_.PublicID, newChikitoSnapshot(&current), newChikitoSnapshot(&chikito_)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Printf("DB Error committing chikito update: %v\n", err)
+
+		return nil, err
+	}
+
+	return toDomainChikito(chikito_), nil
+}
+
+func (r *Repository) DeleteChikito(ctx context.Context, id string) error {
+	tx, err := r.connpool.Begin(ctx)
+	if err != nil {
+		log.Printf("DB Error starting transaction: %v\n", err)
+
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := r.db.WithTx(tx)
+
+	current, err := getChikitoForUpdate(ctx, qtx, id)
+	if err != nil {
+		return err
+	}
+
+	// The clicks of the chikito are deleted with it
+	if err := qtx.DeleteChikito(ctx, current.ID); err != nil {
+		log.Printf("DB Error deleting chikito: %v\n", err)
+
+		return err
+	}
+
+	if err := recordChange(ctx, qtx, auditDomain.ActionDelete, current.PublicID, newChikitoSnapshot(&current), nil); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Printf("DB Error committing chikito deletion: %v\n", err)
+
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) ClaimClick(ctx context.Context, chikitoID int32) error {
+	if _, err := r.db.ClaimChikitoClick(ctx, chikitoID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrChikitoGone
+		}
+
+		log.Printf("DB Error claiming chikito click: %v\n", err)
+
+		return err
+	}
+
+	return nil
+}
+
+func getChikitoForUpdate(ctx context.Context, db *postgres.Queries, id string) (postgres.Chikito, error) {
+	chikito_, err := db.GetChikitoForUpdate(ctx, id)
+	if err != nil {
+		log.Printf("DB Error getting chikito for update: %v\n", err)
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return chikito_, domain.ErrChikitoNotFound
+		}
+
+		return chikito_, err
+	}
+
+	return chikito_, nil
+}
+
+func toDomainChikito(chikito_ postgres.Chikito) *domain.Chikito {
 	chikito := &domain.Chikito{
-		ID:          chikito_.ID,
-		PublicID:    chikito_.PublicID,
-		URL:         chikito_.Url,
-		Description: chikito_.Description,
-		CreatedAt:   chikito_.CreatedAt.Time,
-		UpdatedAt:   chikito_.UpdatedAt.Time,
+		ExpiresAt:    chikito_.ExpiresAt.Time,
+		CreatedAt:    chikito_.CreatedAt.Time,
+		UpdatedAt:    chikito_.UpdatedAt.Time,
+		PublicID:     chikito_.PublicID,
+		URL:          chikito_.Url,
+		Description:  chikito_.Description,
+		ID:           chikito_.ID,
+		MaxClicks:    chikito_.MaxClicks.Int32,
+		Clicks:       chikito_.Clicks,
+		RedirectCode: chikito_.RedirectCode,
+		Disabled:     chikito_.Disabled,
+		ForwardQuery: chikito_.ForwardQuery,
+	}
+
+	// The chikito still redirects without its UTM parameters if they can't be read
+	if err := json.Unmarshal(chikito_.Utm, &chikito.UTM); err != nil {
+		log.Printf("DB Error reading the UTM parameters of chikito %s: %v\n", chikito_.PublicID, err)
 	}
 
-	return chikito, nil
+	return chikito
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -33,16 +34,18 @@ func TestCreateChikito(t *testing.T) {
 		repo := NewRepo(conn)
 
 		want := &domain.Chikito{
-			ID:          1,
-			PublicID:    "ch_12345",
-			URL:         "https://example.com/my_long_url",
-			Description: "My long URL description",
+			ID:           1,
+			PublicID:     "ch_12345",
+			URL:          "https://example.com/my_long_url",
+			Description:  "My long URL description",
+			RedirectCode: 307,
 		}
 
 		got, err := repo.CreateChikito(ctx, &domain.ChikitoCreate{
-			PublicID:    "ch_12345",
-			URL:         "https://example.com/my_long_url",
-			Description: "My long URL description",
+			PublicID:     "ch_12345",
+			URL:          "https://example.com/my_long_url",
+			Description:  "My long URL description",
+			RedirectCode: 307,
 		})
 		if err != nil {
 			t.Errorf("Expected no error, got: %v", err)
@@ -66,18 +69,20 @@ func TestCreateChikito(t *testing.T) {
 		repo := NewRepo(conn)
 
 		_, err = repo.CreateChikito(ctx, &domain.ChikitoCreate{
-			PublicID:    "ch_12345",
-			URL:         "https://example.com/my_long_url",
-			Description: "My long URL description",
+			PublicID:     "ch_12345",
+			URL:          "https://example.com/my_long_url",
+			Description:  "My long URL description",
+			RedirectCode: 307,
 		})
 		if err != nil {
 			t.Errorf("Expected no error creating first chikito, got: %v", err)
 		}
 
 		_, err = repo.CreateChikito(ctx, &domain.ChikitoCreate{
-			PublicID:    "ch_12345",
-			URL:         "https://example.com/my_long_url",
-			Description: "My long URL description",
+			PublicID:     "ch_12345",
+			URL:          "https://example.com/my_long_url",
+			Description:  "My long URL description",
+			RedirectCode: 307,
 		})
 		if err == nil {
 			t.Errorf("Expected error creating second chikito, got nil")
@@ -102,9 +107,10 @@ func TestCreateChikito(t *testing.T) {
 		repo := NewRepo(conn)
 
 		_, err = repo.CreateChikito(ctx, &domain.ChikitoCreate{
-			PublicID:    "ch_12345",
-			URL:         "https://example.com/my_long_url",
-			Description: "My long URL description",
+			PublicID:     "ch_12345",
+			URL:          "https://example.com/my_long_url",
+			Description:  "My long URL description",
+			RedirectCode: 307,
 		})
 		if err == nil {
 			t.Errorf("Expected error creating second chikito, got nil")
@@ -138,19 +144,21 @@ func TestGetChikito(t *testing.T) {
 		repo := NewRepo(conn)
 
 		_, err = repo.CreateChikito(ctx, &domain.ChikitoCreate{
-			PublicID:    "ch_12345",
-			URL:         "https://example.com/my_long_url",
-			Description: "My long URL description",
+			PublicID:     "ch_12345",
+			URL:          "https://example.com/my_long_url",
+			Description:  "My long URL description",
+			RedirectCode: 307,
 		})
 		if err != nil {
 			t.Errorf("Expected no error creating chikito, got: %v", err)
 		}
 
 		want := &domain.Chikito{
-			ID:          1,
-			PublicID:    "ch_12345",
-			URL:         "https://example.com/my_long_url",
-			Description: "My long URL description",
+			ID:           1,
+			PublicID:     "ch_12345",
+			URL:          "https://example.com/my_long_url",
+			Description:  "My long URL description",
+			RedirectCode: 307,
 		}
 
 		got, err := repo.GetChikito(ctx, "ch_12345")
@@ -209,3 +217,279 @@ func TestGetChikito(t *testing.T) {
 		}
 	})
 }
+
+func TestGetChikitos(t *testing.T) {
+	ctx := context.Background()
+
+	pgContainer, err := testhelpers.CreatePostgresContainer(t, ctx)
+	if err != nil {
+		t.Fatalf("Error creating postgres container: %v", err)
+	}
+
+	t.Run("it should get all the chikitos", func(t *testing.T) {
+		testhelpers.CleanDatabase(t, ctx, pgContainer.ConnString)
+
+		conn, err := pgxpool.New(ctx, pgContainer.ConnString)
+		if err != nil {
+			t.Fatalf("Error creating pgxpool: %v", err)
+		}
+
+		t.Cleanup(func() { conn.Close() })
+
+		repo := NewRepo(conn)
+
+		for _, publicID := range []string{"ch_12345", "ch_67890"} {
+			_, err = repo.CreateChikito(ctx, &domain.ChikitoCreate{
+				PublicID:     publicID,
+				URL:          "https://example.com/my_long_url",
+				Description:  "My long URL description",
+				RedirectCode: 307,
+			})
+			if err != nil {
+				t.Fatalf("Expected no error creating chikito, got: %v", err)
+			}
+		}
+
+		got, err := repo.GetChikitos(ctx)
+		if err != nil {
+			t.Errorf("Expected no error getting chikitos. Got: %v", err)
+		}
+
+		if len(got) != 2 || got[0].PublicID != "ch_12345" || got[1].PublicID != "ch_67890" {
+			t.Errorf("Expected the chikitos in creation order. Got: %v", got)
+		}
+	})
+
+	t.Run("it should return an empty list when there are no chikitos", func(t *testing.T) {
+		testhelpers.CleanDatabase(t, ctx, pgContainer.ConnString)
+
+		conn, err := pgxpool.New(ctx, pgContainer.ConnString)
+		if err != nil {
+			t.Fatalf("Error creating pgxpool: %v", err)
+		}
+
+		t.Cleanup(func() { conn.Close() })
+
+		repo := NewRepo(conn)
+
+		got, err := repo.GetChikitos(ctx)
+		if err != nil {
+			t.Errorf("Expected no error getting chikitos. Got: %v", err)
+		}
+
+		if len(got) != 0 {
+			t.Errorf("Expected no chikitos. Got: %v", got)
+		}
+	})
+}
+
+func TestClickStats(t *testing.T) {
+	ctx := context.Background()
+
+	pgContainer, err := testhelpers.CreatePostgresContainer(t, ctx)
+	if err != nil {
+		t.Fatalf("Error creating postgres container: %v", err)
+	}
+
+	t.Run("it should count the clicks of the chikito", func(t *testing.T) {
+		testhelpers.CleanDatabase(t, ctx, pgContainer.ConnString)
+
+		conn, err := pgxpool.New(ctx, pgContainer.ConnString)
+		if err != nil {
+			t.Fatalf("Error creating pgxpool: %v", err)
+		}
+
+		t.Cleanup(func() { conn.Close() })
+
+		repo := NewRepo(conn)
+
+		chikito, err := repo.CreateChikito(ctx, &domain.ChikitoCreate{
+			PublicID:     "ch_12345",
+			URL:          "https://example.com/my_long_url",
+			Description:  "My long URL description",
+			RedirectCode: 307,
+		})
+		if err != nil {
+			t.Fatalf("Error creating chikito: %v", err)
+		}
+
+		day := time.Date(2024, 7, 14, 0, 0, 0, 0, time.UTC)
+
+		err = repo.CreateClicks(ctx, []*domain.Click{
+			{ChikitoID: chikito.ID, ClickedAt: day.Add(-2 * time.Hour), ReferrerHost: "t.co", UserAgentFamily: domain.UserAgentChrome},
+			{ChikitoID: chikito.ID, ClickedAt: day.Add(10 * time.Minute), UserAgentFamily: domain.UserAgentChrome},
+			{ChikitoID: chikito.ID, ClickedAt: day.Add(20 * time.Minute), ReferrerHost: "t.co", UserAgentFamily: domain.UserAgentSafari},
+			{ChikitoID: chikito.ID, ClickedAt: day.Add(3 * time.Hour), ReferrerHost: "t.co", UserAgentFamily: domain.UserAgentChrome},
+		})
+		if err != nil {
+			t.Fatalf("Error creating clicks: %v", err)
+		}
+
+		got, err := repo.GetClickStats(ctx, chikito.ID, day.AddDate(0, 0, -1), day)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		want := &domain.ClickStats{
+			Referrers:  []domain.ClickTotal{{Name: "t.co", Clicks: 3}, {Name: "", Clicks: 1}},
+			UserAgents: []domain.ClickTotal{{Name: domain.UserAgentChrome, Clicks: 3}, {Name: domain.UserAgentSafari, Clicks: 1}},
+			Daily:      []domain.ClickCount{{Time: day.AddDate(0, 0, -1), Clicks: 1}, {Time: day, Clicks: 3}},
+			Hourly:     []domain.ClickCount{{Time: day, Clicks: 2}, {Time: day.Add(3 * time.Hour), Clicks: 1}},
+			Total:      4,
+		}
+
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("Mismatch getting click stats. (-want,+got):\n%s", diff)
+		}
+	})
+
+	t.Run("it should skip the clicks of deleted chikitos and keep storing the others", func(t *testing.T) {
+		testhelpers.CleanDatabase(t, ctx, pgContainer.ConnString)
+
+		conn, err := pgxpool.New(ctx, pgContainer.ConnString)
+		if err != nil {
+			t.Fatalf("Error creating pgxpool: %v", err)
+		}
+
+		t.Cleanup(func() { conn.Close() })
+
+		repo := NewRepo(conn)
+
+		kept, err := repo.CreateChikito(ctx, &domain.ChikitoCreate{PublicID: "ch_12345", URL: "https://example.com/kept", RedirectCode: 307})
+		if err != nil {
+			t.Fatalf("Error creating chikito: %v", err)
+		}
+
+		deleted, err := repo.CreateChikito(ctx, &domain.ChikitoCreate{PublicID: "ch_67890", URL: "https://example.com/deleted", RedirectCode: 307})
+		if err != nil {
+			t.Fatalf("Error creating chikito: %v", err)
+		}
+
+		clickedAt := time.Date(2024, 7, 14, 10, 30, 0, 0, time.UTC)
+		pending := []*domain.Click{
+			{ChikitoID: kept.ID, ClickedAt: clickedAt},
+			{ChikitoID: deleted.ID, ClickedAt: clickedAt},
+		}
+
+		if err := repo.DeleteChikito(ctx, "ch_67890"); err != nil {
+			t.Fatalf("Error deleting chikito: %v", err)
+		}
+
+		if err := repo.CreateClicks(ctx, pending); err != nil {
+			t.Fatalf("Expected the pending clicks to be written, got: %v", err)
+		}
+
+		if err := repo.CreateClicks(ctx, []*domain.Click{{ChikitoID: kept.ID, ClickedAt: clickedAt}}); err != nil {
+			t.Fatalf("Expected the next batch to be written, got: %v", err)
+		}
+
+		got, err := repo.GetClickStats(ctx, kept.ID, clickedAt, clickedAt)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		if got.Total != 2 {
+			t.Errorf("Expected both clicks of the kept chikito to be stored, got: %d", got.Total)
+		}
+	})
+}
+
+func TestChikitoLifecycle(t *testing.T) {
+	ctx := context.Background()
+
+	pgContainer, err := testhelpers.CreatePostgresContainer(t, ctx)
+	if err != nil {
+		t.Fatalf("Error creating postgres container: %v", err)
+	}
+
+	t.Run("it should stop counting clicks at the max clicks", func(t *testing.T) {
+		testhelpers.CleanDatabase(t, ctx, pgContainer.ConnString)
+
+		conn, err := pgxpool.New(ctx, pgContainer.ConnString)
+		if err != nil {
+			t.Fatalf("Error creating pgxpool: %v", err)
+		}
+
+		t.Cleanup(func() { conn.Close() })
+
+		repo := NewRepo(conn)
+
+		chikito, err := repo.CreateChikito(ctx, &domain.ChikitoCreate{
+			PublicID:     "ch_12345",
+			URL:          "https://example.com/registrations",
+			Description:  "Registrations",
+			RedirectCode: 307,
+			MaxClicks:    2,
+		})
+		if err != nil {
+			t.Fatalf("Error creating chikito: %v", err)
+		}
+
+		for range 2 {
+			if err := repo.ClaimClick(ctx, chikito.ID); err != nil {
+				t.Fatalf("Expected no error claiming a click, got: %v", err)
+			}
+		}
+
+		if err := repo.ClaimClick(ctx, chikito.ID); !errors.Is(err, domain.ErrChikitoGone) {
+			t.Errorf("Expected ErrChikitoGone past the max clicks, got: %v", err)
+		}
+
+		got, err := repo.GetChikito(ctx, "ch_12345")
+		if err != nil {
+			t.Fatalf("Error getting chikito: %v", err)
+		}
+
+		if got.Clicks != 2 || !got.Gone(time.Now()) {
+			t.Errorf("Expected the chikito to be out of clicks, got: %+v", got)
+		}
+	})
+
+	t.Run("it should update and delete a chikito", func(t *testing.T) {
+		testhelpers.CleanDatabase(t, ctx, pgContainer.ConnString)
+
+		conn, err := pgxpool.New(ctx, pgContainer.ConnString)
+		if err != nil {
+			t.Fatalf("Error creating pgxpool: %v", err)
+		}
+
+		t.Cleanup(func() { conn.Close() })
+
+		repo := NewRepo(conn)
+
+		chikito, err := repo.CreateChikito(ctx, &domain.ChikitoCreate{
+			PublicID:     "ch_12345",
+			URL:          "https://example.com/registrations",
+			Description:  "Registrations",
+			RedirectCode: 307,
+		})
+		if err != nil {
+			t.Fatalf("Error creating chikito: %v", err)
+		}
+
+		expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+		chikito.ExpiresAt = expiresAt
+		chikito.Disabled = true
+
+		got, err := repo.UpdateChikito(ctx, chikito)
+		if err != nil {
+			t.Fatalf("Expected no error updating chikito, got: %v", err)
+		}
+
+		if !got.ExpiresAt.Equal(expiresAt) || !got.Disabled {
+			t.Errorf("Expected the chikito to be updated, got: %+v", got)
+		}
+
+		if err := repo.DeleteChikito(ctx, "ch_12345"); err != nil {
+			t.Fatalf("Expected no error deleting chikito, got: %v", err)
+		}
+
+		if _, err := repo.GetChikito(ctx, "ch_12345"); !errors.Is(err, domain.ErrChikitoNotFound) {
+			t.Errorf("Expected the chikito to be deleted, got: %v", err)
+		}
+
+		if err := repo.DeleteChikito(ctx, "ch_12345"); !errors.Is(err, domain.ErrChikitoNotFound) {
+			t.Errorf("Expected ErrChikitoNotFound deleting it again, got: %v", err)
+		}
+	})
+}
@@ -1,20 +1,247 @@
 package ui
 
-import "time"
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/yavurb/goyurback/internal/chikitos/domain"
+)
 
 type CreateIn struct {
-	URL         string `json:"url" validate:"required,url"`
-	Description string `json:"description" validate:"required"`
+	ExpiresAt    *time.Time `json:"expires_at"`
+	UTM          *UTM       `json:"utm"`
+	URL          string     `json:"url" validate:"required,url"`
+	Description  string     `json:"description" validate:"required"`
+	Alias        string     `json:"alias"`
+	MaxClicks    int32      `json:"max_clicks" validate:"min=0"`
+	RedirectCode int32      `json:"redirect_code" validate:"omitempty,oneof=301 302 307 308"`
+	ForwardQuery bool       `json:"forward_query"`
+}
+
+func (c CreateIn) toOptions() *domain.ChikitoOptions {
+	options := &domain.ChikitoOptions{
+		MaxClicks:    c.MaxClicks,
+		RedirectCode: c.RedirectCode,
+		ForwardQuery: c.ForwardQuery,
+	}
+
+	if c.ExpiresAt != nil {
+		options.ExpiresAt = *c.ExpiresAt
+	}
+
+	if c.UTM != nil {
+		options.UTM = domain.UTM(*c.UTM)
+	}
+
+	return options
+}
+
+// UTM has the UTM parameters added to the destination, like utm_source for Source.
+type UTM struct {
+	Source   string `json:"source,omitempty" validate:"max=100"`
+	Medium   string `json:"medium,omitempty" validate:"max=100"`
+	Campaign string `json:"campaign,omitempty" validate:"max=100"`
+	Term     string `json:"term,omitempty" validate:"max=100"`
+	Content  string `json:"content,omitempty" validate:"max=100"`
+}
+
+// UpdateIn changes the given fields of a chikito. A null expires_at removes the expiry, and a
+// zero max_clicks the limit of clicks.
+type UpdateIn struct {
+	ExpiresAt    optionalTime `json:"expires_at"`
+	UTM          *UTM         `json:"utm"`
+	URL          *string      `json:"url" validate:"omitempty,url"`
+	Description  *string      `json:"description" validate:"omitempty,min=1"`
+	MaxClicks    *int32       `json:"max_clicks" validate:"omitempty,min=0"`
+	RedirectCode *int32       `json:"redirect_code" validate:"omitempty,oneof=301 302 307 308"`
+	Disabled     *bool        `json:"disabled"`
+	ForwardQuery *bool        `json:"forward_query"`
+	ID           string       `param:"id" validate:"required"`
 }
 
-type CreateOut struct {
-	ID          string    `json:"id"`
-	URL         string    `json:"url"`
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+func (u UpdateIn) toDomain() *domain.ChikitoUpdate {
+	update := &domain.ChikitoUpdate{
+		URL:          u.URL,
+		Description:  u.Description,
+		MaxClicks:    u.MaxClicks,
+		RedirectCode: u.RedirectCode,
+		Disabled:     u.Disabled,
+		ForwardQuery: u.ForwardQuery,
+	}
+
+	if u.ExpiresAt.Set {
+		update.ExpiresAt = &u.ExpiresAt.Time
+	}
+
+	if u.UTM != nil {
+		utm := domain.UTM(*u.UTM)
+		update.UTM = &utm
+	}
+
+	return update
+}
+
+// optionalTime tells a null time, which is decoded as the zero time, from a missing one.
+type optionalTime struct {
+	Time time.Time
+	Set  bool
+}
+
+func (t *optionalTime) UnmarshalJSON(data []byte) error {
+	t.Set = true
+
+	if string(data) == "null" {
+		t.Time = time.Time{}
+
+		return nil
+	}
+
+	return json.Unmarshal(data, &t.Time)
+}
+
+type ChikitoOut struct {
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	ExpiresAt    *time.Time `json:"expires_at"`
+	MaxClicks    *int32     `json:"max_clicks"`
+	UTM          UTM        `json:"utm"`
+	ID           string     `json:"id"`
+	URL          string     `json:"url"`
+	Description  string     `json:"description"`
+	Clicks       int32      `json:"clicks"`
+	RedirectCode int32      `json:"redirect_code"`
+	Disabled     bool       `json:"disabled"`
+	ForwardQuery bool       `json:"forward_query"`
+}
+
+func toChikitoOut(chikito *domain.Chikito) *ChikitoOut {
+	chikitoOut := &ChikitoOut{
+		CreatedAt:    chikito.CreatedAt,
+		UpdatedAt:    chikito.UpdatedAt,
+		UTM:          UTM(chikito.UTM),
+		ID:           chikito.PublicID,
+		URL:          chikito.URL,
+		Description:  chikito.Description,
+		Clicks:       chikito.Clicks,
+		RedirectCode: chikito.RedirectCode,
+		Disabled:     chikito.Disabled,
+		ForwardQuery: chikito.ForwardQuery,
+	}
+
+	if !chikito.ExpiresAt.IsZero() {
+		chikitoOut.ExpiresAt = &chikito.ExpiresAt
+	}
+
+	if chikito.MaxClicks > 0 {
+		chikitoOut.MaxClicks = &chikito.MaxClicks
+	}
+
+	return chikitoOut
 }
 
 type GetChikitoParams struct {
 	ID string `param:"id" validate:"required"`
 }
+
+type StatsParams struct {
+	ID    string `param:"id" validate:"required"`
+	Days  int    `query:"days" validate:"omitempty,min=1,max=365"`
+	Hours int    `query:"hours" validate:"omitempty,min=1,max=168"`
+}
+
+type ClickCountOut struct {
+	Time   time.Time `json:"time"`
+	Clicks int64     `json:"clicks"`
+}
+
+type ClickTotalOut struct {
+	Name   string `json:"name"`
+	Clicks int64  `json:"clicks"`
+}
+
+type StatsOut struct {
+	ID         string          `json:"id"`
+	Referrers  []ClickTotalOut `json:"referrers"`
+	UserAgents []ClickTotalOut `json:"user_agents"`
+	Daily      []ClickCountOut `json:"daily"`
+	Hourly     []ClickCountOut `json:"hourly"`
+	Total      int64           `json:"total"`
+}
+
+func toStatsOut(id string, stats *domain.ClickStats) *StatsOut {
+	return &StatsOut{
+		ID:         id,
+		Referrers:  toClickTotalsOut(stats.Referrers),
+		UserAgents: toClickTotalsOut(stats.UserAgents),
+		Daily:      toClickCountsOut(stats.Daily),
+		Hourly:     toClickCountsOut(stats.Hourly),
+		Total:      stats.Total,
+	}
+}
+
+func toClickTotalsOut(totals []domain.ClickTotal) []ClickTotalOut {
+	totalsOut := []ClickTotalOut{}
+
+	for _, total := range totals {
+		totalsOut = append(totalsOut, ClickTotalOut{Name: total.Name, Clicks: total.Clicks})
+	}
+
+	return totalsOut
+}
+
+func toClickCountsOut(counts []domain.ClickCount) []ClickCountOut {
+	countsOut := []ClickCountOut{}
+
+	for _, count := range counts {
+		countsOut = append(countsOut, ClickCountOut{Time: count.Time, Clicks: count.Clicks})
+	}
+
+	return countsOut
+}
+
+// QRParams has the options of a QR code. Colors are hex, like `1a2b3c`, and the level is the
+// error correction level, one of L, M, Q or H.
+type QRParams struct {
+	ID         string `param:"id" validate:"required"`
+	Format     string `query:"format" validate:"oneof=png svg"`
+	Level      string `query:"level"`
+	Foreground string `query:"foreground"`
+	Background string `query:"background"`
+	Size       int    `query:"size" validate:"min=64,max=2048"`
+	Margin     int    `query:"margin" validate:"min=0,max=16"`
+}
+
+// newQRParams returns the params with the defaults set, so binding only overrides the given
+// ones and a zero margin can be told apart from a missing one.
+func newQRParams() QRParams {
+	return QRParams{
+		Format:     string(domain.QRFormatPNG),
+		Level:      string(domain.QRLevelMedium),
+		Foreground: "000000",
+		Background: "ffffff",
+		Size:       domain.DefaultQRSize,
+		Margin:     domain.DefaultQRMargin,
+	}
+}
+
+func (p QRParams) toOptions() (*domain.QROptions, error) {
+	foreground, err := domain.ParseHexColor(p.Foreground)
+	if err != nil {
+		return nil, err
+	}
+
+	background, err := domain.ParseHexColor(p.Background)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.QROptions{
+		Foreground: foreground,
+		Background: background,
+		Format:     domain.QRFormat(p.Format),
+		Level:      domain.QRLevel(strings.ToUpper(p.Level)),
+		Size:       p.Size,
+		Margin:     p.Margin,
+	}, nil
+}
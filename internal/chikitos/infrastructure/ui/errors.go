@@ -47,3 +47,7 @@ func (e HTTPError) ErrUnprocessableEntity() error {
 
 	return err
 }
+
+func (e HTTPError) Gone() error {
+	return echo.NewHTTPError(http.StatusGone, e.Message)
+}
@@ -7,14 +7,45 @@ import (
 )
 
 type MockChikitosUsecase struct {
-	CreateFn func(ctx context.Context, url, description string) (*domain.Chikito, error)
+	CreateFn func(ctx context.Context, url, description, alias string, options *domain.ChikitoOptions) (*domain.Chikito, error)
 	GetFn    func(ctx context.Context, id string) (*domain.Chikito, error)
+	UpdateFn func(ctx context.Context, id string, update *domain.ChikitoUpdate) (*domain.Chikito, error)
+	DeleteFn func(ctx context.Context, id string) error
+
+	GetChikitosFn func(ctx context.Context) ([]*domain.Chikito, error)
+	VisitFn       func(ctx context.Context, id string, visitor *domain.Visitor) (*domain.Chikito, error)
+	GetStatsFn    func(ctx context.Context, id string, days, hours int) (*domain.ClickStats, error)
+	GetQRCodeFn   func(ctx context.Context, id string, options *domain.QROptions) ([]byte, error)
+}
+
+func (m *MockChikitosUsecase) Create(ctx context.Context, url, description, alias string, options *domain.ChikitoOptions) (*domain.Chikito, error) {
+	return m.CreateFn(ctx, url, description, alias, options)
 }
 
-func (m *MockChikitosUsecase) Create(ctx context.Context, url, description string) (*domain.Chikito, error) {
-	return m.CreateFn(ctx, url, description)
+func (m *MockChikitosUsecase) Update(ctx context.Context, id string, update *domain.ChikitoUpdate) (*domain.Chikito, error) {
+	return m.UpdateFn(ctx, id, update)
+}
+
+func (m *MockChikitosUsecase) Delete(ctx context.Context, id string) error {
+	return m.DeleteFn(ctx, id)
 }
 
 func (m *MockChikitosUsecase) Get(ctx context.Context, id string) (*domain.Chikito, error) {
 	return m.GetFn(ctx, id)
 }
+
+func (m *MockChikitosUsecase) GetChikitos(ctx context.Context) ([]*domain.Chikito, error) {
+	return m.GetChikitosFn(ctx)
+}
+
+func (m *MockChikitosUsecase) Visit(ctx context.Context, id string, visitor *domain.Visitor) (*domain.Chikito, error) {
+	return m.VisitFn(ctx, id, visitor)
+}
+
+func (m *MockChikitosUsecase) GetStats(ctx context.Context, id string, days, hours int) (*domain.ClickStats, error) {
+	return m.GetStatsFn(ctx, id, days, hours)
+}
+
+func (m *MockChikitosUsecase) GetQRCode(ctx context.Context, id string, options *domain.QROptions) ([]byte, error) {
+	return m.GetQRCodeFn(ctx, id, options)
+}
@@ -3,23 +3,36 @@ package ui
 import (
 	"log"
 	"net/http"
+	"strings"
 
 	"github.com/labstack/echo/v4"
+	authDomain "github.com/yavurb/goyurback/internal/auth/domain"
+	authUI "github.com/yavurb/goyurback/internal/auth/infrastructure/ui"
 	"github.com/yavurb/goyurback/internal/chikitos/domain"
 )
 
 type chikitoRouterCtx struct {
-	usecase domain.ChikitoUsecase
+	usecase     domain.ChikitoUsecase
+	fallbackURL string
 }
 
-func NewChikitosRouter(e *echo.Echo, usecase domain.ChikitoUsecase) *chikitoRouterCtx {
+// NewChikitosRouter creates the chikitos routes. The chikitos that no longer redirect respond
+// with 410 Gone, or redirect to fallbackURL when it is set.
+func NewChikitosRouter(e *echo.Echo, usecase domain.ChikitoUsecase, fallbackURL string) *chikitoRouterCtx {
 	routerGroup := e.Group("/chikitos")
 	routerCtx := &chikitoRouterCtx{
-		usecase: usecase,
+		usecase:     usecase,
+		fallbackURL: fallbackURL,
 	}
 
-	routerGroup.POST("", routerCtx.create)
+	canWrite := authUI.RequireScope(authDomain.ScopeChikitosWrite)
+
+	routerGroup.POST("", routerCtx.create, canWrite)
 	routerGroup.GET("/:id", routerCtx.get)
+	routerGroup.PATCH("/:id", routerCtx.update, canWrite)
+	routerGroup.DELETE("/:id", routerCtx.delete, canWrite)
+	routerGroup.GET("/:id/stats", routerCtx.getStats, canWrite)
+	routerGroup.GET("/:id/qr", routerCtx.getQRCode, canWrite)
 
 	return routerCtx
 }
@@ -42,24 +55,14 @@ func (ctx *chikitoRouterCtx) create(c echo.Context) error {
 		}.ErrUnprocessableEntity()
 	}
 
-	chikito_, err := ctx.usecase.Create(c.Request().Context(), chikito.URL, chikito.Description)
+	chikito_, err := ctx.usecase.Create(c.Request().Context(), chikito.URL, chikito.Description, chikito.Alias, chikito.toOptions())
 	if err != nil {
 		log.Printf("Could not create chikito. %v", err)
 
-		return HTTPError{
-			Message: "Unable to create chikito",
-		}.InternalServerError()
-	}
-
-	chikitoOut := &CreateOut{
-		ID:          chikito_.PublicID,
-		URL:         chikito_.URL,
-		Description: chikito.Description,
-		CreatedAt:   chikito_.CreatedAt,
-		UpdatedAt:   chikito_.UpdatedAt,
+		return handleErr(err)
 	}
 
-	return c.JSON(http.StatusCreated, chikitoOut)
+	return c.JSON(http.StatusCreated, toChikitoOut(chikito_))
 }
 
 func (ctx *chikitoRouterCtx) get(c echo.Context) error {
@@ -81,7 +84,21 @@ func (ctx *chikitoRouterCtx) get(c echo.Context) error {
 		}.ErrUnprocessableEntity()
 	}
 
-	chikito, err := ctx.usecase.Get(c.Request().Context(), chikitoParams.ID)
+	chikito, err := ctx.usecase.Visit(c.Request().Context(), chikitoParams.ID, &domain.Visitor{
+		IP:        c.RealIP(),
+		Referrer:  c.Request().Referer(),
+		UserAgent: c.Request().UserAgent(),
+	})
+	if err == domain.ErrChikitoGone {
+		if ctx.fallbackURL != "" {
+			return c.Redirect(http.StatusFound, ctx.fallbackURL)
+		}
+
+		return HTTPError{
+			Message: "Chikito is no longer available",
+		}.Gone()
+	}
+
 	if err != nil {
 		log.Printf("Could not get chikito. %v\n", err)
 
@@ -90,5 +107,131 @@ func (ctx *chikitoRouterCtx) get(c echo.Context) error {
 		}.NotFound()
 	}
 
-	return c.Redirect(http.StatusPermanentRedirect, chikito.URL)
+	return c.Redirect(int(chikito.RedirectCode), chikito.Destination(c.QueryParams()))
+}
+
+func (ctx *chikitoRouterCtx) update(c echo.Context) error {
+	var chikito UpdateIn
+
+	if err := c.Bind(&chikito); err != nil {
+		return HTTPError{
+			Message: "Invalid request body",
+		}.ErrUnprocessableEntity()
+	}
+
+	if err := c.Validate(chikito); err != nil {
+		return HTTPError{
+			Message: "Invalid params",
+		}.ErrUnprocessableEntity()
+	}
+
+	chikito_, err := ctx.usecase.Update(c.Request().Context(), chikito.ID, chikito.toDomain())
+	if err != nil {
+		return handleErr(err)
+	}
+
+	return c.JSON(http.StatusOK, toChikitoOut(chikito_))
+}
+
+func (ctx *chikitoRouterCtx) delete(c echo.Context) error {
+	var chikitoParams GetChikitoParams
+
+	if err := c.Bind(&chikitoParams); err != nil {
+		return HTTPError{
+			Message: "Bad chikito params",
+		}.ErrUnprocessableEntity()
+	}
+
+	if err := c.Validate(chikitoParams); err != nil {
+		return HTTPError{
+			Message: "Bad request params",
+		}.ErrUnprocessableEntity()
+	}
+
+	if err := ctx.usecase.Delete(c.Request().Context(), chikitoParams.ID); err != nil {
+		return handleErr(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (ctx *chikitoRouterCtx) getStats(c echo.Context) error {
+	var params StatsParams
+
+	if err := c.Bind(&params); err != nil {
+		return HTTPError{
+			Message: "Invalid stats params",
+		}.BadRequest()
+	}
+
+	if err := c.Validate(params); err != nil {
+		return HTTPError{
+			Message: "Invalid stats params",
+		}.ErrUnprocessableEntity()
+	}
+
+	stats, err := ctx.usecase.GetStats(c.Request().Context(), params.ID, params.Days, params.Hours)
+	if err != nil {
+		return handleErr(err)
+	}
+
+	return c.JSON(http.StatusOK, toStatsOut(strings.ToLower(params.ID), stats))
+}
+
+func (ctx *chikitoRouterCtx) getQRCode(c echo.Context) error {
+	params := newQRParams()
+
+	if err := c.Bind(&params); err != nil {
+		return HTTPError{
+			Message: "Invalid QR code params",
+		}.BadRequest()
+	}
+
+	if err := c.Validate(params); err != nil {
+		return HTTPError{
+			Message: "Invalid QR code params",
+		}.ErrUnprocessableEntity()
+	}
+
+	options, err := params.toOptions()
+	if err != nil {
+		return handleErr(err)
+	}
+
+	qrCode, err := ctx.usecase.GetQRCode(c.Request().Context(), params.ID, options)
+	if err != nil {
+		return handleErr(err)
+	}
+
+	return c.Blob(http.StatusOK, options.Format.ContentType(), qrCode)
+}
+
+func handleErr(err error) error {
+	switch err {
+	case domain.ErrChikitoNotFound:
+		return HTTPError{
+			Message: "Chikito not found",
+		}.NotFound()
+	case domain.ErrQRCodesDisabled:
+		return HTTPError{
+			Message: "QR codes are not enabled",
+		}.NotFound()
+	case domain.ErrChikitoGone:
+		return HTTPError{
+			Message: "Chikito is no longer available",
+		}.Gone()
+	case domain.ErrPublicIDAlreadyExists:
+		return HTTPError{
+			Message: "Alias is already taken",
+		}.Conflict()
+	case domain.ErrInvalidAlias, domain.ErrReservedAlias, domain.ErrInvalidExpiry, domain.ErrInvalidMaxClicks, domain.ErrInvalidRedirectCode,
+		domain.ErrInvalidQROptions:
+		return HTTPError{
+			Message: err.Error(),
+		}.ErrUnprocessableEntity()
+	default:
+		return HTTPError{
+			Message: "Internal server error",
+		}.InternalServerError()
+	}
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"image/color"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -28,11 +29,18 @@ func TestCreateChikito(t *testing.T) {
 			"description": "Some random description",
 		}
 		want := map[string]any{
-			"id":          "ch_12345",
-			"url":         "https://example.com",
-			"description": "Some random description",
-			"created_at":  time.Now().UTC().Format(time.RFC3339),
-			"updated_at":  time.Now().UTC().Format(time.RFC3339),
+			"id":            "ch_12345",
+			"url":           "https://example.com",
+			"description":   "Some random description",
+			"created_at":    time.Now().UTC().Format(time.RFC3339),
+			"updated_at":    time.Now().UTC().Format(time.RFC3339),
+			"expires_at":    nil,
+			"max_clicks":    nil,
+			"clicks":        float64(0),
+			"disabled":      false,
+			"redirect_code": float64(307),
+			"forward_query": false,
+			"utm":           map[string]any{},
 		}
 
 		jsonBytes, err := json.Marshal(chikitoIn)
@@ -49,21 +57,22 @@ func TestCreateChikito(t *testing.T) {
 
 		c := e.NewContext(req, rec)
 		uc := &mocks.MockChikitosUsecase{}
-		uc.CreateFn = func(ctx context.Context, url, description string) (*domain.Chikito, error) {
+		uc.CreateFn = func(ctx context.Context, url, description, alias string, options *domain.ChikitoOptions) (*domain.Chikito, error) {
 			createdAt, _ := time.Parse(time.RFC3339, want["created_at"].(string))
 			updatedAt, _ := time.Parse(time.RFC3339, want["updated_at"].(string))
 
 			return &domain.Chikito{
-				ID:          1,
-				PublicID:    "ch_12345",
-				URL:         url,
-				Description: description,
-				CreatedAt:   createdAt,
-				UpdatedAt:   updatedAt,
+				ID:           1,
+				PublicID:     "ch_12345",
+				URL:          url,
+				Description:  description,
+				RedirectCode: domain.DefaultRedirectCode,
+				CreatedAt:    createdAt,
+				UpdatedAt:    updatedAt,
 			}, nil
 		}
 
-		h := NewChikitosRouter(e, uc)
+		h := NewChikitosRouter(e, uc, "")
 
 		err = h.create(c)
 		if err != nil {
@@ -103,11 +112,11 @@ func TestCreateChikito(t *testing.T) {
 
 		c := e.NewContext(req, rec)
 		uc := &mocks.MockChikitosUsecase{}
-		uc.CreateFn = func(ctx context.Context, url, description string) (*domain.Chikito, error) {
+		uc.CreateFn = func(ctx context.Context, url, description, alias string, options *domain.ChikitoOptions) (*domain.Chikito, error) {
 			return &domain.Chikito{}, nil
 		}
 
-		h := NewChikitosRouter(e, uc)
+		h := NewChikitosRouter(e, uc, "")
 
 		err = h.create(c)
 		if err == nil {
@@ -139,11 +148,11 @@ func TestCreateChikito(t *testing.T) {
 
 		c := e.NewContext(req, rec)
 		uc := &mocks.MockChikitosUsecase{}
-		uc.CreateFn = func(ctx context.Context, url, description string) (*domain.Chikito, error) {
-			return nil, domain.ErrPublicIDAlreadyExists
+		uc.CreateFn = func(ctx context.Context, url, description, alias string, options *domain.ChikitoOptions) (*domain.Chikito, error) {
+			return nil, errors.New("DB Error")
 		}
 
-		h := NewChikitosRouter(e, uc)
+		h := NewChikitosRouter(e, uc, "")
 
 		err = h.create(c)
 		if err == nil {
@@ -154,6 +163,58 @@ func TestCreateChikito(t *testing.T) {
 			t.Errorf("Expected error to be echo.ErrInternalServerError, got %v", err)
 		}
 	})
+
+	t.Run("It should return a conflict error when the alias is taken", func(t *testing.T) {
+		jsonString := `{"url":"https://example.com","description":"Some random description","alias":"talk-gophercon"}`
+
+		req := httptest.NewRequest(http.MethodPost, "/chikitos", strings.NewReader(jsonString))
+		rec := httptest.NewRecorder()
+
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		c := e.NewContext(req, rec)
+		uc := &mocks.MockChikitosUsecase{}
+		uc.CreateFn = func(ctx context.Context, url, description, alias string, options *domain.ChikitoOptions) (*domain.Chikito, error) {
+			if alias != "talk-gophercon" {
+				t.Errorf("Expected alias to be talk-gophercon, got %s", alias)
+			}
+
+			return nil, domain.ErrPublicIDAlreadyExists
+		}
+
+		h := NewChikitosRouter(e, uc, "")
+
+		err := h.create(c)
+
+		var httpErr *echo.HTTPError
+		if !errors.As(err, &httpErr) || httpErr.Code != http.StatusConflict {
+			t.Errorf("Expected a conflict error, got %v", err)
+		}
+	})
+
+	t.Run("It should return an unprocessable entity error when the alias is invalid", func(t *testing.T) {
+		for _, aliasErr := range []error{domain.ErrInvalidAlias, domain.ErrReservedAlias} {
+			jsonString := `{"url":"https://example.com","description":"Some random description","alias":"admin"}`
+
+			req := httptest.NewRequest(http.MethodPost, "/chikitos", strings.NewReader(jsonString))
+			rec := httptest.NewRecorder()
+
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			c := e.NewContext(req, rec)
+			uc := &mocks.MockChikitosUsecase{}
+			uc.CreateFn = func(ctx context.Context, url, description, alias string, options *domain.ChikitoOptions) (*domain.Chikito, error) {
+				return nil, aliasErr
+			}
+
+			h := NewChikitosRouter(e, uc, "")
+
+			err := h.create(c)
+			if !errors.Is(err, echo.ErrUnprocessableEntity) {
+				t.Errorf("Expected error to be echo.ErrUnprocessableEntity, got %v", err)
+			}
+		}
+	})
 }
 
 func TestGetChikito(t *testing.T) {
@@ -169,31 +230,40 @@ func TestGetChikito(t *testing.T) {
 		c.SetParamNames("id")
 		c.SetParamValues("ch_12345")
 
+		req.Header.Set("Referer", "https://news.ycombinator.com/item?id=1")
+		req.Header.Set("User-Agent", "curl/8.5.0")
+
 		uc := &mocks.MockChikitosUsecase{}
-		uc.GetFn = func(ctx context.Context, id string) (*domain.Chikito, error) {
+		uc.VisitFn = func(ctx context.Context, id string, visitor *domain.Visitor) (*domain.Chikito, error) {
 			if id != "ch_12345" {
 				return nil, domain.ErrChikitoNotFound
 			}
 
+			want := &domain.Visitor{IP: "192.0.2.1", Referrer: "https://news.ycombinator.com/item?id=1", UserAgent: "curl/8.5.0"}
+			if diff := cmp.Diff(want, visitor); diff != "" {
+				t.Errorf("Visitor mismatch (-want +got):\n%s", diff)
+			}
+
 			return &domain.Chikito{
-				ID:          1,
-				PublicID:    "ch_12345",
-				URL:         "https://example.com",
-				Description: "Some random description",
-				CreatedAt:   time.Now().UTC(),
-				UpdatedAt:   time.Now().UTC(),
+				ID:           1,
+				PublicID:     "ch_12345",
+				URL:          "https://example.com",
+				Description:  "Some random description",
+				RedirectCode: domain.DefaultRedirectCode,
+				CreatedAt:    time.Now().UTC(),
+				UpdatedAt:    time.Now().UTC(),
 			}, nil
 		}
 
-		h := NewChikitosRouter(e, uc)
+		h := NewChikitosRouter(e, uc, "")
 
 		err := h.get(c)
 		if err != nil {
 			t.Errorf("Expected no error getting chikito. Got: %v", err)
 		}
 
-		if rec.Code != http.StatusPermanentRedirect {
-			t.Errorf("Expected response code to be a 308 (StatusPermanentRedirect). Got: %d", rec.Code)
+		if rec.Code != http.StatusTemporaryRedirect {
+			t.Errorf("Expected response code to be a 307 (StatusTemporaryRedirect). Got: %d", rec.Code)
 		}
 
 		if rec.Result().Header.Get("Location") != "https://example.com" {
@@ -211,11 +281,11 @@ func TestGetChikito(t *testing.T) {
 		c.SetParamValues("ch_12345")
 
 		uc := &mocks.MockChikitosUsecase{}
-		uc.GetFn = func(ctx context.Context, id string) (*domain.Chikito, error) {
+		uc.VisitFn = func(ctx context.Context, id string, visitor *domain.Visitor) (*domain.Chikito, error) {
 			return nil, domain.ErrChikitoNotFound
 		}
 
-		h := NewChikitosRouter(e, uc)
+		h := NewChikitosRouter(e, uc, "")
 
 		err := h.get(c)
 		if err == nil {
@@ -236,11 +306,11 @@ func TestGetChikito(t *testing.T) {
 		c.SetParamNames("id")
 
 		uc := &mocks.MockChikitosUsecase{}
-		uc.GetFn = func(ctx context.Context, id string) (*domain.Chikito, error) {
+		uc.VisitFn = func(ctx context.Context, id string, visitor *domain.Visitor) (*domain.Chikito, error) {
 			return nil, domain.ErrChikitoNotFound
 		}
 
-		h := NewChikitosRouter(e, uc)
+		h := NewChikitosRouter(e, uc, "")
 
 		err := h.get(c)
 		if err == nil {
@@ -251,4 +321,476 @@ func TestGetChikito(t *testing.T) {
 			t.Errorf("Expected error to be a 422 (ErrUnprocessableEntity). Got: %v", err)
 		}
 	})
+
+	t.Run("It should return a gone error when the chikito no longer redirects", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/chikitos/:id", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("ch_12345")
+
+		uc := &mocks.MockChikitosUsecase{}
+		uc.VisitFn = func(ctx context.Context, id string, visitor *domain.Visitor) (*domain.Chikito, error) {
+			return nil, domain.ErrChikitoGone
+		}
+
+		h := NewChikitosRouter(e, uc, "")
+
+		err := h.get(c)
+
+		var httpErr *echo.HTTPError
+		if !errors.As(err, &httpErr) || httpErr.Code != http.StatusGone {
+			t.Errorf("Expected a 410 (StatusGone) error. Got: %v", err)
+		}
+	})
+
+	t.Run("It should redirect to the fallback URL when the chikito no longer redirects", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/chikitos/:id", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("ch_12345")
+
+		uc := &mocks.MockChikitosUsecase{}
+		uc.VisitFn = func(ctx context.Context, id string, visitor *domain.Visitor) (*domain.Chikito, error) {
+			return nil, domain.ErrChikitoGone
+		}
+
+		h := NewChikitosRouter(e, uc, "https://yurb.dev/expired")
+
+		if err := h.get(c); err != nil {
+			t.Fatalf("Expected no error getting chikito. Got: %v", err)
+		}
+
+		if rec.Code != http.StatusFound {
+			t.Errorf("Expected response code to be a 302 (StatusFound). Got: %d", rec.Code)
+		}
+
+		if location := rec.Result().Header.Get("Location"); location != "https://yurb.dev/expired" {
+			t.Errorf("Expected location to be the fallback URL. Got: %s", location)
+		}
+	})
+}
+
+func TestGetChikitoRedirect(t *testing.T) {
+	e := echo.New()
+	e.Validator = mods.NewAppValidator()
+
+	t.Run("It should redirect with the code of the chikito and forward the query", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/chikitos/talk-gophercon?ref=slides&utm_source=twitter", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/chikitos/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("talk-gophercon")
+
+		uc := &mocks.MockChikitosUsecase{}
+		uc.VisitFn = func(ctx context.Context, id string, visitor *domain.Visitor) (*domain.Chikito, error) {
+			return &domain.Chikito{
+				PublicID:     id,
+				URL:          "https://example.com/talk?lang=en",
+				RedirectCode: http.StatusMovedPermanently,
+				ForwardQuery: true,
+				UTM:          domain.UTM{Source: "chikitos", Campaign: "gophercon"},
+			}, nil
+		}
+
+		h := NewChikitosRouter(e, uc, "")
+
+		if err := h.get(c); err != nil {
+			t.Fatalf("Expected no error getting chikito. Got: %v", err)
+		}
+
+		if rec.Code != http.StatusMovedPermanently {
+			t.Errorf("Expected response code to be a 301 (StatusMovedPermanently). Got: %d", rec.Code)
+		}
+
+		want := "https://example.com/talk?lang=en&ref=slides&utm_campaign=gophercon&utm_source=chikitos"
+		if location := rec.Result().Header.Get("Location"); location != want {
+			t.Errorf("Expected location to be %q. Got: %q", want, location)
+		}
+	})
+}
+
+func TestUpdateChikito(t *testing.T) {
+	e := echo.New()
+	e.Validator = mods.NewAppValidator()
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	newContext := func(body string) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodPatch, "/chikitos/ch_12345", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/chikitos/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("ch_12345")
+
+		return c, rec
+	}
+
+	t.Run("It should update the given fields", func(t *testing.T) {
+		c, rec := newContext(`{"expires_at":"2030-01-01T00:00:00Z","max_clicks":100,"disabled":true}`)
+
+		uc := &mocks.MockChikitosUsecase{}
+		uc.UpdateFn = func(ctx context.Context, id string, update *domain.ChikitoUpdate) (*domain.Chikito, error) {
+			maxClicks := int32(100)
+			disabled := true
+			want := &domain.ChikitoUpdate{ExpiresAt: &expiresAt, MaxClicks: &maxClicks, Disabled: &disabled}
+			if diff := cmp.Diff(want, update); diff != "" {
+				t.Errorf("Update mismatch (-want +got):\n%s", diff)
+			}
+
+			return &domain.Chikito{
+				ExpiresAt: expiresAt,
+				PublicID:  id,
+				URL:       "https://example.com",
+				MaxClicks: 100,
+				Disabled:  true,
+			}, nil
+		}
+
+		h := NewChikitosRouter(e, uc, "")
+
+		if err := h.update(c); err != nil {
+			t.Fatalf("Expected no error updating chikito. Got: %v", err)
+		}
+
+		var got map[string]any
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatal(err)
+		}
+
+		if got["expires_at"] != "2030-01-01T00:00:00Z" || got["max_clicks"] != float64(100) || got["disabled"] != true {
+			t.Errorf("Unexpected chikito: %v", got)
+		}
+	})
+
+	t.Run("It should remove the expiry when it is null", func(t *testing.T) {
+		c, _ := newContext(`{"expires_at":null}`)
+
+		uc := &mocks.MockChikitosUsecase{}
+		uc.UpdateFn = func(ctx context.Context, id string, update *domain.ChikitoUpdate) (*domain.Chikito, error) {
+			if update.ExpiresAt == nil || !update.ExpiresAt.IsZero() {
+				t.Errorf("Expected a zero expiry. Got: %v", update.ExpiresAt)
+			}
+
+			if update.MaxClicks != nil || update.Disabled != nil || update.URL != nil {
+				t.Errorf("Expected the missing fields to be kept. Got: %+v", update)
+			}
+
+			return &domain.Chikito{PublicID: id}, nil
+		}
+
+		h := NewChikitosRouter(e, uc, "")
+
+		if err := h.update(c); err != nil {
+			t.Fatalf("Expected no error updating chikito. Got: %v", err)
+		}
+	})
+
+	t.Run("It should return an unprocessable entity error when the expiry is past", func(t *testing.T) {
+		c, _ := newContext(`{"expires_at":"2020-01-01T00:00:00Z"}`)
+
+		uc := &mocks.MockChikitosUsecase{}
+		uc.UpdateFn = func(ctx context.Context, id string, update *domain.ChikitoUpdate) (*domain.Chikito, error) {
+			return nil, domain.ErrInvalidExpiry
+		}
+
+		h := NewChikitosRouter(e, uc, "")
+
+		if err := h.update(c); !errors.Is(err, echo.ErrUnprocessableEntity) {
+			t.Errorf("Expected error to be a 422 (ErrUnprocessableEntity). Got: %v", err)
+		}
+	})
+
+	t.Run("It should return a not found error", func(t *testing.T) {
+		c, _ := newContext(`{"disabled":true}`)
+
+		uc := &mocks.MockChikitosUsecase{}
+		uc.UpdateFn = func(ctx context.Context, id string, update *domain.ChikitoUpdate) (*domain.Chikito, error) {
+			return nil, domain.ErrChikitoNotFound
+		}
+
+		h := NewChikitosRouter(e, uc, "")
+
+		if err := h.update(c); !errors.Is(err, echo.ErrNotFound) {
+			t.Errorf("Expected error to be a 404 (ErrNotFound). Got: %v", err)
+		}
+	})
+}
+
+func TestDeleteChikito(t *testing.T) {
+	e := echo.New()
+	e.Validator = mods.NewAppValidator()
+
+	newContext := func() (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodDelete, "/chikitos/ch_12345", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/chikitos/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("ch_12345")
+
+		return c, rec
+	}
+
+	t.Run("It should delete the chikito", func(t *testing.T) {
+		c, rec := newContext()
+
+		uc := &mocks.MockChikitosUsecase{}
+		uc.DeleteFn = func(ctx context.Context, id string) error {
+			if id != "ch_12345" {
+				t.Errorf("Expected chikito ch_12345 to be deleted. Got: %s", id)
+			}
+
+			return nil
+		}
+
+		h := NewChikitosRouter(e, uc, "")
+
+		if err := h.delete(c); err != nil {
+			t.Fatalf("Expected no error deleting chikito. Got: %v", err)
+		}
+
+		if rec.Code != http.StatusNoContent {
+			t.Errorf("Expected response code to be a 204 (StatusNoContent). Got: %d", rec.Code)
+		}
+	})
+
+	t.Run("It should return a not found error", func(t *testing.T) {
+		c, _ := newContext()
+
+		uc := &mocks.MockChikitosUsecase{}
+		uc.DeleteFn = func(ctx context.Context, id string) error {
+			return domain.ErrChikitoNotFound
+		}
+
+		h := NewChikitosRouter(e, uc, "")
+
+		if err := h.delete(c); !errors.Is(err, echo.ErrNotFound) {
+			t.Errorf("Expected error to be a 404 (ErrNotFound). Got: %v", err)
+		}
+	})
+}
+
+func TestGetChikitoStats(t *testing.T) {
+	e := echo.New()
+	e.Validator = mods.NewAppValidator()
+	day := time.Date(2024, 7, 14, 0, 0, 0, 0, time.UTC)
+
+	t.Run("It should return the stats of the chikito", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/chikitos/Talk-GopherCon/stats?days=7", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/chikitos/:id/stats")
+		c.SetParamNames("id")
+		c.SetParamValues("Talk-GopherCon")
+
+		uc := &mocks.MockChikitosUsecase{}
+		uc.GetStatsFn = func(ctx context.Context, id string, days, hours int) (*domain.ClickStats, error) {
+			if days != 7 || hours != 0 {
+				t.Errorf("Expected 7 days and the default hours. Got: %d days, %d hours", days, hours)
+			}
+
+			return &domain.ClickStats{
+				Referrers:  []domain.ClickTotal{{Name: "", Clicks: 2}, {Name: "twitter.com", Clicks: 1}},
+				UserAgents: []domain.ClickTotal{{Name: domain.UserAgentChrome, Clicks: 3}},
+				Daily:      []domain.ClickCount{{Time: day, Clicks: 3}},
+				Hourly:     []domain.ClickCount{{Time: day.Add(time.Hour), Clicks: 0}},
+				Total:      3,
+			}, nil
+		}
+
+		h := NewChikitosRouter(e, uc, "")
+
+		if err := h.getStats(c); err != nil {
+			t.Fatalf("Expected no error getting the stats. Got: %v", err)
+		}
+
+		want := map[string]any{
+			"id":          "talk-gophercon",
+			"total":       float64(3),
+			"referrers":   []any{map[string]any{"name": "", "clicks": float64(2)}, map[string]any{"name": "twitter.com", "clicks": float64(1)}},
+			"user_agents": []any{map[string]any{"name": "chrome", "clicks": float64(3)}},
+			"daily":       []any{map[string]any{"time": "2024-07-14T00:00:00Z", "clicks": float64(3)}},
+			"hourly":      []any{map[string]any{"time": "2024-07-14T01:00:00Z", "clicks": float64(0)}},
+		}
+
+		var got map[string]any
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatal(err)
+		}
+
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("Stats mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("It should return a not found error", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/chikitos/ch_12345/stats", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/chikitos/:id/stats")
+		c.SetParamNames("id")
+		c.SetParamValues("ch_12345")
+
+		uc := &mocks.MockChikitosUsecase{}
+		uc.GetStatsFn = func(ctx context.Context, id string, days, hours int) (*domain.ClickStats, error) {
+			return nil, domain.ErrChikitoNotFound
+		}
+
+		h := NewChikitosRouter(e, uc, "")
+
+		if err := h.getStats(c); !errors.Is(err, echo.ErrNotFound) {
+			t.Errorf("Expected error to be a 404 (ErrNotFound). Got: %v", err)
+		}
+	})
+
+	t.Run("It should reject too many days", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/chikitos/ch_12345/stats?days=1000", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/chikitos/:id/stats")
+		c.SetParamNames("id")
+		c.SetParamValues("ch_12345")
+
+		h := NewChikitosRouter(e, &mocks.MockChikitosUsecase{}, "")
+
+		if err := h.getStats(c); !errors.Is(err, echo.ErrUnprocessableEntity) {
+			t.Errorf("Expected error to be a 422 (ErrUnprocessableEntity). Got: %v", err)
+		}
+	})
+}
+
+func TestGetChikitoQRCode(t *testing.T) {
+	e := echo.New()
+	e.Validator = mods.NewAppValidator()
+
+	t.Run("It should return the QR code with the given options", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/chikitos/talk-gophercon/qr?format=svg&size=256&margin=0&level=h&foreground=%231a2b3c", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/chikitos/:id/qr")
+		c.SetParamNames("id")
+		c.SetParamValues("talk-gophercon")
+
+		uc := &mocks.MockChikitosUsecase{}
+		uc.GetQRCodeFn = func(ctx context.Context, id string, options *domain.QROptions) ([]byte, error) {
+			want := &domain.QROptions{
+				Foreground: color.RGBA{R: 0x1a, G: 0x2b, B: 0x3c, A: 0xff},
+				Background: color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+				Format:     domain.QRFormatSVG,
+				Level:      domain.QRLevelHigh,
+				Size:       256,
+				Margin:     0,
+			}
+
+			if diff := cmp.Diff(want, options); diff != "" {
+				t.Errorf("Options mismatch (-want +got):\n%s", diff)
+			}
+
+			return []byte("<svg/>"), nil
+		}
+
+		h := NewChikitosRouter(e, uc, "")
+
+		if err := h.getQRCode(c); err != nil {
+			t.Fatalf("Expected no error getting the QR code. Got: %v", err)
+		}
+
+		if got := rec.Header().Get(echo.HeaderContentType); got != "image/svg+xml" {
+			t.Errorf("Expected an SVG content type. Got: %s", got)
+		}
+
+		if got := rec.Body.String(); got != "<svg/>" {
+			t.Errorf("Expected the rendered QR code. Got: %s", got)
+		}
+	})
+
+	t.Run("It should default to a PNG", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/chikitos/talk-gophercon/qr", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/chikitos/:id/qr")
+		c.SetParamNames("id")
+		c.SetParamValues("talk-gophercon")
+
+		uc := &mocks.MockChikitosUsecase{}
+		uc.GetQRCodeFn = func(ctx context.Context, id string, options *domain.QROptions) ([]byte, error) {
+			if diff := cmp.Diff(domain.DefaultQROptions(), options); diff != "" {
+				t.Errorf("Options mismatch (-want +got):\n%s", diff)
+			}
+
+			return []byte("png"), nil
+		}
+
+		h := NewChikitosRouter(e, uc, "")
+
+		if err := h.getQRCode(c); err != nil {
+			t.Fatalf("Expected no error getting the QR code. Got: %v", err)
+		}
+
+		if got := rec.Header().Get(echo.HeaderContentType); got != "image/png" {
+			t.Errorf("Expected a PNG content type. Got: %s", got)
+		}
+	})
+
+	invalid := []string{"format=gif", "size=10000", "margin=-1", "foreground=blue", "level=X"}
+	for _, query := range invalid {
+		t.Run("It should reject "+query, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/chikitos/talk-gophercon/qr?"+query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			c.SetPath("/chikitos/:id/qr")
+			c.SetParamNames("id")
+			c.SetParamValues("talk-gophercon")
+
+			uc := &mocks.MockChikitosUsecase{}
+			uc.GetQRCodeFn = func(ctx context.Context, id string, options *domain.QROptions) ([]byte, error) {
+				return nil, options.Validate()
+			}
+
+			h := NewChikitosRouter(e, uc, "")
+
+			if err := h.getQRCode(c); !errors.Is(err, echo.ErrUnprocessableEntity) {
+				t.Errorf("Expected error to be a 422 (ErrUnprocessableEntity). Got: %v", err)
+			}
+		})
+	}
+
+	t.Run("It should return a gone error", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/chikitos/ch_12345/qr", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/chikitos/:id/qr")
+		c.SetParamNames("id")
+		c.SetParamValues("ch_12345")
+
+		uc := &mocks.MockChikitosUsecase{}
+		uc.GetQRCodeFn = func(ctx context.Context, id string, options *domain.QROptions) ([]byte, error) {
+			return nil, domain.ErrChikitoGone
+		}
+
+		h := NewChikitosRouter(e, uc, "")
+
+		var httpErr *echo.HTTPError
+		if err := h.getQRCode(c); !errors.As(err, &httpErr) || httpErr.Code != http.StatusGone {
+			t.Errorf("Expected a 410 error. Got: %v", err)
+		}
+	})
 }
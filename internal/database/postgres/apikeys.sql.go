@@ -7,20 +7,157 @@ package postgres
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const claimSignatureNonce = `-- name: ClaimSignatureNonce :execrows
+INSERT INTO signature_nonces (key_id, nonce, expires_at) VALUES ($1, $2, $3)
+ON CONFLICT (key_id, nonce) DO UPDATE SET expires_at = EXCLUDED.expires_at
+WHERE signature_nonces.expires_at <= $4::TIMESTAMP
+`
+
+type ClaimSignatureNonceParams struct {
+	KeyID     string
+	Nonce     string
+	ExpiresAt pgtype.Timestamp
+	Now       pgtype.Timestamp
+}
+
+func (q *Queries) ClaimSignatureNonce(ctx context.Context, arg ClaimSignatureNonceParams) (int64, error) {
+	result, err := q.db.Exec(ctx, claimSignatureNonce,
+		arg.KeyID,
+		arg.Nonce,
+		arg.ExpiresAt,
+		arg.Now,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const createAPIKey = `-- name: CreateAPIKey :one
-INSERT INTO apikeys (public_id, name, key) VALUES ($1, $2, $3) RETURNING id, public_id, name, key, revoked, revoked_at, created_at, updated_at
+INSERT INTO apikeys (public_id, name, key, key_id, scopes, expires_at, signing_secret, rate_limit) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, public_id, name, key, revoked, revoked_at, created_at, updated_at, expires_at, scopes, last_used_at, last_used_ip, key_id, signing_secret, rate_limit
 `
 
 type CreateAPIKeyParams struct {
-	PublicID string
-	Name     string
-	Key      string
+	PublicID      string
+	Name          string
+	Key           string
+	KeyID         string
+	Scopes        []string
+	ExpiresAt     pgtype.Timestamp
+	SigningSecret []byte
+	RateLimit     pgtype.Int4
 }
 
 func (q *Queries) CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (Apikey, error) {
-	row := q.db.QueryRow(ctx, createAPIKey, arg.PublicID, arg.Name, arg.Key)
+	row := q.db.QueryRow(ctx, createAPIKey,
+		arg.PublicID,
+		arg.Name,
+		arg.Key,
+		arg.KeyID,
+		arg.Scopes,
+		arg.ExpiresAt,
+		arg.SigningSecret,
+		arg.RateLimit,
+	)
+	var i Apikey
+	err := row.Scan(
+		&i.ID,
+		&i.PublicID,
+		&i.Name,
+		&i.Key,
+		&i.Revoked,
+		&i.RevokedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ExpiresAt,
+		&i.Scopes,
+		&i.LastUsedAt,
+		&i.LastUsedIp,
+		&i.KeyID,
+		&i.SigningSecret,
+		&i.RateLimit,
+	)
+	return i, err
+}
+
+const deleteExpiredSignatureNonces = `-- name: DeleteExpiredSignatureNonces :exec
+DELETE FROM signature_nonces WHERE expires_at <= $1
+`
+
+func (q *Queries) DeleteExpiredSignatureNonces(ctx context.Context, expiresAt pgtype.Timestamp) error {
+	_, err := q.db.Exec(ctx, deleteExpiredSignatureNonces, expiresAt)
+	return err
+}
+
+const expireAPIKey = `-- name: ExpireAPIKey :one
+UPDATE apikeys SET expires_at = LEAST(COALESCE(expires_at, $1::TIMESTAMP), $1::TIMESTAMP), updated_at = now() WHERE public_id = $2 RETURNING id, public_id, name, key, revoked, revoked_at, created_at, updated_at, expires_at, scopes, last_used_at, last_used_ip, key_id, signing_secret, rate_limit
+`
+
+type ExpireAPIKeyParams struct {
+	ExpiresAt pgtype.Timestamp
+	PublicID  string
+}
+
+func (q *Queries) ExpireAPIKey(ctx context.Context, arg ExpireAPIKeyParams) (Apikey, error) {
+	row := q.db.QueryRow(ctx, expireAPIKey, arg.ExpiresAt, arg.PublicID)
+	var i Apikey
+	err := row.Scan(
+		&i.ID,
+		&i.PublicID,
+		&i.Name,
+		&i.Key,
+		&i.Revoked,
+		&i.RevokedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ExpiresAt,
+		&i.Scopes,
+		&i.LastUsedAt,
+		&i.LastUsedIp,
+		&i.KeyID,
+		&i.SigningSecret,
+		&i.RateLimit,
+	)
+	return i, err
+}
+
+const getAPIKey = `-- name: GetAPIKey :one
+SELECT id, public_id, name, key, revoked, revoked_at, created_at, updated_at, expires_at, scopes, last_used_at, last_used_ip, key_id, signing_secret, rate_limit FROM apikeys WHERE public_id = $1
+`
+
+func (q *Queries) GetAPIKey(ctx context.Context, publicID string) (Apikey, error) {
+	row := q.db.QueryRow(ctx, getAPIKey, publicID)
+	var i Apikey
+	err := row.Scan(
+		&i.ID,
+		&i.PublicID,
+		&i.Name,
+		&i.Key,
+		&i.Revoked,
+		&i.RevokedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ExpiresAt,
+		&i.Scopes,
+		&i.LastUsedAt,
+		&i.LastUsedIp,
+		&i.KeyID,
+		&i.SigningSecret,
+		&i.RateLimit,
+	)
+	return i, err
+}
+
+const getAPIKeyByKeyID = `-- name: GetAPIKeyByKeyID :one
+SELECT id, public_id, name, key, revoked, revoked_at, created_at, updated_at, expires_at, scopes, last_used_at, last_used_ip, key_id, signing_secret, rate_limit FROM apikeys WHERE key_id = $1
+`
+
+func (q *Queries) GetAPIKeyByKeyID(ctx context.Context, keyID string) (Apikey, error) {
+	row := q.db.QueryRow(ctx, getAPIKeyByKeyID, keyID)
 	var i Apikey
 	err := row.Scan(
 		&i.ID,
@@ -31,16 +168,23 @@ func (q *Queries) CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (Api
 		&i.RevokedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ExpiresAt,
+		&i.Scopes,
+		&i.LastUsedAt,
+		&i.LastUsedIp,
+		&i.KeyID,
+		&i.SigningSecret,
+		&i.RateLimit,
 	)
 	return i, err
 }
 
-const getAPIKeyByValue = `-- name: GetAPIKeyByValue :one
-SELECT id, public_id, name, key, revoked, revoked_at, created_at, updated_at from apikeys WHERE key = $1
+const getAPIKeyForUpdate = `-- name: GetAPIKeyForUpdate :one
+SELECT id, public_id, name, key, revoked, revoked_at, created_at, updated_at, expires_at, scopes, last_used_at, last_used_ip, key_id, signing_secret, rate_limit FROM apikeys WHERE public_id = $1 FOR UPDATE
 `
 
-func (q *Queries) GetAPIKeyByValue(ctx context.Context, key string) (Apikey, error) {
-	row := q.db.QueryRow(ctx, getAPIKeyByValue, key)
+func (q *Queries) GetAPIKeyForUpdate(ctx context.Context, publicID string) (Apikey, error) {
+	row := q.db.QueryRow(ctx, getAPIKeyForUpdate, publicID)
 	var i Apikey
 	err := row.Scan(
 		&i.ID,
@@ -51,15 +195,99 @@ func (q *Queries) GetAPIKeyByValue(ctx context.Context, key string) (Apikey, err
 		&i.RevokedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ExpiresAt,
+		&i.Scopes,
+		&i.LastUsedAt,
+		&i.LastUsedIp,
+		&i.KeyID,
+		&i.SigningSecret,
+		&i.RateLimit,
 	)
 	return i, err
 }
 
-const revokeAPIKey = `-- name: RevokeAPIKey :exec
-UPDATE apikeys SET revoked_at = now(), updated_at = now(), revoked = true WHERE public_id = $1 RETURNING id, public_id, name, key, revoked, revoked_at, created_at, updated_at
+const getAPIKeys = `-- name: GetAPIKeys :many
+SELECT id, public_id, name, key, revoked, revoked_at, created_at, updated_at, expires_at, scopes, last_used_at, last_used_ip, key_id, signing_secret, rate_limit FROM apikeys ORDER BY created_at DESC, id DESC
 `
 
-func (q *Queries) RevokeAPIKey(ctx context.Context, publicID string) error {
-	_, err := q.db.Exec(ctx, revokeAPIKey, publicID)
+func (q *Queries) GetAPIKeys(ctx context.Context) ([]Apikey, error) {
+	rows, err := q.db.Query(ctx, getAPIKeys)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Apikey
+	for rows.Next() {
+		var i Apikey
+		if err := rows.Scan(
+			&i.ID,
+			&i.PublicID,
+			&i.Name,
+			&i.Key,
+			&i.Revoked,
+			&i.RevokedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ExpiresAt,
+			&i.Scopes,
+			&i.LastUsedAt,
+			&i.LastUsedIp,
+			&i.KeyID,
+			&i.SigningSecret,
+			&i.RateLimit,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const revokeAPIKey = `-- name: RevokeAPIKey :one
+UPDATE apikeys SET revoked_at = COALESCE(revoked_at, now()), updated_at = now(), revoked = true WHERE public_id = $1 RETURNING id, public_id, name, key, revoked, revoked_at, created_at, updated_at, expires_at, scopes, last_used_at, last_used_ip, key_id, signing_secret, rate_limit
+`
+
+func (q *Queries) RevokeAPIKey(ctx context.Context, publicID string) (Apikey, error) {
+	row := q.db.QueryRow(ctx, revokeAPIKey, publicID)
+	var i Apikey
+	err := row.Scan(
+		&i.ID,
+		&i.PublicID,
+		&i.Name,
+		&i.Key,
+		&i.Revoked,
+		&i.RevokedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ExpiresAt,
+		&i.Scopes,
+		&i.LastUsedAt,
+		&i.LastUsedIp,
+		&i.KeyID,
+		&i.SigningSecret,
+		&i.RateLimit,
+	)
+	return i, err
+}
+
+const updateAPIKeysUsage = `-- name: UpdateAPIKeysUsage :exec
+UPDATE apikeys SET last_used_at = usage.used_at, last_used_ip = NULLIF(usage.ip, '')
+FROM (
+  SELECT unnest($1::INTEGER[]) AS id, unnest($2::TIMESTAMP[]) AS used_at, unnest($3::VARCHAR[]) AS ip
+) AS usage
+WHERE apikeys.id = usage.id AND (apikeys.last_used_at IS NULL OR apikeys.last_used_at < usage.used_at)
+`
+
+type UpdateAPIKeysUsageParams struct {
+	Ids    []int32
+	UsedAt []pgtype.Timestamp
+	Ips    []string
+}
+
+func (q *Queries) UpdateAPIKeysUsage(ctx context.Context, arg UpdateAPIKeysUsageParams) error {
+	_, err := q.db.Exec(ctx, updateAPIKeysUsage, arg.Ids, arg.UsedAt, arg.Ips)
 	return err
 }
@@ -7,20 +7,58 @@ package postgres
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const claimChikitoClick = `-- name: ClaimChikitoClick :one
+UPDATE chikitos SET clicks = clicks + 1 WHERE id = $1 AND (max_clicks IS NULL OR clicks < max_clicks) RETURNING clicks
+`
+
+func (q *Queries) ClaimChikitoClick(ctx context.Context, id int32) (int32, error) {
+	row := q.db.QueryRow(ctx, claimChikitoClick, id)
+	var clicks int32
+	err := row.Scan(&clicks)
+	return clicks, err
+}
+
+const countChikitoClicks = `-- name: CountChikitoClicks :one
+SELECT count(*) FROM chikito_clicks WHERE chikito_id = $1
+`
+
+func (q *Queries) CountChikitoClicks(ctx context.Context, chikitoID int32) (int64, error) {
+	row := q.db.QueryRow(ctx, countChikitoClicks, chikitoID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createChikito = `-- name: CreateChikito :one
-INSERT INTO chikitos (public_id, url, description) VALUES ($1, $2, $3) RETURNING id, public_id, url, description, created_at, updated_at
+INSERT INTO chikitos (public_id, url, description, expires_at, max_clicks, redirect_code, forward_query, utm) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, public_id, url, description, created_at, updated_at, expires_at, max_clicks, disabled, clicks, redirect_code, forward_query, utm
 `
 
 type CreateChikitoParams struct {
-	PublicID    string
-	Url         string
-	Description string
+	PublicID     string
+	Url          string
+	Description  string
+	ExpiresAt    pgtype.Timestamp
+	MaxClicks    pgtype.Int4
+	RedirectCode int32
+	ForwardQuery bool
+	Utm          []byte
 }
 
 func (q *Queries) CreateChikito(ctx context.Context, arg CreateChikitoParams) (Chikito, error) {
-	row := q.db.QueryRow(ctx, createChikito, arg.PublicID, arg.Url, arg.Description)
+	row := q.db.QueryRow(ctx, createChikito,
+		arg.PublicID,
+		arg.Url,
+		arg.Description,
+		arg.ExpiresAt,
+		arg.MaxClicks,
+		arg.RedirectCode,
+		arg.ForwardQuery,
+		arg.Utm,
+	)
 	var i Chikito
 	err := row.Scan(
 		&i.ID,
@@ -29,12 +67,55 @@ func (q *Queries) CreateChikito(ctx context.Context, arg CreateChikitoParams) (C
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ExpiresAt,
+		&i.MaxClicks,
+		&i.Disabled,
+		&i.Clicks,
+		&i.RedirectCode,
+		&i.ForwardQuery,
+		&i.Utm,
 	)
 	return i, err
 }
 
+const createChikitoClicks = `-- name: CreateChikitoClicks :exec
+INSERT INTO chikito_clicks (chikito_id, clicked_at, referrer_host, user_agent_family, ip_hash)
+SELECT c.chikito_id, c.clicked_at, c.referrer_host, c.user_agent_family, c.ip_hash
+FROM unnest($1::INTEGER[], $2::TIMESTAMP[], $3::VARCHAR[], $4::VARCHAR[], $5::VARCHAR[])
+  AS c (chikito_id, clicked_at, referrer_host, user_agent_family, ip_hash)
+JOIN chikitos ON chikitos.id = c.chikito_id
+`
+
+type CreateChikitoClicksParams struct {
+	ChikitoIds        []int32
+	ClickedAt         []pgtype.Timestamp
+	ReferrerHosts     []string
+	UserAgentFamilies []string
+	IpHashes          []string
+}
+
+func (q *Queries) CreateChikitoClicks(ctx context.Context, arg CreateChikitoClicksParams) error {
+	_, err := q.db.Exec(ctx, createChikitoClicks,
+		arg.ChikitoIds,
+		arg.ClickedAt,
+		arg.ReferrerHosts,
+		arg.UserAgentFamilies,
+		arg.IpHashes,
+	)
+	return err
+}
+
+const deleteChikito = `-- name: DeleteChikito :exec
+DELETE FROM chikitos WHERE id = $1
+`
+
+func (q *Queries) DeleteChikito(ctx context.Context, id int32) error {
+	_, err := q.db.Exec(ctx, deleteChikito, id)
+	return err
+}
+
 const getChikito = `-- name: GetChikito :one
-SELECT id, public_id, url, description, created_at, updated_at FROM chikitos WHERE public_id = $1
+SELECT id, public_id, url, description, created_at, updated_at, expires_at, max_clicks, disabled, clicks, redirect_code, forward_query, utm FROM chikitos WHERE public_id = $1
 `
 
 func (q *Queries) GetChikito(ctx context.Context, publicID string) (Chikito, error) {
@@ -47,6 +128,252 @@ func (q *Queries) GetChikito(ctx context.Context, publicID string) (Chikito, err
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ExpiresAt,
+		&i.MaxClicks,
+		&i.Disabled,
+		&i.Clicks,
+		&i.RedirectCode,
+		&i.ForwardQuery,
+		&i.Utm,
+	)
+	return i, err
+}
+
+const getChikitoClickReferrers = `-- name: GetChikitoClickReferrers :many
+SELECT referrer_host, count(*) AS clicks
+FROM chikito_clicks
+WHERE chikito_id = $1
+GROUP BY referrer_host
+ORDER BY clicks DESC, referrer_host
+LIMIT $2
+`
+
+type GetChikitoClickReferrersRow struct {
+	ReferrerHost string
+	Clicks       int64
+}
+
+type GetChikitoClickReferrersParams struct {
+	ChikitoID int32
+	Limit     int32
+}
+
+func (q *Queries) GetChikitoClickReferrers(ctx context.Context, arg GetChikitoClickReferrersParams) ([]GetChikitoClickReferrersRow, error) {
+	rows, err := q.db.Query(ctx, getChikitoClickReferrers, arg.ChikitoID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetChikitoClickReferrersRow
+	for rows.Next() {
+		var i GetChikitoClickReferrersRow
+		if err := rows.Scan(
+			&i.ReferrerHost,
+			&i.Clicks,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getChikitoClickSeries = `-- name: GetChikitoClickSeries :many
+SELECT date_trunc($1::TEXT, clicked_at)::TIMESTAMP AS time, count(*) AS clicks
+FROM chikito_clicks
+WHERE chikito_id = $2 AND clicked_at >= $3::TIMESTAMP
+GROUP BY 1
+ORDER BY 1
+`
+
+type GetChikitoClickSeriesRow struct {
+	Time   pgtype.Timestamp
+	Clicks int64
+}
+
+type GetChikitoClickSeriesParams struct {
+	Bucket    string
+	ChikitoID int32
+	Since     pgtype.Timestamp
+}
+
+func (q *Queries) GetChikitoClickSeries(ctx context.Context, arg GetChikitoClickSeriesParams) ([]GetChikitoClickSeriesRow, error) {
+	rows, err := q.db.Query(ctx, getChikitoClickSeries, arg.Bucket, arg.ChikitoID, arg.Since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetChikitoClickSeriesRow
+	for rows.Next() {
+		var i GetChikitoClickSeriesRow
+		if err := rows.Scan(
+			&i.Time,
+			&i.Clicks,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getChikitoClickUserAgents = `-- name: GetChikitoClickUserAgents :many
+SELECT user_agent_family, count(*) AS clicks
+FROM chikito_clicks
+WHERE chikito_id = $1
+GROUP BY user_agent_family
+ORDER BY clicks DESC, user_agent_family
+LIMIT $2
+`
+
+type GetChikitoClickUserAgentsRow struct {
+	UserAgentFamily string
+	Clicks          int64
+}
+
+type GetChikitoClickUserAgentsParams struct {
+	ChikitoID int32
+	Limit     int32
+}
+
+func (q *Queries) GetChikitoClickUserAgents(ctx context.Context, arg GetChikitoClickUserAgentsParams) ([]GetChikitoClickUserAgentsRow, error) {
+	rows, err := q.db.Query(ctx, getChikitoClickUserAgents, arg.ChikitoID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetChikitoClickUserAgentsRow
+	for rows.Next() {
+		var i GetChikitoClickUserAgentsRow
+		if err := rows.Scan(
+			&i.UserAgentFamily,
+			&i.Clicks,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getChikitoForUpdate = `-- name: GetChikitoForUpdate :one
+SELECT id, public_id, url, description, created_at, updated_at, expires_at, max_clicks, disabled, clicks, redirect_code, forward_query, utm FROM chikitos WHERE public_id = $1 FOR UPDATE
+`
+
+func (q *Queries) GetChikitoForUpdate(ctx context.Context, publicID string) (Chikito, error) {
+	row := q.db.QueryRow(ctx, getChikitoForUpdate, publicID)
+	var i Chikito
+	err := row.Scan(
+		&i.ID,
+		&i.PublicID,
+		&i.Url,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ExpiresAt,
+		&i.MaxClicks,
+		&i.Disabled,
+		&i.Clicks,
+		&i.RedirectCode,
+		&i.ForwardQuery,
+		&i.Utm,
+	)
+	return i, err
+}
+
+const getChikitos = `-- name: GetChikitos :many
+SELECT id, public_id, url, description, created_at, updated_at, expires_at, max_clicks, disabled, clicks, redirect_code, forward_query, utm FROM chikitos ORDER BY id
+`
+
+func (q *Queries) GetChikitos(ctx context.Context) ([]Chikito, error) {
+	rows, err := q.db.Query(ctx, getChikitos)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chikito
+	for rows.Next() {
+		var i Chikito
+		if err := rows.Scan(
+			&i.ID,
+			&i.PublicID,
+			&i.Url,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ExpiresAt,
+			&i.MaxClicks,
+			&i.Disabled,
+			&i.Clicks,
+			&i.RedirectCode,
+			&i.ForwardQuery,
+			&i.Utm,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateChikito = `-- name: UpdateChikito :one
+UPDATE chikitos SET url = $2, description = $3, expires_at = $4, max_clicks = $5, disabled = $6,
+  redirect_code = $7, forward_query = $8, utm = $9, updated_at = now()
+WHERE id = $1
+RETURNING id, public_id, url, description, created_at, updated_at, expires_at, max_clicks, disabled, clicks, redirect_code, forward_query, utm
+`
+
+type UpdateChikitoParams struct {
+	ID           int32
+	Url          string
+	Description  string
+	ExpiresAt    pgtype.Timestamp
+	MaxClicks    pgtype.Int4
+	Disabled     bool
+	RedirectCode int32
+	ForwardQuery bool
+	Utm          []byte
+}
+
+func (q *Queries) UpdateChikito(ctx context.Context, arg UpdateChikitoParams) (Chikito, error) {
+	row := q.db.QueryRow(ctx, updateChikito,
+		arg.ID,
+		arg.Url,
+		arg.Description,
+		arg.ExpiresAt,
+		arg.MaxClicks,
+		arg.Disabled,
+		arg.RedirectCode,
+		arg.ForwardQuery,
+		arg.Utm,
+	)
+	var i Chikito
+	err := row.Scan(
+		&i.ID,
+		&i.PublicID,
+		&i.Url,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ExpiresAt,
+		&i.MaxClicks,
+		&i.Disabled,
+		&i.Clicks,
+		&i.RedirectCode,
+		&i.ForwardQuery,
+		&i.Utm,
 	)
 	return i, err
 }
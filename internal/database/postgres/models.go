@@ -17,6 +17,7 @@ const (
 	PostStatusDraft     PostStatus = "draft"
 	PostStatusPublished PostStatus = "published"
 	PostStatusArchived  PostStatus = "archived"
+	PostStatusScheduled PostStatus = "scheduled"
 )
 
 func (e *PostStatus) Scan(src interface{}) error {
@@ -55,37 +56,84 @@ func (ns NullPostStatus) Value() (driver.Value, error) {
 }
 
 type Chikito struct {
-	ID          int32
-	PublicID    string
-	Url         string
-	Description string
-	CreatedAt   pgtype.Timestamp
-	UpdatedAt   pgtype.Timestamp
+	ID           int32
+	PublicID     string
+	Url          string
+	Description  string
+	CreatedAt    pgtype.Timestamp
+	UpdatedAt    pgtype.Timestamp
+	ExpiresAt    pgtype.Timestamp
+	MaxClicks    pgtype.Int4
+	Disabled     bool
+	Clicks       int32
+	RedirectCode int32
+	ForwardQuery bool
+	Utm          []byte
+}
+
+type ChikitoClick struct {
+	ID              int64
+	ChikitoID       int32
+	ClickedAt       pgtype.Timestamp
+	ReferrerHost    string
+	UserAgentFamily string
+	IpHash          string
 }
 
 type Apikey struct {
-	ID        int32
-	PublicID  string
-	Name      string
-	Key       string
-	Revoked   bool
-	RevokedAt pgtype.Timestamp
-	CreatedAt pgtype.Timestamp
-	UpdatedAt pgtype.Timestamp
+	ID            int32
+	PublicID      string
+	Name          string
+	Key           string
+	Revoked       bool
+	RevokedAt     pgtype.Timestamp
+	CreatedAt     pgtype.Timestamp
+	UpdatedAt     pgtype.Timestamp
+	ExpiresAt     pgtype.Timestamp
+	Scopes        []string
+	LastUsedAt    pgtype.Timestamp
+	LastUsedIp    pgtype.Text
+	KeyID         string
+	SigningSecret []byte
+	RateLimit     pgtype.Int4
 }
 
 type Post struct {
+	ID           int32
+	PublicID     string
+	Title        string
+	Author       string
+	Content      string
+	Description  string
+	Slug         string
+	Status       PostStatus
+	PublishedAt  pgtype.Timestamp
+	CreatedAt    pgtype.Timestamp
+	UpdatedAt    pgtype.Timestamp
+	PublishAt    pgtype.Timestamp
+	SearchVector interface{}
+}
+
+type PostRevision struct {
 	ID          int32
-	PublicID    string
+	PostID      int32
+	Revision    int32
 	Title       string
-	Author      string
-	Content     string
 	Description string
-	Slug        string
-	Status      PostStatus
-	PublishedAt pgtype.Timestamp
+	Content     string
 	CreatedAt   pgtype.Timestamp
-	UpdatedAt   pgtype.Timestamp
+}
+
+type PostSlugHistory struct {
+	ID        int32
+	PostID    int32
+	Slug      string
+	CreatedAt pgtype.Timestamp
+}
+
+type PostTag struct {
+	PostID int32
+	TagID  int32
 }
 
 type Project struct {
@@ -100,4 +148,35 @@ type Project struct {
 	CreatedAt    pgtype.Timestamp
 	UpdatedAt    pgtype.Timestamp
 	PostID       pgtype.Int4
+	SearchVector interface{}
+}
+
+type Tag struct {
+	ID        int32
+	Name      string
+	CreatedAt pgtype.Timestamp
+}
+
+type AuditLog struct {
+	ID         int32
+	Actor      string
+	Action     string
+	EntityType string
+	EntityID   string
+	RequestID  string
+	Ip         string
+	Diff       []byte
+	CreatedAt  pgtype.Timestamp
+}
+
+type RateLimit struct {
+	Key         string
+	WindowStart pgtype.Timestamp
+	Hits        int32
+}
+
+type SignatureNonce struct {
+	KeyID     string
+	Nonce     string
+	ExpiresAt pgtype.Timestamp
 }
@@ -12,7 +12,7 @@ import (
 )
 
 const createPost = `-- name: CreatePost :one
-INSERT INTO posts (public_id, title, author, slug, description, content) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, public_id, title, author, content, description, slug, status, published_at, created_at, updated_at
+INSERT INTO posts (public_id, title, author, slug, description, content) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, public_id, title, author, content, description, slug, status, published_at, created_at, updated_at, publish_at, search_vector
 `
 
 type CreatePostParams struct {
@@ -46,12 +46,102 @@ func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, e
 		&i.PublishedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PublishAt,
+		&i.SearchVector,
 	)
 	return i, err
 }
 
+const createPostRevision = `-- name: CreatePostRevision :one
+INSERT INTO post_revisions (post_id, revision, title, description, content)
+VALUES ($1, COALESCE((SELECT MAX(revision) FROM post_revisions WHERE post_id = $1), 0) + 1, $2, $3, $4)
+RETURNING id, post_id, revision, title, description, content, created_at
+`
+
+type CreatePostRevisionParams struct {
+	PostID      int32
+	Title       string
+	Description string
+	Content     string
+}
+
+func (q *Queries) CreatePostRevision(ctx context.Context, arg CreatePostRevisionParams) (PostRevision, error) {
+	row := q.db.QueryRow(ctx, createPostRevision,
+		arg.PostID,
+		arg.Title,
+		arg.Description,
+		arg.Content,
+	)
+	var i PostRevision
+	err := row.Scan(
+		&i.ID,
+		&i.PostID,
+		&i.Revision,
+		&i.Title,
+		&i.Description,
+		&i.Content,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createPostSlugHistory = `-- name: CreatePostSlugHistory :exec
+INSERT INTO post_slug_history (post_id, slug) VALUES ($1, $2) ON CONFLICT (slug) DO UPDATE SET post_id = EXCLUDED.post_id, created_at = now()
+`
+
+type CreatePostSlugHistoryParams struct {
+	PostID int32
+	Slug   string
+}
+
+func (q *Queries) CreatePostSlugHistory(ctx context.Context, arg CreatePostSlugHistoryParams) error {
+	_, err := q.db.Exec(ctx, createPostSlugHistory, arg.PostID, arg.Slug)
+	return err
+}
+
+const createPostTags = `-- name: CreatePostTags :exec
+INSERT INTO post_tags (post_id, tag_id) SELECT $1::integer, id FROM tags WHERE name = ANY($2::varchar[])
+`
+
+type CreatePostTagsParams struct {
+	PostID int32
+	Names  []string
+}
+
+func (q *Queries) CreatePostTags(ctx context.Context, arg CreatePostTagsParams) error {
+	_, err := q.db.Exec(ctx, createPostTags, arg.PostID, arg.Names)
+	return err
+}
+
+const createTags = `-- name: CreateTags :exec
+INSERT INTO tags (name) SELECT unnest($1::varchar[]) ON CONFLICT (name) DO NOTHING
+`
+
+func (q *Queries) CreateTags(ctx context.Context, names []string) error {
+	_, err := q.db.Exec(ctx, createTags, names)
+	return err
+}
+
+const deletePostSlugHistory = `-- name: DeletePostSlugHistory :exec
+DELETE FROM post_slug_history WHERE slug = $1
+`
+
+func (q *Queries) DeletePostSlugHistory(ctx context.Context, slug string) error {
+	_, err := q.db.Exec(ctx, deletePostSlugHistory, slug)
+	return err
+}
+
+const deletePostTags = `-- name: DeletePostTags :exec
+DELETE FROM post_tags WHERE post_id = $1
+`
+
+func (q *Queries) DeletePostTags(ctx context.Context, postID int32) error {
+	_, err := q.db.Exec(ctx, deletePostTags, postID)
+	return err
+}
+
 const getPost = `-- name: GetPost :one
-SELECT id, public_id, title, author, content, description, slug, status, published_at, created_at, updated_at FROM posts WHERE public_id = $1
+SELECT id, public_id, title, author, content, description, slug, status, published_at, created_at, updated_at, publish_at, search_vector FROM posts WHERE public_id = $1
 `
 
 func (q *Queries) GetPost(ctx context.Context, publicID string) (Post, error) {
@@ -69,12 +159,39 @@ func (q *Queries) GetPost(ctx context.Context, publicID string) (Post, error) {
 		&i.PublishedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PublishAt,
+		&i.SearchVector,
+	)
+	return i, err
+}
+
+const getPostByPreviousSlug = `-- name: GetPostByPreviousSlug :one
+SELECT posts.id, posts.public_id, posts.title, posts.author, posts.content, posts.description, posts.slug, posts.status, posts.published_at, posts.created_at, posts.updated_at, posts.publish_at, posts.search_vector FROM posts JOIN post_slug_history ON post_slug_history.post_id = posts.id WHERE post_slug_history.slug = $1
+`
+
+func (q *Queries) GetPostByPreviousSlug(ctx context.Context, slug string) (Post, error) {
+	row := q.db.QueryRow(ctx, getPostByPreviousSlug, slug)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.PublicID,
+		&i.Title,
+		&i.Author,
+		&i.Content,
+		&i.Description,
+		&i.Slug,
+		&i.Status,
+		&i.PublishedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PublishAt,
+		&i.SearchVector,
 	)
 	return i, err
 }
 
 const getPostBySlug = `-- name: GetPostBySlug :one
-SELECT id, public_id, title, author, content, description, slug, status, published_at, created_at, updated_at FROM posts WHERE slug = $1
+SELECT id, public_id, title, author, content, description, slug, status, published_at, created_at, updated_at, publish_at, search_vector FROM posts WHERE slug = $1
 `
 
 func (q *Queries) GetPostBySlug(ctx context.Context, slug string) (Post, error) {
@@ -92,16 +209,265 @@ func (q *Queries) GetPostBySlug(ctx context.Context, slug string) (Post, error)
 		&i.PublishedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PublishAt,
+		&i.SearchVector,
+	)
+	return i, err
+}
+
+const getPostForUpdate = `-- name: GetPostForUpdate :one
+SELECT id, public_id, title, author, content, description, slug, status, published_at, created_at, updated_at, publish_at, search_vector FROM posts WHERE id = $1 FOR UPDATE
+`
+
+func (q *Queries) GetPostForUpdate(ctx context.Context, id int32) (Post, error) {
+	row := q.db.QueryRow(ctx, getPostForUpdate, id)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.PublicID,
+		&i.Title,
+		&i.Author,
+		&i.Content,
+		&i.Description,
+		&i.Slug,
+		&i.Status,
+		&i.PublishedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PublishAt,
+		&i.SearchVector,
+	)
+	return i, err
+}
+
+const getPostRevision = `-- name: GetPostRevision :one
+SELECT id, post_id, revision, title, description, content, created_at FROM post_revisions WHERE post_id = $1 AND revision = $2
+`
+
+type GetPostRevisionParams struct {
+	PostID   int32
+	Revision int32
+}
+
+func (q *Queries) GetPostRevision(ctx context.Context, arg GetPostRevisionParams) (PostRevision, error) {
+	row := q.db.QueryRow(ctx, getPostRevision, arg.PostID, arg.Revision)
+	var i PostRevision
+	err := row.Scan(
+		&i.ID,
+		&i.PostID,
+		&i.Revision,
+		&i.Title,
+		&i.Description,
+		&i.Content,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getPostRevisions = `-- name: GetPostRevisions :many
+SELECT id, post_id, revision, title, description, content, created_at FROM post_revisions WHERE post_id = $1 ORDER BY revision DESC
+`
+
+func (q *Queries) GetPostRevisions(ctx context.Context, postID int32) ([]PostRevision, error) {
+	rows, err := q.db.Query(ctx, getPostRevisions, postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PostRevision
+	for rows.Next() {
+		var i PostRevision
+		if err := rows.Scan(
+			&i.ID,
+			&i.PostID,
+			&i.Revision,
+			&i.Title,
+			&i.Description,
+			&i.Content,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPostSlugHistory = `-- name: GetPostSlugHistory :one
+SELECT id, post_id, slug, created_at FROM post_slug_history WHERE slug = $1
+`
+
+func (q *Queries) GetPostSlugHistory(ctx context.Context, slug string) (PostSlugHistory, error) {
+	row := q.db.QueryRow(ctx, getPostSlugHistory, slug)
+	var i PostSlugHistory
+	err := row.Scan(
+		&i.ID,
+		&i.PostID,
+		&i.Slug,
+		&i.CreatedAt,
 	)
 	return i, err
 }
 
 const getPosts = `-- name: GetPosts :many
-SELECT id, public_id, title, author, content, description, slug, status, published_at, created_at, updated_at FROM posts WHERE status = 'published' ORDER BY published_at DESC
+SELECT id, public_id, title, author, content, description, slug, status, published_at, created_at, updated_at, publish_at, search_vector FROM posts
+WHERE status = 'published'
+  AND ($1::text IS NULL OR author = $1)
+  AND ($2::text IS NULL OR EXISTS (
+    SELECT 1 FROM post_tags JOIN tags ON tags.id = post_tags.tag_id WHERE post_tags.post_id = posts.id AND tags.name = $2
+  ))
+  AND ($3::timestamp IS NULL OR published_at >= $3)
+  AND ($4::timestamp IS NULL OR published_at < $4)
+  AND ($5::timestamp IS NULL OR (published_at, id) < ($5, $6::integer))
+ORDER BY published_at DESC, id DESC
+LIMIT $7
+`
+
+type GetPostsParams struct {
+	Author            pgtype.Text
+	Tag               pgtype.Text
+	PublishedAfter    pgtype.Timestamp
+	PublishedBefore   pgtype.Timestamp
+	CursorPublishedAt pgtype.Timestamp
+	CursorID          pgtype.Int4
+	Limit             int32
+}
+
+func (q *Queries) GetPosts(ctx context.Context, arg GetPostsParams) ([]Post, error) {
+	rows, err := q.db.Query(ctx, getPosts,
+		arg.Author,
+		arg.Tag,
+		arg.PublishedAfter,
+		arg.PublishedBefore,
+		arg.CursorPublishedAt,
+		arg.CursorID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Post
+	for rows.Next() {
+		var i Post
+		if err := rows.Scan(
+			&i.ID,
+			&i.PublicID,
+			&i.Title,
+			&i.Author,
+			&i.Content,
+			&i.Description,
+			&i.Slug,
+			&i.Status,
+			&i.PublishedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PublishAt,
+			&i.SearchVector,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPostsTags = `-- name: GetPostsTags :many
+SELECT post_tags.post_id, tags.name FROM post_tags
+JOIN tags ON tags.id = post_tags.tag_id
+WHERE post_tags.post_id = ANY($1::integer[])
+ORDER BY tags.name
 `
 
-func (q *Queries) GetPosts(ctx context.Context) ([]Post, error) {
-	rows, err := q.db.Query(ctx, getPosts)
+type GetPostsTagsRow struct {
+	PostID int32
+	Name   string
+}
+
+func (q *Queries) GetPostsTags(ctx context.Context, postIds []int32) ([]GetPostsTagsRow, error) {
+	rows, err := q.db.Query(ctx, getPostsTags, postIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPostsTagsRow
+	for rows.Next() {
+		var i GetPostsTagsRow
+		if err := rows.Scan(
+			&i.PostID,
+			&i.Name,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTags = `-- name: GetTags :many
+SELECT tags.name, COUNT(posts.id) AS posts FROM tags
+JOIN post_tags ON post_tags.tag_id = tags.id
+JOIN posts ON posts.id = post_tags.post_id AND posts.status = 'published'
+GROUP BY tags.name
+ORDER BY tags.name
+`
+
+type GetTagsRow struct {
+	Name  string
+	Posts int64
+}
+
+func (q *Queries) GetTags(ctx context.Context) ([]GetTagsRow, error) {
+	rows, err := q.db.Query(ctx, getTags)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTagsRow
+	for rows.Next() {
+		var i GetTagsRow
+		if err := rows.Scan(
+			&i.Name,
+			&i.Posts,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const publishDuePosts = `-- name: PublishDuePosts :many
+UPDATE posts SET status = 'published', published_at = COALESCE(published_at, publish_at), publish_at = NULL, updated_at = now()
+WHERE id IN (
+  SELECT id FROM posts
+  WHERE status = 'scheduled' AND publish_at <= $1
+  ORDER BY publish_at
+  LIMIT $2
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING id, public_id, title, author, content, description, slug, status, published_at, created_at, updated_at, publish_at, search_vector
+`
+
+type PublishDuePostsParams struct {
+	PublishAt pgtype.Timestamp
+	Limit     int32
+}
+
+func (q *Queries) PublishDuePosts(ctx context.Context, arg PublishDuePostsParams) ([]Post, error) {
+	rows, err := q.db.Query(ctx, publishDuePosts, arg.PublishAt, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -121,6 +487,8 @@ func (q *Queries) GetPosts(ctx context.Context) ([]Post, error) {
 			&i.PublishedAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.PublishAt,
+			&i.SearchVector,
 		); err != nil {
 			return nil, err
 		}
@@ -133,7 +501,7 @@ func (q *Queries) GetPosts(ctx context.Context) ([]Post, error) {
 }
 
 const updatePost = `-- name: UpdatePost :one
-UPDATE posts SET title = $1, author = $2, slug = $3, description = $4, content = $5, status = $6, published_at = $7, updated_at = now() WHERE id = $8 RETURNING id, public_id, title, author, content, description, slug, status, published_at, created_at, updated_at
+UPDATE posts SET title = $1, author = $2, slug = $3, description = $4, content = $5, status = $6, published_at = $7, publish_at = $8, updated_at = now() WHERE id = $9 RETURNING id, public_id, title, author, content, description, slug, status, published_at, created_at, updated_at, publish_at, search_vector
 `
 
 type UpdatePostParams struct {
@@ -144,6 +512,7 @@ type UpdatePostParams struct {
 	Content     string
 	Status      PostStatus
 	PublishedAt pgtype.Timestamp
+	PublishAt   pgtype.Timestamp
 	ID          int32
 }
 
@@ -156,6 +525,7 @@ func (q *Queries) UpdatePost(ctx context.Context, arg UpdatePostParams) (Post, e
 		arg.Content,
 		arg.Status,
 		arg.PublishedAt,
+		arg.PublishAt,
 		arg.ID,
 	)
 	var i Post
@@ -171,6 +541,8 @@ func (q *Queries) UpdatePost(ctx context.Context, arg UpdatePostParams) (Post, e
 		&i.PublishedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PublishAt,
+		&i.SearchVector,
 	)
 	return i, err
 }
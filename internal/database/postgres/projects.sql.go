@@ -12,7 +12,7 @@ import (
 )
 
 const createProject = `-- name: CreateProject :one
-INSERT INTO projects (public_id, name, description, tags, thumbnail_url, website_url, live, post_id) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, public_id, name, description, tags, thumbnail_url, website_url, live, created_at, updated_at, post_id
+INSERT INTO projects (public_id, name, description, tags, thumbnail_url, website_url, live, post_id) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, public_id, name, description, tags, thumbnail_url, website_url, live, created_at, updated_at, post_id, search_vector
 `
 
 type CreateProjectParams struct {
@@ -50,12 +50,13 @@ func (q *Queries) CreateProject(ctx context.Context, arg CreateProjectParams) (P
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.PostID,
+		&i.SearchVector,
 	)
 	return i, err
 }
 
 const getProject = `-- name: GetProject :one
-SELECT id, public_id, name, description, tags, thumbnail_url, website_url, live, created_at, updated_at, post_id FROM projects WHERE public_id = $1
+SELECT id, public_id, name, description, tags, thumbnail_url, website_url, live, created_at, updated_at, post_id, search_vector FROM projects WHERE public_id = $1
 `
 
 func (q *Queries) GetProject(ctx context.Context, publicID string) (Project, error) {
@@ -73,12 +74,13 @@ func (q *Queries) GetProject(ctx context.Context, publicID string) (Project, err
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.PostID,
+		&i.SearchVector,
 	)
 	return i, err
 }
 
 const getProjects = `-- name: GetProjects :many
-SELECT id, public_id, name, description, tags, thumbnail_url, website_url, live, created_at, updated_at, post_id FROM projects ORDER BY created_at DESC
+SELECT id, public_id, name, description, tags, thumbnail_url, website_url, live, created_at, updated_at, post_id, search_vector FROM projects ORDER BY created_at DESC
 `
 
 func (q *Queries) GetProjects(ctx context.Context) ([]Project, error) {
@@ -102,6 +104,7 @@ func (q *Queries) GetProjects(ctx context.Context) ([]Project, error) {
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.PostID,
+			&i.SearchVector,
 		); err != nil {
 			return nil, err
 		}
@@ -0,0 +1,76 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/yavurb/goyurback/internal/feeds/domain"
+	postsDomain "github.com/yavurb/goyurback/internal/posts/domain"
+)
+
+func (uc *feedUsecase) GetFeed(ctx context.Context, tag string) (*domain.Feed, error) {
+	tag = postsDomain.NormalizeTag(tag)
+
+	page, err := uc.postUsecase.GetPosts(ctx, &postsDomain.PostFilter{
+		Tag:   tag,
+		Limit: domain.FeedSize,
+	})
+	if err != nil {
+		log.Printf("Error retrieving posts for the feed. Got: %v\n", err)
+
+		return nil, err
+	}
+
+	feed := &domain.Feed{
+		Title:       uc.siteTitle,
+		Description: fmt.Sprintf("Latest posts from %s", uc.siteTitle),
+		Link:        uc.siteURL,
+		Tag:         tag,
+		Items:       []*domain.FeedItem{},
+	}
+
+	if tag != "" {
+		feed.Title = fmt.Sprintf("%s - %s", uc.siteTitle, tag)
+		feed.Description = fmt.Sprintf("Latest posts tagged %s from %s", tag, uc.siteTitle)
+	}
+
+	for _, post := range page.Posts {
+		contentHTML, err := uc.postUsecase.Render(ctx, post)
+		if err != nil {
+			return nil, err
+		}
+
+		feed.Items = append(feed.Items, &domain.FeedItem{
+			Published:   post.PublishedAt,
+			Updated:     post.UpdatedAt,
+			ID:          uc.itemID(post),
+			Title:       post.Title,
+			Link:        fmt.Sprintf("%s/posts/%s", uc.siteURL, post.Slug),
+			Author:      post.Author,
+			Summary:     post.Description,
+			ContentHTML: contentHTML,
+			Tags:        post.Tags,
+		})
+
+		if post.UpdatedAt.After(feed.Updated) {
+			feed.Updated = post.UpdatedAt
+		}
+	}
+
+	return feed, nil
+}
+
+// itemID builds a tag URI (RFC 4151) for the post so feed readers don't show it again when its
+// slug changes.
+func (uc *feedUsecase) itemID(post *postsDomain.Post) string {
+	authority := uc.siteURL
+
+	if siteURL, err := url.Parse(uc.siteURL); err == nil && siteURL.Hostname() != "" {
+		authority = siteURL.Hostname()
+	}
+
+	return fmt.Sprintf("tag:%s,%s:posts/%s", authority, post.PublishedAt.UTC().Format(time.DateOnly), post.PublicID)
+}
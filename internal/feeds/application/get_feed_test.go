@@ -0,0 +1,140 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/yavurb/goyurback/internal/feeds/domain"
+	postsDomain "github.com/yavurb/goyurback/internal/posts/domain"
+	postMocks "github.com/yavurb/goyurback/internal/posts/infrastructure/ui/mocks"
+)
+
+func TestGetFeed(t *testing.T) {
+	publishedAt := time.Date(2024, 7, 15, 10, 30, 0, 0, time.UTC)
+	posts := []*postsDomain.Post{
+		{
+			ID:          2,
+			PublicID:    "po_2",
+			Title:       "Second post",
+			Author:      "Roy",
+			Slug:        "second-post",
+			Status:      postsDomain.Published,
+			Description: "The second post",
+			Content:     "# Second",
+			Tags:        []string{"go"},
+			PublishedAt: publishedAt,
+			UpdatedAt:   publishedAt.Add(time.Hour),
+		},
+		{
+			ID:          1,
+			PublicID:    "po_1",
+			Title:       "First post",
+			Author:      "Roy",
+			Slug:        "first-post",
+			Status:      postsDomain.Published,
+			Description: "The first post",
+			Content:     "# First",
+			PublishedAt: publishedAt.Add(-48 * time.Hour),
+			UpdatedAt:   publishedAt.Add(2 * time.Hour),
+		},
+	}
+
+	newPostUsecase := func(gotFilter **postsDomain.PostFilter) *postMocks.MockPostsUsecase {
+		return &postMocks.MockPostsUsecase{
+			GetPostsFn: func(ctx context.Context, filter *postsDomain.PostFilter) (*postsDomain.PostPage, error) {
+				*gotFilter = filter
+
+				return &postsDomain.PostPage{Posts: posts, NextCursor: "next"}, nil
+			},
+			RenderFn: func(ctx context.Context, post *postsDomain.Post) (string, error) {
+				return "<p>" + post.Title + "</p>", nil
+			},
+		}
+	}
+
+	t.Run("it should build the feed from the latest published posts", func(t *testing.T) {
+		var gotFilter *postsDomain.PostFilter
+
+		uc := NewFeedUsecase(newPostUsecase(&gotFilter), "yurb.dev", "https://yurb.dev/")
+
+		feed, err := uc.GetFeed(context.Background(), "")
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		if gotFilter.Limit != domain.FeedSize || gotFilter.Tag != "" {
+			t.Errorf("Expected the latest %d posts without a tag filter, got: %+v", domain.FeedSize, gotFilter)
+		}
+
+		want := &domain.Feed{
+			Updated:     publishedAt.Add(2 * time.Hour),
+			Title:       "yurb.dev",
+			Description: "Latest posts from yurb.dev",
+			Link:        "https://yurb.dev",
+			Items: []*domain.FeedItem{
+				{
+					Published:   publishedAt,
+					Updated:     publishedAt.Add(time.Hour),
+					ID:          "tag:yurb.dev,2024-07-15:posts/po_2",
+					Title:       "Second post",
+					Link:        "https://yurb.dev/posts/second-post",
+					Author:      "Roy",
+					Summary:     "The second post",
+					ContentHTML: "<p>Second post</p>",
+					Tags:        []string{"go"},
+				},
+				{
+					Published:   publishedAt.Add(-48 * time.Hour),
+					Updated:     publishedAt.Add(2 * time.Hour),
+					ID:          "tag:yurb.dev,2024-07-13:posts/po_1",
+					Title:       "First post",
+					Link:        "https://yurb.dev/posts/first-post",
+					Author:      "Roy",
+					Summary:     "The first post",
+					ContentHTML: "<p>First post</p>",
+				},
+			},
+		}
+
+		if diff := cmp.Diff(want, feed); diff != "" {
+			t.Errorf("GetFeed() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("it should filter the feed by tag", func(t *testing.T) {
+		var gotFilter *postsDomain.PostFilter
+
+		uc := NewFeedUsecase(newPostUsecase(&gotFilter), "yurb.dev", "https://yurb.dev")
+
+		feed, err := uc.GetFeed(context.Background(), " Go ")
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		if gotFilter.Tag != "go" {
+			t.Errorf("Expected tag filter to be go, got: %s", gotFilter.Tag)
+		}
+
+		if feed.Tag != "go" || feed.Title != "yurb.dev - go" {
+			t.Errorf("Expected a feed for the go tag, got: %s (%s)", feed.Title, feed.Tag)
+		}
+	})
+
+	t.Run("it should return an error if the posts can't be retrieved", func(t *testing.T) {
+		postUsecase := &postMocks.MockPostsUsecase{
+			GetPostsFn: func(ctx context.Context, filter *postsDomain.PostFilter) (*postsDomain.PostPage, error) {
+				return nil, errors.New("DB error")
+			},
+		}
+
+		uc := NewFeedUsecase(postUsecase, "yurb.dev", "https://yurb.dev")
+
+		_, err := uc.GetFeed(context.Background(), "")
+		if err == nil {
+			t.Error("Expected error, got nil")
+		}
+	})
+}
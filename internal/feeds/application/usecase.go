@@ -0,0 +1,22 @@
+package application
+
+import (
+	"strings"
+
+	"github.com/yavurb/goyurback/internal/feeds/domain"
+	postsDomain "github.com/yavurb/goyurback/internal/posts/domain"
+)
+
+type feedUsecase struct {
+	postUsecase postsDomain.PostUsecase
+	siteTitle   string
+	siteURL     string
+}
+
+func NewFeedUsecase(postUsecase postsDomain.PostUsecase, siteTitle, siteURL string) domain.FeedUsecase {
+	return &feedUsecase{
+		postUsecase: postUsecase,
+		siteTitle:   siteTitle,
+		siteURL:     strings.TrimSuffix(siteURL, "/"),
+	}
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// FeedSize is the number of posts included in a feed
+const FeedSize int32 = 20
+
+type Feed struct {
+	Updated     time.Time // Newest updated_at of the items. Zero when the feed is empty
+	Title       string
+	Description string
+	Link        string
+	Tag         string
+	Items       []*FeedItem
+}
+
+type FeedItem struct {
+	Published   time.Time
+	Updated     time.Time
+	ID          string // Stable across slug and title changes
+	Title       string
+	Link        string
+	Author      string
+	Summary     string
+	ContentHTML string
+	Tags        []string
+}
+
+// Version identifies the current state of the feed. It changes whenever a post in the feed is
+// updated or the set of posts changes, even when the newest updated_at stays the same.
+func (f *Feed) Version() string {
+	hash := sha256.New()
+
+	fmt.Fprintf(hash, "%s\n", f.Tag)
+
+	for _, item := range f.Items {
+		fmt.Fprintf(hash, "%s %d\n", item.ID, item.Updated.UnixNano())
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)[:16])
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFeedVersion(t *testing.T) {
+	updatedAt := time.Date(2024, 7, 15, 10, 30, 0, 0, time.UTC)
+	feed := func(items ...*FeedItem) *Feed {
+		return &Feed{Items: items}
+	}
+
+	first := &FeedItem{ID: "po_1", Updated: updatedAt}
+	second := &FeedItem{ID: "po_2", Updated: updatedAt.Add(-time.Hour)}
+
+	if feed(first, second).Version() != feed(first, second).Version() {
+		t.Error("Expected the same feed to have the same version")
+	}
+
+	if feed(first, second).Version() == feed(first).Version() {
+		t.Error("Expected the version to change when a post leaves the feed")
+	}
+
+	edited := &FeedItem{ID: "po_2", Updated: updatedAt.Add(-time.Minute)}
+	if feed(first, second).Version() == feed(first, edited).Version() {
+		t.Error("Expected the version to change when a post is updated")
+	}
+}
@@ -0,0 +1,9 @@
+package domain
+
+import "context"
+
+type FeedUsecase interface {
+	// GetFeed returns the latest published posts. The feed only includes posts with the given tag
+	// when tag is not empty.
+	GetFeed(ctx context.Context, tag string) (*Feed, error)
+}
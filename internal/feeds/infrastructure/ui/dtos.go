@@ -0,0 +1,218 @@
+package ui
+
+import (
+	"encoding/xml"
+	"time"
+
+	"github.com/yavurb/goyurback/internal/feeds/domain"
+)
+
+const (
+	MIMEApplicationRSS      = "application/rss+xml; charset=UTF-8"
+	MIMEApplicationAtom     = "application/atom+xml; charset=UTF-8"
+	MIMEApplicationJSONFeed = "application/feed+json; charset=UTF-8"
+)
+
+type RSSOut struct {
+	XMLName   xml.Name   `xml:"rss"`
+	Version   string     `xml:"version,attr"`
+	AtomNS    string     `xml:"xmlns:atom,attr"`
+	ContentNS string     `xml:"xmlns:content,attr"`
+	DCNS      string     `xml:"xmlns:dc,attr"`
+	Channel   RSSChannel `xml:"channel"`
+}
+
+type RSSChannel struct {
+	Title         string     `xml:"title"`
+	Link          string     `xml:"link"`
+	Description   string     `xml:"description"`
+	LastBuildDate string     `xml:"lastBuildDate,omitempty"`
+	Self          AtomLink   `xml:"atom:link"`
+	Items         []*RSSItem `xml:"item"`
+}
+
+type RSSItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        RSSGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+	Creator     string   `xml:"dc:creator"`
+	Description string   `xml:"description"`
+	Content     string   `xml:"content:encoded"`
+	Categories  []string `xml:"category"`
+}
+
+type RSSGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type AtomOut struct {
+	XMLName  xml.Name     `xml:"feed"`
+	NS       string       `xml:"xmlns,attr"`
+	ID       string       `xml:"id"`
+	Title    string       `xml:"title"`
+	Subtitle string       `xml:"subtitle"`
+	Updated  string       `xml:"updated"`
+	Links    []AtomLink   `xml:"link"`
+	Entries  []*AtomEntry `xml:"entry"`
+}
+
+type AtomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type AtomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Link       AtomLink       `xml:"link"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Author     AtomAuthor     `xml:"author"`
+	Summary    string         `xml:"summary"`
+	Content    AtomContent    `xml:"content"`
+	Categories []AtomCategory `xml:"category"`
+}
+
+type AtomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type AtomContent struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type AtomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type JSONFeedOut struct {
+	Version     string             `json:"version"`
+	Title       string             `json:"title"`
+	HomePageURL string             `json:"home_page_url"`
+	FeedURL     string             `json:"feed_url"`
+	Description string             `json:"description"`
+	Items       []*JSONFeedItemOut `json:"items"`
+}
+
+type JSONFeedItemOut struct {
+	DatePublished time.Time            `json:"date_published"`
+	DateModified  time.Time            `json:"date_modified"`
+	ID            string               `json:"id"`
+	URL           string               `json:"url"`
+	Title         string               `json:"title"`
+	Summary       string               `json:"summary"`
+	ContentHTML   string               `json:"content_html"`
+	Authors       []*JSONFeedAuthorOut `json:"authors"`
+	Tags          []string             `json:"tags,omitempty"`
+}
+
+type JSONFeedAuthorOut struct {
+	Name string `json:"name"`
+}
+
+func toRSSOut(feed *domain.Feed, selfURL string) *RSSOut {
+	rss := &RSSOut{
+		Version:   "2.0",
+		AtomNS:    "http://www.w3.org/2005/Atom",
+		ContentNS: "http://purl.org/rss/1.0/modules/content/",
+		DCNS:      "http://purl.org/dc/elements/1.1/",
+		Channel: RSSChannel{
+			Title:       feed.Title,
+			Link:        feed.Link,
+			Description: feed.Description,
+			Self:        AtomLink{Href: selfURL, Rel: "self", Type: "application/rss+xml"},
+			Items:       []*RSSItem{},
+		},
+	}
+
+	if !feed.Updated.IsZero() {
+		rss.Channel.LastBuildDate = feed.Updated.UTC().Format(time.RFC1123Z)
+	}
+
+	for _, item := range feed.Items {
+		rss.Channel.Items = append(rss.Channel.Items, &RSSItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			GUID:        RSSGUID{Value: item.ID},
+			PubDate:     item.Published.UTC().Format(time.RFC1123Z),
+			Creator:     item.Author,
+			Description: item.Summary,
+			Content:     item.ContentHTML,
+			Categories:  item.Tags,
+		})
+	}
+
+	return rss
+}
+
+func toAtomOut(feed *domain.Feed, selfURL string) *AtomOut {
+	updated := feed.Updated
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+
+	atom := &AtomOut{
+		NS:       "http://www.w3.org/2005/Atom",
+		ID:       selfURL,
+		Title:    feed.Title,
+		Subtitle: feed.Description,
+		Updated:  updated.UTC().Format(time.RFC3339),
+		Links: []AtomLink{
+			{Href: feed.Link, Rel: "alternate", Type: "text/html"},
+			{Href: selfURL, Rel: "self", Type: "application/atom+xml"},
+		},
+		Entries: []*AtomEntry{},
+	}
+
+	for _, item := range feed.Items {
+		entry := &AtomEntry{
+			ID:        item.ID,
+			Title:     item.Title,
+			Link:      AtomLink{Href: item.Link, Rel: "alternate", Type: "text/html"},
+			Published: item.Published.UTC().Format(time.RFC3339),
+			Updated:   item.Updated.UTC().Format(time.RFC3339),
+			Author:    AtomAuthor{Name: item.Author},
+			Summary:   item.Summary,
+			Content:   AtomContent{Type: "html", Value: item.ContentHTML},
+		}
+
+		for _, tag := range item.Tags {
+			entry.Categories = append(entry.Categories, AtomCategory{Term: tag})
+		}
+
+		atom.Entries = append(atom.Entries, entry)
+	}
+
+	return atom
+}
+
+func toJSONFeedOut(feed *domain.Feed, selfURL string) *JSONFeedOut {
+	jsonFeed := &JSONFeedOut{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       feed.Title,
+		HomePageURL: feed.Link,
+		FeedURL:     selfURL,
+		Description: feed.Description,
+		Items:       []*JSONFeedItemOut{},
+	}
+
+	for _, item := range feed.Items {
+		jsonFeed.Items = append(jsonFeed.Items, &JSONFeedItemOut{
+			DatePublished: item.Published.UTC(),
+			DateModified:  item.Updated.UTC(),
+			ID:            item.ID,
+			URL:           item.Link,
+			Title:         item.Title,
+			Summary:       item.Summary,
+			ContentHTML:   item.ContentHTML,
+			Authors:       []*JSONFeedAuthorOut{{Name: item.Author}},
+			Tags:          item.Tags,
+		})
+	}
+
+	return jsonFeed
+}
@@ -0,0 +1,15 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/yavurb/goyurback/internal/feeds/domain"
+)
+
+type MockFeedsUsecase struct {
+	GetFeedFn func(ctx context.Context, tag string) (*domain.Feed, error)
+}
+
+func (uc *MockFeedsUsecase) GetFeed(ctx context.Context, tag string) (*domain.Feed, error) {
+	return uc.GetFeedFn(ctx, tag)
+}
@@ -0,0 +1,130 @@
+package ui
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yavurb/goyurback/internal/feeds/domain"
+)
+
+type feedRouterCtx struct {
+	feedUsecase domain.FeedUsecase
+}
+
+func NewFeedsRouter(e *echo.Echo, feedUsecase domain.FeedUsecase) *feedRouterCtx {
+	routerGroup := e.Group("/feeds")
+	routerCtx := &feedRouterCtx{
+		feedUsecase,
+	}
+
+	routerGroup.GET("/rss.xml", routerCtx.getRSS)
+	routerGroup.GET("/atom.xml", routerCtx.getAtom)
+	routerGroup.GET("/feed.json", routerCtx.getJSONFeed)
+	routerGroup.GET("/tags/:tag/rss.xml", routerCtx.getRSS)
+	routerGroup.GET("/tags/:tag/atom.xml", routerCtx.getAtom)
+	routerGroup.GET("/tags/:tag/feed.json", routerCtx.getJSONFeed)
+
+	return routerCtx
+}
+
+func (ctx *feedRouterCtx) getRSS(c echo.Context) error {
+	feed, err := ctx.getFeed(c)
+	if err != nil {
+		return err
+	}
+
+	if notModified(c, feed, "rss") {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	return xmlBlob(c, MIMEApplicationRSS, toRSSOut(feed, selfURL(c, feed)))
+}
+
+func (ctx *feedRouterCtx) getAtom(c echo.Context) error {
+	feed, err := ctx.getFeed(c)
+	if err != nil {
+		return err
+	}
+
+	if notModified(c, feed, "atom") {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	return xmlBlob(c, MIMEApplicationAtom, toAtomOut(feed, selfURL(c, feed)))
+}
+
+func (ctx *feedRouterCtx) getJSONFeed(c echo.Context) error {
+	feed, err := ctx.getFeed(c)
+	if err != nil {
+		return err
+	}
+
+	if notModified(c, feed, "json") {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, MIMEApplicationJSONFeed)
+
+	return c.JSON(http.StatusOK, toJSONFeedOut(feed, selfURL(c, feed)))
+}
+
+func (ctx *feedRouterCtx) getFeed(c echo.Context) (*domain.Feed, error) {
+	feed, err := ctx.feedUsecase.GetFeed(c.Request().Context(), c.Param("tag"))
+	if err != nil {
+		return nil, HTTPError{
+			Message: "Unable to build the feed",
+		}.InternalServerError()
+	}
+
+	return feed, nil
+}
+
+// notModified sets the caching headers of the feed and reports whether the client already has
+// its current version. If-None-Match takes precedence over If-Modified-Since.
+func notModified(c echo.Context, feed *domain.Feed, format string) bool {
+	etag := `"` + format + "-" + feed.Version() + `"`
+	header := c.Response().Header()
+	header.Set("ETag", etag)
+
+	if !feed.Updated.IsZero() {
+		header.Set(echo.HeaderLastModified, feed.Updated.UTC().Format(http.TimeFormat))
+	}
+
+	if ifNoneMatch := c.Request().Header.Get("If-None-Match"); ifNoneMatch != "" {
+		for _, candidate := range strings.Split(ifNoneMatch, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == etag || candidate == "*" {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	ifModifiedSince, err := http.ParseTime(c.Request().Header.Get(echo.HeaderIfModifiedSince))
+	if err != nil || feed.Updated.IsZero() {
+		return false
+	}
+
+	// HTTP dates have second precision
+	return !feed.Updated.Truncate(time.Second).After(ifModifiedSince)
+}
+
+func xmlBlob(c echo.Context, contentType string, v any) error {
+	body, err := xml.Marshal(v)
+	if err != nil {
+		return HTTPError{
+			Message: "Unable to build the feed",
+		}.InternalServerError()
+	}
+
+	return c.Blob(http.StatusOK, contentType, append([]byte(xml.Header), body...))
+}
+
+// selfURL is the URL of the feed on the configured site, as the Host header is set by the client.
+func selfURL(c echo.Context, feed *domain.Feed) string {
+	return feed.Link + c.Request().URL.Path
+}
@@ -0,0 +1,329 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yavurb/goyurback/internal/feeds/domain"
+	"github.com/yavurb/goyurback/internal/feeds/infrastructure/ui/mocks"
+)
+
+func TestFeeds(t *testing.T) {
+	e := echo.New()
+
+	updatedAt := time.Date(2024, 7, 15, 12, 30, 0, 0, time.UTC)
+	feed := &domain.Feed{
+		Updated:     updatedAt,
+		Title:       "yurb.dev",
+		Description: "Latest posts from yurb.dev",
+		Link:        "https://yurb.dev",
+		Items: []*domain.FeedItem{
+			{
+				Published:   updatedAt.Add(-time.Hour),
+				Updated:     updatedAt,
+				ID:          "tag:yurb.dev,2024-07-15:posts/po_1",
+				Title:       "My post",
+				Link:        "https://yurb.dev/posts/my-post",
+				Author:      "Roy",
+				Summary:     "Some description",
+				ContentHTML: "<p>Some <em>content</em></p>",
+				Tags:        []string{"go"},
+			},
+		},
+	}
+
+	var gotTag string
+
+	uc := &mocks.MockFeedsUsecase{
+		GetFeedFn: func(ctx context.Context, tag string) (*domain.Feed, error) {
+			gotTag = tag
+
+			return feed, nil
+		},
+	}
+
+	newContext := func(path, tag string, headers map[string]string) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if tag != "" {
+			c.SetParamNames("tag")
+			c.SetParamValues(tag)
+		}
+
+		return c, rec
+	}
+
+	t.Run("it should return an RSS feed", func(t *testing.T) {
+		c, rec := newContext("/feeds/rss.xml", "", nil)
+		h := NewFeedsRouter(e, uc)
+
+		if err := h.getRSS(c); err != nil {
+			t.Fatalf("Expected no errors getting the feed. Got: %v", err)
+		}
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected status code to be %d. Got: %d", http.StatusOK, rec.Code)
+		}
+
+		if got := rec.Header().Get(echo.HeaderContentType); got != MIMEApplicationRSS {
+			t.Errorf("Expected content type to be %s. Got: %s", MIMEApplicationRSS, got)
+		}
+
+		if got := rec.Header().Get(echo.HeaderLastModified); got != "Mon, 15 Jul 2024 12:30:00 GMT" {
+			t.Errorf("Expected Last-Modified to be the newest update. Got: %s", got)
+		}
+
+		if rec.Header().Get("ETag") == "" {
+			t.Error("Expected an ETag header")
+		}
+
+		var got struct {
+			Channel struct {
+				Title string `xml:"title"`
+				Items []struct {
+					GUID    string `xml:"guid"`
+					Link    string `xml:"link"`
+					PubDate string `xml:"pubDate"`
+					Content string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+				} `xml:"item"`
+			} `xml:"channel"`
+		}
+
+		if err := xml.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatalf("Error unmarshalling response: %s", err)
+		}
+
+		if got.Channel.Title != "yurb.dev" || len(got.Channel.Items) != 1 {
+			t.Fatalf("Expected the feed channel with one item. Got: %+v", got.Channel)
+		}
+
+		item := got.Channel.Items[0]
+		if item.GUID != feed.Items[0].ID || item.Link != feed.Items[0].Link {
+			t.Errorf("Expected the item to point to the post. Got: %+v", item)
+		}
+
+		if item.PubDate != "Mon, 15 Jul 2024 11:30:00 +0000" {
+			t.Errorf("Expected pubDate to be an RFC 1123 date. Got: %s", item.PubDate)
+		}
+
+		if item.Content != feed.Items[0].ContentHTML {
+			t.Errorf("Expected the rendered content. Got: %s", item.Content)
+		}
+	})
+
+	t.Run("it should return an Atom feed", func(t *testing.T) {
+		c, rec := newContext("/feeds/atom.xml", "", nil)
+		h := NewFeedsRouter(e, uc)
+
+		if err := h.getAtom(c); err != nil {
+			t.Fatalf("Expected no errors getting the feed. Got: %v", err)
+		}
+
+		if got := rec.Header().Get(echo.HeaderContentType); got != MIMEApplicationAtom {
+			t.Errorf("Expected content type to be %s. Got: %s", MIMEApplicationAtom, got)
+		}
+
+		var got struct {
+			XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+			ID      string   `xml:"id"`
+			Updated string   `xml:"updated"`
+			Entries []struct {
+				ID      string `xml:"id"`
+				Content string `xml:"content"`
+			} `xml:"entry"`
+		}
+
+		if err := xml.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatalf("Error unmarshalling response: %s", err)
+		}
+
+		if got.ID != "https://yurb.dev/feeds/atom.xml" {
+			t.Errorf("Expected the feed id to be its own URL. Got: %s", got.ID)
+		}
+
+		if got.Updated != "2024-07-15T12:30:00Z" {
+			t.Errorf("Expected updated to be the newest update. Got: %s", got.Updated)
+		}
+
+		if len(got.Entries) != 1 || got.Entries[0].ID != feed.Items[0].ID || got.Entries[0].Content != feed.Items[0].ContentHTML {
+			t.Errorf("Expected one entry for the post. Got: %+v", got.Entries)
+		}
+	})
+
+	t.Run("it should ignore the Host header in the feed URL", func(t *testing.T) {
+		c, rec := newContext("/feeds/feed.json", "", nil)
+		c.Request().Host = "attacker.example"
+		h := NewFeedsRouter(e, uc)
+
+		if err := h.getJSONFeed(c); err != nil {
+			t.Fatalf("Expected no errors getting the feed. Got: %v", err)
+		}
+
+		got := JSONFeedOut{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatalf("Error unmarshalling response: %s", err)
+		}
+
+		if got.FeedURL != "https://yurb.dev/feeds/feed.json" {
+			t.Errorf("Expected the feed URL to be on the site. Got: %s", got.FeedURL)
+		}
+	})
+
+	t.Run("it should return a JSON feed", func(t *testing.T) {
+		c, rec := newContext("/feeds/feed.json", "", nil)
+		h := NewFeedsRouter(e, uc)
+
+		if err := h.getJSONFeed(c); err != nil {
+			t.Fatalf("Expected no errors getting the feed. Got: %v", err)
+		}
+
+		if got := rec.Header().Get(echo.HeaderContentType); got != MIMEApplicationJSONFeed {
+			t.Errorf("Expected content type to be %s. Got: %s", MIMEApplicationJSONFeed, got)
+		}
+
+		got := JSONFeedOut{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatalf("Error unmarshalling response: %s", err)
+		}
+
+		if got.Version != "https://jsonfeed.org/version/1.1" || got.FeedURL != "https://yurb.dev/feeds/feed.json" {
+			t.Errorf("Expected a JSON Feed 1.1 document. Got: %+v", got)
+		}
+
+		if len(got.Items) != 1 || got.Items[0].ContentHTML != feed.Items[0].ContentHTML || got.Items[0].Authors[0].Name != "Roy" {
+			t.Errorf("Expected one item for the post. Got: %+v", got.Items)
+		}
+	})
+
+	t.Run("it should pass the tag of per-tag feeds", func(t *testing.T) {
+		c, _ := newContext("/feeds/tags/go/rss.xml", "go", nil)
+		h := NewFeedsRouter(e, uc)
+
+		if err := h.getRSS(c); err != nil {
+			t.Fatalf("Expected no errors getting the feed. Got: %v", err)
+		}
+
+		if gotTag != "go" {
+			t.Errorf("Expected the feed to be filtered by go. Got: %q", gotTag)
+		}
+	})
+
+	t.Run("it should return not modified when the ETag matches", func(t *testing.T) {
+		c, rec := newContext("/feeds/rss.xml", "", nil)
+		h := NewFeedsRouter(e, uc)
+
+		if err := h.getRSS(c); err != nil {
+			t.Fatalf("Expected no errors getting the feed. Got: %v", err)
+		}
+
+		etag := rec.Header().Get("ETag")
+
+		c, rec = newContext("/feeds/rss.xml", "", map[string]string{"If-None-Match": etag})
+
+		if err := h.getRSS(c); err != nil {
+			t.Fatalf("Expected no errors getting the feed. Got: %v", err)
+		}
+
+		if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+			t.Errorf("Expected status code to be %d without a body. Got: %d", http.StatusNotModified, rec.Code)
+		}
+
+		c, rec = newContext("/feeds/atom.xml", "", map[string]string{"If-None-Match": etag})
+
+		if err := h.getAtom(c); err != nil {
+			t.Fatalf("Expected no errors getting the feed. Got: %v", err)
+		}
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected the ETag to differ between formats. Got: %d", rec.Code)
+		}
+	})
+
+	t.Run("it should use If-Modified-Since", func(t *testing.T) {
+		h := NewFeedsRouter(e, uc)
+
+		c, rec := newContext("/feeds/feed.json", "", map[string]string{echo.HeaderIfModifiedSince: "Mon, 15 Jul 2024 12:30:00 GMT"})
+
+		if err := h.getJSONFeed(c); err != nil {
+			t.Fatalf("Expected no errors getting the feed. Got: %v", err)
+		}
+
+		if rec.Code != http.StatusNotModified {
+			t.Errorf("Expected status code to be %d. Got: %d", http.StatusNotModified, rec.Code)
+		}
+
+		c, rec = newContext("/feeds/feed.json", "", map[string]string{echo.HeaderIfModifiedSince: "Mon, 15 Jul 2024 12:00:00 GMT"})
+
+		if err := h.getJSONFeed(c); err != nil {
+			t.Fatalf("Expected no errors getting the feed. Got: %v", err)
+		}
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected status code to be %d. Got: %d", http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("it should return an internal server error if the feed can't be built", func(t *testing.T) {
+		c, _ := newContext("/feeds/rss.xml", "", nil)
+		h := NewFeedsRouter(e, &mocks.MockFeedsUsecase{
+			GetFeedFn: func(ctx context.Context, tag string) (*domain.Feed, error) {
+				return nil, errors.New("DB error")
+			},
+		})
+
+		err := h.getRSS(c)
+
+		var httpErr *echo.HTTPError
+		if !errors.As(err, &httpErr) || httpErr.Code != http.StatusInternalServerError {
+			t.Errorf("Expected an internal server error. Got: %v", err)
+		}
+	})
+}
+
+func TestEmptyFeed(t *testing.T) {
+	e := echo.New()
+	uc := &mocks.MockFeedsUsecase{
+		GetFeedFn: func(ctx context.Context, tag string) (*domain.Feed, error) {
+			return &domain.Feed{Title: "yurb.dev - rust", Tag: tag, Items: []*domain.FeedItem{}}, nil
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/feeds/tags/rust/rss.xml", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	c.SetParamNames("tag")
+	c.SetParamValues("rust")
+
+	h := NewFeedsRouter(e, uc)
+
+	if err := h.getRSS(c); err != nil {
+		t.Fatalf("Expected no errors getting the feed. Got: %v", err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status code to be %d. Got: %d", http.StatusOK, rec.Code)
+	}
+
+	if rec.Header().Get(echo.HeaderLastModified) != "" {
+		t.Errorf("Expected no Last-Modified header for an empty feed")
+	}
+
+	if strings.Contains(rec.Body.String(), "<item>") {
+		t.Errorf("Expected no items. Got: %s", rec.Body.String())
+	}
+}
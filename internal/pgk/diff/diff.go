@@ -0,0 +1,210 @@
+package diff
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// contextLines is the number of unchanged lines shown around each change, as in `diff -u`.
+const contextLines = 3
+
+// MaxLines and MaxEditDistance bound the work of a diff. The trace kept to build the edit
+// script grows with the square of the edit distance, so texts that differ in more lines than
+// MaxEditDistance are not diffed.
+const (
+	MaxLines        = 10000
+	MaxEditDistance = 1000
+)
+
+var ErrTooLarge = errors.New("texts are too large or too different to diff")
+
+type operation int
+
+const (
+	equal operation = iota
+	insert
+	remove
+)
+
+type edit struct {
+	line string
+	op   operation
+}
+
+// Unified returns the line based unified diff between a and b, or an empty string if
+// both texts are equal. fromName and toName are used in the `---` and `+++` headers. It returns
+// ErrTooLarge when the texts exceed MaxLines or MaxEditDistance.
+func Unified(fromName, toName, a, b string) (string, error) {
+	edits, err := myers(splitLines(a), splitLines(b))
+	if err != nil {
+		return "", err
+	}
+
+	// fromLine and toLine hold the line of each text where every edit starts
+	fromLine := make([]int, len(edits)+1)
+	toLine := make([]int, len(edits)+1)
+	changes := []int{}
+
+	for i, e := range edits {
+		fromLine[i+1], toLine[i+1] = fromLine[i], toLine[i]
+
+		if e.op != insert {
+			fromLine[i+1]++
+		}
+
+		if e.op != remove {
+			toLine[i+1]++
+		}
+
+		if e.op != equal {
+			changes = append(changes, i)
+		}
+	}
+
+	if len(changes) == 0 {
+		return "", nil
+	}
+
+	var out strings.Builder
+
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+
+	for i := 0; i < len(changes); {
+		// Changes close enough to share their context lines go in the same hunk
+		j := i
+		for j+1 < len(changes) && changes[j+1]-changes[j] <= 2*contextLines+1 {
+			j++
+		}
+
+		start := max(changes[i]-contextLines, 0)
+		end := min(changes[j]+contextLines+1, len(edits))
+
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n",
+			hunkRange(fromLine[start], fromLine[end]-fromLine[start]),
+			hunkRange(toLine[start], toLine[end]-toLine[start]),
+		)
+
+		for _, e := range edits[start:end] {
+			switch e.op {
+			case equal:
+				out.WriteString(" ")
+			case insert:
+				out.WriteString("+")
+			case remove:
+				out.WriteString("-")
+			}
+
+			out.WriteString(e.line)
+			out.WriteString("\n")
+		}
+
+		i = j + 1
+	}
+
+	return out.String(), nil
+}
+
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		// An empty range points to the line right before the change
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	default:
+		return fmt.Sprintf("%d,%d", start+1, count)
+	}
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return []string{}
+	}
+
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// myers finds the shortest edit script that turns a into b using Myers' diff algorithm.
+func myers(a, b []string) ([]edit, error) {
+	n, m := len(a), len(b)
+	if n > MaxLines || m > MaxLines {
+		return nil, ErrTooLarge
+	}
+
+	maxD := min(n+m, MaxEditDistance)
+	offset := maxD + 1
+	v := make([]int, 2*offset+1)
+	trace := [][]int{}
+
+	for d := 0; d <= maxD; d++ {
+		// Step d only reads the diagonals -d-1 to d+1 of the previous step, so only those are kept
+		trace = append(trace, slices.Clone(v[offset-d-1:offset+d+2]))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				return backtrack(trace, a, b), nil
+			}
+		}
+	}
+
+	return nil, ErrTooLarge
+}
+
+// backtrack walks the trace from the end of both texts to build the edit script. trace[d]
+// holds the diagonals -d-1 to d+1, so diagonal k is at index k+d+1.
+func backtrack(trace [][]int, a, b []string) []edit {
+	x, y := len(a), len(b)
+	edits := []edit{}
+
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && v[k-1+d+1] < v[k+1+d+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+
+		prevX := v[prevK+d+1]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			edits = append(edits, edit{a[x-1], equal})
+			x--
+			y--
+		}
+
+		if d > 0 {
+			if x == prevX {
+				edits = append(edits, edit{b[y-1], insert})
+			} else {
+				edits = append(edits, edit{a[x-1], remove})
+			}
+
+			x, y = prevX, prevY
+		}
+	}
+
+	slices.Reverse(edits)
+
+	return edits
+}
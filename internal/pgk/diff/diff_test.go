@@ -0,0 +1,117 @@
+package diff
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestUnified(t *testing.T) {
+	t.Run("it should return an empty diff for equal texts", func(t *testing.T) {
+		got, err := Unified("a", "b", "one\ntwo\n", "one\ntwo\n")
+		if err != nil {
+			t.Fatalf("Unified() error = %v, want nil", err)
+		}
+
+		if got != "" {
+			t.Errorf("Unified() = %q, want empty", got)
+		}
+	})
+
+	t.Run("it should diff a changed line", func(t *testing.T) {
+		got, err := Unified("revision 1", "revision 2", "one\ntwo\nthree\n", "one\n2\nthree\n")
+		if err != nil {
+			t.Fatalf("Unified() error = %v, want nil", err)
+		}
+
+		want := "--- revision 1\n+++ revision 2\n@@ -1,3 +1,3 @@\n one\n-two\n+2\n three\n"
+
+		if got != want {
+			t.Errorf("Unified() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("it should diff from an empty text", func(t *testing.T) {
+		got, err := Unified("a", "b", "", "one\n")
+		if err != nil {
+			t.Fatalf("Unified() error = %v, want nil", err)
+		}
+
+		want := "--- a\n+++ b\n@@ -0,0 +1 @@\n+one\n"
+
+		if got != want {
+			t.Errorf("Unified() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("it should split distant changes in hunks", func(t *testing.T) {
+		from := []string{}
+		for _, l := range "abcdefghijklmnopqrst" {
+			from = append(from, string(l))
+		}
+
+		to := append([]string{}, from...)
+		to[0] = "A"
+		to[19] = "T"
+
+		got, err := Unified("a", "b", strings.Join(from, "\n"), strings.Join(to, "\n"))
+		if err != nil {
+			t.Fatalf("Unified() error = %v, want nil", err)
+		}
+
+		want := "--- a\n+++ b\n" +
+			"@@ -1,4 +1,4 @@\n-a\n+A\n b\n c\n d\n" +
+			"@@ -17,4 +17,4 @@\n q\n r\n s\n-t\n+T\n"
+
+		if got != want {
+			t.Errorf("Unified() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("it should keep close changes in the same hunk", func(t *testing.T) {
+		got, err := Unified("a", "b", "1\n2\n3\n4\n5\n6\n7\n8\n", "1\n2\nthree\n4\n5\n6\nseven\n8\n")
+		if err != nil {
+			t.Fatalf("Unified() error = %v, want nil", err)
+		}
+
+		want := "--- a\n+++ b\n@@ -1,8 +1,8 @@\n 1\n 2\n-3\n+three\n 4\n 5\n 6\n-7\n+seven\n 8\n"
+
+		if got != want {
+			t.Errorf("Unified() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("it should diff long texts with few changes", func(t *testing.T) {
+		from := make([]string, MaxLines)
+		for i := range from {
+			from[i] = strconv.Itoa(i)
+		}
+
+		to := append([]string{}, from...)
+		to[5000] = "changed"
+
+		got, err := Unified("a", "b", strings.Join(from, "\n"), strings.Join(to, "\n"))
+		if err != nil {
+			t.Fatalf("Unified() error = %v, want nil", err)
+		}
+
+		if !strings.Contains(got, "@@ -4998,7 +4998,7 @@\n") {
+			t.Errorf("Unified() = %q, want a single hunk around line 5001", got)
+		}
+	})
+
+	t.Run("it should refuse texts with too many lines", func(t *testing.T) {
+		_, err := Unified("a", "b", strings.Repeat("line\n", MaxLines+1), "")
+		if !errors.Is(err, ErrTooLarge) {
+			t.Errorf("Unified() error = %v, want ErrTooLarge", err)
+		}
+	})
+
+	t.Run("it should refuse texts that are too different", func(t *testing.T) {
+		_, err := Unified("a", "b", strings.Repeat("a\n", MaxEditDistance), strings.Repeat("b\n", MaxEditDistance))
+		if !errors.Is(err, ErrTooLarge) {
+			t.Errorf("Unified() error = %v, want ErrTooLarge", err)
+		}
+	})
+}
@@ -0,0 +1,15 @@
+package ips
+
+import "net/netip"
+
+// Normalize returns the canonical form of an IP address, like `2001:db8::1` or `10.0.0.1` for an
+// IPv4-mapped IPv6 address, without its zone. It returns an empty string when value is not an
+// IP address, so whatever a client sent is never stored as is.
+func Normalize(value string) string {
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return ""
+	}
+
+	return addr.WithZone("").Unmap().String()
+}
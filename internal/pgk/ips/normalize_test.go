@@ -0,0 +1,29 @@
+package ips
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	cases := []struct {
+		value string
+		want  string
+	}{
+		{value: "10.0.0.1", want: "10.0.0.1"},
+		{value: "2001:DB8:0:0::1", want: "2001:db8::1"},
+		{value: "::ffff:10.0.0.1", want: "10.0.0.1"},
+		{value: "fe80::1%eth0", want: "fe80::1"},
+		{value: "10.0.0.1, 10.0.0.2", want: ""},
+		{value: strings.Repeat("1", 100), want: ""},
+		{value: "", want: ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.value, func(t *testing.T) {
+			if got := Normalize(tc.value); got != tc.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tc.value, got, tc.want)
+			}
+		})
+	}
+}
@@ -11,7 +11,7 @@ import (
 
 const prefix = "po"
 
-func (uc *postUsecase) Create(ctx context.Context, title, author, slug, description, content string) (*domain.Post, error) {
+func (uc *postUsecase) Create(ctx context.Context, title, author, slug, description, content string, tags []string) (*domain.Post, error) {
 	id, _ := ids.NewPublicID(prefix) // TODO: handle errors and validate if the id already exists
 
 	postToCreate := &domain.PostCreate{
@@ -21,11 +21,17 @@ func (uc *postUsecase) Create(ctx context.Context, title, author, slug, descript
 		Slug:        slug,
 		Description: description,
 		Content:     content,
+		Tags:        domain.NormalizeTags(tags),
 	}
 
 	postCreated, err := uc.repository.CreatePost(ctx, postToCreate)
 	if err != nil {
 		log.Printf("Error creating post, got error: %v\n", err)
+
+		if errors.Is(err, domain.ErrSlugAlreadyExists) {
+			return nil, err
+		}
+
 		return nil, errors.New("unable to create post")
 	}
 
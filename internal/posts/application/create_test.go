@@ -41,10 +41,10 @@ func TestCreatePost(t *testing.T) {
 		},
 	}
 
-	uc := NewPostUsecase(repo)
+	uc := NewPostUsecase(repo, &mocks.MockContentRenderer{})
 	ctx := context.Background()
 
-	got, err := uc.Create(ctx, want.Title, want.Author, want.Slug, want.Description, want.Content)
+	got, err := uc.Create(ctx, want.Title, want.Author, want.Slug, want.Description, want.Content, want.Tags)
 	if err != nil {
 		t.Errorf("Expected no error, got: %v", err)
 	}
@@ -68,10 +68,10 @@ func TestCreatePostWithDBError(t *testing.T) {
 		},
 	}
 
-	uc := NewPostUsecase(repo)
+	uc := NewPostUsecase(repo, &mocks.MockContentRenderer{})
 	ctx := context.Background()
 
-	_, err := uc.Create(ctx, "Some post", "Royner Perez", "Some Slug", "Some Description", "Some content")
+	_, err := uc.Create(ctx, "Some post", "Royner Perez", "Some Slug", "Some Description", "Some content", nil)
 	if err == nil {
 		t.Errorf("Expected error, got nil")
 	}
@@ -80,3 +80,19 @@ func TestCreatePostWithDBError(t *testing.T) {
 		t.Errorf("Expected error to be %v, got: %v", want, err)
 	}
 }
+
+func TestCreatePostWithDuplicatedSlug(t *testing.T) {
+	repo := &mocks.MockPostsRepository{
+		CreatePostFn: func(ctx context.Context, post *domain.PostCreate) (*domain.Post, error) {
+			return nil, domain.ErrSlugAlreadyExists
+		},
+	}
+
+	uc := NewPostUsecase(repo, &mocks.MockContentRenderer{})
+	ctx := context.Background()
+
+	_, err := uc.Create(ctx, "Some post", "Royner Perez", "some-slug", "Some Description", "Some content", nil)
+	if !errors.Is(err, domain.ErrSlugAlreadyExists) {
+		t.Errorf("Expected error to be %v, got: %v", domain.ErrSlugAlreadyExists, err)
+	}
+}
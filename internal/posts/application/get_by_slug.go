@@ -0,0 +1,34 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/yavurb/goyurback/internal/posts/domain"
+)
+
+func (uc *postUsecase) GetBySlug(ctx context.Context, slug string) (*domain.Post, error) {
+	post, err := uc.repository.GetPostBySlug(ctx, slug)
+	if err == nil {
+		return post, nil
+	}
+
+	if !errors.Is(err, domain.ErrPostNotFound) {
+		log.Printf("Error getting post by slug. Got: %v\n", err)
+
+		return nil, err
+	}
+
+	// The slug may belong to a post that was renamed
+	post, err = uc.repository.GetPostByPreviousSlug(ctx, slug)
+	if err != nil {
+		if !errors.Is(err, domain.ErrPostNotFound) {
+			log.Printf("Error getting post by previous slug. Got: %v\n", err)
+		}
+
+		return nil, err
+	}
+
+	return post, nil
+}
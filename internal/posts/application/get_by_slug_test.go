@@ -0,0 +1,103 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/yavurb/goyurback/internal/posts/application/mocks"
+	"github.com/yavurb/goyurback/internal/posts/domain"
+)
+
+func TestGetBySlug(t *testing.T) {
+	want := &domain.Post{
+		ID:       1,
+		PublicID: "po_12345",
+		Title:    "some title",
+		Slug:     "current-slug",
+		Status:   domain.Published,
+	}
+
+	t.Run("it should get a post by its current slug", func(t *testing.T) {
+		repo := &mocks.MockPostsRepository{
+			GetPostBySlugFn: func(ctx context.Context, slug string) (*domain.Post, error) {
+				if slug != "current-slug" {
+					return nil, domain.ErrPostNotFound
+				}
+
+				return want, nil
+			},
+		}
+
+		uc := NewPostUsecase(repo, &mocks.MockContentRenderer{})
+
+		got, err := uc.GetBySlug(context.Background(), "current-slug")
+		if err != nil {
+			t.Errorf("Expected no error, got: %v", err)
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Expected post to be %v, got: %v", want, got)
+		}
+	})
+
+	t.Run("it should get a post by a previous slug", func(t *testing.T) {
+		repo := &mocks.MockPostsRepository{
+			GetPostBySlugFn: func(ctx context.Context, slug string) (*domain.Post, error) {
+				return nil, domain.ErrPostNotFound
+			},
+			GetPostByPreviousSlugFn: func(ctx context.Context, slug string) (*domain.Post, error) {
+				if slug != "old-slug" {
+					return nil, domain.ErrPostNotFound
+				}
+
+				return want, nil
+			},
+		}
+
+		uc := NewPostUsecase(repo, &mocks.MockContentRenderer{})
+
+		got, err := uc.GetBySlug(context.Background(), "old-slug")
+		if err != nil {
+			t.Errorf("Expected no error, got: %v", err)
+		}
+
+		if got.Slug != "current-slug" {
+			t.Errorf("Expected post slug to be current-slug, got: %s", got.Slug)
+		}
+	})
+
+	t.Run("it should return a not found error", func(t *testing.T) {
+		repo := &mocks.MockPostsRepository{
+			GetPostBySlugFn: func(ctx context.Context, slug string) (*domain.Post, error) {
+				return nil, domain.ErrPostNotFound
+			},
+			GetPostByPreviousSlugFn: func(ctx context.Context, slug string) (*domain.Post, error) {
+				return nil, domain.ErrPostNotFound
+			},
+		}
+
+		uc := NewPostUsecase(repo, &mocks.MockContentRenderer{})
+
+		_, err := uc.GetBySlug(context.Background(), "unknown-slug")
+		if !errors.Is(err, domain.ErrPostNotFound) {
+			t.Errorf("Expected error to be %v, got: %v", domain.ErrPostNotFound, err)
+		}
+	})
+
+	t.Run("it should not look into the slug history on db errors", func(t *testing.T) {
+		repo := &mocks.MockPostsRepository{
+			GetPostBySlugFn: func(ctx context.Context, slug string) (*domain.Post, error) {
+				return nil, errors.New("DB error")
+			},
+		}
+
+		uc := NewPostUsecase(repo, &mocks.MockContentRenderer{})
+
+		_, err := uc.GetBySlug(context.Background(), "current-slug")
+		if err == nil || err.Error() != "DB error" {
+			t.Errorf("Expected error to be 'DB error', got: %v", err)
+		}
+	})
+}
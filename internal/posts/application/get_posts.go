@@ -7,14 +7,49 @@ import (
 	"github.com/yavurb/goyurback/internal/posts/domain"
 )
 
-func (uc *postUsecase) GetPosts(ctx context.Context) ([]*domain.Post, error) {
-	posts, err := uc.repository.GetPosts(ctx)
+func (uc *postUsecase) GetPosts(ctx context.Context, filter *domain.PostFilter) (*domain.PostPage, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = domain.DefaultPageSize
+	}
+
+	if limit > domain.MaxPageSize {
+		limit = domain.MaxPageSize
+	}
+
+	// The dates are stored without a time zone, in UTC
+	query := &domain.PostQuery{
+		PublishedAfter:  filter.PublishedAfter.UTC(),
+		PublishedBefore: filter.PublishedBefore.UTC(),
+		Author:          filter.Author,
+		Tag:             domain.NormalizeTag(filter.Tag),
+		Limit:           limit + 1, // Fetch an extra post to know if there is a next page
+	}
+
+	if filter.Cursor != "" {
+		cursor, err := domain.DecodePostCursor(filter.Cursor)
+		if err != nil {
+			return nil, err
+		}
 
+		query.After = cursor
+	}
+
+	posts, err := uc.repository.GetPosts(ctx, query)
 	if err != nil {
 		log.Printf("Unable to retrieve posts. Got: %v", err)
 
 		return nil, err
 	}
 
-	return posts, nil
+	page := &domain.PostPage{
+		Posts: posts,
+	}
+
+	if int32(len(posts)) > limit {
+		page.Posts = posts[:limit]
+		page.NextCursor = domain.NewPostCursor(page.Posts[limit-1]).Encode()
+	}
+
+	return page, nil
 }
@@ -42,33 +42,180 @@ func TestGetPosts(t *testing.T) {
 	}
 
 	repo := &mocks.MockPostsRepository{
-		GetPostsFn: func(ctx context.Context) ([]*domain.Post, error) {
+		GetPostsFn: func(ctx context.Context, query *domain.PostQuery) ([]*domain.Post, error) {
 			return want, nil
 		},
 	}
 
-	uc := NewPostUsecase(repo)
+	uc := NewPostUsecase(repo, &mocks.MockContentRenderer{})
 
-	posts, err := uc.GetPosts(context.Background())
+	page, err := uc.GetPosts(context.Background(), &domain.PostFilter{})
 	if err != nil {
 		t.Errorf("Expected no error, got: %v", err)
 	}
 
-	if !reflect.DeepEqual(posts, want) {
-		t.Errorf("Expected posts to be %v, got: %v", want, posts)
+	if !reflect.DeepEqual(page.Posts, want) {
+		t.Errorf("Expected posts to be %v, got: %v", want, page.Posts)
 	}
+
+	if page.NextCursor != "" {
+		t.Errorf("Expected no next cursor, got: %s", page.NextCursor)
+	}
+}
+
+func TestGetPostsPagination(t *testing.T) {
+	publishedAt := time.Date(2024, 7, 15, 10, 30, 0, 0, time.UTC)
+	posts := []*domain.Post{
+		{ID: 3, PublicID: "public-id-3", Status: domain.Published, PublishedAt: publishedAt},
+		{ID: 2, PublicID: "public-id-2", Status: domain.Published, PublishedAt: publishedAt.Add(-time.Hour)},
+		{ID: 1, PublicID: "public-id-1", Status: domain.Published, PublishedAt: publishedAt.Add(-2 * time.Hour)},
+	}
+
+	t.Run("it should return a next cursor when there are more posts", func(t *testing.T) {
+		var gotQuery *domain.PostQuery
+
+		repo := &mocks.MockPostsRepository{
+			GetPostsFn: func(ctx context.Context, query *domain.PostQuery) ([]*domain.Post, error) {
+				gotQuery = query
+
+				return posts[:query.Limit], nil
+			},
+		}
+
+		uc := NewPostUsecase(repo, &mocks.MockContentRenderer{})
+
+		page, err := uc.GetPosts(context.Background(), &domain.PostFilter{Limit: 2, Author: "Roy", Tag: "Go"})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		if gotQuery.Limit != 3 {
+			t.Errorf("Expected repository limit to be 3, got: %d", gotQuery.Limit)
+		}
+
+		if gotQuery.Author != "Roy" {
+			t.Errorf("Expected author filter to be Roy, got: %s", gotQuery.Author)
+		}
+
+		if gotQuery.Tag != "go" {
+			t.Errorf("Expected tag filter to be go, got: %s", gotQuery.Tag)
+		}
+
+		if !reflect.DeepEqual(page.Posts, posts[:2]) {
+			t.Errorf("Expected posts to be %v, got: %v", posts[:2], page.Posts)
+		}
+
+		cursor, err := domain.DecodePostCursor(page.NextCursor)
+		if err != nil {
+			t.Fatalf("Expected a valid next cursor, got: %v", err)
+		}
+
+		if cursor.ID != 2 || !cursor.PublishedAt.Equal(posts[1].PublishedAt) {
+			t.Errorf("Expected cursor to point to post 2, got: %+v", cursor)
+		}
+	})
+
+	t.Run("it should pass the decoded cursor to the repository", func(t *testing.T) {
+		var gotQuery *domain.PostQuery
+
+		repo := &mocks.MockPostsRepository{
+			GetPostsFn: func(ctx context.Context, query *domain.PostQuery) ([]*domain.Post, error) {
+				gotQuery = query
+
+				return posts[2:], nil
+			},
+		}
+
+		uc := NewPostUsecase(repo, &mocks.MockContentRenderer{})
+		cursor := domain.NewPostCursor(posts[1]).Encode()
+
+		page, err := uc.GetPosts(context.Background(), &domain.PostFilter{Limit: 2, Cursor: cursor})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		if gotQuery.After == nil || gotQuery.After.ID != 2 || !gotQuery.After.PublishedAt.Equal(posts[1].PublishedAt) {
+			t.Errorf("Expected query to start after post 2, got: %+v", gotQuery.After)
+		}
+
+		if page.NextCursor != "" {
+			t.Errorf("Expected no next cursor on the last page, got: %s", page.NextCursor)
+		}
+	})
+
+	t.Run("it should cap the page size", func(t *testing.T) {
+		var gotQuery *domain.PostQuery
+
+		repo := &mocks.MockPostsRepository{
+			GetPostsFn: func(ctx context.Context, query *domain.PostQuery) ([]*domain.Post, error) {
+				gotQuery = query
+
+				return []*domain.Post{}, nil
+			},
+		}
+
+		uc := NewPostUsecase(repo, &mocks.MockContentRenderer{})
+
+		_, err := uc.GetPosts(context.Background(), &domain.PostFilter{Limit: 1000})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		if gotQuery.Limit != domain.MaxPageSize+1 {
+			t.Errorf("Expected repository limit to be %d, got: %d", domain.MaxPageSize+1, gotQuery.Limit)
+		}
+	})
+
+	t.Run("it should filter by the dates in UTC", func(t *testing.T) {
+		var gotQuery *domain.PostQuery
+
+		repo := &mocks.MockPostsRepository{
+			GetPostsFn: func(ctx context.Context, query *domain.PostQuery) ([]*domain.Post, error) {
+				gotQuery = query
+
+				return []*domain.Post{}, nil
+			},
+		}
+
+		uc := NewPostUsecase(repo, &mocks.MockContentRenderer{})
+
+		after := time.Date(2026, 1, 1, 0, 0, 0, 0, time.FixedZone("", 2*60*60))
+		_, err := uc.GetPosts(context.Background(), &domain.PostFilter{PublishedAfter: after})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		want := time.Date(2025, 12, 31, 22, 0, 0, 0, time.UTC)
+		if gotQuery.PublishedAfter != want {
+			t.Errorf("Expected published after to be %v, got: %v", want, gotQuery.PublishedAfter)
+		}
+
+		if !gotQuery.PublishedBefore.IsZero() {
+			t.Errorf("Expected no published before, got: %v", gotQuery.PublishedBefore)
+		}
+	})
+
+	t.Run("it should return an error if the cursor is invalid", func(t *testing.T) {
+		repo := &mocks.MockPostsRepository{}
+		uc := NewPostUsecase(repo, &mocks.MockContentRenderer{})
+
+		_, err := uc.GetPosts(context.Background(), &domain.PostFilter{Cursor: "not a cursor"})
+		if !errors.Is(err, domain.ErrInvalidCursor) {
+			t.Errorf("Expected error to be %v, got: %v", domain.ErrInvalidCursor, err)
+		}
+	})
 }
 
 func TestGetPostsError(t *testing.T) {
 	repo := &mocks.MockPostsRepository{
-		GetPostsFn: func(ctx context.Context) ([]*domain.Post, error) {
+		GetPostsFn: func(ctx context.Context, query *domain.PostQuery) ([]*domain.Post, error) {
 			return nil, errors.New("DB error")
 		},
 	}
 
-	uc := NewPostUsecase(repo)
+	uc := NewPostUsecase(repo, &mocks.MockContentRenderer{})
 
-	_, err := uc.GetPosts(context.Background())
+	_, err := uc.GetPosts(context.Background(), &domain.PostFilter{})
 
 	if err == nil {
 		t.Errorf("Expected error, got nil")
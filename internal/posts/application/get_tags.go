@@ -0,0 +1,19 @@
+package application
+
+import (
+	"context"
+	"log"
+
+	"github.com/yavurb/goyurback/internal/posts/domain"
+)
+
+func (uc *postUsecase) GetTags(ctx context.Context) ([]*domain.Tag, error) {
+	tags, err := uc.repository.GetTags(ctx)
+	if err != nil {
+		log.Printf("Unable to retrieve tags. Got: %v", err)
+
+		return nil, err
+	}
+
+	return tags, nil
+}
@@ -0,0 +1,121 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/yavurb/goyurback/internal/posts/application/mocks"
+	"github.com/yavurb/goyurback/internal/posts/domain"
+)
+
+func TestGetTags(t *testing.T) {
+	t.Run("it should get the tags", func(t *testing.T) {
+		want := []*domain.Tag{{Name: "go", Posts: 2}, {Name: "postgres", Posts: 1}}
+
+		repo := &mocks.MockPostsRepository{
+			GetTagsFn: func(ctx context.Context) ([]*domain.Tag, error) {
+				return want, nil
+			},
+		}
+
+		uc := NewPostUsecase(repo, &mocks.MockContentRenderer{})
+
+		got, err := uc.GetTags(context.Background())
+		if err != nil {
+			t.Errorf("Expected no error, got: %v", err)
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Expected tags to be %v, got: %v", want, got)
+		}
+	})
+
+	t.Run("it should return an error", func(t *testing.T) {
+		repo := &mocks.MockPostsRepository{
+			GetTagsFn: func(ctx context.Context) ([]*domain.Tag, error) {
+				return nil, errors.New("DB error")
+			},
+		}
+
+		uc := NewPostUsecase(repo, &mocks.MockContentRenderer{})
+
+		_, err := uc.GetTags(context.Background())
+		if err == nil {
+			t.Error("Expected error, got nil")
+		}
+	})
+}
+
+func TestPostTags(t *testing.T) {
+	t.Run("it should normalize the tags of a new post", func(t *testing.T) {
+		var got []string
+
+		repo := &mocks.MockPostsRepository{
+			CreatePostFn: func(ctx context.Context, post *domain.PostCreate) (*domain.Post, error) {
+				got = post.Tags
+
+				return &domain.Post{ID: 1, PublicID: post.PublicID, Tags: post.Tags}, nil
+			},
+		}
+
+		uc := NewPostUsecase(repo, &mocks.MockContentRenderer{})
+
+		_, err := uc.Create(context.Background(), "Some Post", "Some Author", "some-post", "Some Description", "Some Content", []string{"Go", " go modules ", "", "postgres", "go"})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		want := []string{"go", "go-modules", "postgres"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Expected tags to be %v, got: %v", want, got)
+		}
+	})
+
+	newRepo := func(got *[]string) *mocks.MockPostsRepository {
+		return &mocks.MockPostsRepository{
+			GetPostFn: func(ctx context.Context, id string) (*domain.Post, error) {
+				postCopy := post
+				postCopy.Tags = []string{"go"}
+
+				return &postCopy, nil
+			},
+			UpdatePostFn: func(ctx context.Context, p *domain.Post) (*domain.Post, error) {
+				*got = p.Tags
+
+				return p, nil
+			},
+		}
+	}
+
+	t.Run("it should keep the tags when they are not updated", func(t *testing.T) {
+		var got []string
+
+		uc := NewPostUsecase(newRepo(&got), &mocks.MockContentRenderer{})
+
+		_, err := uc.Update(context.Background(), "pk_1", pointer("title"), nil, nil, nil, nil, nil, nil)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		if !reflect.DeepEqual(got, []string{"go"}) {
+			t.Errorf("Expected tags to be [go], got: %v", got)
+		}
+	})
+
+	t.Run("it should remove all the tags", func(t *testing.T) {
+		var got []string
+
+		uc := NewPostUsecase(newRepo(&got), &mocks.MockContentRenderer{})
+
+		_, err := uc.Update(context.Background(), "pk_1", nil, nil, nil, nil, nil, nil, []string{})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		if got == nil || len(got) != 0 {
+			t.Errorf("Expected tags to be empty, got: %v", got)
+		}
+	})
+}
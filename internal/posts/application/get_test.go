@@ -36,7 +36,7 @@ func TestGet(t *testing.T) {
 		},
 	}
 
-	uc := NewPostUsecase(repo)
+	uc := NewPostUsecase(repo, &mocks.MockContentRenderer{})
 
 	post, err := uc.Get(context.Background(), want.PublicID)
 	if err != nil {
@@ -55,7 +55,7 @@ func TestGetPostNotFound(t *testing.T) {
 		},
 	}
 
-	uc := NewPostUsecase(repo)
+	uc := NewPostUsecase(repo, &mocks.MockContentRenderer{})
 
 	_, err := uc.Get(context.Background(), "non-existing-id")
 
@@ -0,0 +1,11 @@
+package mocks
+
+import "github.com/yavurb/goyurback/internal/posts/domain"
+
+type MockContentRenderer struct {
+	RenderFn func(post *domain.Post) (string, error)
+}
+
+func (m *MockContentRenderer) Render(post *domain.Post) (string, error) {
+	return m.RenderFn(post)
+}
@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/yavurb/goyurback/internal/posts/domain"
 )
@@ -9,8 +10,16 @@ import (
 type MockPostsRepository struct {
 	CreatePostFn func(ctx context.Context, post *domain.PostCreate) (*domain.Post, error)
 	GetPostFn    func(ctx context.Context, id string) (*domain.Post, error)
-	GetPostsFn   func(ctx context.Context) ([]*domain.Post, error)
-	UpdatePostFn func(ctx context.Context, post *domain.Post) (*domain.Post, error)
+	GetPostsFn   func(ctx context.Context, query *domain.PostQuery) ([]*domain.Post, error)
+
+	GetPostBySlugFn         func(ctx context.Context, slug string) (*domain.Post, error)
+	GetPostByPreviousSlugFn func(ctx context.Context, slug string) (*domain.Post, error)
+	UpdatePostFn            func(ctx context.Context, post *domain.Post) (*domain.Post, error)
+	PublishDuePostsFn       func(ctx context.Context, now time.Time, limit int32) ([]*domain.Post, error)
+
+	GetPostRevisionsFn func(ctx context.Context, postID int32) ([]*domain.PostRevision, error)
+	GetPostRevisionFn  func(ctx context.Context, postID, revision int32) (*domain.PostRevision, error)
+	GetTagsFn          func(ctx context.Context) ([]*domain.Tag, error)
 }
 
 func (m *MockPostsRepository) CreatePost(ctx context.Context, post *domain.PostCreate) (*domain.Post, error) {
@@ -25,6 +34,30 @@ func (m *MockPostsRepository) GetPost(ctx context.Context, postID string) (*doma
 	return m.GetPostFn(ctx, postID)
 }
 
-func (m *MockPostsRepository) GetPosts(ctx context.Context) ([]*domain.Post, error) {
-	return m.GetPostsFn(ctx)
+func (m *MockPostsRepository) GetPosts(ctx context.Context, query *domain.PostQuery) ([]*domain.Post, error) {
+	return m.GetPostsFn(ctx, query)
+}
+
+func (m *MockPostsRepository) GetPostBySlug(ctx context.Context, slug string) (*domain.Post, error) {
+	return m.GetPostBySlugFn(ctx, slug)
+}
+
+func (m *MockPostsRepository) GetPostByPreviousSlug(ctx context.Context, slug string) (*domain.Post, error) {
+	return m.GetPostByPreviousSlugFn(ctx, slug)
+}
+
+func (m *MockPostsRepository) PublishDuePosts(ctx context.Context, now time.Time, limit int32) ([]*domain.Post, error) {
+	return m.PublishDuePostsFn(ctx, now, limit)
+}
+
+func (m *MockPostsRepository) GetPostRevisions(ctx context.Context, postID int32) ([]*domain.PostRevision, error) {
+	return m.GetPostRevisionsFn(ctx, postID)
+}
+
+func (m *MockPostsRepository) GetPostRevision(ctx context.Context, postID, revision int32) (*domain.PostRevision, error) {
+	return m.GetPostRevisionFn(ctx, postID, revision)
+}
+
+func (m *MockPostsRepository) GetTags(ctx context.Context) ([]*domain.Tag, error) {
+	return m.GetTagsFn(ctx)
 }
@@ -0,0 +1,19 @@
+package application
+
+import (
+	"context"
+	"log"
+
+	"github.com/yavurb/goyurback/internal/posts/domain"
+)
+
+func (uc *postUsecase) Render(ctx context.Context, post *domain.Post) (string, error) {
+	html, err := uc.renderer.Render(post)
+	if err != nil {
+		log.Printf("Error rendering post content. Got: %v\n", err)
+
+		return "", err
+	}
+
+	return html, nil
+}
@@ -0,0 +1,46 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yavurb/goyurback/internal/posts/application/mocks"
+	"github.com/yavurb/goyurback/internal/posts/domain"
+)
+
+func TestRender(t *testing.T) {
+	t.Run("it should render the content of the post", func(t *testing.T) {
+		renderer := &mocks.MockContentRenderer{
+			RenderFn: func(post *domain.Post) (string, error) {
+				return "<p>" + post.Content + "</p>", nil
+			},
+		}
+
+		uc := NewPostUsecase(&mocks.MockPostsRepository{}, renderer)
+
+		got, err := uc.Render(context.Background(), &domain.Post{ID: 1, Content: "some content"})
+		if err != nil {
+			t.Errorf("Expected no error, got: %v", err)
+		}
+
+		if got != "<p>some content</p>" {
+			t.Errorf("Expected html to be <p>some content</p>, got: %s", got)
+		}
+	})
+
+	t.Run("it should return an error", func(t *testing.T) {
+		renderer := &mocks.MockContentRenderer{
+			RenderFn: func(post *domain.Post) (string, error) {
+				return "", errors.New("render error")
+			},
+		}
+
+		uc := NewPostUsecase(&mocks.MockPostsRepository{}, renderer)
+
+		_, err := uc.Render(context.Background(), &domain.Post{ID: 1})
+		if err == nil {
+			t.Error("Expected error, got nil")
+		}
+	})
+}
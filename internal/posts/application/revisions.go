@@ -0,0 +1,98 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/yavurb/goyurback/internal/pgk/diff"
+	"github.com/yavurb/goyurback/internal/posts/domain"
+)
+
+func (uc *postUsecase) GetRevisions(ctx context.Context, id string) ([]*domain.PostRevision, error) {
+	post, err := uc.repository.GetPost(ctx, id)
+	if err != nil {
+		log.Printf("Error getting post. Got: %v\n", err)
+
+		return nil, domain.ErrPostNotFound
+	}
+
+	revisions, err := uc.repository.GetPostRevisions(ctx, post.ID)
+	if err != nil {
+		log.Printf("Error getting post revisions. Got: %v\n", err)
+
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+func (uc *postUsecase) GetRevision(ctx context.Context, id string, revision int32) (*domain.PostRevision, error) {
+	post, err := uc.repository.GetPost(ctx, id)
+	if err != nil {
+		log.Printf("Error getting post. Got: %v\n", err)
+
+		return nil, domain.ErrPostNotFound
+	}
+
+	return uc.repository.GetPostRevision(ctx, post.ID, revision)
+}
+
+func (uc *postUsecase) DiffRevisions(ctx context.Context, id string, from, to int32) (string, error) {
+	post, err := uc.repository.GetPost(ctx, id)
+	if err != nil {
+		log.Printf("Error getting post. Got: %v\n", err)
+
+		return "", domain.ErrPostNotFound
+	}
+
+	fromRevision, err := uc.repository.GetPostRevision(ctx, post.ID, from)
+	if err != nil {
+		return "", err
+	}
+
+	toRevision, err := uc.repository.GetPostRevision(ctx, post.ID, to)
+	if err != nil {
+		return "", err
+	}
+
+	unified, err := diff.Unified(
+		fmt.Sprintf("revision %d", from),
+		fmt.Sprintf("revision %d", to),
+		fromRevision.Text(),
+		toRevision.Text(),
+	)
+	if errors.Is(err, diff.ErrTooLarge) {
+		return "", domain.ErrDiffTooLarge
+	}
+
+	return unified, err
+}
+
+func (uc *postUsecase) RestoreRevision(ctx context.Context, id string, revision int32) (*domain.Post, error) {
+	post, err := uc.repository.GetPost(ctx, id)
+	if err != nil {
+		log.Printf("Error getting post. Got: %v\n", err)
+
+		return nil, domain.ErrPostNotFound
+	}
+
+	postRevision, err := uc.repository.GetPostRevision(ctx, post.ID, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	post.Title = postRevision.Title
+	post.Description = postRevision.Description
+	post.Content = postRevision.Content
+
+	postUpdated, err := uc.repository.UpdatePost(ctx, post)
+	if err != nil {
+		log.Printf("Error restoring post revision. Got: %v\n", err)
+
+		return nil, err
+	}
+
+	return postUpdated, nil
+}
@@ -0,0 +1,130 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yavurb/goyurback/internal/pgk/diff"
+	"github.com/yavurb/goyurback/internal/posts/application/mocks"
+	"github.com/yavurb/goyurback/internal/posts/domain"
+)
+
+func TestRevisions(t *testing.T) {
+	revisions := []*domain.PostRevision{
+		{ID: 2, PostID: 1, Revision: 2, Title: "some title", Description: "some description", Content: "first line\nsecond line"},
+		{ID: 1, PostID: 1, Revision: 1, Title: "some title", Description: "some description", Content: "first line"},
+	}
+
+	newRepo := func() *mocks.MockPostsRepository {
+		return &mocks.MockPostsRepository{
+			GetPostFn: func(ctx context.Context, id string) (*domain.Post, error) {
+				if id != "pk_1" {
+					return nil, domain.ErrPostNotFound
+				}
+
+				postCopy := post
+				return &postCopy, nil
+			},
+			GetPostRevisionsFn: func(ctx context.Context, postID int32) ([]*domain.PostRevision, error) {
+				return revisions, nil
+			},
+			GetPostRevisionFn: func(ctx context.Context, postID, revision int32) (*domain.PostRevision, error) {
+				for _, r := range revisions {
+					if r.PostID == postID && r.Revision == revision {
+						return r, nil
+					}
+				}
+
+				return nil, domain.ErrRevisionNotFound
+			},
+			UpdatePostFn: func(ctx context.Context, p *domain.Post) (*domain.Post, error) {
+				return p, nil
+			},
+		}
+	}
+
+	t.Run("it should get the revisions of a post", func(t *testing.T) {
+		uc := NewPostUsecase(newRepo(), &mocks.MockContentRenderer{})
+
+		got, err := uc.GetRevisions(context.Background(), "pk_1")
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		if !reflect.DeepEqual(got, revisions) {
+			t.Errorf("Expected revisions to be %v, got: %v", revisions, got)
+		}
+	})
+
+	t.Run("it should return an error if the revision does not exist", func(t *testing.T) {
+		uc := NewPostUsecase(newRepo(), &mocks.MockContentRenderer{})
+
+		_, err := uc.GetRevision(context.Background(), "pk_1", 3)
+		if !errors.Is(err, domain.ErrRevisionNotFound) {
+			t.Errorf("Expected error to be %v, got: %v", domain.ErrRevisionNotFound, err)
+		}
+	})
+
+	t.Run("it should return an error if the post does not exist", func(t *testing.T) {
+		uc := NewPostUsecase(newRepo(), &mocks.MockContentRenderer{})
+
+		_, err := uc.GetRevisions(context.Background(), "pk_2")
+		if !errors.Is(err, domain.ErrPostNotFound) {
+			t.Errorf("Expected error to be %v, got: %v", domain.ErrPostNotFound, err)
+		}
+	})
+
+	t.Run("it should diff two revisions", func(t *testing.T) {
+		uc := NewPostUsecase(newRepo(), &mocks.MockContentRenderer{})
+
+		got, err := uc.DiffRevisions(context.Background(), "pk_1", 1, 2)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		if !strings.HasPrefix(got, "--- revision 1\n+++ revision 2\n") {
+			t.Errorf("Expected diff headers for revisions 1 and 2, got: %q", got)
+		}
+
+		if !strings.Contains(got, "+second line\n") {
+			t.Errorf("Expected diff to add the second line, got: %q", got)
+		}
+	})
+
+	t.Run("it should refuse to diff revisions that are too different", func(t *testing.T) {
+		repo := newRepo()
+		repo.GetPostRevisionFn = func(ctx context.Context, postID, revision int32) (*domain.PostRevision, error) {
+			line := fmt.Sprintf("revision %d\n", revision)
+
+			return &domain.PostRevision{PostID: postID, Revision: revision, Content: strings.Repeat(line, diff.MaxEditDistance)}, nil
+		}
+
+		uc := NewPostUsecase(repo, &mocks.MockContentRenderer{})
+
+		_, err := uc.DiffRevisions(context.Background(), "pk_1", 1, 2)
+		if !errors.Is(err, domain.ErrDiffTooLarge) {
+			t.Errorf("Expected error to be %v, got: %v", domain.ErrDiffTooLarge, err)
+		}
+	})
+
+	t.Run("it should restore a revision", func(t *testing.T) {
+		uc := NewPostUsecase(newRepo(), &mocks.MockContentRenderer{})
+
+		got, err := uc.RestoreRevision(context.Background(), "pk_1", 1)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		if got.Content != "first line" || got.Title != "some title" {
+			t.Errorf("Expected post to have the content of revision 1, got: %v", got)
+		}
+
+		if got.Slug != post.Slug || got.Status != post.Status {
+			t.Errorf("Expected slug and status to be kept, got: %s and %s", got.Slug, got.Status)
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package application
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/yavurb/goyurback/internal/posts/domain"
+)
+
+// Scheduler periodically publishes the scheduled posts that are due.
+type Scheduler struct {
+	postUsecase domain.PostUsecase
+	interval    time.Duration
+}
+
+func NewScheduler(postUsecase domain.PostUsecase, interval time.Duration) *Scheduler {
+	return &Scheduler{postUsecase, interval}
+}
+
+// Run blocks until ctx is done. Posts are published right away on start, so a restart
+// doesn't delay the ones that became due while the server was down.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.publishDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) publishDue(ctx context.Context) {
+	for {
+		posts, err := s.postUsecase.PublishScheduled(ctx)
+		if err != nil {
+			return
+		}
+
+		for _, post := range posts {
+			log.Printf("Published scheduled post %s\n", post.PublicID)
+		}
+
+		// A full batch means there may be more due posts left
+		if int32(len(posts)) < domain.PublishBatchSize || ctx.Err() != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/yavurb/goyurback/internal/posts/application/mocks"
+	"github.com/yavurb/goyurback/internal/posts/domain"
+)
+
+func TestScheduler(t *testing.T) {
+	t.Run("it should publish due posts until a batch is not full", func(t *testing.T) {
+		calls := 0
+		batches := [][]*domain.Post{make([]*domain.Post, domain.PublishBatchSize), {}}
+
+		for i := range batches[0] {
+			batches[0][i] = &domain.Post{PublicID: fmt.Sprintf("po_%d", i), Status: domain.Published}
+		}
+
+		repo := &mocks.MockPostsRepository{
+			PublishDuePostsFn: func(ctx context.Context, now time.Time, limit int32) ([]*domain.Post, error) {
+				if limit != domain.PublishBatchSize {
+					t.Errorf("Expected limit to be %d, got: %d", domain.PublishBatchSize, limit)
+				}
+
+				batch := batches[calls]
+				calls++
+
+				return batch, nil
+			},
+		}
+
+		scheduler := NewScheduler(NewPostUsecase(repo, &mocks.MockContentRenderer{}), time.Hour)
+		scheduler.publishDue(context.Background())
+
+		if calls != 2 {
+			t.Errorf("Expected 2 batches to be published, got: %d", calls)
+		}
+	})
+
+	t.Run("it should stop when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+
+		repo := &mocks.MockPostsRepository{
+			PublishDuePostsFn: func(ctx context.Context, now time.Time, limit int32) ([]*domain.Post, error) {
+				calls++
+
+				cancel()
+
+				return nil, errors.New("context canceled")
+			},
+		}
+
+		done := make(chan struct{})
+
+		go func() {
+			NewScheduler(NewPostUsecase(repo, &mocks.MockContentRenderer{}), time.Hour).Run(ctx)
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("Expected the scheduler to stop")
+		}
+
+		if calls != 1 {
+			t.Errorf("Expected a single run, got: %d", calls)
+		}
+	})
+}
@@ -0,0 +1,110 @@
+package application
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/yavurb/goyurback/internal/posts/domain"
+)
+
+func (uc *postUsecase) Publish(ctx context.Context, id string) (*domain.Post, error) {
+	return uc.transitionPost(ctx, id, domain.Published)
+}
+
+func (uc *postUsecase) Unpublish(ctx context.Context, id string) (*domain.Post, error) {
+	return uc.transitionPost(ctx, id, domain.Draft)
+}
+
+func (uc *postUsecase) Archive(ctx context.Context, id string) (*domain.Post, error) {
+	return uc.transitionPost(ctx, id, domain.Archived)
+}
+
+func (uc *postUsecase) transitionPost(ctx context.Context, id string, status domain.Status) (*domain.Post, error) {
+	post, err := uc.repository.GetPost(ctx, id)
+	if err != nil {
+		log.Printf("Error getting post. Got: %v\n", err)
+
+		return nil, domain.ErrPostNotFound
+	}
+
+	if err := transition(post, status); err != nil {
+		return nil, err
+	}
+
+	postUpdated, err := uc.repository.UpdatePost(ctx, post)
+	if err != nil {
+		log.Printf("Error updating post status. Got: %v\n", err)
+
+		return nil, err
+	}
+
+	return postUpdated, nil
+}
+
+func (uc *postUsecase) Schedule(ctx context.Context, id string, publishAt time.Time) (*domain.Post, error) {
+	post, err := uc.repository.GetPost(ctx, id)
+	if err != nil {
+		log.Printf("Error getting post. Got: %v\n", err)
+
+		return nil, domain.ErrPostNotFound
+	}
+
+	post.PublishAt = publishAt.UTC()
+
+	// A scheduled post can be rescheduled without going through another status
+	if post.Status == domain.Scheduled {
+		if !post.PublishAt.After(time.Now().UTC()) {
+			return nil, domain.ErrInvalidPublishDate
+		}
+	} else if err := transition(post, domain.Scheduled); err != nil {
+		return nil, err
+	}
+
+	postUpdated, err := uc.repository.UpdatePost(ctx, post)
+	if err != nil {
+		log.Printf("Error scheduling post. Got: %v\n", err)
+
+		return nil, err
+	}
+
+	return postUpdated, nil
+}
+
+func (uc *postUsecase) PublishScheduled(ctx context.Context) ([]*domain.Post, error) {
+	posts, err := uc.repository.PublishDuePosts(ctx, time.Now().UTC(), domain.PublishBatchSize)
+	if err != nil {
+		log.Printf("Error publishing scheduled posts. Got: %v\n", err)
+
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+// transition moves the post to the given status. The publish date is only set the first
+// time a post is published, so unpublishing and publishing again keeps the original date.
+func transition(post *domain.Post, status domain.Status) error {
+	if !post.Status.CanTransitionTo(status) {
+		return domain.ErrInvalidStatusTransition
+	}
+
+	switch status {
+	case domain.Scheduled:
+		if !post.PublishAt.After(time.Now().UTC()) {
+			return domain.ErrInvalidPublishDate
+		}
+	case domain.Published:
+		if post.PublishedAt.IsZero() {
+			post.PublishedAt = time.Now().UTC()
+		}
+
+		post.PublishAt = time.Time{}
+	default:
+		post.PublishAt = time.Time{}
+	}
+
+	post.Status = status
+
+	return nil
+}
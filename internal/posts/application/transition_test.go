@@ -0,0 +1,201 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yavurb/goyurback/internal/posts/application/mocks"
+	"github.com/yavurb/goyurback/internal/posts/domain"
+)
+
+func TestTransition(t *testing.T) {
+	firstPublishedAt := time.Date(2024, 7, 15, 10, 30, 0, 0, time.UTC)
+
+	newUsecase := func(status domain.Status, publishedAt time.Time) domain.PostUsecase {
+		return NewPostUsecase(&mocks.MockPostsRepository{
+			GetPostFn: func(ctx context.Context, id string) (*domain.Post, error) {
+				return &domain.Post{ID: 1, PublicID: id, Status: status, PublishedAt: publishedAt}, nil
+			},
+			UpdatePostFn: func(ctx context.Context, p *domain.Post) (*domain.Post, error) {
+				return p, nil
+			},
+		}, &mocks.MockContentRenderer{})
+	}
+
+	t.Run("it should set the publish date the first time a post is published", func(t *testing.T) {
+		uc := newUsecase(domain.Draft, time.Time{})
+
+		post, err := uc.Publish(context.Background(), "pk_1")
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		if post.Status != domain.Published {
+			t.Errorf("Expected status to be %s, got: %s", domain.Published, post.Status)
+		}
+
+		if post.PublishedAt.IsZero() {
+			t.Error("Expected publish date to be set")
+		}
+	})
+
+	t.Run("it should keep the original publish date when publishing again", func(t *testing.T) {
+		uc := newUsecase(domain.Draft, firstPublishedAt)
+
+		post, err := uc.Publish(context.Background(), "pk_1")
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		if !post.PublishedAt.Equal(firstPublishedAt) {
+			t.Errorf("Expected publish date to be %v, got: %v", firstPublishedAt, post.PublishedAt)
+		}
+	})
+
+	t.Run("it should unpublish a published post", func(t *testing.T) {
+		uc := newUsecase(domain.Published, firstPublishedAt)
+
+		post, err := uc.Unpublish(context.Background(), "pk_1")
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		if post.Status != domain.Draft {
+			t.Errorf("Expected status to be %s, got: %s", domain.Draft, post.Status)
+		}
+
+		if !post.PublishedAt.Equal(firstPublishedAt) {
+			t.Errorf("Expected publish date to be %v, got: %v", firstPublishedAt, post.PublishedAt)
+		}
+	})
+
+	t.Run("it should archive a draft without publishing it", func(t *testing.T) {
+		uc := newUsecase(domain.Draft, time.Time{})
+
+		post, err := uc.Archive(context.Background(), "pk_1")
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		if post.Status != domain.Archived {
+			t.Errorf("Expected status to be %s, got: %s", domain.Archived, post.Status)
+		}
+
+		if !post.PublishedAt.IsZero() {
+			t.Errorf("Expected publish date to be empty, got: %v", post.PublishedAt)
+		}
+	})
+
+	t.Run("it should reject illegal transitions", func(t *testing.T) {
+		tests := []struct {
+			action func(uc domain.PostUsecase) (*domain.Post, error)
+			from   domain.Status
+		}{
+			{func(uc domain.PostUsecase) (*domain.Post, error) { return uc.Publish(context.Background(), "pk_1") }, domain.Archived},
+			{func(uc domain.PostUsecase) (*domain.Post, error) { return uc.Publish(context.Background(), "pk_1") }, domain.Published},
+			{func(uc domain.PostUsecase) (*domain.Post, error) { return uc.Unpublish(context.Background(), "pk_1") }, domain.Draft},
+			{func(uc domain.PostUsecase) (*domain.Post, error) { return uc.Archive(context.Background(), "pk_1") }, domain.Archived},
+		}
+
+		for _, test := range tests {
+			_, err := test.action(newUsecase(test.from, firstPublishedAt))
+			if !errors.Is(err, domain.ErrInvalidStatusTransition) {
+				t.Errorf("Expected error to be %v from %s, got: %v", domain.ErrInvalidStatusTransition, test.from, err)
+			}
+		}
+	})
+
+	t.Run("it should reject an unknown status on update", func(t *testing.T) {
+		uc := newUsecase(domain.Draft, time.Time{})
+
+		_, err := uc.Update(context.Background(), "pk_1", nil, nil, nil, nil, nil, pointer(domain.Status("deleted")), nil)
+		if !errors.Is(err, domain.ErrInvalidStatusTransition) {
+			t.Errorf("Expected error to be %v, got: %v", domain.ErrInvalidStatusTransition, err)
+		}
+	})
+
+	t.Run("it should schedule a draft", func(t *testing.T) {
+		uc := newUsecase(domain.Draft, time.Time{})
+		publishAt := time.Now().Add(time.Hour)
+
+		post, err := uc.Schedule(context.Background(), "pk_1", publishAt)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		if post.Status != domain.Scheduled {
+			t.Errorf("Expected status to be %s, got: %s", domain.Scheduled, post.Status)
+		}
+
+		if !post.PublishAt.Equal(publishAt) {
+			t.Errorf("Expected publish_at to be %v, got: %v", publishAt, post.PublishAt)
+		}
+	})
+
+	t.Run("it should reject a publish date in the past", func(t *testing.T) {
+		uc := newUsecase(domain.Draft, time.Time{})
+
+		_, err := uc.Schedule(context.Background(), "pk_1", time.Now().Add(-time.Hour))
+		if !errors.Is(err, domain.ErrInvalidPublishDate) {
+			t.Errorf("Expected error to be %v, got: %v", domain.ErrInvalidPublishDate, err)
+		}
+	})
+
+	t.Run("it should reschedule a scheduled post", func(t *testing.T) {
+		uc := newUsecase(domain.Scheduled, time.Time{})
+		publishAt := time.Now().Add(2 * time.Hour)
+
+		post, err := uc.Schedule(context.Background(), "pk_1", publishAt)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		if post.Status != domain.Scheduled || !post.PublishAt.Equal(publishAt) {
+			t.Errorf("Expected post to be scheduled at %v, got: %s at %v", publishAt, post.Status, post.PublishAt)
+		}
+	})
+
+	t.Run("it should not schedule a published post", func(t *testing.T) {
+		uc := newUsecase(domain.Published, firstPublishedAt)
+
+		_, err := uc.Schedule(context.Background(), "pk_1", time.Now().Add(time.Hour))
+		if !errors.Is(err, domain.ErrInvalidStatusTransition) {
+			t.Errorf("Expected error to be %v, got: %v", domain.ErrInvalidStatusTransition, err)
+		}
+	})
+
+	t.Run("it should clear the publish date when a scheduled post is unpublished", func(t *testing.T) {
+		uc := NewPostUsecase(&mocks.MockPostsRepository{
+			GetPostFn: func(ctx context.Context, id string) (*domain.Post, error) {
+				return &domain.Post{ID: 1, PublicID: id, Status: domain.Scheduled, PublishAt: time.Now().Add(time.Hour)}, nil
+			},
+			UpdatePostFn: func(ctx context.Context, p *domain.Post) (*domain.Post, error) {
+				return p, nil
+			},
+		}, &mocks.MockContentRenderer{})
+
+		post, err := uc.Unpublish(context.Background(), "pk_1")
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		if post.Status != domain.Draft || !post.PublishAt.IsZero() {
+			t.Errorf("Expected an unscheduled draft, got: %s at %v", post.Status, post.PublishAt)
+		}
+	})
+
+	t.Run("it should return an error if the post does not exist", func(t *testing.T) {
+		uc := NewPostUsecase(&mocks.MockPostsRepository{
+			GetPostFn: func(ctx context.Context, id string) (*domain.Post, error) {
+				return nil, domain.ErrPostNotFound
+			},
+		}, &mocks.MockContentRenderer{})
+
+		_, err := uc.Publish(context.Background(), "pk_1")
+		if !errors.Is(err, domain.ErrPostNotFound) {
+			t.Errorf("Expected error to be %v, got: %v", domain.ErrPostNotFound, err)
+		}
+	})
+}
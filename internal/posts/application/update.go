@@ -7,7 +7,7 @@ import (
 	"github.com/yavurb/goyurback/internal/posts/domain"
 )
 
-func (uc *postUsecase) Update(ctx context.Context, id string, title, author, slug, description, content *string, status *domain.Status) (*domain.Post, error) {
+func (uc *postUsecase) Update(ctx context.Context, id string, title, author, slug, description, content *string, status *domain.Status, tags []string) (*domain.Post, error) {
 	post, err := uc.repository.GetPost(ctx, id)
 	if err != nil {
 		log.Printf("Error getting post. Got: %v\n", err)
@@ -35,8 +35,14 @@ func (uc *postUsecase) Update(ctx context.Context, id string, title, author, slu
 		post.Content = *content
 	}
 
-	if status != nil {
-		post.Status = *status
+	if tags != nil {
+		post.Tags = domain.NormalizeTags(tags)
+	}
+
+	if status != nil && *status != post.Status {
+		if err := transition(post, *status); err != nil {
+			return nil, err
+		}
 	}
 
 	postUpdated, err := uc.repository.UpdatePost(ctx, post)
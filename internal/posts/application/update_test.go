@@ -64,7 +64,7 @@ func TestUpdate(t *testing.T) {
 		},
 	}
 
-	uc := NewPostUsecase(repo)
+	uc := NewPostUsecase(repo, &mocks.MockContentRenderer{})
 
 	for _, test := range tests {
 		testName := fmt.Sprintf("it should update field %s", structToString(test.toUpdate))
@@ -78,6 +78,7 @@ func TestUpdate(t *testing.T) {
 				test.toUpdate.Description,
 				test.toUpdate.Content,
 				test.toUpdate.Status,
+				nil,
 			)
 			if err != nil {
 				t.Errorf("Expected no error, got: %v", err)
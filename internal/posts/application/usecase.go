@@ -4,8 +4,9 @@ import "github.com/yavurb/goyurback/internal/posts/domain"
 
 type postUsecase struct {
 	repository domain.PostRepository
+	renderer   domain.ContentRenderer
 }
 
-func NewPostUsecase(repository domain.PostRepository) domain.PostUsecase {
-	return &postUsecase{repository}
+func NewPostUsecase(repository domain.PostRepository, renderer domain.ContentRenderer) domain.PostUsecase {
+	return &postUsecase{repository, renderer}
 }
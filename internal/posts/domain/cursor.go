@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"encoding/base64"
+	"fmt"
+	"time"
+)
+
+// PostCursor points to the last post of a page. Posts are sorted by (published_at, id)
+// so both values are needed to resume the listing without skipping or repeating posts.
+type PostCursor struct {
+	PublishedAt time.Time
+	ID          int32
+}
+
+func NewPostCursor(post *Post) *PostCursor {
+	return &PostCursor{
+		PublishedAt: post.PublishedAt,
+		ID:          post.ID,
+	}
+}
+
+// Encode returns an opaque representation of the cursor that is safe to use in URLs.
+func (c PostCursor) Encode() string {
+	raw := fmt.Sprintf("%d:%d", c.PublishedAt.UnixMicro(), c.ID)
+
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodePostCursor(cursor string) (*PostCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var (
+		publishedAt int64
+		id          int32
+	)
+
+	if _, err := fmt.Sscanf(string(raw), "%d:%d", &publishedAt, &id); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &PostCursor{
+		PublishedAt: time.UnixMicro(publishedAt).UTC(),
+		ID:          id,
+	}, nil
+}
@@ -3,5 +3,13 @@ package domain
 import "errors"
 
 var (
-	ErrPostNotFound = errors.New("post not found")
+	ErrPostNotFound     = errors.New("post not found")
+	ErrRevisionNotFound = errors.New("post revision not found")
+	ErrDiffTooLarge     = errors.New("post revisions are too large or too different to diff")
+	ErrInvalidCursor    = errors.New("invalid cursor")
+
+	ErrSlugAlreadyExists = errors.New("slug already exists")
+
+	ErrInvalidStatusTransition = errors.New("invalid post status transition")
+	ErrInvalidPublishDate      = errors.New("publish date must be in the future")
 )
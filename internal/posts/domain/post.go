@@ -2,6 +2,7 @@ package domain
 
 import (
 	"reflect"
+	"slices"
 	"time"
 )
 
@@ -11,10 +12,24 @@ const (
 	Draft     Status = "draft"
 	Published Status = "published"
 	Archived  Status = "archived"
+	Scheduled Status = "scheduled"
 )
 
+// statusTransitions lists the statuses a post can move to from its current status.
+var statusTransitions = map[Status][]Status{
+	Draft:     {Published, Scheduled, Archived},
+	Published: {Draft, Archived},
+	Scheduled: {Published, Draft},
+	Archived:  {Draft},
+}
+
+func (s Status) CanTransitionTo(next Status) bool {
+	return slices.Contains(statusTransitions[s], next)
+}
+
 type Post struct {
 	PublishedAt time.Time
+	PublishAt   time.Time // Only set while the post is scheduled
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 	PublicID    string
@@ -24,6 +39,7 @@ type Post struct {
 	Status      Status
 	Description string
 	Content     string
+	Tags        []string
 	ID          int32
 }
 
@@ -42,6 +58,7 @@ type PostCreate struct {
 	Slug        string
 	Description string
 	Content     string
+	Tags        []string
 }
 
 type PostUpdate struct {
@@ -52,3 +69,37 @@ type PostUpdate struct {
 	Content     string
 	Status      Status
 }
+
+const (
+	// PublishBatchSize is the max number of scheduled posts published on each run of the scheduler
+	PublishBatchSize int32 = 50
+
+	DefaultPageSize int32 = 20
+	MaxPageSize     int32 = 100
+)
+
+// PostFilter holds the listing options accepted by the usecase. Cursor is the opaque
+// value returned as NextCursor by a previous page.
+type PostFilter struct {
+	PublishedAfter  time.Time
+	PublishedBefore time.Time
+	Author          string
+	Tag             string
+	Cursor          string
+	Limit           int32
+}
+
+// PostQuery is the decoded version of PostFilter used by the repository.
+type PostQuery struct {
+	PublishedAfter  time.Time
+	PublishedBefore time.Time
+	After           *PostCursor
+	Author          string
+	Tag             string
+	Limit           int32
+}
+
+type PostPage struct {
+	Posts      []*Post
+	NextCursor string
+}
@@ -0,0 +1,6 @@
+package domain
+
+// ContentRenderer converts the Markdown content of a post to sanitized HTML.
+type ContentRenderer interface {
+	Render(post *Post) (string, error)
+}
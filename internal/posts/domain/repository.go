@@ -1,11 +1,19 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type PostRepository interface {
 	GetPost(ctx context.Context, id string) (*Post, error)
-	GetPosts(ctx context.Context) ([]*Post, error)
-	// GetPostBySlug(ctx context.Context, slug string) (*Post, error)
+	GetPosts(ctx context.Context, query *PostQuery) ([]*Post, error)
+	GetPostBySlug(ctx context.Context, slug string) (*Post, error)
+	GetPostByPreviousSlug(ctx context.Context, slug string) (*Post, error)
 	CreatePost(ctx context.Context, post *PostCreate) (*Post, error)
 	UpdatePost(ctx context.Context, post *Post) (*Post, error)
+	PublishDuePosts(ctx context.Context, now time.Time, limit int32) ([]*Post, error)
+	GetPostRevisions(ctx context.Context, postID int32) ([]*PostRevision, error)
+	GetPostRevision(ctx context.Context, postID, revision int32) (*PostRevision, error)
+	GetTags(ctx context.Context) ([]*Tag, error)
 }
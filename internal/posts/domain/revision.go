@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// PostRevision is a snapshot of the content of a post. A new revision is saved every time
+// the title, description or content of the post changes.
+type PostRevision struct {
+	CreatedAt   time.Time
+	Title       string
+	Description string
+	Content     string
+	ID          int32
+	PostID      int32
+	Revision    int32
+}
+
+// Text returns the revision as a single document, used to diff revisions.
+func (r *PostRevision) Text() string {
+	return fmt.Sprintf("Title: %s\nDescription: %s\n\n%s\n", r.Title, r.Description, r.Content)
+}
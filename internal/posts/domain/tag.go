@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+)
+
+type Tag struct {
+	Name  string
+	Posts int64 // Number of published posts with the tag
+}
+
+// NormalizeTag lowercases the tag and joins its words with dashes, so "Go Modules" and
+// "go-modules" end up being the same tag.
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.Join(strings.Fields(tag), "-"))
+}
+
+// NormalizeTags normalizes, sorts and removes duplicated tags. A nil slice is kept nil.
+func NormalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+
+	normalized := []string{}
+
+	for _, tag := range tags {
+		if tag = NormalizeTag(tag); tag != "" {
+			normalized = append(normalized, tag)
+		}
+	}
+
+	slices.Sort(normalized)
+
+	return slices.Compact(normalized)
+}
@@ -2,11 +2,31 @@ package domain
 
 import (
 	"context"
+	"time"
 )
 
 type PostUsecase interface {
 	Get(ctx context.Context, id string) (*Post, error)
-	GetPosts(ctx context.Context) ([]*Post, error)
-	Create(ctx context.Context, title, author, slug, description, content string) (*Post, error)
-	Update(ctx context.Context, id string, title, author, slug, description, content *string, status *Status) (*Post, error)
+	// GetBySlug resolves the current and previous slugs of a post. The returned post's slug differs
+	// from the requested one when the post was renamed.
+	GetBySlug(ctx context.Context, slug string) (*Post, error)
+	GetPosts(ctx context.Context, filter *PostFilter) (*PostPage, error)
+	Create(ctx context.Context, title, author, slug, description, content string, tags []string) (*Post, error)
+	// Update only changes the given fields. The tags of the post are kept when tags is nil.
+	Update(ctx context.Context, id string, title, author, slug, description, content *string, status *Status, tags []string) (*Post, error)
+	Publish(ctx context.Context, id string) (*Post, error)
+	Unpublish(ctx context.Context, id string) (*Post, error)
+	Archive(ctx context.Context, id string) (*Post, error)
+	Schedule(ctx context.Context, id string, publishAt time.Time) (*Post, error)
+	// PublishScheduled publishes the scheduled posts whose publish date is due.
+	PublishScheduled(ctx context.Context) ([]*Post, error)
+	GetRevisions(ctx context.Context, id string) ([]*PostRevision, error)
+	GetRevision(ctx context.Context, id string, revision int32) (*PostRevision, error)
+	// DiffRevisions returns the unified diff between two revisions of a post.
+	DiffRevisions(ctx context.Context, id string, from, to int32) (string, error)
+	// RestoreRevision brings back the content of a revision, which is saved as a new revision.
+	RestoreRevision(ctx context.Context, id string, revision int32) (*Post, error)
+	GetTags(ctx context.Context) ([]*Tag, error)
+	// Render returns the content of the post as sanitized HTML.
+	Render(ctx context.Context, post *Post) (string, error)
 }
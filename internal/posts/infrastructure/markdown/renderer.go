@@ -0,0 +1,121 @@
+package markdown
+
+import (
+	"bytes"
+	"container/list"
+	"regexp"
+	"sync"
+	"time"
+
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yavurb/goyurback/internal/posts/domain"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+)
+
+// maxCacheEntries is the number of posts whose HTML is kept. The least recently rendered are
+// evicted first.
+const maxCacheEntries = 1000
+
+type cacheEntry struct {
+	postID    int32
+	updatedAt time.Time
+	html      string
+}
+
+// Renderer renders GitHub Flavored Markdown. Code blocks are highlighted with CSS classes
+// instead of inline styles, so clients can pick their own theme.
+type Renderer struct {
+	md     goldmark.Markdown
+	policy *bluemonday.Policy
+
+	mu         sync.Mutex
+	maxEntries int
+	cache      map[int32]*list.Element // Latest rendered content of each post
+	recent     *list.List              // Entries from the most to the least recently rendered
+}
+
+func NewRenderer() domain.ContentRenderer {
+	md := goldmark.New(
+		goldmark.WithExtensions(
+			extension.GFM,
+			highlighting.NewHighlighting(
+				highlighting.WithFormatOptions(chromahtml.WithClasses(true)),
+			),
+		),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+	)
+
+	policy := bluemonday.UGCPolicy()
+	policy.AllowAttrs("id").Matching(regexp.MustCompile(`^[\w-]+$`)).OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^[\w -]+$`)).OnElements("pre", "code", "span")
+	policy.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	policy.AllowAttrs("checked", "disabled").OnElements("input")
+
+	return &Renderer{
+		md:         md,
+		policy:     policy,
+		maxEntries: maxCacheEntries,
+		cache:      map[int32]*list.Element{},
+		recent:     list.New(),
+	}
+}
+
+// Render returns the cached HTML of the post unless the post was updated since it was rendered.
+func (r *Renderer) Render(post *domain.Post) (string, error) {
+	if html, ok := r.cached(post); ok {
+		return html, nil
+	}
+
+	var buf bytes.Buffer
+	if err := r.md.Convert([]byte(post.Content), &buf); err != nil {
+		return "", err
+	}
+
+	html := r.policy.Sanitize(buf.String())
+
+	r.store(&cacheEntry{postID: post.ID, updatedAt: post.UpdatedAt, html: html})
+
+	return html, nil
+}
+
+func (r *Renderer) cached(post *domain.Post) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	element, ok := r.cache[post.ID]
+	if !ok {
+		return "", false
+	}
+
+	entry := element.Value.(*cacheEntry)
+	if !entry.updatedAt.Equal(post.UpdatedAt) {
+		return "", false
+	}
+
+	r.recent.MoveToFront(element)
+
+	return entry.html, true
+}
+
+func (r *Renderer) store(entry *cacheEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if element, ok := r.cache[entry.postID]; ok {
+		element.Value = entry
+		r.recent.MoveToFront(element)
+
+		return
+	}
+
+	r.cache[entry.postID] = r.recent.PushFront(entry)
+
+	if r.recent.Len() > r.maxEntries {
+		oldest := r.recent.Remove(r.recent.Back()).(*cacheEntry)
+		delete(r.cache, oldest.postID)
+	}
+}
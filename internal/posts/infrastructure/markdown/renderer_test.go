@@ -0,0 +1,107 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yavurb/goyurback/internal/posts/domain"
+)
+
+func TestRender(t *testing.T) {
+	renderer := NewRenderer()
+
+	t.Run("it should render GFM tables", func(t *testing.T) {
+		got, err := renderer.Render(&domain.Post{ID: 1, Content: "| a | b |\n|---|---|\n| 1 | 2 |\n"})
+		if err != nil {
+			t.Fatalf("Render() error = %v, want nil", err)
+		}
+
+		if !strings.Contains(got, "<table>") || !strings.Contains(got, "<td>1</td>") {
+			t.Errorf("Render() = %s, want a table", got)
+		}
+	})
+
+	t.Run("it should highlight fenced code with classes", func(t *testing.T) {
+		got, err := renderer.Render(&domain.Post{ID: 2, Content: "```go\nfmt.Println(\"hi\")\n```\n"})
+		if err != nil {
+			t.Fatalf("Render() error = %v, want nil", err)
+		}
+
+		if !strings.Contains(got, `<pre class="chroma">`) || !strings.Contains(got, `<span class="nf">Println</span>`) {
+			t.Errorf("Render() = %s, want highlighted code", got)
+		}
+
+		if strings.Contains(got, "style=") {
+			t.Errorf("Render() = %s, want no inline styles", got)
+		}
+	})
+
+	t.Run("it should add anchors to headings", func(t *testing.T) {
+		got, err := renderer.Render(&domain.Post{ID: 3, Content: "# Hello World\n"})
+		if err != nil {
+			t.Fatalf("Render() error = %v, want nil", err)
+		}
+
+		if got != "<h1 id=\"hello-world\">Hello World</h1>\n" {
+			t.Errorf("Render() = %q, want a heading with an id", got)
+		}
+	})
+
+	t.Run("it should sanitize the output", func(t *testing.T) {
+		content := "<script>alert(1)</script>\n\n[link](javascript:alert(1)) <b onclick=\"alert(1)\">bold</b>\n"
+
+		got, err := renderer.Render(&domain.Post{ID: 4, Content: content})
+		if err != nil {
+			t.Fatalf("Render() error = %v, want nil", err)
+		}
+
+		for _, unsafe := range []string{"<script", "javascript:", "onclick"} {
+			if strings.Contains(got, unsafe) {
+				t.Errorf("Render() = %s, want %s to be removed", got, unsafe)
+			}
+		}
+	})
+
+	t.Run("it should cache the output until the post is updated", func(t *testing.T) {
+		updatedAt := time.Date(2024, 7, 15, 10, 30, 0, 0, time.UTC)
+		post := &domain.Post{ID: 5, Content: "first", UpdatedAt: updatedAt}
+
+		if _, err := renderer.Render(post); err != nil {
+			t.Fatalf("Render() error = %v, want nil", err)
+		}
+
+		post.Content = "second"
+
+		got, _ := renderer.Render(post)
+		if got != "<p>first</p>\n" {
+			t.Errorf("Render() = %q, want the cached output", got)
+		}
+
+		post.UpdatedAt = updatedAt.Add(time.Second)
+
+		got, _ = renderer.Render(post)
+		if got != "<p>second</p>\n" {
+			t.Errorf("Render() = %q, want the output of the updated post", got)
+		}
+	})
+
+	t.Run("it should evict the least recently rendered posts", func(t *testing.T) {
+		renderer := NewRenderer().(*Renderer)
+		renderer.maxEntries = 2
+
+		for id := int32(1); id <= 3; id++ {
+			if _, err := renderer.Render(&domain.Post{ID: id, Content: "post"}); err != nil {
+				t.Fatalf("Render() error = %v, want nil", err)
+			}
+		}
+
+		if len(renderer.cache) != 2 || renderer.recent.Len() != 2 {
+			t.Fatalf("Expected 2 cached posts, got %d", len(renderer.cache))
+		}
+
+		if _, ok := renderer.cache[1]; ok {
+			t.Errorf("Expected the first post to be evicted")
+		}
+	})
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	auditDomain "github.com/yavurb/goyurback/internal/audit/domain"
+	auditRepository "github.com/yavurb/goyurback/internal/audit/infrastructure/repository"
+	"github.com/yavurb/goyurback/internal/database/postgres"
+)
+
+// postSnapshot is the state of a post recorded in the audit log.
+type postSnapshot struct {
+	PublishedAt *time.Time `json:"published_at"`
+	PublishAt   *time.Time `json:"publish_at"`
+	Title       string     `json:"title"`
+	Author      string     `json:"author"`
+	Slug        string     `json:"slug"`
+	Status      string     `json:"status"`
+	Description string     `json:"description"`
+	Content     string     `json:"content"`
+	Tags        []string   `json:"tags"`
+}
+
+// postStatusSnapshot is recorded when only the status of a post changes, like when the
+// scheduler publishes it.
+type postStatusSnapshot struct {
+	Status string `json:"status"`
+}
+
+func newPostSnapshot(post *postgres.Post, tags []string) *postSnapshot {
+	return &postSnapshot{
+		PublishedAt: timestampOrNil(post.PublishedAt),
+		PublishAt:   timestampOrNil(post.PublishAt),
+		Title:       post.Title,
+		Author:      post.Author,
+		Slug:        post.Slug,
+		Status:      string(post.Status),
+		Description: post.Description,
+		Content:     post.Content,
+		Tags:        tags,
+	}
+}
+
+func recordChange(ctx context.Context, db *postgres.Queries, action auditDomain.Action, publicID string, before, after any) error {
+	return auditRepository.Record(ctx, db, &auditDomain.Change{
+		Before:     before,
+		After:      after,
+		EntityID:   publicID,
+		Action:     action,
+		EntityType: auditDomain.EntityPosts,
+	})
+}
+
+// getTags returns the tags of a single post.
+func getTags(ctx context.Context, db *postgres.Queries, postID int32) ([]string, error) {
+	rows, err := db.GetPostsTags(ctx, []int32{postID})
+	if err != nil {
+		log.Printf("DB Error obtaining post tags: %v\n", err)
+
+		return nil, err
+	}
+
+	tags := []string{}
+
+	for _, row := range rows {
+		tags = append(tags, row.Name)
+	}
+
+	return tags, nil
+}
+
+func timestampOrNil(timestamp pgtype.Timestamp) *time.Time {
+	if !timestamp.Valid {
+		return nil
+	}
+
+	return &timestamp.Time
+}
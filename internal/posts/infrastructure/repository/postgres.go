@@ -4,28 +4,48 @@ import (
 	"context"
 	"errors"
 	"log"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
+	auditDomain "github.com/yavurb/goyurback/internal/audit/domain"
 	"github.com/yavurb/goyurback/internal/database/postgres"
 	"github.com/yavurb/goyurback/internal/posts/domain"
 )
 
 type Repository struct {
-	db *postgres.Queries
+	db       *postgres.Queries
+	connpool *pgxpool.Pool
 }
 
 func NewRepo(connpool *pgxpool.Pool) domain.PostRepository {
 	db := postgres.New(connpool)
 
 	return &Repository{
-		db: db,
+		db:       db,
+		connpool: connpool,
 	}
 }
 
+// CreatePost creates the post along with its tags and first revision.
 func (r *Repository) CreatePost(ctx context.Context, post *domain.PostCreate) (*domain.Post, error) {
-	post_, err := r.db.CreatePost(ctx, postgres.CreatePostParams{
+	tx, err := r.connpool.Begin(ctx)
+	if err != nil {
+		log.Printf("DB Error starting transaction: %v\n", err)
+
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := r.db.WithTx(tx)
+
+	if err := checkSlugHistory(ctx, qtx, post.Slug, 0); err != nil {
+		return nil, err
+	}
+
+	post_, err := qtx.CreatePost(ctx, postgres.CreatePostParams{
 		PublicID:    post.PublicID,
 		Title:       post.Title,
 		Author:      post.Author,
@@ -35,24 +55,32 @@ func (r *Repository) CreatePost(ctx context.Context, post *domain.PostCreate) (*
 	})
 	if err != nil {
 		log.Printf("DB Error creating post: %v\n", err)
+
+		return nil, handleUniqueViolation(err)
+	}
+
+	if err := createRevision(ctx, qtx, &post_); err != nil {
 		return nil, err
 	}
 
-	newPost := &domain.Post{
-		ID:          post_.ID,
-		PublicID:    post_.PublicID,
-		Title:       post_.Title,
-		Author:      post_.Author,
-		Slug:        post_.Slug,
-		Description: post_.Description,
-		Content:     post_.Content,
-		Status:      domain.Status(post_.Status),
-		PublishedAt: post_.PublishedAt.Time,
-		CreatedAt:   post_.CreatedAt.Time,
-		UpdatedAt:   post_.UpdatedAt.Time,
+	if err := setTags(ctx, qtx, post_.ID, post.Tags); err != nil {
+		return nil, err
 	}
 
-	return newPost, nil
+	if err := recordChange(ctx, qtx, auditDomain.ActionCreate, post_.PublicID, nil, newPostSnapshot(&post_, post.Tags)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Printf("DB Error committing post creation: %v\n", err)
+
+		return nil, err
+	}
+
+	postCreated := toDomainStruct(&post_)
+	postCreated.Tags = post.Tags
+
+	return postCreated, nil
 }
 
 func (r *Repository) GetPost(ctx context.Context, id string) (*domain.Post, error) {
@@ -67,26 +95,66 @@ func (r *Repository) GetPost(ctx context.Context, id string) (*domain.Post, erro
 		log.Panicf("DB Error obtaining post: %v", err)
 	}
 
-	post := &domain.Post{
-		ID:          post_.ID,
-		PublicID:    post_.PublicID,
-		Title:       post_.Title,
-		Author:      post_.Author,
-		Slug:        post_.Slug,
-		Description: post_.Description,
-		Content:     post_.Content,
-		Status:      domain.Status(post_.Status),
-		PublishedAt: post_.PublishedAt.Time,
-		CreatedAt:   post_.CreatedAt.Time,
-		UpdatedAt:   post_.UpdatedAt.Time,
+	return r.withTags(ctx, toDomainStruct(&post_))
+}
+
+func (r *Repository) GetPostBySlug(ctx context.Context, slug string) (*domain.Post, error) {
+	post_, err := r.db.GetPostBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrPostNotFound
+		}
+
+		log.Printf("DB Error getting post by slug: %v\n", err)
+
+		return nil, err
 	}
 
-	return post, nil
+	return r.withTags(ctx, toDomainStruct(&post_))
 }
 
-// TODO: Add pagination
-func (r *Repository) GetPosts(ctx context.Context) ([]*domain.Post, error) {
-	posts, err := r.db.GetPosts(ctx)
+func (r *Repository) GetPostByPreviousSlug(ctx context.Context, slug string) (*domain.Post, error) {
+	post_, err := r.db.GetPostByPreviousSlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrPostNotFound
+		}
+
+		log.Printf("DB Error getting post by previous slug: %v\n", err)
+
+		return nil, err
+	}
+
+	return r.withTags(ctx, toDomainStruct(&post_))
+}
+
+func (r *Repository) GetPosts(ctx context.Context, query *domain.PostQuery) ([]*domain.Post, error) {
+	params := postgres.GetPostsParams{
+		Author: pgtype.Text{
+			String: query.Author,
+			Valid:  query.Author != "",
+		},
+		PublishedAfter: pgtype.Timestamp{
+			Time:  query.PublishedAfter,
+			Valid: !query.PublishedAfter.IsZero(),
+		},
+		PublishedBefore: pgtype.Timestamp{
+			Time:  query.PublishedBefore,
+			Valid: !query.PublishedBefore.IsZero(),
+		},
+		Tag: pgtype.Text{
+			String: query.Tag,
+			Valid:  query.Tag != "",
+		},
+		Limit: query.Limit,
+	}
+
+	if query.After != nil {
+		params.CursorPublishedAt = pgtype.Timestamp{Time: query.After.PublishedAt, Valid: true}
+		params.CursorID = pgtype.Int4{Int32: query.After.ID, Valid: true}
+	}
+
+	posts, err := r.db.GetPosts(ctx, params)
 	if err != nil {
 		log.Printf("DB Error obtaining posts: %v", err)
 
@@ -100,26 +168,54 @@ func (r *Repository) GetPosts(ctx context.Context) ([]*domain.Post, error) {
 	posts_ := []*domain.Post{}
 
 	for _, post := range posts {
-		posts_ = append(posts_, &domain.Post{
-			ID:          post.ID,
-			PublicID:    post.PublicID,
-			Title:       post.Title,
-			Author:      post.Author,
-			Slug:        post.Slug,
-			Description: post.Description,
-			Content:     post.Content,
-			Status:      domain.Status(post.Status),
-			PublishedAt: post.PublishedAt.Time,
-			CreatedAt:   post.CreatedAt.Time,
-			UpdatedAt:   post.UpdatedAt.Time,
-		})
+		posts_ = append(posts_, toDomainStruct(&post))
+	}
+
+	if err := loadTags(ctx, r.db, posts_...); err != nil {
+		return nil, err
 	}
 
 	return posts_, nil
 }
 
+// UpdatePost updates the post and, when its slug changes, keeps the previous slug in the
+// history so old links can still be resolved. A new revision is saved when the content of
+// the post changes. The tags of the post are replaced by post.Tags. All writes happen in
+// the same transaction.
 func (r *Repository) UpdatePost(ctx context.Context, post *domain.Post) (*domain.Post, error) {
-	post_, err := r.db.UpdatePost(ctx, postgres.UpdatePostParams{
+	tx, err := r.connpool.Begin(ctx)
+	if err != nil {
+		log.Printf("DB Error starting transaction: %v\n", err)
+
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := r.db.WithTx(tx)
+
+	current, err := qtx.GetPostForUpdate(ctx, post.ID)
+	if err != nil {
+		log.Printf("DB Error getting post to update: %v\n", err)
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrPostNotFound
+		}
+
+		return nil, err
+	}
+
+	currentTags, err := getTags(ctx, qtx, current.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if current.Slug != post.Slug {
+		if err := checkSlugHistory(ctx, qtx, post.Slug, post.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	post_, err := qtx.UpdatePost(ctx, postgres.UpdatePostParams{
 		ID:          post.ID,
 		Title:       post.Title,
 		Author:      post.Author,
@@ -128,17 +224,288 @@ func (r *Repository) UpdatePost(ctx context.Context, post *domain.Post) (*domain
 		Content:     post.Content,
 		Status:      postgres.PostStatus(post.Status),
 		PublishedAt: pgtype.Timestamp{
-			Valid: true,
+			Valid: !post.PublishedAt.IsZero(),
 			Time:  post.PublishedAt,
 		},
+		PublishAt: pgtype.Timestamp{
+			Valid: !post.PublishAt.IsZero(),
+			Time:  post.PublishAt,
+		},
 	})
 	if err != nil {
 		log.Printf("DB Error updating post: %v\n", err)
 
+		return nil, handleUniqueViolation(err)
+	}
+
+	if err := setTags(ctx, qtx, post_.ID, post.Tags); err != nil {
+		return nil, err
+	}
+
+	if current.Title != post_.Title || current.Description != post_.Description || current.Content != post_.Content {
+		if err := createRevision(ctx, qtx, &post_); err != nil {
+			return nil, err
+		}
+	}
+
+	if current.Slug != post_.Slug {
+		// The post may be taking back one of its previous slugs
+		if err := qtx.DeletePostSlugHistory(ctx, post_.Slug); err != nil {
+			log.Printf("DB Error deleting slug history: %v\n", err)
+
+			return nil, err
+		}
+
+		err = qtx.CreatePostSlugHistory(ctx, postgres.CreatePostSlugHistoryParams{
+			PostID: post_.ID,
+			Slug:   current.Slug,
+		})
+		if err != nil {
+			log.Printf("DB Error saving slug history: %v\n", err)
+
+			return nil, err
+		}
+	}
+
+	err = recordChange(ctx, qtx, auditDomain.ActionUpdate, post_.PublicID, newPostSnapshot(&current, currentTags), newPostSnapshot(&post_, post.Tags))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Printf("DB Error committing post update: %v\n", err)
+
+		return nil, err
+	}
+
+	postUpdated := toDomainStruct(&post_)
+	postUpdated.Tags = post.Tags
+
+	return postUpdated, nil
+}
+
+// PublishDuePosts publishes up to limit scheduled posts due at now. Rows locked by another
+// instance are skipped, so several schedulers can run at the same time.
+func (r *Repository) PublishDuePosts(ctx context.Context, now time.Time, limit int32) ([]*domain.Post, error) {
+	tx, err := r.connpool.Begin(ctx)
+	if err != nil {
+		log.Printf("DB Error starting transaction: %v\n", err)
+
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := r.db.WithTx(tx)
+
+	posts, err := qtx.PublishDuePosts(ctx, postgres.PublishDuePostsParams{
+		PublishAt: pgtype.Timestamp{Time: now, Valid: true},
+		Limit:     limit,
+	})
+	if err != nil {
+		log.Printf("DB Error publishing scheduled posts: %v\n", err)
+
+		return nil, err
+	}
+
+	posts_ := []*domain.Post{}
+
+	for _, post := range posts {
+		before := &postStatusSnapshot{Status: string(domain.Scheduled)}
+		after := &postStatusSnapshot{Status: string(post.Status)}
+
+		if err := recordChange(ctx, qtx, auditDomain.ActionUpdate, post.PublicID, before, after); err != nil {
+			return nil, err
+		}
+
+		posts_ = append(posts_, toDomainStruct(&post))
+	}
+
+	if err := loadTags(ctx, qtx, posts_...); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Printf("DB Error committing scheduled posts: %v\n", err)
+
 		return nil, err
 	}
 
-	postUpdated := &domain.Post{
+	return posts_, nil
+}
+
+func (r *Repository) GetPostRevisions(ctx context.Context, postID int32) ([]*domain.PostRevision, error) {
+	revisions, err := r.db.GetPostRevisions(ctx, postID)
+	if err != nil {
+		log.Printf("DB Error obtaining post revisions: %v\n", err)
+
+		return nil, err
+	}
+
+	revisions_ := []*domain.PostRevision{}
+
+	for _, revision := range revisions {
+		revisions_ = append(revisions_, toDomainRevision(&revision))
+	}
+
+	return revisions_, nil
+}
+
+func (r *Repository) GetPostRevision(ctx context.Context, postID, revision int32) (*domain.PostRevision, error) {
+	revision_, err := r.db.GetPostRevision(ctx, postgres.GetPostRevisionParams{
+		PostID:   postID,
+		Revision: revision,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrRevisionNotFound
+		}
+
+		log.Printf("DB Error getting post revision: %v\n", err)
+
+		return nil, err
+	}
+
+	return toDomainRevision(&revision_), nil
+}
+
+func (r *Repository) GetTags(ctx context.Context) ([]*domain.Tag, error) {
+	tags, err := r.db.GetTags(ctx)
+	if err != nil {
+		log.Printf("DB Error obtaining tags: %v\n", err)
+
+		return nil, err
+	}
+
+	tags_ := []*domain.Tag{}
+
+	for _, tag := range tags {
+		tags_ = append(tags_, &domain.Tag{
+			Name:  tag.Name,
+			Posts: tag.Posts,
+		})
+	}
+
+	return tags_, nil
+}
+
+func (r *Repository) withTags(ctx context.Context, post *domain.Post) (*domain.Post, error) {
+	if err := loadTags(ctx, r.db, post); err != nil {
+		return nil, err
+	}
+
+	return post, nil
+}
+
+// loadTags sets the tags of all the posts with a single query.
+func loadTags(ctx context.Context, db *postgres.Queries, posts ...*domain.Post) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	postsByID := make(map[int32]*domain.Post, len(posts))
+	ids := make([]int32, 0, len(posts))
+
+	for _, post := range posts {
+		postsByID[post.ID] = post
+		ids = append(ids, post.ID)
+	}
+
+	rows, err := db.GetPostsTags(ctx, ids)
+	if err != nil {
+		log.Printf("DB Error obtaining post tags: %v\n", err)
+
+		return err
+	}
+
+	for _, row := range rows {
+		post := postsByID[row.PostID]
+		post.Tags = append(post.Tags, row.Name)
+	}
+
+	return nil
+}
+
+// setTags replaces the tags of the post, creating the ones that don't exist yet.
+func setTags(ctx context.Context, db *postgres.Queries, postID int32, tags []string) error {
+	if err := db.DeletePostTags(ctx, postID); err != nil {
+		log.Printf("DB Error deleting post tags: %v\n", err)
+
+		return err
+	}
+
+	if len(tags) == 0 {
+		return nil
+	}
+
+	if err := db.CreateTags(ctx, tags); err != nil {
+		log.Printf("DB Error saving tags: %v\n", err)
+
+		return err
+	}
+
+	err := db.CreatePostTags(ctx, postgres.CreatePostTagsParams{
+		PostID: postID,
+		Names:  tags,
+	})
+	if err != nil {
+		log.Printf("DB Error saving post tags: %v\n", err)
+
+		return err
+	}
+
+	return nil
+}
+
+func createRevision(ctx context.Context, db *postgres.Queries, post *postgres.Post) error {
+	_, err := db.CreatePostRevision(ctx, postgres.CreatePostRevisionParams{
+		PostID:      post.ID,
+		Title:       post.Title,
+		Description: post.Description,
+		Content:     post.Content,
+	})
+	if err != nil {
+		log.Printf("DB Error saving post revision: %v\n", err)
+
+		return err
+	}
+
+	return nil
+}
+
+// checkSlugHistory returns ErrSlugAlreadyExists if the slug used to belong to a post other than postID,
+// as taking it would break the redirects of that post.
+func checkSlugHistory(ctx context.Context, db *postgres.Queries, slug string, postID int32) error {
+	history, err := db.GetPostSlugHistory(ctx, slug)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+
+		log.Printf("DB Error getting slug history: %v\n", err)
+
+		return err
+	}
+
+	if history.PostID != postID {
+		return domain.ErrSlugAlreadyExists
+	}
+
+	return nil
+}
+
+func handleUniqueViolation(err error) error {
+	pgErr := new(pgconn.PgError)
+	if errors.As(err, &pgErr) {
+		if pgErr.Code == "23505" && pgErr.ConstraintName == "posts_slug_key" {
+			return domain.ErrSlugAlreadyExists
+		}
+	}
+
+	return err
+}
+
+func toDomainStruct(post_ *postgres.Post) *domain.Post {
+	return &domain.Post{
 		ID:          post_.ID,
 		PublicID:    post_.PublicID,
 		Title:       post_.Title,
@@ -148,9 +515,20 @@ func (r *Repository) UpdatePost(ctx context.Context, post *domain.Post) (*domain
 		Content:     post_.Content,
 		Status:      domain.Status(post_.Status),
 		PublishedAt: post_.PublishedAt.Time,
+		PublishAt:   post_.PublishAt.Time,
 		CreatedAt:   post_.CreatedAt.Time,
 		UpdatedAt:   post_.UpdatedAt.Time,
 	}
+}
 
-	return postUpdated, nil
+func toDomainRevision(revision *postgres.PostRevision) *domain.PostRevision {
+	return &domain.PostRevision{
+		ID:          revision.ID,
+		PostID:      revision.PostID,
+		Revision:    revision.Revision,
+		Title:       revision.Title,
+		Description: revision.Description,
+		Content:     revision.Content,
+		CreatedAt:   revision.CreatedAt.Time,
+	}
 }
@@ -3,11 +3,13 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 	"slices"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/yavurb/goyurback/internal/posts/domain"
 	"github.com/yavurb/goyurback/testhelpers"
@@ -63,6 +65,25 @@ func TestCreatePost(t *testing.T) {
 		}
 	})
 
+	t.Run("it should return an error if the slug is taken", func(t *testing.T) {
+		testhelpers.CleanDatabase(t, ctx, pgContainer.ConnString)
+
+		for _, publicID := range []string{"po_18892", "po_18893"} {
+			_, err = repo.CreatePost(ctx, &domain.PostCreate{
+				PublicID:    publicID,
+				Title:       "My Post",
+				Author:      "Roy",
+				Slug:        "my-post",
+				Description: "my post description",
+				Content:     "# My Post\n\nThis is my post content.",
+			})
+		}
+
+		if !errors.Is(err, domain.ErrSlugAlreadyExists) {
+			t.Errorf("Got %v creating post, want %v", err, domain.ErrSlugAlreadyExists)
+		}
+	})
+
 	t.Run("it should return an error if a db error occurs", func(t *testing.T) {
 		testhelpers.DeleteDatabase(t, ctx, pgContainer.ConnString)
 
@@ -217,7 +238,7 @@ func TestGetPosts(t *testing.T) {
 			}
 		}
 
-		got, err := repo.GetPosts(ctx)
+		got, err := repo.GetPosts(ctx, &domain.PostQuery{Limit: 10})
 		if err != nil {
 			t.Errorf("Got error getting posts, want no error: %v", err)
 		}
@@ -231,6 +252,69 @@ func TestGetPosts(t *testing.T) {
 		}
 	})
 
+	t.Run("it should paginate and filter posts", func(t *testing.T) {
+		testhelpers.CleanDatabase(t, ctx, pgContainer.ConnString)
+
+		publishedAt := time.Date(2024, 7, 15, 10, 0, 0, 0, time.UTC)
+		authors := []string{"Roy", "Roy", "Jane"}
+
+		for i, author := range authors {
+			postCreated, err := repo.CreatePost(ctx, &domain.PostCreate{
+				PublicID:    fmt.Sprintf("po_1889%d", i),
+				Title:       "My Post",
+				Author:      author,
+				Slug:        fmt.Sprintf("my-post-%d", i),
+				Description: "my post description",
+				Content:     "# My Post\n\nThis is my post content.",
+			})
+			if err != nil {
+				t.Fatalf("Got error creating post, want no error: %v", err)
+			}
+
+			postCreated.Status = domain.Published
+			postCreated.PublishedAt = publishedAt.Add(time.Duration(i) * time.Hour)
+
+			if _, err = repo.UpdatePost(ctx, postCreated); err != nil {
+				t.Fatalf("Got error updating post, want no error: %v", err)
+			}
+		}
+
+		firstPage, err := repo.GetPosts(ctx, &domain.PostQuery{Author: "Roy", Limit: 1})
+		if err != nil {
+			t.Fatalf("Got error getting posts, want no error: %v", err)
+		}
+
+		if len(firstPage) != 1 || firstPage[0].PublicID != "po_18891" {
+			t.Fatalf("Got %v, want only post po_18891", firstPage)
+		}
+
+		secondPage, err := repo.GetPosts(ctx, &domain.PostQuery{
+			Author: "Roy",
+			After:  domain.NewPostCursor(firstPage[0]),
+			Limit:  10,
+		})
+		if err != nil {
+			t.Fatalf("Got error getting posts, want no error: %v", err)
+		}
+
+		if len(secondPage) != 1 || secondPage[0].PublicID != "po_18890" {
+			t.Errorf("Got %v, want only post po_18890", secondPage)
+		}
+
+		inRange, err := repo.GetPosts(ctx, &domain.PostQuery{
+			PublishedAfter:  publishedAt.Add(time.Hour),
+			PublishedBefore: publishedAt.Add(2 * time.Hour),
+			Limit:           10,
+		})
+		if err != nil {
+			t.Fatalf("Got error getting posts, want no error: %v", err)
+		}
+
+		if len(inRange) != 1 || inRange[0].PublicID != "po_18891" {
+			t.Errorf("Got %v, want only post po_18891", inRange)
+		}
+	})
+
 	t.Run("it should return an empty slice", func(t *testing.T) {
 		conn, err := pgxpool.New(ctx, pgContainer.ConnString)
 		if err != nil {
@@ -242,7 +326,7 @@ func TestGetPosts(t *testing.T) {
 		repo := NewRepo(conn)
 		want := []*domain.Post{}
 
-		got, err := repo.GetPosts(ctx)
+		got, err := repo.GetPosts(ctx, &domain.PostQuery{Limit: 10})
 		if err != nil {
 			t.Errorf("Got error getting posts, want no error: %v", err)
 		}
@@ -309,6 +393,82 @@ func TestPostUpdate(t *testing.T) {
 		}
 	})
 
+	t.Run("it should not set a publish date on unpublished posts", func(t *testing.T) {
+		testhelpers.CleanDatabase(t, ctx, pgContainer.ConnString)
+
+		postCreated, err := repo.CreatePost(ctx, &domain.PostCreate{
+			PublicID:    "po_18892",
+			Title:       "My Post",
+			Author:      "Roy",
+			Slug:        "my-post",
+			Description: "my post description",
+			Content:     "# My Post\n\nThis is my post content.",
+		})
+		if err != nil {
+			t.Fatalf("Got error creating post, want no error: %v", err)
+		}
+
+		postCreated.Title = "My Post Alt"
+
+		if _, err = repo.UpdatePost(ctx, postCreated); err != nil {
+			t.Fatalf("Got error updating post, want no error: %v", err)
+		}
+
+		var publishedAt pgtype.Timestamp
+
+		err = conn.QueryRow(ctx, "SELECT published_at FROM posts WHERE public_id = $1", "po_18892").Scan(&publishedAt)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if publishedAt.Valid {
+			t.Errorf("Got publish date %v, want NULL", publishedAt.Time)
+		}
+	})
+
+	t.Run("it should keep the previous slug in the history", func(t *testing.T) {
+		testhelpers.CleanDatabase(t, ctx, pgContainer.ConnString)
+
+		postCreated, err := repo.CreatePost(ctx, &domain.PostCreate{
+			PublicID:    "po_18892",
+			Title:       "My Post",
+			Author:      "Roy",
+			Slug:        "my-post",
+			Description: "my post description",
+			Content:     "# My Post\n\nThis is my post content.",
+		})
+		if err != nil {
+			t.Fatalf("Got error creating post, want no error: %v", err)
+		}
+
+		postCreated.Slug = "my-renamed-post"
+
+		if _, err = repo.UpdatePost(ctx, postCreated); err != nil {
+			t.Fatalf("Got error updating post, want no error: %v", err)
+		}
+
+		got, err := repo.GetPostByPreviousSlug(ctx, "my-post")
+		if err != nil {
+			t.Fatalf("Got error getting post by previous slug, want no error: %v", err)
+		}
+
+		if got.PublicID != "po_18892" || got.Slug != "my-renamed-post" {
+			t.Errorf("Got %v, want post po_18892 with slug my-renamed-post", got)
+		}
+
+		_, err = repo.CreatePost(ctx, &domain.PostCreate{
+			PublicID:    "po_18893",
+			Title:       "My Post",
+			Author:      "Roy",
+			Slug:        "my-post",
+			Description: "my post description",
+			Content:     "# My Post\n\nThis is my post content.",
+		})
+		if !errors.Is(err, domain.ErrSlugAlreadyExists) {
+			t.Errorf("Got %v creating post with a previous slug, want %v", err, domain.ErrSlugAlreadyExists)
+		}
+	})
+
 	t.Run("it should return an error if a post does not exists", func(t *testing.T) {
 		testhelpers.CleanDatabase(t, ctx, pgContainer.ConnString)
 
@@ -351,3 +511,232 @@ func TestPostUpdate(t *testing.T) {
 		}
 	})
 }
+
+func TestPublishDuePosts(t *testing.T) {
+	ctx := context.Background()
+
+	pgContainer, err := testhelpers.CreatePostgresContainer(t, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := pgxpool.New(ctx, pgContainer.ConnString)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { conn.Close() })
+
+	repo := NewRepo(conn)
+
+	t.Run("it should publish only the due posts", func(t *testing.T) {
+		testhelpers.CleanDatabase(t, ctx, pgContainer.ConnString)
+
+		now := time.Date(2024, 7, 15, 10, 0, 0, 0, time.UTC)
+		publishDates := []time.Time{now.Add(-time.Hour), now.Add(time.Hour)}
+
+		for i, publishAt := range publishDates {
+			postCreated, err := repo.CreatePost(ctx, &domain.PostCreate{
+				PublicID:    fmt.Sprintf("po_1889%d", i),
+				Title:       "My Post",
+				Author:      "Roy",
+				Slug:        fmt.Sprintf("my-post-%d", i),
+				Description: "my post description",
+				Content:     "# My Post\n\nThis is my post content.",
+			})
+			if err != nil {
+				t.Fatalf("Got error creating post, want no error: %v", err)
+			}
+
+			postCreated.Status = domain.Scheduled
+			postCreated.PublishAt = publishAt
+
+			if _, err = repo.UpdatePost(ctx, postCreated); err != nil {
+				t.Fatalf("Got error scheduling post, want no error: %v", err)
+			}
+		}
+
+		got, err := repo.PublishDuePosts(ctx, now, 10)
+		if err != nil {
+			t.Fatalf("Got error publishing posts, want no error: %v", err)
+		}
+
+		if len(got) != 1 {
+			t.Fatalf("Got %d published posts, want 1", len(got))
+		}
+
+		if got[0].PublicID != "po_18890" || got[0].Status != domain.Published {
+			t.Errorf("Got post %s with status %s, want po_18890 published", got[0].PublicID, got[0].Status)
+		}
+
+		if !got[0].PublishedAt.Equal(publishDates[0]) || !got[0].PublishAt.IsZero() {
+			t.Errorf("Got published_at %v and publish_at %v, want %v and no publish_at", got[0].PublishedAt, got[0].PublishAt, publishDates[0])
+		}
+
+		scheduled, err := repo.GetPost(ctx, "po_18891")
+		if err != nil {
+			t.Fatalf("Got error getting post, want no error: %v", err)
+		}
+
+		if scheduled.Status != domain.Scheduled {
+			t.Errorf("Got status %s for a post not yet due, want %s", scheduled.Status, domain.Scheduled)
+		}
+	})
+}
+
+func TestPostRevisions(t *testing.T) {
+	ctx := context.Background()
+
+	pgContainer, err := testhelpers.CreatePostgresContainer(t, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := pgxpool.New(ctx, pgContainer.ConnString)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { conn.Close() })
+
+	repo := NewRepo(conn)
+
+	t.Run("it should save a revision when the content changes", func(t *testing.T) {
+		testhelpers.CleanDatabase(t, ctx, pgContainer.ConnString)
+
+		postCreated, err := repo.CreatePost(ctx, &domain.PostCreate{
+			PublicID:    "po_18892",
+			Title:       "My Post",
+			Author:      "Roy",
+			Slug:        "my-post",
+			Description: "my post description",
+			Content:     "# My Post\n\nThis is my post content.",
+		})
+		if err != nil {
+			t.Fatalf("Got error creating post, want no error: %v", err)
+		}
+
+		postCreated.Content = "# My Post\n\nThis is my post content alt."
+
+		if _, err = repo.UpdatePost(ctx, postCreated); err != nil {
+			t.Fatalf("Got error updating post, want no error: %v", err)
+		}
+
+		// Changes outside of the content don't create revisions
+		postCreated.Author = "Roy Perez"
+
+		if _, err = repo.UpdatePost(ctx, postCreated); err != nil {
+			t.Fatalf("Got error updating post, want no error: %v", err)
+		}
+
+		got, err := repo.GetPostRevisions(ctx, postCreated.ID)
+		if err != nil {
+			t.Fatalf("Got error getting revisions, want no error: %v", err)
+		}
+
+		if len(got) != 2 {
+			t.Fatalf("Got %d revisions, want 2", len(got))
+		}
+
+		if got[0].Revision != 2 || got[0].Content != postCreated.Content {
+			t.Errorf("Got latest revision %d with content %q, want revision 2 with %q", got[0].Revision, got[0].Content, postCreated.Content)
+		}
+
+		first, err := repo.GetPostRevision(ctx, postCreated.ID, 1)
+		if err != nil {
+			t.Fatalf("Got error getting revision, want no error: %v", err)
+		}
+
+		if first.Content != "# My Post\n\nThis is my post content." {
+			t.Errorf("Got first revision content %q, want the original content", first.Content)
+		}
+	})
+
+	t.Run("it should return a not found error", func(t *testing.T) {
+		_, err := repo.GetPostRevision(ctx, 1000, 1)
+		if !errors.Is(err, domain.ErrRevisionNotFound) {
+			t.Errorf("Got %v getting revision, want %v", err, domain.ErrRevisionNotFound)
+		}
+	})
+}
+
+func TestPostTags(t *testing.T) {
+	ctx := context.Background()
+
+	pgContainer, err := testhelpers.CreatePostgresContainer(t, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := pgxpool.New(ctx, pgContainer.ConnString)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { conn.Close() })
+
+	repo := NewRepo(conn)
+
+	t.Run("it should save, replace and filter by tags", func(t *testing.T) {
+		testhelpers.CleanDatabase(t, ctx, pgContainer.ConnString)
+
+		tags := [][]string{{"go", "postgres"}, {"go"}}
+
+		for i, postTags := range tags {
+			postCreated, err := repo.CreatePost(ctx, &domain.PostCreate{
+				PublicID:    fmt.Sprintf("po_1889%d", i),
+				Title:       "My Post",
+				Author:      "Roy",
+				Slug:        fmt.Sprintf("my-post-%d", i),
+				Description: "my post description",
+				Content:     "# My Post\n\nThis is my post content.",
+				Tags:        postTags,
+			})
+			if err != nil {
+				t.Fatalf("Got error creating post, want no error: %v", err)
+			}
+
+			postCreated.Status = domain.Published
+			postCreated.PublishedAt = time.Now().UTC()
+
+			if _, err = repo.UpdatePost(ctx, postCreated); err != nil {
+				t.Fatalf("Got error updating post, want no error: %v", err)
+			}
+		}
+
+		got, err := repo.GetPost(ctx, "po_18890")
+		if err != nil {
+			t.Fatalf("Got error getting post, want no error: %v", err)
+		}
+
+		if !cmp.Equal(got.Tags, tags[0]) {
+			t.Errorf("Mismatch getting post tags (-want,+got):\n%s", cmp.Diff(tags[0], got.Tags))
+		}
+
+		posts, err := repo.GetPosts(ctx, &domain.PostQuery{Tag: "postgres", Limit: 10})
+		if err != nil {
+			t.Fatalf("Got error getting posts, want no error: %v", err)
+		}
+
+		if len(posts) != 1 || posts[0].PublicID != "po_18890" {
+			t.Errorf("Got %v filtering by tag, want only po_18890", posts)
+		}
+
+		got.Tags = []string{"databases"}
+
+		if _, err = repo.UpdatePost(ctx, got); err != nil {
+			t.Fatalf("Got error updating post, want no error: %v", err)
+		}
+
+		gotTags, err := repo.GetTags(ctx)
+		if err != nil {
+			t.Fatalf("Got error getting tags, want no error: %v", err)
+		}
+
+		// Tags without published posts are not listed
+		wantTags := []*domain.Tag{{Name: "databases", Posts: 1}, {Name: "go", Posts: 1}}
+		if !cmp.Equal(wantTags, gotTags) {
+			t.Errorf("Mismatch getting tags (-want,+got):\n%s", cmp.Diff(wantTags, gotTags))
+		}
+	})
+}
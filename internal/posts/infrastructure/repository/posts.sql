@@ -5,10 +5,79 @@ INSERT INTO posts (public_id, title, author, slug, description, content) VALUES
 SELECT * FROM posts WHERE public_id = $1;
 
 -- name: GetPosts :many
-SELECT * FROM posts WHERE status = 'published' ORDER BY published_at DESC;
+SELECT * FROM posts
+WHERE status = 'published'
+  AND (sqlc.narg('author')::text IS NULL OR author = sqlc.narg('author'))
+  AND (sqlc.narg('tag')::text IS NULL OR EXISTS (
+    SELECT 1 FROM post_tags JOIN tags ON tags.id = post_tags.tag_id WHERE post_tags.post_id = posts.id AND tags.name = sqlc.narg('tag')
+  ))
+  AND (sqlc.narg('published_after')::timestamp IS NULL OR published_at >= sqlc.narg('published_after'))
+  AND (sqlc.narg('published_before')::timestamp IS NULL OR published_at < sqlc.narg('published_before'))
+  AND (sqlc.narg('cursor_published_at')::timestamp IS NULL OR (published_at, id) < (sqlc.narg('cursor_published_at'), sqlc.narg('cursor_id')::integer))
+ORDER BY published_at DESC, id DESC
+LIMIT sqlc.arg('limit');
 
 -- name: GetPostBySlug :one
 SELECT * FROM posts WHERE slug = $1;
 
 -- name: UpdatePost :one
-UPDATE posts SET title = $1, author = $2, slug = $3, description = $4, content = $5, status = $6, published_at = $7, updated_at = now() WHERE id = $8 RETURNING *;
+UPDATE posts SET title = $1, author = $2, slug = $3, description = $4, content = $5, status = $6, published_at = $7, publish_at = $8, updated_at = now() WHERE id = $9 RETURNING *;
+
+-- name: GetPostForUpdate :one
+SELECT * FROM posts WHERE id = $1 FOR UPDATE;
+
+-- name: GetPostByPreviousSlug :one
+SELECT posts.* FROM posts JOIN post_slug_history ON post_slug_history.post_id = posts.id WHERE post_slug_history.slug = $1;
+
+-- name: GetPostSlugHistory :one
+SELECT * FROM post_slug_history WHERE slug = $1;
+
+-- name: CreatePostSlugHistory :exec
+INSERT INTO post_slug_history (post_id, slug) VALUES ($1, $2) ON CONFLICT (slug) DO UPDATE SET post_id = EXCLUDED.post_id, created_at = now();
+
+-- name: DeletePostSlugHistory :exec
+DELETE FROM post_slug_history WHERE slug = $1;
+
+-- name: PublishDuePosts :many
+UPDATE posts SET status = 'published', published_at = COALESCE(published_at, publish_at), publish_at = NULL, updated_at = now()
+WHERE id IN (
+  SELECT id FROM posts
+  WHERE status = 'scheduled' AND publish_at <= $1
+  ORDER BY publish_at
+  LIMIT $2
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: CreatePostRevision :one
+INSERT INTO post_revisions (post_id, revision, title, description, content)
+VALUES ($1, COALESCE((SELECT MAX(revision) FROM post_revisions WHERE post_id = $1), 0) + 1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetPostRevisions :many
+SELECT * FROM post_revisions WHERE post_id = $1 ORDER BY revision DESC;
+
+-- name: GetPostRevision :one
+SELECT * FROM post_revisions WHERE post_id = $1 AND revision = $2;
+
+-- name: CreateTags :exec
+INSERT INTO tags (name) SELECT unnest(sqlc.arg('names')::varchar[]) ON CONFLICT (name) DO NOTHING;
+
+-- name: CreatePostTags :exec
+INSERT INTO post_tags (post_id, tag_id) SELECT sqlc.arg('post_id')::integer, id FROM tags WHERE name = ANY(sqlc.arg('names')::varchar[]);
+
+-- name: DeletePostTags :exec
+DELETE FROM post_tags WHERE post_id = $1;
+
+-- name: GetPostsTags :many
+SELECT post_tags.post_id, tags.name FROM post_tags
+JOIN tags ON tags.id = post_tags.tag_id
+WHERE post_tags.post_id = ANY(sqlc.arg('post_ids')::integer[])
+ORDER BY tags.name;
+
+-- name: GetTags :many
+SELECT tags.name, COUNT(posts.id) AS posts FROM tags
+JOIN post_tags ON post_tags.tag_id = tags.id
+JOIN posts ON posts.id = post_tags.post_id AND posts.status = 'published'
+GROUP BY tags.name
+ORDER BY tags.name;
@@ -6,18 +6,23 @@ import (
 	"github.com/yavurb/goyurback/internal/posts/domain"
 )
 
+// formatHTML is the value of the `format` query param that adds the rendered content to posts.
+const formatHTML = "html"
+
 type PostIn struct {
-	Title       string `json:"title" validate:"required,min=5,max=128"`
-	Author      string `json:"author" validate:"required,min=3,max=64"`
-	Slug        string `json:"slug" validate:"required"`
-	Description string `json:"description" validate:"required,min=5,max=255"`
-	Content     string `json:"content" validate:"required,min=10"`
+	Title       string   `json:"title" validate:"required,min=5,max=128"`
+	Author      string   `json:"author" validate:"required,min=3,max=64"`
+	Slug        string   `json:"slug" validate:"required"`
+	Description string   `json:"description" validate:"required,min=5,max=255"`
+	Content     string   `json:"content" validate:"required,min=10"`
+	Tags        []string `json:"tags" validate:"omitempty,max=10,dive,required,max=32"`
 }
 
 type PostOut struct {
-	PublishedAt time.Time `json:"published_at"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	PublishedAt *time.Time `json:"published_at"`
+	PublishAt   *time.Time `json:"publish_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
 
 	ID          string        `json:"id"`
 	Title       string        `json:"title"`
@@ -26,8 +31,42 @@ type PostOut struct {
 	Status      domain.Status `json:"status"`
 	Description string        `json:"description"`
 	Content     string        `json:"content"`
+	ContentHTML string        `json:"content_html,omitempty"`
+	Tags        []string      `json:"tags"`
 }
 
+func toPostOut(post *domain.Post) *PostOut {
+	postOut := &PostOut{
+		ID:          post.PublicID,
+		Title:       post.Title,
+		Author:      post.Author,
+		Slug:        post.Slug,
+		Status:      post.Status,
+		Description: post.Description,
+		Content:     post.Content,
+		Tags:        post.Tags,
+		CreatedAt:   post.CreatedAt,
+		UpdatedAt:   post.UpdatedAt,
+	}
+
+	if postOut.Tags == nil {
+		postOut.Tags = []string{}
+	}
+
+	// Posts that were never published have no publish date
+	if !post.PublishedAt.IsZero() {
+		postOut.PublishedAt = &post.PublishedAt
+	}
+
+	if !post.PublishAt.IsZero() {
+		postOut.PublishAt = &post.PublishAt
+	}
+
+	return postOut
+}
+
+// PostUpdate can't schedule a post, as it needs a publish date. Posts are scheduled with
+// PostSchedule instead.
 type PostUpdate struct {
 	Status      *domain.Status `json:"status" validate:"omitempty,oneof=draft published archived"`
 	Title       *string        `json:"title" validate:"omitempty,required,min=5,max=128"`
@@ -35,13 +74,90 @@ type PostUpdate struct {
 	Slug        *string        `json:"slug" validate:"omitempty,required"`
 	Description *string        `json:"description" validate:"omitempty,required,min=5,max=255"`
 	Content     *string        `json:"content" validate:"omitempty,required,min=10"`
+	Tags        []string       `json:"tags" validate:"omitempty,max=10,dive,required,max=32"`
 	ID          string         `param:"id" validate:"required"`
 }
 
+type PostSchedule struct {
+	PublishAt time.Time `json:"publish_at" validate:"required"`
+	ID        string    `param:"id" validate:"required"`
+}
+
 type PostsOut struct {
-	Data []*PostOut `json:"data"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+	Data       []*PostOut `json:"data"`
+}
+
+type GetPostsParams struct {
+	PublishedAfter  time.Time `query:"published_after"`
+	PublishedBefore time.Time `query:"published_before"`
+	Author          string    `query:"author"`
+	Tag             string    `query:"tag"`
+	Cursor          string    `query:"cursor"`
+	Format          string    `query:"format" validate:"omitempty,oneof=markdown html"`
+	Limit           int32     `query:"limit" validate:"omitempty,min=1,max=100"`
 }
 
 type GetPostParams struct {
-	ID string `param:"id" validate:"required"`
+	ID     string `param:"id" validate:"required"`
+	Format string `query:"format" validate:"omitempty,oneof=markdown html"`
+}
+
+type GetPostBySlugParams struct {
+	Slug   string `param:"slug" validate:"required"`
+	Format string `query:"format" validate:"omitempty,oneof=markdown html"`
+}
+
+type PostRevisionOut struct {
+	CreatedAt   time.Time `json:"created_at"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Content     string    `json:"content"`
+	Revision    int32     `json:"revision"`
+}
+
+func toPostRevisionOut(revision *domain.PostRevision) *PostRevisionOut {
+	return &PostRevisionOut{
+		Revision:    revision.Revision,
+		Title:       revision.Title,
+		Description: revision.Description,
+		Content:     revision.Content,
+		CreatedAt:   revision.CreatedAt,
+	}
+}
+
+type PostRevisionsOut struct {
+	Data []*PostRevisionOut `json:"data"`
+}
+
+type PostRevisionsDiffOut struct {
+	Diff string `json:"diff"`
+	From int32  `json:"from"`
+	To   int32  `json:"to"`
+}
+
+type GetPostRevisionParams struct {
+	ID       string `param:"id" validate:"required"`
+	Revision int32  `param:"rev" validate:"required,min=1"`
+}
+
+type DiffPostRevisionsParams struct {
+	ID   string `param:"id" validate:"required"`
+	From int32  `param:"from" validate:"required,min=1"`
+	To   int32  `param:"to" validate:"required,min=1"`
+}
+
+type TagOut struct {
+	Name  string `json:"name"`
+	Posts int64  `json:"posts"`
+}
+
+type TagsOut struct {
+	Data []*TagOut `json:"data"`
+}
+
+// PostRedirectOut is returned when a post is requested by one of its previous slugs.
+type PostRedirectOut struct {
+	Slug     string `json:"slug"`
+	Location string `json:"location"`
 }
@@ -2,29 +2,91 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/yavurb/goyurback/internal/posts/domain"
 )
 
 type MockPostsUsecase struct {
-	GetFn      func(ctx context.Context, id string) (*domain.Post, error)
-	GetPostsFn func(ctx context.Context) ([]*domain.Post, error)
-	CreateFn   func(ctx context.Context, title, author, slug, description, content string) (*domain.Post, error)
-	UpdateFn   func(ctx context.Context, id string, title, author, slug, description, content *string, status *domain.Status) (*domain.Post, error)
+	GetFn       func(ctx context.Context, id string) (*domain.Post, error)
+	GetBySlugFn func(ctx context.Context, slug string) (*domain.Post, error)
+	GetPostsFn  func(ctx context.Context, filter *domain.PostFilter) (*domain.PostPage, error)
+	CreateFn    func(ctx context.Context, title, author, slug, description, content string, tags []string) (*domain.Post, error)
+	UpdateFn    func(ctx context.Context, id string, title, author, slug, description, content *string, status *domain.Status, tags []string) (*domain.Post, error)
+	PublishFn   func(ctx context.Context, id string) (*domain.Post, error)
+	UnpublishFn func(ctx context.Context, id string) (*domain.Post, error)
+	ArchiveFn   func(ctx context.Context, id string) (*domain.Post, error)
+	ScheduleFn  func(ctx context.Context, id string, publishAt time.Time) (*domain.Post, error)
+
+	PublishScheduledFn func(ctx context.Context) ([]*domain.Post, error)
+	GetRevisionsFn     func(ctx context.Context, id string) ([]*domain.PostRevision, error)
+	GetRevisionFn      func(ctx context.Context, id string, revision int32) (*domain.PostRevision, error)
+	DiffRevisionsFn    func(ctx context.Context, id string, from, to int32) (string, error)
+	RestoreRevisionFn  func(ctx context.Context, id string, revision int32) (*domain.Post, error)
+	GetTagsFn          func(ctx context.Context) ([]*domain.Tag, error)
+	RenderFn           func(ctx context.Context, post *domain.Post) (string, error)
 }
 
 func (m *MockPostsUsecase) Get(ctx context.Context, id string) (*domain.Post, error) {
 	return m.GetFn(ctx, id)
 }
 
-func (m *MockPostsUsecase) GetPosts(ctx context.Context) ([]*domain.Post, error) {
-	return m.GetPostsFn(ctx)
+func (m *MockPostsUsecase) GetBySlug(ctx context.Context, slug string) (*domain.Post, error) {
+	return m.GetBySlugFn(ctx, slug)
+}
+
+func (m *MockPostsUsecase) GetPosts(ctx context.Context, filter *domain.PostFilter) (*domain.PostPage, error) {
+	return m.GetPostsFn(ctx, filter)
+}
+
+func (m *MockPostsUsecase) Create(ctx context.Context, title, author, slug, description, content string, tags []string) (*domain.Post, error) {
+	return m.CreateFn(ctx, title, author, slug, description, content, tags)
+}
+
+func (m *MockPostsUsecase) Update(ctx context.Context, id string, title, author, slug, description, content *string, status *domain.Status, tags []string) (*domain.Post, error) {
+	return m.UpdateFn(ctx, id, title, author, slug, description, content, status, tags)
+}
+
+func (m *MockPostsUsecase) Publish(ctx context.Context, id string) (*domain.Post, error) {
+	return m.PublishFn(ctx, id)
+}
+
+func (m *MockPostsUsecase) Unpublish(ctx context.Context, id string) (*domain.Post, error) {
+	return m.UnpublishFn(ctx, id)
+}
+
+func (m *MockPostsUsecase) Archive(ctx context.Context, id string) (*domain.Post, error) {
+	return m.ArchiveFn(ctx, id)
+}
+
+func (m *MockPostsUsecase) Schedule(ctx context.Context, id string, publishAt time.Time) (*domain.Post, error) {
+	return m.ScheduleFn(ctx, id, publishAt)
+}
+
+func (m *MockPostsUsecase) PublishScheduled(ctx context.Context) ([]*domain.Post, error) {
+	return m.PublishScheduledFn(ctx)
+}
+
+func (m *MockPostsUsecase) GetRevisions(ctx context.Context, id string) ([]*domain.PostRevision, error) {
+	return m.GetRevisionsFn(ctx, id)
+}
+
+func (m *MockPostsUsecase) GetRevision(ctx context.Context, id string, revision int32) (*domain.PostRevision, error) {
+	return m.GetRevisionFn(ctx, id, revision)
+}
+
+func (m *MockPostsUsecase) DiffRevisions(ctx context.Context, id string, from, to int32) (string, error) {
+	return m.DiffRevisionsFn(ctx, id, from, to)
+}
+
+func (m *MockPostsUsecase) RestoreRevision(ctx context.Context, id string, revision int32) (*domain.Post, error) {
+	return m.RestoreRevisionFn(ctx, id, revision)
 }
 
-func (m *MockPostsUsecase) Create(ctx context.Context, title, author, slug, description, content string) (*domain.Post, error) {
-	return m.CreateFn(ctx, title, author, slug, description, content)
+func (m *MockPostsUsecase) GetTags(ctx context.Context) ([]*domain.Tag, error) {
+	return m.GetTagsFn(ctx)
 }
 
-func (m *MockPostsUsecase) Update(ctx context.Context, id string, title, author, slug, description, content *string, status *domain.Status) (*domain.Post, error) {
-	return m.UpdateFn(ctx, id, title, author, slug, description, content, status)
+func (m *MockPostsUsecase) Render(ctx context.Context, post *domain.Post) (string, error) {
+	return m.RenderFn(ctx, post)
 }
@@ -1,9 +1,14 @@
 package ui
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"net/url"
 
 	"github.com/labstack/echo/v4"
+	authDomain "github.com/yavurb/goyurback/internal/auth/domain"
+	authUI "github.com/yavurb/goyurback/internal/auth/infrastructure/ui"
 	"github.com/yavurb/goyurback/internal/posts/domain"
 )
 
@@ -19,10 +24,24 @@ func NewPostsRouter(e *echo.Echo, postUsecase domain.PostUsecase) *postRouterCtx
 		postUsecase,
 	}
 
-	routerGroup.POST("", routerCtx.createPost)
+	canWrite := authUI.RequireScope(authDomain.ScopePostsWrite)
+	canRead := authUI.RequireScope(authDomain.ScopePostsRead, authDomain.ScopePostsWrite)
+
+	routerGroup.POST("", routerCtx.createPost, canWrite)
 	routerGroup.GET("/:id", routerCtx.getPost)
+	routerGroup.GET("/slug/:slug", routerCtx.getPostBySlug)
 	routerGroup.GET("", routerCtx.getPosts)
-	routerGroup.PATCH("/:id", routerCtx.updatePost)
+	routerGroup.PATCH("/:id", routerCtx.updatePost, canWrite)
+	routerGroup.POST("/:id/publish", routerCtx.publishPost, canWrite)
+	routerGroup.POST("/:id/unpublish", routerCtx.unpublishPost, canWrite)
+	routerGroup.POST("/:id/archive", routerCtx.archivePost, canWrite)
+	routerGroup.POST("/:id/schedule", routerCtx.schedulePost, canWrite)
+	routerGroup.GET("/:id/revisions", routerCtx.getPostRevisions, canRead)
+	routerGroup.GET("/:id/revisions/:rev", routerCtx.getPostRevision, canRead)
+	routerGroup.GET("/:id/revisions/:from/diff/:to", routerCtx.diffPostRevisions, canRead)
+	routerGroup.POST("/:id/revisions/:rev/restore", routerCtx.restorePostRevision, canWrite)
+
+	e.GET("/tags", routerCtx.getTags)
 
 	return routerCtx
 }
@@ -42,23 +61,12 @@ func (ctx *postRouterCtx) createPost(c echo.Context) error {
 		}.ErrUnprocessableEntity()
 	}
 
-	post_, err := ctx.postUsecase.Create(c.Request().Context(), post.Title, post.Author, post.Slug, post.Description, post.Content)
+	post_, err := ctx.postUsecase.Create(c.Request().Context(), post.Title, post.Author, post.Slug, post.Description, post.Content, post.Tags)
 	if err != nil {
 		return handleErr(err)
 	}
 
-	postOut := &PostOut{
-		ID:          post_.PublicID,
-		Title:       post_.Title,
-		Author:      post_.Author,
-		Slug:        post_.Slug,
-		Status:      post_.Status,
-		Description: post_.Description,
-		Content:     post_.Content,
-		PublishedAt: post_.PublishedAt,
-		CreatedAt:   post_.CreatedAt,
-		UpdatedAt:   post_.UpdatedAt,
-	}
+	postOut := toPostOut(post_)
 
 	return c.JSON(http.StatusCreated, postOut)
 }
@@ -83,47 +91,122 @@ func (ctx *postRouterCtx) getPost(c echo.Context) error {
 		return handleErr(err)
 	}
 
-	postOut := &PostOut{
-		ID:          post.PublicID,
-		Title:       post.Title,
-		Author:      post.Author,
-		Slug:        post.Slug,
-		Status:      post.Status,
-		Description: post.Description,
-		Content:     post.Content,
-		PublishedAt: post.PublishedAt,
-		CreatedAt:   post.CreatedAt,
-		UpdatedAt:   post.UpdatedAt,
+	if !canSee(c, post) {
+		return handleErr(domain.ErrPostNotFound)
+	}
+
+	postOut, err := ctx.toPostOutWithFormat(c, post, params.Format)
+	if err != nil {
+		return handleErr(err)
+	}
+
+	return c.JSON(http.StatusOK, postOut)
+}
+
+func (ctx *postRouterCtx) getPostBySlug(c echo.Context) error {
+	var params GetPostBySlugParams
+
+	if err := c.Bind(&params); err != nil {
+		return HTTPError{
+			Message: "Invalid params",
+		}.BadRequest()
+	}
+
+	if err := c.Validate(params); err != nil {
+		return HTTPError{
+			Message: "Invalid params",
+		}.ErrUnprocessableEntity()
+	}
+
+	post, err := ctx.postUsecase.GetBySlug(c.Request().Context(), params.Slug)
+	if err != nil {
+		return handleErr(err)
+	}
+
+	if !canSee(c, post) {
+		return handleErr(domain.ErrPostNotFound)
+	}
+
+	if post.Slug != params.Slug {
+		location := fmt.Sprintf("/posts/slug/%s", url.PathEscape(post.Slug))
+
+		c.Response().Header().Set(echo.HeaderLocation, location)
+
+		return c.JSON(http.StatusMovedPermanently, &PostRedirectOut{
+			Slug:     post.Slug,
+			Location: location,
+		})
+	}
+
+	postOut, err := ctx.toPostOutWithFormat(c, post, params.Format)
+	if err != nil {
+		return handleErr(err)
 	}
 
 	return c.JSON(http.StatusOK, postOut)
 }
 
 func (ctx *postRouterCtx) getPosts(c echo.Context) error {
-	posts, err := ctx.postUsecase.GetPosts(c.Request().Context())
+	var params GetPostsParams
+
+	if err := c.Bind(&params); err != nil {
+		return HTTPError{
+			Message: "Invalid query params",
+		}.BadRequest()
+	}
+
+	if err := c.Validate(params); err != nil {
+		return HTTPError{
+			Message: "Invalid query params",
+		}.ErrUnprocessableEntity()
+	}
+
+	page, err := ctx.postUsecase.GetPosts(c.Request().Context(), &domain.PostFilter{
+		PublishedAfter:  params.PublishedAfter,
+		PublishedBefore: params.PublishedBefore,
+		Author:          params.Author,
+		Tag:             params.Tag,
+		Cursor:          params.Cursor,
+		Limit:           params.Limit,
+	})
 	if err != nil {
 		return handleErr(err)
 	}
 
 	postsOut := []*PostOut{}
 
-	for _, post := range posts {
-		postsOut = append(postsOut, &PostOut{
-			ID:          post.PublicID,
-			Title:       post.Title,
-			Author:      post.Author,
-			Slug:        post.Slug,
-			Status:      post.Status,
-			Description: post.Description,
-			Content:     post.Content,
-			PublishedAt: post.PublishedAt,
-			CreatedAt:   post.CreatedAt,
-			UpdatedAt:   post.UpdatedAt,
-		})
+	for _, post := range page.Posts {
+		postOut, err := ctx.toPostOutWithFormat(c, post, params.Format)
+		if err != nil {
+			return handleErr(err)
+		}
+
+		postsOut = append(postsOut, postOut)
 	}
 
 	return c.JSON(http.StatusOK, &PostsOut{
-		Data: postsOut,
+		NextCursor: page.NextCursor,
+		Data:       postsOut,
+	})
+}
+
+func (ctx *postRouterCtx) getTags(c echo.Context) error {
+	tags, err := ctx.postUsecase.GetTags(c.Request().Context())
+	if err != nil {
+		return handleErr(err)
+	}
+
+	tagsOut := []*TagOut{}
+
+	for _, tag := range tags {
+		tagsOut = append(tagsOut, &TagOut{
+			Name:  tag.Name,
+			Posts: tag.Posts,
+		})
+	}
+
+	return c.JSON(http.StatusOK, &TagsOut{
+		Data: tagsOut,
 	})
 }
 
@@ -136,33 +219,250 @@ func (ctx *postRouterCtx) updatePost(c echo.Context) error {
 		}.ErrUnprocessableEntity()
 	}
 
-	post_, err := ctx.postUsecase.Update(c.Request().Context(), post.ID, post.Title, post.Author, post.Slug, post.Description, post.Content, post.Status)
+	if post.Status != nil && *post.Status == domain.Scheduled {
+		return HTTPError{
+			Message: "Posts are scheduled with POST /posts/:id/schedule and a publish_at",
+		}.ErrUnprocessableEntity()
+	}
+
+	if err := c.Validate(post); err != nil {
+		return HTTPError{
+			Message: "Invalid params",
+		}.ErrUnprocessableEntity()
+	}
+
+	post_, err := ctx.postUsecase.Update(c.Request().Context(), post.ID, post.Title, post.Author, post.Slug, post.Description, post.Content, post.Status, post.Tags)
 	if err != nil {
 		return handleErr(err)
 	}
 
-	postOut := &PostOut{
-		ID:          post_.PublicID,
-		Title:       post_.Title,
-		Author:      post_.Author,
-		Slug:        post_.Slug,
-		Status:      post_.Status,
-		Description: post_.Description,
-		Content:     post_.Content,
-		PublishedAt: post_.PublishedAt,
-		CreatedAt:   post_.CreatedAt,
-		UpdatedAt:   post_.UpdatedAt,
-	}
+	postOut := toPostOut(post_)
 
 	return c.JSON(http.StatusOK, postOut)
 }
 
+func (ctx *postRouterCtx) publishPost(c echo.Context) error {
+	return ctx.transitionPost(c, ctx.postUsecase.Publish)
+}
+
+func (ctx *postRouterCtx) unpublishPost(c echo.Context) error {
+	return ctx.transitionPost(c, ctx.postUsecase.Unpublish)
+}
+
+func (ctx *postRouterCtx) archivePost(c echo.Context) error {
+	return ctx.transitionPost(c, ctx.postUsecase.Archive)
+}
+
+func (ctx *postRouterCtx) schedulePost(c echo.Context) error {
+	var params PostSchedule
+
+	if err := c.Bind(&params); err != nil {
+		return HTTPError{
+			Message: "Invalid request body",
+		}.ErrUnprocessableEntity()
+	}
+
+	if err := c.Validate(params); err != nil {
+		return HTTPError{
+			Message: "Invalid params",
+		}.ErrUnprocessableEntity()
+	}
+
+	post, err := ctx.postUsecase.Schedule(c.Request().Context(), params.ID, params.PublishAt)
+	if err != nil {
+		return handleErr(err)
+	}
+
+	return c.JSON(http.StatusOK, toPostOut(post))
+}
+
+func (ctx *postRouterCtx) transitionPost(c echo.Context, action func(ctx context.Context, id string) (*domain.Post, error)) error {
+	var params GetPostParams
+
+	if err := c.Bind(&params); err != nil {
+		return HTTPError{
+			Message: "Invalid params",
+		}.BadRequest()
+	}
+
+	if err := c.Validate(params); err != nil {
+		return HTTPError{
+			Message: "Invalid params",
+		}.ErrUnprocessableEntity()
+	}
+
+	post, err := action(c.Request().Context(), params.ID)
+	if err != nil {
+		return handleErr(err)
+	}
+
+	return c.JSON(http.StatusOK, toPostOut(post))
+}
+
+func (ctx *postRouterCtx) getPostRevisions(c echo.Context) error {
+	var params GetPostParams
+
+	if err := c.Bind(&params); err != nil {
+		return HTTPError{
+			Message: "Invalid params",
+		}.BadRequest()
+	}
+
+	if err := c.Validate(params); err != nil {
+		return HTTPError{
+			Message: "Invalid params",
+		}.ErrUnprocessableEntity()
+	}
+
+	revisions, err := ctx.postUsecase.GetRevisions(c.Request().Context(), params.ID)
+	if err != nil {
+		return handleErr(err)
+	}
+
+	revisionsOut := []*PostRevisionOut{}
+
+	for _, revision := range revisions {
+		revisionsOut = append(revisionsOut, toPostRevisionOut(revision))
+	}
+
+	return c.JSON(http.StatusOK, &PostRevisionsOut{
+		Data: revisionsOut,
+	})
+}
+
+func (ctx *postRouterCtx) getPostRevision(c echo.Context) error {
+	var params GetPostRevisionParams
+
+	if err := c.Bind(&params); err != nil {
+		return HTTPError{
+			Message: "Invalid params",
+		}.BadRequest()
+	}
+
+	if err := c.Validate(params); err != nil {
+		return HTTPError{
+			Message: "Invalid params",
+		}.ErrUnprocessableEntity()
+	}
+
+	revision, err := ctx.postUsecase.GetRevision(c.Request().Context(), params.ID, params.Revision)
+	if err != nil {
+		return handleErr(err)
+	}
+
+	return c.JSON(http.StatusOK, toPostRevisionOut(revision))
+}
+
+func (ctx *postRouterCtx) diffPostRevisions(c echo.Context) error {
+	var params DiffPostRevisionsParams
+
+	if err := c.Bind(&params); err != nil {
+		return HTTPError{
+			Message: "Invalid params",
+		}.BadRequest()
+	}
+
+	if err := c.Validate(params); err != nil {
+		return HTTPError{
+			Message: "Invalid params",
+		}.ErrUnprocessableEntity()
+	}
+
+	diff, err := ctx.postUsecase.DiffRevisions(c.Request().Context(), params.ID, params.From, params.To)
+	if err != nil {
+		return handleErr(err)
+	}
+
+	return c.JSON(http.StatusOK, &PostRevisionsDiffOut{
+		From: params.From,
+		To:   params.To,
+		Diff: diff,
+	})
+}
+
+func (ctx *postRouterCtx) restorePostRevision(c echo.Context) error {
+	var params GetPostRevisionParams
+
+	if err := c.Bind(&params); err != nil {
+		return HTTPError{
+			Message: "Invalid params",
+		}.BadRequest()
+	}
+
+	if err := c.Validate(params); err != nil {
+		return HTTPError{
+			Message: "Invalid params",
+		}.ErrUnprocessableEntity()
+	}
+
+	post, err := ctx.postUsecase.RestoreRevision(c.Request().Context(), params.ID, params.Revision)
+	if err != nil {
+		return handleErr(err)
+	}
+
+	return c.JSON(http.StatusOK, toPostOut(post))
+}
+
+// canSee reports whether the request can read the post. Published posts are public, the others
+// need a key with the posts:read or posts:write scope and are not found otherwise.
+func canSee(c echo.Context, post *domain.Post) bool {
+	if post.Status == domain.Published {
+		return true
+	}
+
+	apiKey, ok := authUI.APIKeyFromContext(c)
+
+	return ok && apiKey.HasAnyScope(authDomain.ScopePostsRead, authDomain.ScopePostsWrite)
+}
+
+// toPostOutWithFormat adds the rendered content to the post when the html format is requested.
+func (ctx *postRouterCtx) toPostOutWithFormat(c echo.Context, post *domain.Post, format string) (*PostOut, error) {
+	postOut := toPostOut(post)
+
+	if format != formatHTML {
+		return postOut, nil
+	}
+
+	html, err := ctx.postUsecase.Render(c.Request().Context(), post)
+	if err != nil {
+		return nil, err
+	}
+
+	postOut.ContentHTML = html
+
+	return postOut, nil
+}
+
 func handleErr(err error) error {
 	switch err {
 	case domain.ErrPostNotFound:
 		return HTTPError{
 			Message: "Post not found",
 		}.NotFound()
+	case domain.ErrRevisionNotFound:
+		return HTTPError{
+			Message: "Revision not found",
+		}.NotFound()
+	case domain.ErrSlugAlreadyExists:
+		return HTTPError{
+			Message: "Slug already in use",
+		}.Conflict()
+	case domain.ErrInvalidStatusTransition:
+		return HTTPError{
+			Message: "Invalid status transition",
+		}.Conflict()
+	case domain.ErrInvalidPublishDate:
+		return HTTPError{
+			Message: "Publish date must be in the future",
+		}.ErrUnprocessableEntity()
+	case domain.ErrDiffTooLarge:
+		return HTTPError{
+			Message: "Revisions are too large or too different to diff",
+		}.ErrUnprocessableEntity()
+	case domain.ErrInvalidCursor:
+		return HTTPError{
+			Message: "Invalid cursor",
+		}.BadRequest()
 	default:
 		return HTTPError{
 			Message: "Internal server error",
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -13,6 +14,8 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/labstack/echo/v4"
 	"github.com/yavurb/goyurback/internal/app/mods"
+	authDomain "github.com/yavurb/goyurback/internal/auth/domain"
+	authUI "github.com/yavurb/goyurback/internal/auth/infrastructure/ui"
 	"github.com/yavurb/goyurback/internal/posts/domain"
 	"github.com/yavurb/goyurback/internal/posts/infrastructure/ui/mocks"
 	"github.com/yavurb/goyurback/testhelpers"
@@ -29,6 +32,7 @@ func TestCreatePost(t *testing.T) {
 			"slug":        "my-test-post",
 			"description": "Some post description",
 			"content":     "Some post content",
+			"tags":        []string{"go", "testing"},
 		}
 		want := map[string]any{
 			"id":           "po_12345",
@@ -38,9 +42,10 @@ func TestCreatePost(t *testing.T) {
 			"status":       "draft",
 			"description":  "Some post description",
 			"content":      "Some post content",
+			"tags":         []string{"go", "testing"},
 			"created_at":   time.Now().UTC().Format(time.RFC3339),
 			"updated_at":   time.Now().UTC().Format(time.RFC3339),
-			"published_at": new(time.Time).Format(time.RFC3339),
+			"published_at": nil,
 		}
 
 		jsonBytes, err := json.Marshal(postIn)
@@ -55,7 +60,7 @@ func TestCreatePost(t *testing.T) {
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 
 		uc := &mocks.MockPostsUsecase{}
-		uc.CreateFn = func(ctx context.Context, title, author, slug, description, content string) (*domain.Post, error) {
+		uc.CreateFn = func(ctx context.Context, title, author, slug, description, content string, tags []string) (*domain.Post, error) {
 			createdAt, _ := time.Parse(time.RFC3339, want["created_at"].(string))
 			updatedAt, _ := time.Parse(time.RFC3339, want["updated_at"].(string))
 
@@ -68,6 +73,7 @@ func TestCreatePost(t *testing.T) {
 				Status:      domain.Draft,
 				Description: description,
 				Content:     content,
+				Tags:        tags,
 				CreatedAt:   createdAt,
 				UpdatedAt:   updatedAt,
 			}, nil
@@ -117,7 +123,7 @@ func TestCreatePost(t *testing.T) {
 		rec := httptest.NewRecorder()
 		c := e.NewContext(req, rec)
 		uc := &mocks.MockPostsUsecase{}
-		uc.CreateFn = func(ctx context.Context, title, author, slug, description, content string) (*domain.Post, error) {
+		uc.CreateFn = func(ctx context.Context, title, author, slug, description, content string, tags []string) (*domain.Post, error) {
 			return nil, errors.New("Unknown usecase error")
 		}
 
@@ -167,6 +173,35 @@ func TestCreatePost(t *testing.T) {
 			t.Errorf("Expected error to be a 422 (ErrUnprocessableEntity). Got: %v", err)
 		}
 	})
+
+	t.Run("it should return a validation error if a tag is too long", func(t *testing.T) {
+		post := map[string]any{
+			"title":       "My test post",
+			"author":      "Roy",
+			"slug":        "my-test-post",
+			"description": "Some post description",
+			"content":     "Some post content",
+			"tags":        []string{"go", strings.Repeat("a", 33)},
+		}
+
+		jsonBytes, err := json.Marshal(post)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/posts", strings.NewReader(string(jsonBytes)))
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		h := NewPostsRouter(e, &mocks.MockPostsUsecase{})
+
+		err = h.createPost(c)
+		if !errors.Is(err, echo.ErrUnprocessableEntity) {
+			t.Errorf("Expected error to be a 422 (ErrUnprocessableEntity). Got: %v", err)
+		}
+	})
 }
 
 func TestGetPost(t *testing.T) {
@@ -181,6 +216,7 @@ func TestGetPost(t *testing.T) {
 		c.SetPath("/:id")
 		c.SetParamNames("id")
 		c.SetParamValues("po_12345")
+		c.Set(authUI.APIKeyContextKey, &authDomain.APIKey{Scopes: []authDomain.Scope{authDomain.ScopePostsRead}})
 
 		uc := &mocks.MockPostsUsecase{}
 
@@ -194,7 +230,8 @@ func TestGetPost(t *testing.T) {
 			"content":      "Some post content",
 			"created_at":   time.Now().UTC().Format(time.RFC3339),
 			"updated_at":   time.Now().UTC().Format(time.RFC3339),
-			"published_at": new(time.Time).Format(time.RFC3339),
+			"tags":         []string{},
+			"published_at": nil,
 		}
 
 		uc.GetFn = func(ctx context.Context, id string) (*domain.Post, error) {
@@ -240,6 +277,46 @@ func TestGetPost(t *testing.T) {
 		}
 	})
 
+	t.Run("it should only show published posts to anonymous requests", func(t *testing.T) {
+		statuses := map[domain.Status]int{
+			domain.Published: http.StatusOK,
+			domain.Draft:     http.StatusNotFound,
+			domain.Scheduled: http.StatusNotFound,
+			domain.Archived:  http.StatusNotFound,
+		}
+
+		for status, wantCode := range statuses {
+			req := httptest.NewRequest(http.MethodGet, "/posts/:id", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			c.SetPath("/posts/:id")
+			c.SetParamNames("id")
+			c.SetParamValues("po_12345")
+
+			uc := &mocks.MockPostsUsecase{}
+			uc.GetFn = func(ctx context.Context, id string) (*domain.Post, error) {
+				return &domain.Post{ID: 1, PublicID: id, Status: status}, nil
+			}
+
+			h := NewPostsRouter(e, uc)
+
+			code := http.StatusOK
+			if err := h.getPost(c); err != nil {
+				var httpErr *echo.HTTPError
+				if !errors.As(err, &httpErr) {
+					t.Fatalf("Expected an HTTP error. Got: %v", err)
+				}
+
+				code = httpErr.Code
+			}
+
+			if code != wantCode {
+				t.Errorf("Expected a %d getting a %s post. Got: %d", wantCode, status, code)
+			}
+		}
+	})
+
 	t.Run("it should return a bad unprocessable entity", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/posts/:id", nil)
 		rec := httptest.NewRecorder()
@@ -309,6 +386,7 @@ func TestGetPosts(t *testing.T) {
 					"content":      "Some post content",
 					"created_at":   time.Now().UTC().Format(time.RFC3339),
 					"updated_at":   time.Now().UTC().Format(time.RFC3339),
+					"tags":         []string{},
 					"published_at": time.Now().UTC().Format(time.RFC3339),
 				},
 				{
@@ -321,12 +399,13 @@ func TestGetPosts(t *testing.T) {
 					"content":      "Some post content 2",
 					"created_at":   time.Now().UTC().Format(time.RFC3339),
 					"updated_at":   time.Now().UTC().Format(time.RFC3339),
+					"tags":         []string{},
 					"published_at": time.Now().UTC().Format(time.RFC3339),
 				},
 			},
 		}
 
-		uc.GetPostsFn = func(ctx context.Context) ([]*domain.Post, error) {
+		uc.GetPostsFn = func(ctx context.Context, filter *domain.PostFilter) (*domain.PostPage, error) {
 			createdAt, _ := time.Parse(time.RFC3339, want["data"][0]["created_at"].(string))
 			updatedAt, _ := time.Parse(time.RFC3339, want["data"][0]["updated_at"].(string))
 			publishedAt, _ := time.Parse(time.RFC3339, want["data"][0]["published_at"].(string))
@@ -334,7 +413,7 @@ func TestGetPosts(t *testing.T) {
 			updatedAt2, _ := time.Parse(time.RFC3339, want["data"][1]["updated_at"].(string))
 			publishedAt2, _ := time.Parse(time.RFC3339, want["data"][1]["published_at"].(string))
 
-			return []*domain.Post{
+			return &domain.PostPage{Posts: []*domain.Post{
 				{
 					ID:          1,
 					PublicID:    "po_12345",
@@ -361,7 +440,7 @@ func TestGetPosts(t *testing.T) {
 					UpdatedAt:   updatedAt2,
 					PublishedAt: publishedAt2,
 				},
-			}, nil
+			}}, nil
 		}
 
 		h := NewPostsRouter(e, uc)
@@ -393,7 +472,7 @@ func TestGetPosts(t *testing.T) {
 		uc := &mocks.MockPostsUsecase{}
 		h := NewPostsRouter(e, uc)
 
-		uc.GetPostsFn = func(ctx context.Context) ([]*domain.Post, error) {
+		uc.GetPostsFn = func(ctx context.Context, filter *domain.PostFilter) (*domain.PostPage, error) {
 			return nil, errors.New("DB Error")
 		}
 
@@ -406,6 +485,85 @@ func TestGetPosts(t *testing.T) {
 			t.Errorf("Expected error to be a 500 Internal Server Error. Got: %v", err)
 		}
 	})
+
+	t.Run("it should pass the pagination params and return the next cursor", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/posts?limit=1&cursor=some-cursor&author=Roy&tag=go&published_after=2024-07-01T00:00:00Z", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		var gotFilter *domain.PostFilter
+
+		uc := &mocks.MockPostsUsecase{}
+		uc.GetPostsFn = func(ctx context.Context, filter *domain.PostFilter) (*domain.PostPage, error) {
+			gotFilter = filter
+
+			return &domain.PostPage{
+				Posts:      []*domain.Post{{ID: 1, PublicID: "po_12345", Status: domain.Published}},
+				NextCursor: "next-cursor",
+			}, nil
+		}
+
+		h := NewPostsRouter(e, uc)
+
+		err := h.getPosts(c)
+		if err != nil {
+			t.Fatalf("Expected no errors getting posts. Got: %v", err)
+		}
+
+		wantFilter := &domain.PostFilter{
+			PublishedAfter: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC),
+			Author:         "Roy",
+			Tag:            "go",
+			Cursor:         "some-cursor",
+			Limit:          1,
+		}
+		if !cmp.Equal(wantFilter, gotFilter) {
+			t.Errorf("Mismatch getting posts filter (-want,+got):\n%s", cmp.Diff(wantFilter, gotFilter))
+		}
+
+		got := PostsOut{}
+		if err = json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Errorf("Error unmarshalling response: %s", err)
+		}
+
+		if got.NextCursor != "next-cursor" {
+			t.Errorf("Expected next_cursor to be next-cursor. Got: %s", got.NextCursor)
+		}
+	})
+
+	t.Run("it should return a bad request error if the cursor is invalid", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/posts?cursor=invalid", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		uc := &mocks.MockPostsUsecase{}
+		uc.GetPostsFn = func(ctx context.Context, filter *domain.PostFilter) (*domain.PostPage, error) {
+			return nil, domain.ErrInvalidCursor
+		}
+
+		h := NewPostsRouter(e, uc)
+
+		err := h.getPosts(c)
+
+		var httpErr *echo.HTTPError
+		if !errors.As(err, &httpErr) || httpErr.Code != http.StatusBadRequest {
+			t.Errorf("Expected error to be a 400 Bad Request. Got: %v", err)
+		}
+	})
+
+	t.Run("it should return an unprocessable entity error if the limit is out of range", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/posts?limit=1000", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		uc := &mocks.MockPostsUsecase{}
+		h := NewPostsRouter(e, uc)
+
+		err := h.getPosts(c)
+		if !errors.Is(err, echo.ErrUnprocessableEntity) {
+			t.Errorf("Expected error to be a 422 (ErrUnprocessableEntity). Got: %v", err)
+		}
+	})
 }
 
 func TestUpdatePost(t *testing.T) {
@@ -426,7 +584,8 @@ func TestUpdatePost(t *testing.T) {
 			"content":      "Some post content",
 			"created_at":   time.Now().UTC().Format(time.RFC3339),
 			"updated_at":   time.Now().UTC().Format(time.RFC3339),
-			"published_at": new(time.Time).Format(time.RFC3339),
+			"tags":         []string{},
+			"published_at": time.Now().UTC().Format(time.RFC3339),
 		}
 
 		jsonBytes, err := json.Marshal(postIn)
@@ -444,7 +603,7 @@ func TestUpdatePost(t *testing.T) {
 		c.SetParamValues("po_12345")
 
 		uc := &mocks.MockPostsUsecase{}
-		uc.UpdateFn = func(ctx context.Context, id string, title, author, slug, description, content *string, status *domain.Status) (*domain.Post, error) {
+		uc.UpdateFn = func(ctx context.Context, id string, title, author, slug, description, content *string, status *domain.Status, tags []string) (*domain.Post, error) {
 			if id != "po_12345" {
 				return nil, domain.ErrPostNotFound
 			}
@@ -466,7 +625,10 @@ func TestUpdatePost(t *testing.T) {
 			}
 
 			if status != nil {
+				publishedAt, _ := time.Parse(time.RFC3339, want["published_at"].(string))
+
 				post.Status = *status
+				post.PublishedAt = publishedAt
 			}
 
 			return post, nil
@@ -513,7 +675,7 @@ func TestUpdatePost(t *testing.T) {
 		c.SetParamValues("po_12347")
 
 		uc := &mocks.MockPostsUsecase{}
-		uc.UpdateFn = func(ctx context.Context, id string, title, author, slug, description, content *string, status *domain.Status) (*domain.Post, error) {
+		uc.UpdateFn = func(ctx context.Context, id string, title, author, slug, description, content *string, status *domain.Status, tags []string) (*domain.Post, error) {
 			return nil, domain.ErrPostNotFound
 		}
 
@@ -549,7 +711,7 @@ func TestUpdatePost(t *testing.T) {
 		c.SetParamValues("po_12347")
 
 		uc := &mocks.MockPostsUsecase{}
-		uc.UpdateFn = func(ctx context.Context, id string, title, author, slug, description, content *string, status *domain.Status) (*domain.Post, error) {
+		uc.UpdateFn = func(ctx context.Context, id string, title, author, slug, description, content *string, status *domain.Status, tags []string) (*domain.Post, error) {
 			return nil, errors.New("DB Error")
 		}
 
@@ -564,4 +726,750 @@ func TestUpdatePost(t *testing.T) {
 			t.Errorf("Expected error to be a 500 ErrInternalServerError. Got: %v", err)
 		}
 	})
+
+	t.Run("It should point to the schedule route when scheduling", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPatch, "/posts/:id", strings.NewReader(`{"status":"scheduled"}`))
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		c.SetPath("/posts/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("po_12345")
+
+		uc := &mocks.MockPostsUsecase{}
+		uc.UpdateFn = func(ctx context.Context, id string, title, author, slug, description, content *string, status *domain.Status, tags []string) (*domain.Post, error) {
+			t.Error("Expected the post not to be updated")
+
+			return nil, nil
+		}
+
+		h := NewPostsRouter(e, uc)
+
+		var httpErr *echo.HTTPError
+		if err := h.updatePost(c); !errors.As(err, &httpErr) || httpErr.Code != http.StatusUnprocessableEntity {
+			t.Fatalf("Expected a 422 error. Got: %v", err)
+		}
+
+		if !strings.Contains(fmt.Sprint(httpErr.Message), "/posts/:id/schedule") {
+			t.Errorf("Expected the error to point to the schedule route. Got: %v", httpErr.Message)
+		}
+	})
+}
+
+func TestGetPostBySlug(t *testing.T) {
+	e := echo.New()
+	e.Validator = mods.NewAppValidator()
+
+	post := &domain.Post{
+		ID:          1,
+		PublicID:    "po_12345",
+		Title:       "My test post",
+		Author:      "Roy",
+		Slug:        "my-test-post",
+		Status:      domain.Published,
+		Description: "Some post description",
+		Content:     "Some post content",
+	}
+
+	t.Run("it should get a post by its slug", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/posts/slug/:slug", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/posts/slug/:slug")
+		c.SetParamNames("slug")
+		c.SetParamValues("my-test-post")
+
+		uc := &mocks.MockPostsUsecase{}
+		uc.GetBySlugFn = func(ctx context.Context, slug string) (*domain.Post, error) {
+			return post, nil
+		}
+
+		h := NewPostsRouter(e, uc)
+
+		err := h.getPostBySlug(c)
+		if err != nil {
+			t.Errorf("Expected no errors getting post. Got: %v", err)
+		}
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected status code to be a 200 (StatusOK). Got: %d", rec.Code)
+		}
+
+		got := PostOut{}
+		if err = json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Errorf("Error unmarshalling response: %s", err)
+		}
+
+		if got.ID != "po_12345" {
+			t.Errorf("Expected post id to be po_12345. Got: %s", got.ID)
+		}
+	})
+
+	t.Run("it should return a redirect hint for a previous slug", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/posts/slug/:slug", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/posts/slug/:slug")
+		c.SetParamNames("slug")
+		c.SetParamValues("my-old-post")
+
+		uc := &mocks.MockPostsUsecase{}
+		uc.GetBySlugFn = func(ctx context.Context, slug string) (*domain.Post, error) {
+			return post, nil
+		}
+
+		h := NewPostsRouter(e, uc)
+
+		err := h.getPostBySlug(c)
+		if err != nil {
+			t.Errorf("Expected no errors getting post. Got: %v", err)
+		}
+
+		if rec.Code != http.StatusMovedPermanently {
+			t.Errorf("Expected status code to be a 301 (StatusMovedPermanently). Got: %d", rec.Code)
+		}
+
+		if location := rec.Header().Get(echo.HeaderLocation); location != "/posts/slug/my-test-post" {
+			t.Errorf("Expected location to be /posts/slug/my-test-post. Got: %s", location)
+		}
+
+		want := map[string]any{
+			"slug":     "my-test-post",
+			"location": "/posts/slug/my-test-post",
+		}
+
+		got := make(map[string]any)
+		if err = json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Errorf("Error unmarshalling response: %s", err)
+		}
+
+		if !testhelpers.CompareMaps(want, got) {
+			t.Errorf("Mismatch:\n%s", cmp.Diff(want, got))
+		}
+	})
+
+	t.Run("it should return a not found error", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/posts/slug/:slug", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/posts/slug/:slug")
+		c.SetParamNames("slug")
+		c.SetParamValues("unknown")
+
+		uc := &mocks.MockPostsUsecase{}
+		uc.GetBySlugFn = func(ctx context.Context, slug string) (*domain.Post, error) {
+			return nil, domain.ErrPostNotFound
+		}
+
+		h := NewPostsRouter(e, uc)
+
+		err := h.getPostBySlug(c)
+		if !errors.Is(err, echo.ErrNotFound) {
+			t.Errorf("Expected request error to be a 404 (ErrNotFound). Got: %v", err)
+		}
+	})
+
+	t.Run("it should not reveal the slug of a draft to anonymous requests", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/posts/slug/:slug", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/posts/slug/:slug")
+		c.SetParamNames("slug")
+		c.SetParamValues("my-old-post")
+
+		draft := *post
+		draft.Status = domain.Draft
+
+		uc := &mocks.MockPostsUsecase{}
+		uc.GetBySlugFn = func(ctx context.Context, slug string) (*domain.Post, error) {
+			return &draft, nil
+		}
+
+		h := NewPostsRouter(e, uc)
+
+		if err := h.getPostBySlug(c); !errors.Is(err, echo.ErrNotFound) {
+			t.Errorf("Expected error to be a 404 (ErrNotFound). Got: %v", err)
+		}
+
+		if location := rec.Header().Get(echo.HeaderLocation); location != "" {
+			t.Errorf("Expected no location. Got: %s", location)
+		}
+	})
+}
+
+func TestPostSlugConflict(t *testing.T) {
+	e := echo.New()
+	e.Validator = mods.NewAppValidator()
+
+	t.Run("it should return a conflict error creating a post", func(t *testing.T) {
+		postIn := map[string]any{
+			"title":       "My test post",
+			"author":      "Roy",
+			"slug":        "my-test-post",
+			"description": "Some post description",
+			"content":     "Some post content",
+		}
+
+		jsonBytes, err := json.Marshal(postIn)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/posts", strings.NewReader(string(jsonBytes)))
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		uc := &mocks.MockPostsUsecase{}
+		uc.CreateFn = func(ctx context.Context, title, author, slug, description, content string, tags []string) (*domain.Post, error) {
+			return nil, domain.ErrSlugAlreadyExists
+		}
+
+		h := NewPostsRouter(e, uc)
+
+		err = h.createPost(c)
+
+		var httpErr *echo.HTTPError
+		if !errors.As(err, &httpErr) || httpErr.Code != http.StatusConflict {
+			t.Errorf("Expected error to be a 409 Conflict. Got: %v", err)
+		}
+	})
+
+	t.Run("it should return a conflict error updating a post", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPatch, "/posts/:id", strings.NewReader(`{"slug":"taken-slug"}`))
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		c.SetPath("/posts/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("po_12345")
+
+		uc := &mocks.MockPostsUsecase{}
+		uc.UpdateFn = func(ctx context.Context, id string, title, author, slug, description, content *string, status *domain.Status, tags []string) (*domain.Post, error) {
+			return nil, domain.ErrSlugAlreadyExists
+		}
+
+		h := NewPostsRouter(e, uc)
+
+		err := h.updatePost(c)
+
+		var httpErr *echo.HTTPError
+		if !errors.As(err, &httpErr) || httpErr.Code != http.StatusConflict {
+			t.Errorf("Expected error to be a 409 Conflict. Got: %v", err)
+		}
+	})
+}
+
+func TestTransitionPost(t *testing.T) {
+	e := echo.New()
+	e.Validator = mods.NewAppValidator()
+
+	newContext := func(action string) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodPost, "/posts/:id/"+action, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/posts/:id/" + action)
+		c.SetParamNames("id")
+		c.SetParamValues("po_12345")
+
+		return c, rec
+	}
+
+	t.Run("it should publish a post", func(t *testing.T) {
+		c, rec := newContext("publish")
+		publishedAt := time.Date(2024, 7, 15, 10, 30, 0, 0, time.UTC)
+
+		uc := &mocks.MockPostsUsecase{}
+		uc.PublishFn = func(ctx context.Context, id string) (*domain.Post, error) {
+			return &domain.Post{
+				ID:          1,
+				PublicID:    id,
+				Status:      domain.Published,
+				PublishedAt: publishedAt,
+			}, nil
+		}
+
+		h := NewPostsRouter(e, uc)
+
+		err := h.publishPost(c)
+		if err != nil {
+			t.Errorf("Expected no error publishing post. Got: %v", err)
+		}
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected http status code to be %d. Got %d", http.StatusOK, rec.Code)
+		}
+
+		got := PostOut{}
+		if err = json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Errorf("Error unmarshalling response: %s", err)
+		}
+
+		if got.Status != domain.Published {
+			t.Errorf("Expected status to be %s. Got: %s", domain.Published, got.Status)
+		}
+
+		if got.PublishedAt == nil || !got.PublishedAt.Equal(publishedAt) {
+			t.Errorf("Expected published_at to be %v. Got: %v", publishedAt, got.PublishedAt)
+		}
+	})
+
+	t.Run("it should return a null publish date for unpublished drafts", func(t *testing.T) {
+		c, rec := newContext("archive")
+
+		uc := &mocks.MockPostsUsecase{}
+		uc.ArchiveFn = func(ctx context.Context, id string) (*domain.Post, error) {
+			return &domain.Post{ID: 1, PublicID: id, Status: domain.Archived}, nil
+		}
+
+		h := NewPostsRouter(e, uc)
+
+		err := h.archivePost(c)
+		if err != nil {
+			t.Errorf("Expected no error archiving post. Got: %v", err)
+		}
+
+		got := make(map[string]any)
+		if err = json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Errorf("Error unmarshalling response: %s", err)
+		}
+
+		if publishedAt, ok := got["published_at"]; !ok || publishedAt != nil {
+			t.Errorf("Expected published_at to be null. Got: %v", publishedAt)
+		}
+	})
+
+	t.Run("it should return a conflict error for an invalid transition", func(t *testing.T) {
+		c, _ := newContext("unpublish")
+
+		uc := &mocks.MockPostsUsecase{}
+		uc.UnpublishFn = func(ctx context.Context, id string) (*domain.Post, error) {
+			return nil, domain.ErrInvalidStatusTransition
+		}
+
+		h := NewPostsRouter(e, uc)
+
+		err := h.unpublishPost(c)
+
+		var httpErr *echo.HTTPError
+		if !errors.As(err, &httpErr) || httpErr.Code != http.StatusConflict {
+			t.Errorf("Expected error to be a 409 Conflict. Got: %v", err)
+		}
+	})
+
+	t.Run("it should return a not found error", func(t *testing.T) {
+		c, _ := newContext("publish")
+
+		uc := &mocks.MockPostsUsecase{}
+		uc.PublishFn = func(ctx context.Context, id string) (*domain.Post, error) {
+			return nil, domain.ErrPostNotFound
+		}
+
+		h := NewPostsRouter(e, uc)
+
+		err := h.publishPost(c)
+		if !errors.Is(err, echo.ErrNotFound) {
+			t.Errorf("Expected error to be a 404 ErrNotFound. Got: %v", err)
+		}
+	})
+}
+
+func TestSchedulePost(t *testing.T) {
+	e := echo.New()
+	e.Validator = mods.NewAppValidator()
+
+	newContext := func(body string) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodPost, "/posts/:id/schedule", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		c.SetPath("/posts/:id/schedule")
+		c.SetParamNames("id")
+		c.SetParamValues("po_12345")
+
+		return c, rec
+	}
+
+	t.Run("it should schedule a post", func(t *testing.T) {
+		publishAt := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+		c, rec := newContext(fmt.Sprintf(`{"publish_at":"%s"}`, publishAt.Format(time.RFC3339)))
+
+		uc := &mocks.MockPostsUsecase{}
+		uc.ScheduleFn = func(ctx context.Context, id string, publishAt time.Time) (*domain.Post, error) {
+			return &domain.Post{ID: 1, PublicID: id, Status: domain.Scheduled, PublishAt: publishAt}, nil
+		}
+
+		h := NewPostsRouter(e, uc)
+
+		err := h.schedulePost(c)
+		if err != nil {
+			t.Errorf("Expected no error scheduling post. Got: %v", err)
+		}
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected http status code to be %d. Got %d", http.StatusOK, rec.Code)
+		}
+
+		got := PostOut{}
+		if err = json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Errorf("Error unmarshalling response: %s", err)
+		}
+
+		if got.Status != domain.Scheduled {
+			t.Errorf("Expected status to be %s. Got: %s", domain.Scheduled, got.Status)
+		}
+
+		if got.PublishAt == nil || !got.PublishAt.Equal(publishAt) {
+			t.Errorf("Expected publish_at to be %v. Got: %v", publishAt, got.PublishAt)
+		}
+	})
+
+	t.Run("it should require a publish date", func(t *testing.T) {
+		c, _ := newContext(`{}`)
+
+		h := NewPostsRouter(e, &mocks.MockPostsUsecase{})
+
+		err := h.schedulePost(c)
+		if !errors.Is(err, echo.ErrUnprocessableEntity) {
+			t.Errorf("Expected error to be a 422 ErrUnprocessableEntity. Got: %v", err)
+		}
+	})
+
+	t.Run("it should reject a publish date in the past", func(t *testing.T) {
+		c, _ := newContext(`{"publish_at":"2020-01-01T00:00:00Z"}`)
+
+		uc := &mocks.MockPostsUsecase{}
+		uc.ScheduleFn = func(ctx context.Context, id string, publishAt time.Time) (*domain.Post, error) {
+			return nil, domain.ErrInvalidPublishDate
+		}
+
+		h := NewPostsRouter(e, uc)
+
+		err := h.schedulePost(c)
+		if !errors.Is(err, echo.ErrUnprocessableEntity) {
+			t.Errorf("Expected error to be a 422 ErrUnprocessableEntity. Got: %v", err)
+		}
+	})
+}
+
+func TestPostRevisions(t *testing.T) {
+	e := echo.New()
+	e.Validator = mods.NewAppValidator()
+
+	revisions := []*domain.PostRevision{
+		{ID: 2, PostID: 1, Revision: 2, Title: "My test post", Description: "Some post description", Content: "Some new post content"},
+		{ID: 1, PostID: 1, Revision: 1, Title: "My test post", Description: "Some post description", Content: "Some post content"},
+	}
+
+	newContext := func(method, path string, names, values []string) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(method, path, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath(path)
+		c.SetParamNames(names...)
+		c.SetParamValues(values...)
+
+		return c, rec
+	}
+
+	t.Run("it should list the revisions of a post", func(t *testing.T) {
+		c, rec := newContext(http.MethodGet, "/posts/:id/revisions", []string{"id"}, []string{"po_12345"})
+
+		uc := &mocks.MockPostsUsecase{}
+		uc.GetRevisionsFn = func(ctx context.Context, id string) ([]*domain.PostRevision, error) {
+			return revisions, nil
+		}
+
+		h := NewPostsRouter(e, uc)
+
+		err := h.getPostRevisions(c)
+		if err != nil {
+			t.Errorf("Expected no error getting revisions. Got: %v", err)
+		}
+
+		got := PostRevisionsOut{}
+		if err = json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Errorf("Error unmarshalling response: %s", err)
+		}
+
+		if len(got.Data) != 2 || got.Data[0].Revision != 2 || got.Data[1].Revision != 1 {
+			t.Errorf("Expected revisions 2 and 1. Got: %v", got.Data)
+		}
+	})
+
+	t.Run("it should get a revision", func(t *testing.T) {
+		c, rec := newContext(http.MethodGet, "/posts/:id/revisions/:rev", []string{"id", "rev"}, []string{"po_12345", "1"})
+
+		uc := &mocks.MockPostsUsecase{}
+		uc.GetRevisionFn = func(ctx context.Context, id string, revision int32) (*domain.PostRevision, error) {
+			return revisions[2-revision], nil
+		}
+
+		h := NewPostsRouter(e, uc)
+
+		err := h.getPostRevision(c)
+		if err != nil {
+			t.Errorf("Expected no error getting revision. Got: %v", err)
+		}
+
+		got := PostRevisionOut{}
+		if err = json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Errorf("Error unmarshalling response: %s", err)
+		}
+
+		if got.Revision != 1 || got.Content != "Some post content" {
+			t.Errorf("Expected revision 1. Got: %v", got)
+		}
+	})
+
+	t.Run("it should return a not found error for an unknown revision", func(t *testing.T) {
+		c, _ := newContext(http.MethodGet, "/posts/:id/revisions/:rev", []string{"id", "rev"}, []string{"po_12345", "3"})
+
+		uc := &mocks.MockPostsUsecase{}
+		uc.GetRevisionFn = func(ctx context.Context, id string, revision int32) (*domain.PostRevision, error) {
+			return nil, domain.ErrRevisionNotFound
+		}
+
+		h := NewPostsRouter(e, uc)
+
+		err := h.getPostRevision(c)
+		if !errors.Is(err, echo.ErrNotFound) {
+			t.Errorf("Expected error to be a 404 ErrNotFound. Got: %v", err)
+		}
+	})
+
+	t.Run("it should return a bad request for an invalid revision", func(t *testing.T) {
+		c, _ := newContext(http.MethodGet, "/posts/:id/revisions/:rev", []string{"id", "rev"}, []string{"po_12345", "latest"})
+
+		h := NewPostsRouter(e, &mocks.MockPostsUsecase{})
+
+		err := h.getPostRevision(c)
+
+		var httpErr *echo.HTTPError
+		if !errors.As(err, &httpErr) || httpErr.Code != http.StatusBadRequest {
+			t.Errorf("Expected error to be a 400 BadRequest. Got: %v", err)
+		}
+	})
+
+	t.Run("it should diff two revisions", func(t *testing.T) {
+		c, rec := newContext(http.MethodGet, "/posts/:id/revisions/:from/diff/:to", []string{"id", "from", "to"}, []string{"po_12345", "1", "2"})
+
+		uc := &mocks.MockPostsUsecase{}
+		uc.DiffRevisionsFn = func(ctx context.Context, id string, from, to int32) (string, error) {
+			return "--- revision 1\n+++ revision 2\n", nil
+		}
+
+		h := NewPostsRouter(e, uc)
+
+		err := h.diffPostRevisions(c)
+		if err != nil {
+			t.Errorf("Expected no error diffing revisions. Got: %v", err)
+		}
+
+		want := map[string]any{
+			"from": 1,
+			"to":   2,
+			"diff": "--- revision 1\n+++ revision 2\n",
+		}
+
+		got := make(map[string]any)
+		if err = json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Errorf("Error unmarshalling response: %s", err)
+		}
+
+		if !testhelpers.CompareMaps(want, got) {
+			t.Errorf("Mismatch:\n%s", cmp.Diff(want, got))
+		}
+	})
+
+	t.Run("it should restore a revision", func(t *testing.T) {
+		c, rec := newContext(http.MethodPost, "/posts/:id/revisions/:rev/restore", []string{"id", "rev"}, []string{"po_12345", "1"})
+
+		uc := &mocks.MockPostsUsecase{}
+		uc.RestoreRevisionFn = func(ctx context.Context, id string, revision int32) (*domain.Post, error) {
+			return &domain.Post{ID: 1, PublicID: id, Content: revisions[2-revision].Content, Status: domain.Draft}, nil
+		}
+
+		h := NewPostsRouter(e, uc)
+
+		err := h.restorePostRevision(c)
+		if err != nil {
+			t.Errorf("Expected no error restoring revision. Got: %v", err)
+		}
+
+		got := PostOut{}
+		if err = json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Errorf("Error unmarshalling response: %s", err)
+		}
+
+		if got.Content != "Some post content" {
+			t.Errorf("Expected content of revision 1. Got: %s", got.Content)
+		}
+	})
+
+	t.Run("it should require a key to read the revisions", func(t *testing.T) {
+		e := echo.New()
+		e.Validator = mods.NewAppValidator()
+
+		NewPostsRouter(e, &mocks.MockPostsUsecase{})
+
+		paths := []string{"/posts/po_12345/revisions", "/posts/po_12345/revisions/1", "/posts/po_12345/revisions/1/diff/2"}
+		for _, path := range paths {
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+			if rec.Code != http.StatusUnauthorized {
+				t.Errorf("Expected %s to be a 401 (StatusUnauthorized). Got: %d", path, rec.Code)
+			}
+		}
+	})
+}
+
+func TestGetTags(t *testing.T) {
+	e := echo.New()
+	e.Validator = mods.NewAppValidator()
+
+	t.Run("it should return the tags with their post counts", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/tags", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		uc := &mocks.MockPostsUsecase{}
+		uc.GetTagsFn = func(ctx context.Context) ([]*domain.Tag, error) {
+			return []*domain.Tag{{Name: "go", Posts: 2}, {Name: "postgres", Posts: 1}}, nil
+		}
+
+		h := NewPostsRouter(e, uc)
+
+		err := h.getTags(c)
+		if err != nil {
+			t.Errorf("Expected no errors getting tags. Got: %v", err)
+		}
+
+		want := map[string]any{
+			"data": []map[string]any{
+				{"name": "go", "posts": 2},
+				{"name": "postgres", "posts": 1},
+			},
+		}
+
+		got := make(map[string]any)
+		if err = json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Errorf("Error unmarshalling response: %s", err)
+		}
+
+		if !testhelpers.CompareMaps(want, got) {
+			t.Errorf("Mismatch getting tags:\n%s", cmp.Diff(want, got))
+		}
+	})
+
+	t.Run("it should return an internal server error", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/tags", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		uc := &mocks.MockPostsUsecase{}
+		uc.GetTagsFn = func(ctx context.Context) ([]*domain.Tag, error) {
+			return nil, errors.New("DB error")
+		}
+
+		h := NewPostsRouter(e, uc)
+
+		err := h.getTags(c)
+		if !errors.Is(err, echo.ErrInternalServerError) {
+			t.Errorf("Expected error to be a 500 ErrInternalServerError. Got: %v", err)
+		}
+	})
+}
+
+func TestPostContentFormat(t *testing.T) {
+	e := echo.New()
+	e.Validator = mods.NewAppValidator()
+
+	post := &domain.Post{ID: 1, PublicID: "po_12345", Status: domain.Published, Content: "# My test post"}
+
+	newContext := func(query string) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodGet, "/posts/po_12345"+query, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/posts/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("po_12345")
+
+		return c, rec
+	}
+
+	uc := &mocks.MockPostsUsecase{}
+	uc.GetFn = func(ctx context.Context, id string) (*domain.Post, error) {
+		return post, nil
+	}
+	uc.RenderFn = func(ctx context.Context, post *domain.Post) (string, error) {
+		return "<h1 id=\"my-test-post\">My test post</h1>\n", nil
+	}
+
+	t.Run("it should add the rendered content when the html format is requested", func(t *testing.T) {
+		c, rec := newContext("?format=html")
+
+		h := NewPostsRouter(e, uc)
+
+		err := h.getPost(c)
+		if err != nil {
+			t.Errorf("Expected no errors getting post. Got: %v", err)
+		}
+
+		got := PostOut{}
+		if err = json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Errorf("Error unmarshalling response: %s", err)
+		}
+
+		if got.ContentHTML != "<h1 id=\"my-test-post\">My test post</h1>\n" {
+			t.Errorf("Expected content_html to be the rendered content. Got: %q", got.ContentHTML)
+		}
+
+		if got.Content != post.Content {
+			t.Errorf("Expected content to be %q. Got: %q", post.Content, got.Content)
+		}
+	})
+
+	t.Run("it should not render the content by default", func(t *testing.T) {
+		c, rec := newContext("")
+
+		h := NewPostsRouter(e, uc)
+
+		err := h.getPost(c)
+		if err != nil {
+			t.Errorf("Expected no errors getting post. Got: %v", err)
+		}
+
+		got := make(map[string]any)
+		if err = json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Errorf("Error unmarshalling response: %s", err)
+		}
+
+		if _, ok := got["content_html"]; ok {
+			t.Errorf("Expected no content_html. Got: %v", got["content_html"])
+		}
+	})
+
+	t.Run("it should return an unprocessable entity error for an unknown format", func(t *testing.T) {
+		c, _ := newContext("?format=pdf")
+
+		h := NewPostsRouter(e, uc)
+
+		err := h.getPost(c)
+		if !errors.Is(err, echo.ErrUnprocessableEntity) {
+			t.Errorf("Expected error to be a 422 (ErrUnprocessableEntity). Got: %v", err)
+		}
+	})
 }
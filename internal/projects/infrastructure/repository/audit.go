@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+
+	auditDomain "github.com/yavurb/goyurback/internal/audit/domain"
+	auditRepository "github.com/yavurb/goyurback/internal/audit/infrastructure/repository"
+	"github.com/yavurb/goyurback/internal/database/postgres"
+)
+
+// projectSnapshot is the state of a project recorded in the audit log.
+type projectSnapshot struct {
+	PostID       *int32   `json:"post_id"`
+	Name         string   `json:"name"`
+	Description  string   `json:"description"`
+	ThumbnailURL string   `json:"thumbnail_url"`
+	WebsiteURL   string   `json:"website_url"`
+	Tags         []string `json:"tags"`
+	Live         bool     `json:"live"`
+}
+
+func newProjectSnapshot(project *postgres.Project) *projectSnapshot {
+	snapshot := &projectSnapshot{
+		Name:         project.Name,
+		Description:  project.Description,
+		ThumbnailURL: project.ThumbnailUrl,
+		WebsiteURL:   project.WebsiteUrl,
+		Tags:         project.Tags,
+		Live:         project.Live,
+	}
+
+	if project.PostID.Valid {
+		snapshot.PostID = &project.PostID.Int32
+	}
+
+	return snapshot
+}
+
+func recordChange(ctx context.Context, db *postgres.Queries, action auditDomain.Action, publicID string, before, after any) error {
+	return auditRepository.Record(ctx, db, &auditDomain.Change{
+		Before:     before,
+		After:      after,
+		EntityID:   publicID,
+		Action:     action,
+		EntityType: auditDomain.EntityProjects,
+	})
+}
@@ -8,17 +8,20 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
+	auditDomain "github.com/yavurb/goyurback/internal/audit/domain"
 	"github.com/yavurb/goyurback/internal/database/postgres"
 	"github.com/yavurb/goyurback/internal/projects/domain"
 )
 
 type Repository struct {
-	db *postgres.Queries
+	db       *postgres.Queries
+	connpool *pgxpool.Pool
 }
 
 func NewRepo(connpool *pgxpool.Pool) domain.ProjectRepository {
 	return &Repository{
-		db: postgres.New(connpool),
+		db:       postgres.New(connpool),
+		connpool: connpool,
 	}
 }
 
@@ -29,7 +32,17 @@ func (r *Repository) CreateProject(ctx context.Context, project *domain.ProjectC
 		postID = pgtype.Int4{Int32: project.PostID, Valid: true}
 	}
 
-	project_, err := r.db.CreateProject(ctx, postgres.CreateProjectParams{
+	tx, err := r.connpool.Begin(ctx)
+	if err != nil {
+		log.Printf("DB Error starting transaction: %v\n", err)
+
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := r.db.WithTx(tx)
+
+	project_, err := qtx.CreateProject(ctx, postgres.CreateProjectParams{
 		PublicID:     project.PublicID,
 		Name:         project.Name,
 		Description:  project.Description,
@@ -40,6 +53,18 @@ func (r *Repository) CreateProject(ctx context.Context, project *domain.ProjectC
 		PostID:       postID,
 	})
 	if err != nil {
+		log.Printf("DB Error creating project: %v\n", err)
+
+		return nil, err
+	}
+
+	if err := recordChange(ctx, qtx, auditDomain.ActionCreate, project_.PublicID, nil, newProjectSnapshot(&project_)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Printf("DB Error committing project creation: %v\n", err)
+
 		return nil, err
 	}
 
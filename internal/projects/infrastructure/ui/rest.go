@@ -4,6 +4,8 @@ import (
 	"net/http"
 
 	"github.com/labstack/echo/v4"
+	authDomain "github.com/yavurb/goyurback/internal/auth/domain"
+	authUI "github.com/yavurb/goyurback/internal/auth/infrastructure/ui"
 	"github.com/yavurb/goyurback/internal/projects/domain"
 )
 
@@ -17,7 +19,7 @@ func NewProjectsRouter(e *echo.Echo, projectUsecase domain.ProjectUsecase) *proj
 		projectUsecase,
 	}
 
-	routerGroup.POST("", routerCtx.createProject)
+	routerGroup.POST("", routerCtx.createProject, authUI.RequireScope(authDomain.ScopeProjectsWrite))
 	routerGroup.GET("", routerCtx.getProjects)
 	routerGroup.GET("/:id", routerCtx.getProject)
 
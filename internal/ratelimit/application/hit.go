@@ -0,0 +1,48 @@
+package application
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/yavurb/goyurback/internal/ratelimit/domain"
+)
+
+func (uc *rateLimitUsecase) Hit(ctx context.Context, key string, limit int32) (*domain.Decision, error) {
+	now := time.Now().UTC()
+	windowStart := now.Truncate(domain.Window)
+
+	uc.prune(ctx, windowStart)
+
+	hits, err := uc.repository.Hit(ctx, key, windowStart)
+	if err != nil {
+		log.Printf("Error counting rate limit hit. Got: %v\n", err)
+
+		return nil, err
+	}
+
+	return &domain.Decision{
+		ResetAfter: windowStart.Add(domain.Window).Sub(now),
+		Limit:      limit,
+		Remaining:  max(limit-hits, 0),
+		Allowed:    hits <= limit,
+	}, nil
+}
+
+// prune deletes the counters of the past windows, which are only replaced when their client
+// comes back. It runs at most once per window on each instance, and its errors are only logged
+// as the counters are deleted on the next run.
+func (uc *rateLimitUsecase) prune(ctx context.Context, windowStart time.Time) {
+	uc.mu.Lock()
+	if windowStart.Before(uc.nextPrune) {
+		uc.mu.Unlock()
+
+		return
+	}
+	uc.nextPrune = windowStart.Add(domain.Window)
+	uc.mu.Unlock()
+
+	if err := uc.repository.DeleteExpired(ctx, windowStart); err != nil {
+		log.Printf("Error deleting expired rate limits. Got: %v\n", err)
+	}
+}
@@ -0,0 +1,106 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yavurb/goyurback/internal/ratelimit/application/mocks"
+	"github.com/yavurb/goyurback/internal/ratelimit/domain"
+)
+
+func TestHit(t *testing.T) {
+	newUsecase := func(hits int32, err error) (domain.RateLimitUsecase, *time.Time) {
+		var gotWindowStart time.Time
+
+		repo := &mocks.MockRateLimitRepository{
+			HitFn: func(ctx context.Context, key string, windowStart time.Time) (int32, error) {
+				gotWindowStart = windowStart
+
+				return hits, err
+			},
+			DeleteExpiredFn: func(ctx context.Context, windowStart time.Time) error { return nil },
+		}
+
+		return NewRateLimitUsecase(repo), &gotWindowStart
+	}
+
+	t.Run("it should allow requests within the limit", func(t *testing.T) {
+		uc, gotWindowStart := newUsecase(10, nil)
+
+		decision, err := uc.Hit(context.Background(), "key:ak_1:posts", 10)
+		if err != nil {
+			t.Fatalf("Expected no errors. Got: %v", err)
+		}
+
+		if !decision.Allowed || decision.Remaining != 0 || decision.Limit != 10 {
+			t.Errorf("Expected the last request of the window to be allowed. Got: %+v", decision)
+		}
+
+		if !gotWindowStart.Equal(gotWindowStart.Truncate(domain.Window)) {
+			t.Errorf("Expected the window to start at a whole window. Got: %v", gotWindowStart)
+		}
+
+		if decision.ResetAfter <= 0 || decision.ResetAfter > domain.Window {
+			t.Errorf("Expected the window to reset within %v. Got: %v", domain.Window, decision.ResetAfter)
+		}
+	})
+
+	t.Run("it should reject requests over the limit", func(t *testing.T) {
+		uc, _ := newUsecase(11, nil)
+
+		decision, err := uc.Hit(context.Background(), "key:ak_1:posts", 10)
+		if err != nil {
+			t.Fatalf("Expected no errors. Got: %v", err)
+		}
+
+		if decision.Allowed || decision.Remaining != 0 {
+			t.Errorf("Expected the request to be rejected. Got: %+v", decision)
+		}
+	})
+
+	t.Run("it should return the errors of the repository", func(t *testing.T) {
+		uc, _ := newUsecase(0, errors.New("DB error"))
+
+		if _, err := uc.Hit(context.Background(), "key:ak_1:posts", 10); err == nil {
+			t.Errorf("Expected an error")
+		}
+	})
+
+	t.Run("it should delete the past windows once per window", func(t *testing.T) {
+		var firstWindowStart time.Time
+		var deletedBefore []time.Time
+
+		repo := &mocks.MockRateLimitRepository{
+			HitFn: func(ctx context.Context, key string, windowStart time.Time) (int32, error) {
+				if firstWindowStart.IsZero() {
+					firstWindowStart = windowStart
+				}
+
+				return 1, nil
+			},
+			DeleteExpiredFn: func(ctx context.Context, windowStart time.Time) error {
+				deletedBefore = append(deletedBefore, windowStart)
+
+				return errors.New("DB error")
+			},
+		}
+		uc := NewRateLimitUsecase(repo)
+
+		for range 3 {
+			if _, err := uc.Hit(context.Background(), "ip:10.0.0.1:posts", 10); err != nil {
+				t.Fatalf("Expected no errors. Got: %v", err)
+			}
+		}
+
+		if len(deletedBefore) == 0 || !deletedBefore[0].Equal(firstWindowStart) {
+			t.Fatalf("Expected the windows before %v to be deleted. Got: %v", firstWindowStart, deletedBefore)
+		}
+
+		// A second deletion only happens if the hits crossed into the next window
+		if len(deletedBefore) > 1 && deletedBefore[1].Sub(deletedBefore[0]) < domain.Window {
+			t.Errorf("Expected the windows before the current one to be deleted once. Got: %v", deletedBefore)
+		}
+	})
+}
@@ -0,0 +1,18 @@
+package mocks
+
+import (
+	"context"
+	"time"
+)
+
+type MockRateLimitRepository struct {
+	HitFn           func(ctx context.Context, key string, windowStart time.Time) (int32, error)
+	DeleteExpiredFn func(ctx context.Context, windowStart time.Time) error
+}
+
+func (m *MockRateLimitRepository) Hit(ctx context.Context, key string, windowStart time.Time) (int32, error) {
+	return m.HitFn(ctx, key, windowStart)
+}
+func (m *MockRateLimitRepository) DeleteExpired(ctx context.Context, windowStart time.Time) error {
+	return m.DeleteExpiredFn(ctx, windowStart)
+}
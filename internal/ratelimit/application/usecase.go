@@ -0,0 +1,19 @@
+package application
+
+import (
+	"sync"
+	"time"
+
+	"github.com/yavurb/goyurback/internal/ratelimit/domain"
+)
+
+type rateLimitUsecase struct {
+	repository domain.RateLimitRepository
+
+	mu        sync.Mutex
+	nextPrune time.Time
+}
+
+func NewRateLimitUsecase(repository domain.RateLimitRepository) domain.RateLimitUsecase {
+	return &rateLimitUsecase{repository: repository}
+}
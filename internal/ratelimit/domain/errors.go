@@ -0,0 +1,5 @@
+package domain
+
+import "errors"
+
+var ErrInvalidLimits = errors.New("invalid rate limits")
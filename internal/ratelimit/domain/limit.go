@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Window is the period the limits apply to. Hits are counted in fixed windows.
+const Window = time.Minute
+
+// Policy holds the requests per Window allowed to each client. Routes are grouped by the first
+// segment of their path, like `posts` for `/posts/:id`. A limit of zero disables the limiter.
+type Policy struct {
+	Groups  map[string]int32
+	Default int32
+}
+
+// Limit returns the limit of a client in the group. keyLimit is the limit of the API key of the
+// client, zero when it has none or the request is anonymous, and takes precedence.
+func (p *Policy) Limit(group string, keyLimit int32) int32 {
+	if keyLimit > 0 {
+		return keyLimit
+	}
+
+	if limit, ok := p.Groups[group]; ok {
+		return limit
+	}
+
+	return p.Default
+}
+
+// Decision is the outcome of counting a request against a limit.
+type Decision struct {
+	ResetAfter time.Duration // Time left until the window ends
+	Limit      int32
+	Remaining  int32
+	Allowed    bool
+}
+
+// ParseGroupLimits parses limits like `search=30,auth=10`.
+func ParseGroupLimits(value string) (map[string]int32, error) {
+	limits := map[string]int32{}
+
+	if strings.TrimSpace(value) == "" {
+		return limits, nil
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		group, limit, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || group == "" {
+			return nil, ErrInvalidLimits
+		}
+
+		limit_, err := strconv.ParseInt(limit, 10, 32)
+		if err != nil || limit_ < 0 {
+			return nil, ErrInvalidLimits
+		}
+
+		limits[group] = int32(limit_)
+	}
+
+	return limits, nil
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPolicyLimit(t *testing.T) {
+	policy := &Policy{
+		Groups:  map[string]int32{"search": 30, "health": 0},
+		Default: 60,
+	}
+
+	cases := []struct {
+		name     string
+		group    string
+		keyLimit int32
+		want     int32
+	}{
+		{name: "default", group: "posts", want: 60},
+		{name: "group limit", group: "search", want: 30},
+		{name: "disabled group", group: "health", want: 0},
+		{name: "key limit", group: "search", keyLimit: 600, want: 600},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := policy.Limit(tc.group, tc.keyLimit); got != tc.want {
+				t.Errorf("Expected limit to be %d. Got: %d", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestParseGroupLimits(t *testing.T) {
+	t.Run("it should parse the limits", func(t *testing.T) {
+		got, err := ParseGroupLimits("search=30, auth=10,health=0")
+		if err != nil {
+			t.Fatalf("Expected no errors. Got: %v", err)
+		}
+
+		want := map[string]int32{"search": 30, "auth": 10, "health": 0}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("ParseGroupLimits() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("it should accept an empty value", func(t *testing.T) {
+		got, err := ParseGroupLimits("")
+		if err != nil || len(got) != 0 {
+			t.Errorf("Expected no limits. Got: %v, %v", got, err)
+		}
+	})
+
+	for _, value := range []string{"search", "=30", "search=-1", "search=many", "search=30,"} {
+		t.Run("it should reject "+value, func(t *testing.T) {
+			if _, err := ParseGroupLimits(value); !errors.Is(err, ErrInvalidLimits) {
+				t.Errorf("Expected error to be %v. Got: %v", ErrInvalidLimits, err)
+			}
+		})
+	}
+}
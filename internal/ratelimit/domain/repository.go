@@ -0,0 +1,14 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type RateLimitRepository interface {
+	// Hit counts a request of the client identified by key in the window starting at
+	// windowStart and returns the requests counted so far in it.
+	Hit(ctx context.Context, key string, windowStart time.Time) (int32, error)
+	// DeleteExpired deletes the counters of the windows that started before windowStart.
+	DeleteExpired(ctx context.Context, windowStart time.Time) error
+}
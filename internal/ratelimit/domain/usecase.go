@@ -0,0 +1,8 @@
+package domain
+
+import "context"
+
+type RateLimitUsecase interface {
+	// Hit counts a request of the client identified by key and decides if it is within limit.
+	Hit(ctx context.Context, key string, limit int32) (*Decision, error)
+}
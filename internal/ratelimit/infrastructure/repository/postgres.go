@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yavurb/goyurback/internal/database/postgres"
+	"github.com/yavurb/goyurback/internal/ratelimit/domain"
+)
+
+type Repository struct {
+	db *postgres.Queries
+}
+
+func NewRepo(connpool *pgxpool.Pool) domain.RateLimitRepository {
+	db := postgres.New(connpool)
+
+	return &Repository{db}
+}
+
+func (r *Repository) Hit(ctx context.Context, key string, windowStart time.Time) (int32, error) {
+	hits, err := r.db.HitRateLimit(ctx, postgres.HitRateLimitParams{
+		Key:         key,
+		WindowStart: pgtype.Timestamp{Time: windowStart, Valid: true},
+	})
+	if err != nil {
+		log.Printf("DB Error counting rate limit hit: %v\n", err)
+
+		return 0, err
+	}
+
+	return hits, nil
+}
+
+func (r *Repository) DeleteExpired(ctx context.Context, windowStart time.Time) error {
+	if err := r.db.DeleteExpiredRateLimits(ctx, pgtype.Timestamp{Time: windowStart, Valid: true}); err != nil {
+		log.Printf("DB Error deleting expired rate limits: %v\n", err)
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	authUI "github.com/yavurb/goyurback/internal/auth/infrastructure/ui"
+	"github.com/yavurb/goyurback/internal/pgk/ips"
+	"github.com/yavurb/goyurback/internal/ratelimit/domain"
+)
+
+const (
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+)
+
+// Middleware limits the requests of every client to the limits of the policy. Clients are
+// identified by the public id of their API key, or by their IP when they are anonymous, so it
+// must run after authUI.KeyAuth. The IP comes from the IPExtractor of the server, which only
+// trusts the X-Forwarded-For header of the configured proxies. Requests go through if the counters can't be reached.
+func Middleware(rateLimitUsecase domain.RateLimitUsecase, policy *domain.Policy) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			group := routeGroup(c.Path())
+			client := "ip:" + ips.Normalize(c.RealIP())
+
+			var keyLimit int32
+			if apiKey, ok := authUI.APIKeyFromContext(c); ok {
+				client = "key:" + apiKey.PublicID
+				keyLimit = apiKey.RateLimit
+			}
+
+			limit := policy.Limit(group, keyLimit)
+			if limit <= 0 {
+				return next(c)
+			}
+
+			decision, err := rateLimitUsecase.Hit(c.Request().Context(), client+":"+group, limit)
+			if err != nil {
+				return next(c)
+			}
+
+			resetAfter := strconv.Itoa(seconds(decision.ResetAfter))
+
+			header := c.Response().Header()
+			header.Set(HeaderRateLimitLimit, strconv.Itoa(int(decision.Limit)))
+			header.Set(HeaderRateLimitRemaining, strconv.Itoa(int(decision.Remaining)))
+			header.Set(HeaderRateLimitReset, resetAfter)
+
+			if !decision.Allowed {
+				header.Set(echo.HeaderRetryAfter, resetAfter)
+
+				return HTTPError{Message: "Too many requests"}.TooManyRequests()
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// routeGroup returns the first segment of the path of a route.
+func routeGroup(path string) string {
+	group, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+
+	return group
+}
+
+// seconds rounds d up to whole seconds, so clients don't retry before the window ends.
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
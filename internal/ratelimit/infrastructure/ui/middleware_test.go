@@ -0,0 +1,170 @@
+package ui
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	authDomain "github.com/yavurb/goyurback/internal/auth/domain"
+	authUI "github.com/yavurb/goyurback/internal/auth/infrastructure/ui"
+	"github.com/yavurb/goyurback/internal/ratelimit/domain"
+	"github.com/yavurb/goyurback/internal/ratelimit/infrastructure/ui/mocks"
+)
+
+func TestMiddleware(t *testing.T) {
+	policy := &domain.Policy{
+		Groups:  map[string]int32{"search": 30, "health": 0},
+		Default: 60,
+	}
+
+	newServer := func(uc domain.RateLimitUsecase, apiKey *authDomain.APIKey) *echo.Echo {
+		e := echo.New()
+		e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+			return func(c echo.Context) error {
+				if apiKey != nil {
+					c.Set(authUI.APIKeyContextKey, apiKey)
+				}
+
+				return next(c)
+			}
+		})
+		e.Use(Middleware(uc, policy))
+
+		handler := func(c echo.Context) error { return c.NoContent(http.StatusNoContent) }
+		e.GET("/posts/:id", handler)
+		e.GET("/search", handler)
+		e.GET("/health", handler)
+
+		return e
+	}
+
+	cases := []struct {
+		name      string
+		path      string
+		realIP    string
+		apiKey    *authDomain.APIKey
+		decision  *domain.Decision
+		hitErr    error
+		wantKey   string
+		wantLimit int32
+		wantCode  int
+		wantRetry string
+	}{
+		{
+			name:      "anonymous request",
+			path:      "/posts/po_1",
+			decision:  &domain.Decision{ResetAfter: 1500 * time.Millisecond, Limit: 60, Remaining: 59, Allowed: true},
+			wantKey:   "ip:10.0.0.1:posts",
+			wantLimit: 60,
+			wantCode:  http.StatusNoContent,
+		},
+		{
+			name:      "anonymous request from an IPv4-mapped address",
+			path:      "/posts/po_1",
+			realIP:    "::ffff:10.0.0.1",
+			decision:  &domain.Decision{ResetAfter: 1500 * time.Millisecond, Limit: 60, Remaining: 59, Allowed: true},
+			wantKey:   "ip:10.0.0.1:posts",
+			wantLimit: 60,
+			wantCode:  http.StatusNoContent,
+		},
+		{
+			name:      "request with a key",
+			path:      "/search",
+			apiKey:    &authDomain.APIKey{PublicID: "ak_1"},
+			decision:  &domain.Decision{ResetAfter: 1500 * time.Millisecond, Limit: 30, Remaining: 29, Allowed: true},
+			wantKey:   "key:ak_1:search",
+			wantLimit: 30,
+			wantCode:  http.StatusNoContent,
+		},
+		{
+			name:      "request with a key with its own limit",
+			path:      "/search",
+			apiKey:    &authDomain.APIKey{PublicID: "ak_1", RateLimit: 600},
+			decision:  &domain.Decision{ResetAfter: 1500 * time.Millisecond, Limit: 600, Remaining: 599, Allowed: true},
+			wantKey:   "key:ak_1:search",
+			wantLimit: 600,
+			wantCode:  http.StatusNoContent,
+		},
+		{
+			name:      "request over the limit",
+			path:      "/posts/po_1",
+			decision:  &domain.Decision{ResetAfter: 1500 * time.Millisecond, Limit: 60, Remaining: 0, Allowed: false},
+			wantKey:   "ip:10.0.0.1:posts",
+			wantLimit: 60,
+			wantCode:  http.StatusTooManyRequests,
+			wantRetry: "2",
+		},
+		{
+			name:      "unreachable counters",
+			path:      "/posts/po_1",
+			hitErr:    errors.New("DB error"),
+			wantKey:   "ip:10.0.0.1:posts",
+			wantLimit: 60,
+			wantCode:  http.StatusNoContent,
+		},
+		{
+			name:     "group without limit",
+			path:     "/health",
+			wantCode: http.StatusNoContent,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var gotKey string
+			var gotLimit int32
+
+			uc := &mocks.MockRateLimitUsecase{
+				HitFn: func(ctx context.Context, key string, limit int32) (*domain.Decision, error) {
+					gotKey, gotLimit = key, limit
+
+					return tc.decision, tc.hitErr
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			req.Header.Set(echo.HeaderXRealIP, cmp.Or(tc.realIP, "10.0.0.1"))
+			rec := httptest.NewRecorder()
+
+			newServer(uc, tc.apiKey).ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantCode {
+				t.Errorf("Expected status code to be %d. Got: %d", tc.wantCode, rec.Code)
+			}
+
+			if gotKey != tc.wantKey || gotLimit != tc.wantLimit {
+				t.Errorf("Expected the hit of %s with limit %d. Got: %s with limit %d", tc.wantKey, tc.wantLimit, gotKey, gotLimit)
+			}
+
+			if got := rec.Header().Get(echo.HeaderRetryAfter); got != tc.wantRetry {
+				t.Errorf("Expected Retry-After to be %q. Got: %q", tc.wantRetry, got)
+			}
+
+			if tc.decision == nil {
+				if got := rec.Header().Get(HeaderRateLimitLimit); got != "" {
+					t.Errorf("Expected no rate limit headers. Got limit: %q", got)
+				}
+
+				return
+			}
+
+			wantHeaders := map[string]string{
+				HeaderRateLimitLimit:     strconv.Itoa(int(tc.decision.Limit)),
+				HeaderRateLimitRemaining: strconv.Itoa(int(tc.decision.Remaining)),
+				HeaderRateLimitReset:     "2",
+			}
+
+			for header, want := range wantHeaders {
+				if got := rec.Header().Get(header); got != want {
+					t.Errorf("Expected %s to be %q. Got: %q", header, want, got)
+				}
+			}
+		})
+	}
+}
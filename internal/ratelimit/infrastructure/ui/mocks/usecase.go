@@ -0,0 +1,15 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/yavurb/goyurback/internal/ratelimit/domain"
+)
+
+type MockRateLimitUsecase struct {
+	HitFn func(ctx context.Context, key string, limit int32) (*domain.Decision, error)
+}
+
+func (m *MockRateLimitUsecase) Hit(ctx context.Context, key string, limit int32) (*domain.Decision, error) {
+	return m.HitFn(ctx, key, limit)
+}
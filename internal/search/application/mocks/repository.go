@@ -0,0 +1,15 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/yavurb/goyurback/internal/search/domain"
+)
+
+type MockSearchRepository struct {
+	SearchFn func(ctx context.Context, query *domain.SearchQuery) ([]*domain.Result, error)
+}
+
+func (m *MockSearchRepository) Search(ctx context.Context, query *domain.SearchQuery) ([]*domain.Result, error) {
+	return m.SearchFn(ctx, query)
+}
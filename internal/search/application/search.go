@@ -0,0 +1,57 @@
+package application
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/yavurb/goyurback/internal/search/domain"
+)
+
+func (uc *searchUsecase) Search(ctx context.Context, filter *domain.SearchFilter) (*domain.SearchPage, error) {
+	text := strings.TrimSpace(filter.Query)
+	if text == "" {
+		return nil, domain.ErrEmptyQuery
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = domain.DefaultPageSize
+	}
+
+	if limit > domain.MaxPageSize {
+		limit = domain.MaxPageSize
+	}
+
+	query := &domain.SearchQuery{
+		Query: text,
+		Limit: limit + 1, // Fetch an extra result to know if there is a next page
+	}
+
+	if filter.Cursor != "" {
+		cursor, err := domain.DecodeSearchCursor(filter.Cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		query.After = cursor
+	}
+
+	results, err := uc.repository.Search(ctx, query)
+	if err != nil {
+		log.Printf("Error searching posts and projects. Got: %v\n", err)
+
+		return nil, err
+	}
+
+	page := &domain.SearchPage{
+		Results: results,
+	}
+
+	if int32(len(results)) > limit {
+		page.Results = results[:limit]
+		page.NextCursor = domain.NewSearchCursor(page.Results[limit-1]).Encode()
+	}
+
+	return page, nil
+}
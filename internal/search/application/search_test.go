@@ -0,0 +1,141 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/yavurb/goyurback/internal/search/application/mocks"
+	"github.com/yavurb/goyurback/internal/search/domain"
+)
+
+func TestSearch(t *testing.T) {
+	results := []*domain.Result{
+		{ID: 3, Kind: domain.PostKind, PublicID: "po_3", Title: "Go generics", Rank: 0.9},
+		{ID: 1, Kind: domain.ProjectKind, PublicID: "pr_1", Title: "goyurback", Rank: 0.6},
+		{ID: 2, Kind: domain.PostKind, PublicID: "po_2", Title: "Go modules", Rank: 0.3},
+	}
+
+	t.Run("it should return a next cursor when there are more results", func(t *testing.T) {
+		var gotQuery *domain.SearchQuery
+
+		repo := &mocks.MockSearchRepository{
+			SearchFn: func(ctx context.Context, query *domain.SearchQuery) ([]*domain.Result, error) {
+				gotQuery = query
+
+				return results[:query.Limit], nil
+			},
+		}
+
+		uc := NewSearchUsecase(repo)
+
+		page, err := uc.Search(context.Background(), &domain.SearchFilter{Query: "  golang ", Limit: 2})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		if gotQuery.Query != "golang" || gotQuery.Limit != 3 {
+			t.Errorf("Expected the trimmed query and a limit of 3, got: %+v", gotQuery)
+		}
+
+		if !reflect.DeepEqual(page.Results, results[:2]) {
+			t.Errorf("Expected results to be %v, got: %v", results[:2], page.Results)
+		}
+
+		cursor, err := domain.DecodeSearchCursor(page.NextCursor)
+		if err != nil {
+			t.Fatalf("Expected a valid next cursor, got: %v", err)
+		}
+
+		if *cursor != (domain.SearchCursor{Kind: domain.ProjectKind, Rank: 0.6, ID: 1}) {
+			t.Errorf("Expected cursor to point to project 1, got: %+v", cursor)
+		}
+	})
+
+	t.Run("it should pass the decoded cursor to the repository", func(t *testing.T) {
+		var gotQuery *domain.SearchQuery
+
+		repo := &mocks.MockSearchRepository{
+			SearchFn: func(ctx context.Context, query *domain.SearchQuery) ([]*domain.Result, error) {
+				gotQuery = query
+
+				return results[2:], nil
+			},
+		}
+
+		uc := NewSearchUsecase(repo)
+		cursor := domain.NewSearchCursor(results[1]).Encode()
+
+		page, err := uc.Search(context.Background(), &domain.SearchFilter{Query: "go", Limit: 2, Cursor: cursor})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		if gotQuery.After == nil || *gotQuery.After != *domain.NewSearchCursor(results[1]) {
+			t.Errorf("Expected query to start after project 1, got: %+v", gotQuery.After)
+		}
+
+		if page.NextCursor != "" {
+			t.Errorf("Expected no next cursor on the last page, got: %s", page.NextCursor)
+		}
+	})
+
+	t.Run("it should cap the page size", func(t *testing.T) {
+		var gotQuery *domain.SearchQuery
+
+		repo := &mocks.MockSearchRepository{
+			SearchFn: func(ctx context.Context, query *domain.SearchQuery) ([]*domain.Result, error) {
+				gotQuery = query
+
+				return []*domain.Result{}, nil
+			},
+		}
+
+		uc := NewSearchUsecase(repo)
+
+		_, err := uc.Search(context.Background(), &domain.SearchFilter{Query: "go", Limit: 1000})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		if gotQuery.Limit != domain.MaxPageSize+1 {
+			t.Errorf("Expected repository limit to be %d, got: %d", domain.MaxPageSize+1, gotQuery.Limit)
+		}
+	})
+
+	t.Run("it should return an error if the query is empty", func(t *testing.T) {
+		uc := NewSearchUsecase(&mocks.MockSearchRepository{})
+
+		_, err := uc.Search(context.Background(), &domain.SearchFilter{Query: "   "})
+		if !errors.Is(err, domain.ErrEmptyQuery) {
+			t.Errorf("Expected error to be %v, got: %v", domain.ErrEmptyQuery, err)
+		}
+	})
+
+	t.Run("it should return an error if the cursor is invalid", func(t *testing.T) {
+		uc := NewSearchUsecase(&mocks.MockSearchRepository{})
+
+		for _, cursor := range []string{"not a cursor", "MTp1c2VyOjE", "MTpwb3N0"} {
+			_, err := uc.Search(context.Background(), &domain.SearchFilter{Query: "go", Cursor: cursor})
+			if !errors.Is(err, domain.ErrInvalidCursor) {
+				t.Errorf("Expected error to be %v for %s, got: %v", domain.ErrInvalidCursor, cursor, err)
+			}
+		}
+	})
+
+	t.Run("it should return the repository error", func(t *testing.T) {
+		repo := &mocks.MockSearchRepository{
+			SearchFn: func(ctx context.Context, query *domain.SearchQuery) ([]*domain.Result, error) {
+				return nil, errors.New("DB error")
+			},
+		}
+
+		uc := NewSearchUsecase(repo)
+
+		_, err := uc.Search(context.Background(), &domain.SearchFilter{Query: "go"})
+		if err == nil || err.Error() != "DB error" {
+			t.Errorf("Expected error to be 'DB error', got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,11 @@
+package application
+
+import "github.com/yavurb/goyurback/internal/search/domain"
+
+type searchUsecase struct {
+	repository domain.SearchRepository
+}
+
+func NewSearchUsecase(repository domain.SearchRepository) domain.SearchUsecase {
+	return &searchUsecase{repository}
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// SearchCursor points to the last result of a page. Results are sorted by (rank, kind, id)
+// so all of them are needed to resume the search without skipping or repeating results.
+type SearchCursor struct {
+	Kind Kind
+	Rank float32
+	ID   int32
+}
+
+func NewSearchCursor(result *Result) *SearchCursor {
+	return &SearchCursor{
+		Kind: result.Kind,
+		Rank: result.Rank,
+		ID:   result.ID,
+	}
+}
+
+// Encode returns an opaque representation of the cursor that is safe to use in URLs. The rank
+// is encoded by its bits to compare it exactly against the ranks computed by the database.
+func (c SearchCursor) Encode() string {
+	raw := fmt.Sprintf("%d:%s:%d", math.Float32bits(c.Rank), c.Kind, c.ID)
+
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeSearchCursor(cursor string) (*SearchCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 3 {
+		return nil, ErrInvalidCursor
+	}
+
+	rank, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	kind := Kind(parts[1])
+	if kind != PostKind && kind != ProjectKind {
+		return nil, ErrInvalidCursor
+	}
+
+	id, err := strconv.ParseInt(parts[2], 10, 32)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &SearchCursor{
+		Kind: kind,
+		Rank: math.Float32frombits(uint32(rank)),
+		ID:   int32(id),
+	}, nil
+}
@@ -0,0 +1,8 @@
+package domain
+
+import "errors"
+
+var (
+	ErrEmptyQuery    = errors.New("search query is empty")
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
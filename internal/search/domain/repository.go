@@ -0,0 +1,7 @@
+package domain
+
+import "context"
+
+type SearchRepository interface {
+	Search(ctx context.Context, query *SearchQuery) ([]*Result, error)
+}
@@ -0,0 +1,46 @@
+package domain
+
+import "time"
+
+type Kind string
+
+const (
+	PostKind    Kind = "post"
+	ProjectKind Kind = "project"
+)
+
+const (
+	DefaultPageSize int32 = 20
+	MaxPageSize     int32 = 100
+)
+
+type Result struct {
+	UpdatedAt time.Time
+	Kind      Kind
+	PublicID  string
+	Title     string
+	Slug      string // Only set for posts
+	Snippet   string // HTML escaped, with the matched terms wrapped in <mark> tags
+	Rank      float32
+	ID        int32
+}
+
+// SearchFilter holds the search options accepted by the usecase. Cursor is the opaque value
+// returned as NextCursor by a previous page.
+type SearchFilter struct {
+	Query  string
+	Cursor string
+	Limit  int32
+}
+
+// SearchQuery is the decoded version of SearchFilter used by the repository.
+type SearchQuery struct {
+	After *SearchCursor
+	Query string
+	Limit int32
+}
+
+type SearchPage struct {
+	Results    []*Result
+	NextCursor string
+}
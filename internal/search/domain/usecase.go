@@ -0,0 +1,8 @@
+package domain
+
+import "context"
+
+type SearchUsecase interface {
+	// Search looks for published posts and projects matching the query, best matches first.
+	Search(ctx context.Context, filter *SearchFilter) (*SearchPage, error)
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"log"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yavurb/goyurback/internal/database/postgres"
+	"github.com/yavurb/goyurback/internal/search/domain"
+)
+
+type Repository struct {
+	db *postgres.Queries
+}
+
+func NewRepo(connpool *pgxpool.Pool) domain.SearchRepository {
+	db := postgres.New(connpool)
+
+	return &Repository{db}
+}
+
+func (r *Repository) Search(ctx context.Context, query *domain.SearchQuery) ([]*domain.Result, error) {
+	params := postgres.SearchParams{
+		Query: query.Query,
+		Limit: query.Limit,
+	}
+
+	if query.After != nil {
+		params.CursorRank = pgtype.Float4{Float32: query.After.Rank, Valid: true}
+		params.CursorKind = pgtype.Text{String: string(query.After.Kind), Valid: true}
+		params.CursorID = pgtype.Int4{Int32: query.After.ID, Valid: true}
+	}
+
+	rows, err := r.db.Search(ctx, params)
+	if err != nil {
+		log.Printf("DB Error searching: %v\n", err)
+
+		return nil, err
+	}
+
+	results := []*domain.Result{}
+
+	for _, row := range rows {
+		results = append(results, &domain.Result{
+			UpdatedAt: row.UpdatedAt.Time,
+			Kind:      domain.Kind(row.Kind),
+			PublicID:  row.PublicID,
+			Title:     row.Title,
+			Slug:      row.Slug,
+			Snippet:   row.Snippet,
+			Rank:      row.Rank,
+			ID:        row.ID,
+		})
+	}
+
+	return results, nil
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/yavurb/goyurback/internal/database/postgres"
+	"github.com/yavurb/goyurback/internal/search/domain"
+	"github.com/yavurb/goyurback/testhelpers"
+)
+
+func TestSearch(t *testing.T) {
+	ctx := context.Background()
+
+	pgContainer, err := testhelpers.CreatePostgresContainer(t, ctx)
+	if err != nil {
+		t.Fatalf("Error creating postgres container: %v", err)
+	}
+
+	seed := func(t *testing.T, conn *pgxpool.Pool) {
+		t.Helper()
+
+		db := postgres.New(conn)
+		posts := []postgres.CreatePostParams{
+			{PublicID: "po_1", Title: "Concurrency in Go", Author: "Roy", Slug: "concurrency-in-go", Description: "Goroutines and channels", Content: "Some content"},
+			{PublicID: "po_2", Title: "Testing", Author: "Roy", Slug: "testing", Description: "Table driven tests", Content: "Use goroutines carefully in tests"},
+			{PublicID: "po_3", Title: "Goroutines everywhere", Author: "Roy", Slug: "goroutines-everywhere", Description: "A draft", Content: "Draft content"},
+		}
+
+		for _, post := range posts {
+			if _, err := db.CreatePost(ctx, post); err != nil {
+				t.Fatalf("Error creating post: %v", err)
+			}
+		}
+
+		if _, err := conn.Exec(ctx, "UPDATE posts SET status = 'published', published_at = now() WHERE public_id IN ('po_1', 'po_2')"); err != nil {
+			t.Fatalf("Error publishing posts: %v", err)
+		}
+
+		_, err := db.CreateProject(ctx, postgres.CreateProjectParams{
+			PublicID:     "pr_1",
+			Name:         "Worker pool",
+			Description:  "A pool of goroutines",
+			Tags:         []string{"go"},
+			ThumbnailUrl: "https://example.com/image.jpg",
+			WebsiteUrl:   "https://example.com",
+		})
+		if err != nil {
+			t.Fatalf("Error creating project: %v", err)
+		}
+	}
+
+	t.Run("it should rank published posts and projects", func(t *testing.T) {
+		testhelpers.CleanDatabase(t, ctx, pgContainer.ConnString)
+
+		conn, err := pgxpool.New(ctx, pgContainer.ConnString)
+		if err != nil {
+			t.Fatalf("Error creating pgxpool: %v", err)
+		}
+
+		t.Cleanup(func() { conn.Close() })
+
+		seed(t, conn)
+
+		repo := NewRepo(conn)
+
+		results, err := repo.Search(ctx, &domain.SearchQuery{Query: "goroutines", Limit: 10})
+		if err != nil {
+			t.Fatalf("Expected no error searching. Got: %v", err)
+		}
+
+		got := []string{}
+		for _, result := range results {
+			got = append(got, result.PublicID)
+		}
+
+		// Description matches weigh more than content matches and drafts are left out
+		if len(got) != 3 || got[2] != "po_2" {
+			t.Fatalf("Expected the content match to be ranked last. Got: %v", got)
+		}
+
+		for _, result := range results {
+			if !strings.Contains(result.Snippet, "<mark>") {
+				t.Errorf("Expected the snippet of %s to highlight the match. Got: %s", result.PublicID, result.Snippet)
+			}
+		}
+
+		if results[0].Kind == domain.PostKind && results[0].Slug == "" {
+			t.Errorf("Expected post results to have a slug. Got: %+v", results[0])
+		}
+	})
+
+	t.Run("it should resume after the cursor", func(t *testing.T) {
+		testhelpers.CleanDatabase(t, ctx, pgContainer.ConnString)
+
+		conn, err := pgxpool.New(ctx, pgContainer.ConnString)
+		if err != nil {
+			t.Fatalf("Error creating pgxpool: %v", err)
+		}
+
+		t.Cleanup(func() { conn.Close() })
+
+		seed(t, conn)
+
+		repo := NewRepo(conn)
+
+		first, err := repo.Search(ctx, &domain.SearchQuery{Query: "goroutines", Limit: 2})
+		if err != nil {
+			t.Fatalf("Expected no error searching. Got: %v", err)
+		}
+
+		rest, err := repo.Search(ctx, &domain.SearchQuery{Query: "goroutines", Limit: 2, After: domain.NewSearchCursor(first[1])})
+		if err != nil {
+			t.Fatalf("Expected no error searching. Got: %v", err)
+		}
+
+		if len(first) != 2 || len(rest) != 1 || rest[0].PublicID != "po_2" {
+			t.Errorf("Expected the last result on the second page. Got: %v and %v", first, rest)
+		}
+	})
+
+	t.Run("it should update the search vector when a post changes", func(t *testing.T) {
+		testhelpers.CleanDatabase(t, ctx, pgContainer.ConnString)
+
+		conn, err := pgxpool.New(ctx, pgContainer.ConnString)
+		if err != nil {
+			t.Fatalf("Error creating pgxpool: %v", err)
+		}
+
+		t.Cleanup(func() { conn.Close() })
+
+		seed(t, conn)
+
+		if _, err := conn.Exec(ctx, "UPDATE posts SET title = 'Mutexes in Go' WHERE public_id = 'po_1'"); err != nil {
+			t.Fatalf("Error updating post: %v", err)
+		}
+
+		repo := NewRepo(conn)
+
+		results, err := repo.Search(ctx, &domain.SearchQuery{Query: "mutexes", Limit: 10})
+		if err != nil {
+			t.Fatalf("Expected no error searching. Got: %v", err)
+		}
+
+		if len(results) != 1 || results[0].PublicID != "po_1" {
+			t.Errorf("Expected the renamed post to match. Got: %v", results)
+		}
+	})
+
+	t.Run("it should escape the HTML of the snippets", func(t *testing.T) {
+		testhelpers.CleanDatabase(t, ctx, pgContainer.ConnString)
+
+		conn, err := pgxpool.New(ctx, pgContainer.ConnString)
+		if err != nil {
+			t.Fatalf("Error creating pgxpool: %v", err)
+		}
+
+		t.Cleanup(func() { conn.Close() })
+
+		_, err = postgres.New(conn).CreateProject(ctx, postgres.CreateProjectParams{
+			PublicID:     "pr_1",
+			Name:         "Widgets",
+			Description:  "Widgets <img src=x onerror=alert(1)> & <b>gadgets</b>",
+			Tags:         []string{},
+			ThumbnailUrl: "https://example.com/image.jpg",
+			WebsiteUrl:   "https://example.com",
+		})
+		if err != nil {
+			t.Fatalf("Error creating project: %v", err)
+		}
+
+		repo := NewRepo(conn)
+
+		results, err := repo.Search(ctx, &domain.SearchQuery{Query: "gadgets", Limit: 10})
+		if err != nil {
+			t.Fatalf("Expected no error searching. Got: %v", err)
+		}
+
+		if len(results) != 1 {
+			t.Fatalf("Expected the project to match. Got: %v", results)
+		}
+
+		snippet := results[0].Snippet
+		if strings.Contains(snippet, "<img") || strings.Contains(snippet, "<b>") {
+			t.Errorf("Expected the markup of the description to be escaped. Got: %s", snippet)
+		}
+
+		if !strings.Contains(snippet, "&lt;img") || !strings.Contains(snippet, "<mark>gadgets</mark>") {
+			t.Errorf("Expected an escaped snippet highlighting the match. Got: %s", snippet)
+		}
+	})
+}
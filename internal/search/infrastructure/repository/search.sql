@@ -0,0 +1,22 @@
+-- name: Search :many
+WITH results AS (
+  SELECT 'post'::text AS kind, posts.id, posts.public_id, posts.title, posts.slug, posts.updated_at,
+    ts_rank(posts.search_vector, query) AS rank,
+    posts.description || ' ' || posts.content AS document,
+    query
+  FROM posts, websearch_to_tsquery('english', sqlc.arg('query')) query
+  WHERE posts.status = 'published' AND posts.search_vector @@ query
+  UNION ALL
+  SELECT 'project'::text AS kind, projects.id, projects.public_id, projects.name, ''::varchar AS slug, projects.updated_at,
+    ts_rank(projects.search_vector, query) AS rank,
+    projects.description AS document,
+    query
+  FROM projects, websearch_to_tsquery('english', sqlc.arg('query')) query
+  WHERE projects.search_vector @@ query
+)
+SELECT kind, id, public_id, title, slug, updated_at, rank,
+  ts_headline('english', replace(replace(replace(document, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), query, 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10')::text AS snippet
+FROM results
+WHERE sqlc.narg('cursor_rank')::real IS NULL OR (rank, kind, id) < (sqlc.narg('cursor_rank')::real, sqlc.narg('cursor_kind')::text, sqlc.narg('cursor_id')::integer)
+ORDER BY rank DESC, kind DESC, id DESC
+LIMIT sqlc.arg('limit');
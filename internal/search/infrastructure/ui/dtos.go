@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/yavurb/goyurback/internal/search/domain"
+)
+
+type SearchParams struct {
+	Query  string `query:"q" validate:"required,max=256"`
+	Cursor string `query:"cursor"`
+	Limit  int32  `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+type ResultOut struct {
+	UpdatedAt time.Time `json:"updated_at"`
+	Type      string    `json:"type"`
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Slug      string    `json:"slug,omitempty"`
+	Snippet   string    `json:"snippet"`
+	Rank      float32   `json:"rank"`
+}
+
+type SearchOut struct {
+	NextCursor string       `json:"next_cursor,omitempty"`
+	Data       []*ResultOut `json:"data"`
+}
+
+func toResultOut(result *domain.Result) *ResultOut {
+	return &ResultOut{
+		UpdatedAt: result.UpdatedAt,
+		Type:      string(result.Kind),
+		ID:        result.PublicID,
+		Title:     result.Title,
+		Slug:      result.Slug,
+		Snippet:   result.Snippet,
+		Rank:      result.Rank,
+	}
+}
@@ -0,0 +1,15 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/yavurb/goyurback/internal/search/domain"
+)
+
+type MockSearchUsecase struct {
+	SearchFn func(ctx context.Context, filter *domain.SearchFilter) (*domain.SearchPage, error)
+}
+
+func (uc *MockSearchUsecase) Search(ctx context.Context, filter *domain.SearchFilter) (*domain.SearchPage, error) {
+	return uc.SearchFn(ctx, filter)
+}
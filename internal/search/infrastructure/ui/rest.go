@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yavurb/goyurback/internal/search/domain"
+)
+
+type searchRouterCtx struct {
+	searchUsecase domain.SearchUsecase
+}
+
+func NewSearchRouter(e *echo.Echo, searchUsecase domain.SearchUsecase) *searchRouterCtx {
+	routerCtx := &searchRouterCtx{
+		searchUsecase,
+	}
+
+	e.GET("/search", routerCtx.search)
+
+	return routerCtx
+}
+
+func (ctx *searchRouterCtx) search(c echo.Context) error {
+	var params SearchParams
+
+	if err := c.Bind(&params); err != nil {
+		return HTTPError{
+			Message: "Invalid query params",
+		}.BadRequest()
+	}
+
+	if err := c.Validate(params); err != nil {
+		return HTTPError{
+			Message: "Invalid query params",
+		}.ErrUnprocessableEntity()
+	}
+
+	page, err := ctx.searchUsecase.Search(c.Request().Context(), &domain.SearchFilter{
+		Query:  params.Query,
+		Cursor: params.Cursor,
+		Limit:  params.Limit,
+	})
+	if err != nil {
+		return handleErr(err)
+	}
+
+	resultsOut := []*ResultOut{}
+
+	for _, result := range page.Results {
+		resultsOut = append(resultsOut, toResultOut(result))
+	}
+
+	return c.JSON(http.StatusOK, &SearchOut{
+		NextCursor: page.NextCursor,
+		Data:       resultsOut,
+	})
+}
+
+func handleErr(err error) error {
+	switch err {
+	case domain.ErrEmptyQuery:
+		return HTTPError{
+			Message: "Invalid query params",
+		}.ErrUnprocessableEntity()
+	case domain.ErrInvalidCursor:
+		return HTTPError{
+			Message: "Invalid cursor",
+		}.BadRequest()
+	default:
+		return HTTPError{
+			Message: "Internal server error",
+		}.InternalServerError()
+	}
+}
@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+	"github.com/yavurb/goyurback/internal/app/mods"
+	"github.com/yavurb/goyurback/internal/search/domain"
+	"github.com/yavurb/goyurback/internal/search/infrastructure/ui/mocks"
+)
+
+func TestSearch(t *testing.T) {
+	e := echo.New()
+	e.Validator = mods.NewAppValidator()
+
+	updatedAt := time.Date(2024, 7, 15, 10, 30, 0, 0, time.UTC)
+
+	newContext := func(query string) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodGet, "/search"+query, nil)
+		rec := httptest.NewRecorder()
+
+		return e.NewContext(req, rec), rec
+	}
+
+	t.Run("it should return the ranked results", func(t *testing.T) {
+		var gotFilter *domain.SearchFilter
+
+		uc := &mocks.MockSearchUsecase{
+			SearchFn: func(ctx context.Context, filter *domain.SearchFilter) (*domain.SearchPage, error) {
+				gotFilter = filter
+
+				return &domain.SearchPage{
+					Results: []*domain.Result{
+						{ID: 1, Kind: domain.PostKind, PublicID: "po_1", Title: "Concurrency in Go", Slug: "concurrency-in-go", Snippet: "<mark>Goroutines</mark> and channels", Rank: 0.6, UpdatedAt: updatedAt},
+						{ID: 1, Kind: domain.ProjectKind, PublicID: "pr_1", Title: "Worker pool", Snippet: "A pool of <mark>goroutines</mark>", Rank: 0.4, UpdatedAt: updatedAt},
+					},
+					NextCursor: "next",
+				}, nil
+			},
+		}
+
+		c, rec := newContext("?q=goroutines&limit=2&cursor=abc")
+		h := NewSearchRouter(e, uc)
+
+		if err := h.search(c); err != nil {
+			t.Fatalf("Expected no errors searching. Got: %v", err)
+		}
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected status code to be %d. Got: %d", http.StatusOK, rec.Code)
+		}
+
+		if diff := cmp.Diff(&domain.SearchFilter{Query: "goroutines", Cursor: "abc", Limit: 2}, gotFilter); diff != "" {
+			t.Errorf("search() filter mismatch (-want +got):\n%s", diff)
+		}
+
+		got := SearchOut{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatalf("Error unmarshalling response: %s", err)
+		}
+
+		want := SearchOut{
+			NextCursor: "next",
+			Data: []*ResultOut{
+				{Type: "post", ID: "po_1", Title: "Concurrency in Go", Slug: "concurrency-in-go", Snippet: "<mark>Goroutines</mark> and channels", Rank: 0.6, UpdatedAt: updatedAt},
+				{Type: "project", ID: "pr_1", Title: "Worker pool", Snippet: "A pool of <mark>goroutines</mark>", Rank: 0.4, UpdatedAt: updatedAt},
+			},
+		}
+
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("search() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("it should require a query", func(t *testing.T) {
+		c, _ := newContext("")
+		h := NewSearchRouter(e, &mocks.MockSearchUsecase{})
+
+		err := h.search(c)
+
+		var httpErr *echo.HTTPError
+		if !errors.As(err, &httpErr) || httpErr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected an unprocessable entity error. Got: %v", err)
+		}
+	})
+
+	t.Run("it should return a bad request for an invalid cursor", func(t *testing.T) {
+		uc := &mocks.MockSearchUsecase{
+			SearchFn: func(ctx context.Context, filter *domain.SearchFilter) (*domain.SearchPage, error) {
+				return nil, domain.ErrInvalidCursor
+			},
+		}
+
+		c, _ := newContext("?q=go&cursor=abc")
+		h := NewSearchRouter(e, uc)
+
+		err := h.search(c)
+
+		var httpErr *echo.HTTPError
+		if !errors.As(err, &httpErr) || httpErr.Code != http.StatusBadRequest {
+			t.Errorf("Expected a bad request error. Got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	postsDomain "github.com/yavurb/goyurback/internal/posts/domain"
+	"github.com/yavurb/goyurback/internal/sitemap/domain"
+)
+
+// GetURLs returns the cached URLs while they are fresh. The lock is held while they are listed
+// again, so concurrent requests wait for a single listing.
+func (uc *sitemapUsecase) GetURLs(ctx context.Context) ([]*domain.SitemapURL, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	now := uc.now()
+	if uc.urls != nil && now.Sub(uc.cachedAt) < domain.URLsCacheTTL {
+		return uc.urls, nil
+	}
+
+	urls, err := uc.listURLs(ctx, now.UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	uc.urls, uc.cachedAt = urls, now
+
+	return urls, nil
+}
+
+func (uc *sitemapUsecase) listURLs(ctx context.Context, now time.Time) ([]*domain.SitemapURL, error) {
+	home := &domain.SitemapURL{Loc: uc.siteURL + "/"}
+	urls := []*domain.SitemapURL{home}
+
+	filter := &postsDomain.PostFilter{Limit: postsDomain.MaxPageSize}
+
+	for {
+		page, err := uc.postUsecase.GetPosts(ctx, filter)
+		if err != nil {
+			log.Printf("Error retrieving posts for the sitemap. Got: %v\n", err)
+
+			return nil, err
+		}
+
+		for _, post := range page.Posts {
+			urls = append(urls, &domain.SitemapURL{
+				LastMod: post.UpdatedAt,
+				Loc:     fmt.Sprintf("%s/posts/%s", uc.siteURL, post.Slug),
+			})
+
+			// The home page lists the latest posts
+			if post.UpdatedAt.After(home.LastMod) {
+				home.LastMod = post.UpdatedAt
+			}
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+
+		filter.Cursor = page.NextCursor
+	}
+
+	projects, err := uc.projectUsecase.GetProjects(ctx)
+	if err != nil {
+		log.Printf("Error retrieving projects for the sitemap. Got: %v\n", err)
+
+		return nil, err
+	}
+
+	for _, project := range projects {
+		urls = append(urls, &domain.SitemapURL{
+			LastMod: project.UpdatedAt,
+			Loc:     fmt.Sprintf("%s/projects/%s", uc.siteURL, project.PublicID),
+		})
+	}
+
+	if uc.chikitosURL == "" {
+		return urls, nil
+	}
+
+	chikitos, err := uc.chikitoUsecase.GetChikitos(ctx)
+	if err != nil {
+		log.Printf("Error retrieving chikitos for the sitemap. Got: %v\n", err)
+
+		return nil, err
+	}
+
+	for _, chikito := range chikitos {
+		// Links that no longer redirect are left out
+		if chikito.Gone(now) {
+			continue
+		}
+
+		urls = append(urls, &domain.SitemapURL{
+			LastMod: chikito.UpdatedAt,
+			Loc:     fmt.Sprintf("%s/%s", uc.chikitosURL, chikito.PublicID),
+		})
+	}
+
+	return urls, nil
+}
@@ -0,0 +1,132 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	chikitosDomain "github.com/yavurb/goyurback/internal/chikitos/domain"
+	chikitoMocks "github.com/yavurb/goyurback/internal/chikitos/infrastructure/ui/mocks"
+	postsDomain "github.com/yavurb/goyurback/internal/posts/domain"
+	postMocks "github.com/yavurb/goyurback/internal/posts/infrastructure/ui/mocks"
+	projectsDomain "github.com/yavurb/goyurback/internal/projects/domain"
+	projectMocks "github.com/yavurb/goyurback/internal/projects/infrastructure/ui/mocks"
+	"github.com/yavurb/goyurback/internal/sitemap/domain"
+)
+
+func TestGetURLs(t *testing.T) {
+	updatedAt := time.Date(2024, 7, 15, 10, 30, 0, 0, time.UTC)
+
+	var gotCursors []string
+
+	postUsecase := &postMocks.MockPostsUsecase{
+		GetPostsFn: func(ctx context.Context, filter *postsDomain.PostFilter) (*postsDomain.PostPage, error) {
+			gotCursors = append(gotCursors, filter.Cursor)
+
+			if filter.Cursor == "" {
+				return &postsDomain.PostPage{
+					Posts:      []*postsDomain.Post{{Slug: "second-post", UpdatedAt: updatedAt}},
+					NextCursor: "next",
+				}, nil
+			}
+
+			return &postsDomain.PostPage{
+				Posts: []*postsDomain.Post{{Slug: "first-post", UpdatedAt: updatedAt.Add(time.Hour)}},
+			}, nil
+		},
+	}
+	projectUsecase := &projectMocks.MockProjectsUsecase{
+		GetProjectsFn: func(ctx context.Context) ([]*projectsDomain.Project, error) {
+			return []*projectsDomain.Project{{PublicID: "pr_12345", UpdatedAt: updatedAt}}, nil
+		},
+	}
+	chikitoUsecase := &chikitoMocks.MockChikitosUsecase{
+		GetChikitosFn: func(ctx context.Context) ([]*chikitosDomain.Chikito, error) {
+			return []*chikitosDomain.Chikito{{PublicID: "ch_12345", UpdatedAt: updatedAt}, {PublicID: "ch_gone1", UpdatedAt: updatedAt, Disabled: true}}, nil
+		},
+	}
+
+	t.Run("it should list the posts of every page and the projects", func(t *testing.T) {
+		gotCursors = nil
+
+		uc := NewSitemapUsecase(postUsecase, projectUsecase, chikitoUsecase, "https://yurb.dev/", "")
+
+		got, err := uc.GetURLs(context.Background())
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		want := []*domain.SitemapURL{
+			{Loc: "https://yurb.dev/", LastMod: updatedAt.Add(time.Hour)},
+			{Loc: "https://yurb.dev/posts/second-post", LastMod: updatedAt},
+			{Loc: "https://yurb.dev/posts/first-post", LastMod: updatedAt.Add(time.Hour)},
+			{Loc: "https://yurb.dev/projects/pr_12345", LastMod: updatedAt},
+		}
+
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("GetURLs() mismatch (-want +got):\n%s", diff)
+		}
+
+		if diff := cmp.Diff([]string{"", "next"}, gotCursors); diff != "" {
+			t.Errorf("Expected to follow the posts cursor (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("it should list the chikitos when their URL is set", func(t *testing.T) {
+		uc := NewSitemapUsecase(postUsecase, projectUsecase, chikitoUsecase, "https://yurb.dev", "https://yurb.dev/c/")
+
+		got, err := uc.GetURLs(context.Background())
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		last := got[len(got)-1]
+		if last.Loc != "https://yurb.dev/c/ch_12345" || !last.LastMod.Equal(updatedAt) {
+			t.Errorf("Expected the chikito to be listed last. Got: %+v", last)
+		}
+	})
+
+	t.Run("it should return an error if the projects can't be retrieved", func(t *testing.T) {
+		projectUsecase := &projectMocks.MockProjectsUsecase{
+			GetProjectsFn: func(ctx context.Context) ([]*projectsDomain.Project, error) {
+				return nil, errors.New("DB error")
+			},
+		}
+
+		uc := NewSitemapUsecase(postUsecase, projectUsecase, chikitoUsecase, "https://yurb.dev", "")
+
+		_, err := uc.GetURLs(context.Background())
+		if err == nil {
+			t.Error("Expected error, got nil")
+		}
+	})
+
+	t.Run("it should cache the URLs", func(t *testing.T) {
+		listed := 0
+		projectUsecase := &projectMocks.MockProjectsUsecase{
+			GetProjectsFn: func(ctx context.Context) ([]*projectsDomain.Project, error) {
+				listed++
+
+				return nil, nil
+			},
+		}
+
+		uc := NewSitemapUsecase(postUsecase, projectUsecase, chikitoUsecase, "https://yurb.dev", "").(*sitemapUsecase)
+		now := time.Now()
+		uc.now = func() time.Time { return now }
+
+		for _, elapsed := range []time.Duration{0, time.Minute, domain.URLsCacheTTL} {
+			now = now.Add(elapsed)
+
+			if _, err := uc.GetURLs(context.Background()); err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+		}
+
+		if listed != 2 {
+			t.Errorf("Expected the URLs to be listed again only once the cache expired. Got %d listings", listed)
+		}
+	})
+}
@@ -0,0 +1,43 @@
+package application
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	chikitosDomain "github.com/yavurb/goyurback/internal/chikitos/domain"
+	postsDomain "github.com/yavurb/goyurback/internal/posts/domain"
+	projectsDomain "github.com/yavurb/goyurback/internal/projects/domain"
+	"github.com/yavurb/goyurback/internal/sitemap/domain"
+)
+
+type sitemapUsecase struct {
+	postUsecase    postsDomain.PostUsecase
+	projectUsecase projectsDomain.ProjectUsecase
+	chikitoUsecase chikitosDomain.ChikitoUsecase
+	siteURL        string
+	chikitosURL    string
+	now            func() time.Time
+
+	mu       sync.Mutex
+	urls     []*domain.SitemapURL
+	cachedAt time.Time
+}
+
+// NewSitemapUsecase creates the sitemap usecase. Chikitos are only listed when chikitosURL, the
+// public base URL of the short links, is not empty.
+func NewSitemapUsecase(
+	postUsecase postsDomain.PostUsecase,
+	projectUsecase projectsDomain.ProjectUsecase,
+	chikitoUsecase chikitosDomain.ChikitoUsecase,
+	siteURL, chikitosURL string,
+) domain.SitemapUsecase {
+	return &sitemapUsecase{
+		postUsecase:    postUsecase,
+		projectUsecase: projectUsecase,
+		chikitoUsecase: chikitoUsecase,
+		siteURL:        strings.TrimSuffix(siteURL, "/"),
+		chikitosURL:    strings.TrimSuffix(chikitosURL, "/"),
+		now:            time.Now,
+	}
+}
@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// MaxURLs is the max number of URLs allowed in a single sitemap file by the sitemaps protocol
+const MaxURLs = 50000
+
+// URLsCacheTTL is how long the URLs of the site are reused before they are listed again
+const URLsCacheTTL = 5 * time.Minute
+
+type SitemapURL struct {
+	LastMod time.Time
+	Loc     string
+}
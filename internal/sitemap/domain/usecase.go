@@ -0,0 +1,9 @@
+package domain
+
+import "context"
+
+type SitemapUsecase interface {
+	// GetURLs lists the URLs of the site, starting with the home page. The list is cached for
+	// URLsCacheTTL.
+	GetURLs(ctx context.Context) ([]*SitemapURL, error)
+}
@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"encoding/xml"
+	"time"
+
+	"github.com/yavurb/goyurback/internal/sitemap/domain"
+)
+
+const sitemapNS = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+type URLSetOut struct {
+	XMLName xml.Name  `xml:"urlset"`
+	NS      string    `xml:"xmlns,attr"`
+	URLs    []*URLOut `xml:"url"`
+}
+
+type URLOut struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type SitemapIndexOut struct {
+	XMLName  xml.Name      `xml:"sitemapindex"`
+	NS       string        `xml:"xmlns,attr"`
+	Sitemaps []*SitemapOut `xml:"sitemap"`
+}
+
+type SitemapOut struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+func toURLSetOut(urls []*domain.SitemapURL) *URLSetOut {
+	urlSet := &URLSetOut{
+		NS:   sitemapNS,
+		URLs: []*URLOut{},
+	}
+
+	for _, url := range urls {
+		urlSet.URLs = append(urlSet.URLs, &URLOut{
+			Loc:     url.Loc,
+			LastMod: formatLastMod(url.LastMod),
+		})
+	}
+
+	return urlSet
+}
+
+func formatLastMod(lastMod time.Time) string {
+	if lastMod.IsZero() {
+		return ""
+	}
+
+	return lastMod.UTC().Format(time.RFC3339)
+}
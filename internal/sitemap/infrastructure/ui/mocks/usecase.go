@@ -0,0 +1,15 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/yavurb/goyurback/internal/sitemap/domain"
+)
+
+type MockSitemapUsecase struct {
+	GetURLsFn func(ctx context.Context) ([]*domain.SitemapURL, error)
+}
+
+func (uc *MockSitemapUsecase) GetURLs(ctx context.Context) ([]*domain.SitemapURL, error) {
+	return uc.GetURLsFn(ctx)
+}
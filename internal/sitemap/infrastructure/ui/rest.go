@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yavurb/goyurback/internal/sitemap/domain"
+)
+
+type sitemapRouterCtx struct {
+	sitemapUsecase domain.SitemapUsecase
+	siteURL        string
+	pageSize       int
+}
+
+// NewSitemapRouter creates the sitemap routes. The pages of the sitemap index are linked on
+// siteURL, as the Host header is set by the client.
+func NewSitemapRouter(e *echo.Echo, sitemapUsecase domain.SitemapUsecase, siteURL string) *sitemapRouterCtx {
+	routerCtx := &sitemapRouterCtx{
+		sitemapUsecase: sitemapUsecase,
+		siteURL:        strings.TrimSuffix(siteURL, "/"),
+		pageSize:       domain.MaxURLs,
+	}
+
+	e.GET("/sitemap.xml", routerCtx.getSitemap)
+	e.GET("/sitemaps/:page", routerCtx.getSitemapPage)
+
+	return routerCtx
+}
+
+// getSitemap returns every URL of the site, or an index of the sitemap pages when there are
+// more URLs than a single sitemap can hold.
+func (ctx *sitemapRouterCtx) getSitemap(c echo.Context) error {
+	urls, err := ctx.getURLs(c)
+	if err != nil {
+		return err
+	}
+
+	if len(urls) <= ctx.pageSize {
+		return xmlBlob(c, toURLSetOut(urls))
+	}
+
+	index := &SitemapIndexOut{
+		NS: sitemapNS,
+	}
+
+	for page := 1; (page-1)*ctx.pageSize < len(urls); page++ {
+		sitemap := &SitemapOut{
+			Loc: fmt.Sprintf("%s/sitemaps/%d.xml", ctx.siteURL, page),
+		}
+
+		pageURLs := ctx.page(urls, page)
+		lastMod := pageURLs[0].LastMod
+
+		for _, url := range pageURLs {
+			if url.LastMod.After(lastMod) {
+				lastMod = url.LastMod
+			}
+		}
+
+		sitemap.LastMod = formatLastMod(lastMod)
+		index.Sitemaps = append(index.Sitemaps, sitemap)
+	}
+
+	return xmlBlob(c, index)
+}
+
+func (ctx *sitemapRouterCtx) getSitemapPage(c echo.Context) error {
+	param := c.Param("page")
+
+	page, err := strconv.Atoi(strings.TrimSuffix(param, ".xml"))
+	if err != nil || page < 1 || !strings.HasSuffix(param, ".xml") {
+		return HTTPError{
+			Message: "Sitemap not found",
+		}.NotFound()
+	}
+
+	urls, err := ctx.getURLs(c)
+	if err != nil {
+		return err
+	}
+
+	pageURLs := ctx.page(urls, page)
+	if len(pageURLs) == 0 {
+		return HTTPError{
+			Message: "Sitemap not found",
+		}.NotFound()
+	}
+
+	return xmlBlob(c, toURLSetOut(pageURLs))
+}
+
+func (ctx *sitemapRouterCtx) getURLs(c echo.Context) ([]*domain.SitemapURL, error) {
+	urls, err := ctx.sitemapUsecase.GetURLs(c.Request().Context())
+	if err != nil {
+		return nil, HTTPError{
+			Message: "Unable to build the sitemap",
+		}.InternalServerError()
+	}
+
+	return urls, nil
+}
+
+// page returns the URLs of the given 1-based sitemap page.
+func (ctx *sitemapRouterCtx) page(urls []*domain.SitemapURL, page int) []*domain.SitemapURL {
+	start := (page - 1) * ctx.pageSize
+	if start >= len(urls) {
+		return nil
+	}
+
+	return urls[start:min(start+ctx.pageSize, len(urls))]
+}
+
+func xmlBlob(c echo.Context, v any) error {
+	body, err := xml.Marshal(v)
+	if err != nil {
+		return HTTPError{
+			Message: "Unable to build the sitemap",
+		}.InternalServerError()
+	}
+
+	return c.Blob(http.StatusOK, echo.MIMEApplicationXMLCharsetUTF8, append([]byte(xml.Header), body...))
+}
@@ -0,0 +1,149 @@
+package ui
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+	"github.com/yavurb/goyurback/internal/sitemap/domain"
+	"github.com/yavurb/goyurback/internal/sitemap/infrastructure/ui/mocks"
+)
+
+func TestSitemap(t *testing.T) {
+	e := echo.New()
+
+	updatedAt := time.Date(2024, 7, 15, 10, 30, 0, 0, time.UTC)
+	urls := []*domain.SitemapURL{
+		{Loc: "https://yurb.dev/", LastMod: updatedAt.Add(time.Hour)},
+		{Loc: "https://yurb.dev/posts/my-post", LastMod: updatedAt},
+		{Loc: "https://yurb.dev/projects/pr_12345", LastMod: updatedAt.Add(2 * time.Hour)},
+	}
+
+	uc := &mocks.MockSitemapUsecase{
+		GetURLsFn: func(ctx context.Context) ([]*domain.SitemapURL, error) {
+			return urls, nil
+		},
+	}
+
+	newContext := func(path string, params ...string) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if len(params) > 0 {
+			c.SetParamNames("page")
+			c.SetParamValues(params...)
+		}
+
+		return c, rec
+	}
+
+	t.Run("it should return every URL in a single sitemap", func(t *testing.T) {
+		c, rec := newContext("/sitemap.xml")
+		h := NewSitemapRouter(e, uc, "https://yurb.dev/")
+
+		if err := h.getSitemap(c); err != nil {
+			t.Fatalf("Expected no errors getting the sitemap. Got: %v", err)
+		}
+
+		if got := rec.Header().Get(echo.HeaderContentType); got != echo.MIMEApplicationXMLCharsetUTF8 {
+			t.Errorf("Expected content type to be %s. Got: %s", echo.MIMEApplicationXMLCharsetUTF8, got)
+		}
+
+		got := URLSetOut{}
+		if err := xml.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatalf("Error unmarshalling response: %s", err)
+		}
+
+		want := []*URLOut{
+			{Loc: "https://yurb.dev/", LastMod: "2024-07-15T11:30:00Z"},
+			{Loc: "https://yurb.dev/posts/my-post", LastMod: "2024-07-15T10:30:00Z"},
+			{Loc: "https://yurb.dev/projects/pr_12345", LastMod: "2024-07-15T12:30:00Z"},
+		}
+
+		if diff := cmp.Diff(want, got.URLs); diff != "" {
+			t.Errorf("getSitemap() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("it should return a sitemap index when there are too many URLs", func(t *testing.T) {
+		c, rec := newContext("/sitemap.xml")
+		h := NewSitemapRouter(e, uc, "https://yurb.dev/")
+		h.pageSize = 2
+
+		if err := h.getSitemap(c); err != nil {
+			t.Fatalf("Expected no errors getting the sitemap. Got: %v", err)
+		}
+
+		got := SitemapIndexOut{}
+		if err := xml.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatalf("Error unmarshalling response: %s", err)
+		}
+
+		want := []*SitemapOut{
+			{Loc: "https://yurb.dev/sitemaps/1.xml", LastMod: "2024-07-15T11:30:00Z"},
+			{Loc: "https://yurb.dev/sitemaps/2.xml", LastMod: "2024-07-15T12:30:00Z"},
+		}
+
+		if diff := cmp.Diff(want, got.Sitemaps); diff != "" {
+			t.Errorf("getSitemap() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("it should return a page of the sitemap", func(t *testing.T) {
+		c, rec := newContext("/sitemaps/2.xml", "2.xml")
+		h := NewSitemapRouter(e, uc, "https://yurb.dev/")
+		h.pageSize = 2
+
+		if err := h.getSitemapPage(c); err != nil {
+			t.Fatalf("Expected no errors getting the sitemap page. Got: %v", err)
+		}
+
+		got := URLSetOut{}
+		if err := xml.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatalf("Error unmarshalling response: %s", err)
+		}
+
+		if len(got.URLs) != 1 || got.URLs[0].Loc != "https://yurb.dev/projects/pr_12345" {
+			t.Errorf("Expected the last URL on the second page. Got: %+v", got.URLs)
+		}
+	})
+
+	t.Run("it should return not found for unknown pages", func(t *testing.T) {
+		h := NewSitemapRouter(e, uc, "https://yurb.dev/")
+		h.pageSize = 2
+
+		for _, page := range []string{"3.xml", "0.xml", "one.xml", "1"} {
+			c, _ := newContext("/sitemaps/"+page, page)
+
+			err := h.getSitemapPage(c)
+
+			var httpErr *echo.HTTPError
+			if !errors.As(err, &httpErr) || httpErr.Code != http.StatusNotFound {
+				t.Errorf("Expected a not found error for %s. Got: %v", page, err)
+			}
+		}
+	})
+
+	t.Run("it should return an internal server error if the sitemap can't be built", func(t *testing.T) {
+		c, _ := newContext("/sitemap.xml")
+		h := NewSitemapRouter(e, &mocks.MockSitemapUsecase{
+			GetURLsFn: func(ctx context.Context) ([]*domain.SitemapURL, error) {
+				return nil, errors.New("DB error")
+			},
+		}, "https://yurb.dev/")
+
+		err := h.getSitemap(c)
+
+		var httpErr *echo.HTTPError
+		if !errors.As(err, &httpErr) || httpErr.Code != http.StatusInternalServerError {
+			t.Errorf("Expected an internal server error. Got: %v", err)
+		}
+	})
+}
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS posts_published_at_id_idx;
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS posts_published_at_id_idx ON posts (published_at DESC, id DESC) WHERE status = 'published';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS post_slug_history;
+ALTER TABLE posts DROP CONSTRAINT IF EXISTS posts_slug_key;
@@ -0,0 +1,14 @@
+-- Deduplicate existing slugs before enforcing uniqueness, the oldest post keeps the original slug
+UPDATE posts SET slug = posts.slug || '-' || posts.id
+FROM (SELECT id, ROW_NUMBER() OVER (PARTITION BY slug ORDER BY id) AS rn FROM posts) AS duplicated
+WHERE posts.id = duplicated.id AND duplicated.rn > 1;
+
+ALTER TABLE posts ADD CONSTRAINT posts_slug_key UNIQUE (slug);
+
+CREATE TABLE post_slug_history (
+  id SERIAL PRIMARY KEY,
+  post_id INTEGER NOT NULL,
+  slug VARCHAR(128) NOT NULL UNIQUE,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE CASCADE
+);
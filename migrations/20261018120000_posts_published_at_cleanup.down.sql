@@ -0,0 +1 @@
+-- Data only migration, nothing to revert
@@ -0,0 +1,4 @@
+-- Posts updated before the lifecycle rules were stored with the zero time instead of NULL
+UPDATE posts SET published_at = NULL WHERE published_at < '0002-01-01';
+
+UPDATE posts SET published_at = updated_at WHERE status = 'published' AND published_at IS NULL;
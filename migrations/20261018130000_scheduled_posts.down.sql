@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS posts_publish_at_idx;
+ALTER TABLE posts DROP COLUMN IF EXISTS publish_at;
+
+-- Enum values can't be dropped, so the type is recreated without 'scheduled'
+UPDATE posts SET status = 'draft' WHERE status = 'scheduled';
+
+DROP INDEX IF EXISTS posts_published_at_id_idx;
+ALTER TYPE post_status RENAME TO post_status_old;
+CREATE TYPE post_status AS ENUM ('draft', 'published', 'archived');
+ALTER TABLE posts ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE posts ALTER COLUMN status TYPE post_status USING status::text::post_status;
+ALTER TABLE posts ALTER COLUMN status SET DEFAULT 'draft';
+DROP TYPE post_status_old;
+CREATE INDEX IF NOT EXISTS posts_published_at_id_idx ON posts (published_at DESC, id DESC) WHERE status = 'published';
@@ -0,0 +1,6 @@
+ALTER TYPE post_status ADD VALUE IF NOT EXISTS 'scheduled';
+
+ALTER TABLE posts ADD COLUMN publish_at TIMESTAMP DEFAULT NULL;
+
+-- Only scheduled posts have a publish date, so this index stays small
+CREATE INDEX IF NOT EXISTS posts_publish_at_idx ON posts (publish_at) WHERE publish_at IS NOT NULL;
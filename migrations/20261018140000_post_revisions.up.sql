@@ -0,0 +1,15 @@
+CREATE TABLE post_revisions (
+  id SERIAL PRIMARY KEY,
+  post_id INTEGER NOT NULL,
+  revision INTEGER NOT NULL,
+  title VARCHAR(128) NOT NULL,
+  description VARCHAR(255) NOT NULL,
+  content TEXT NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE CASCADE,
+  UNIQUE (post_id, revision)
+);
+
+-- The current content of existing posts becomes their first revision
+INSERT INTO post_revisions (post_id, revision, title, description, content, created_at)
+SELECT id, 1, title, description, content, updated_at FROM posts;
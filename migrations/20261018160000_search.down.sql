@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS projects_search_vector_idx;
+DROP INDEX IF EXISTS posts_search_vector_idx;
+
+DROP TRIGGER IF EXISTS projects_search_vector_update ON projects;
+DROP TRIGGER IF EXISTS posts_search_vector_update ON posts;
+
+DROP FUNCTION IF EXISTS projects_search_vector_update();
+DROP FUNCTION IF EXISTS posts_search_vector_update();
+
+ALTER TABLE projects DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE posts DROP COLUMN IF EXISTS search_vector;
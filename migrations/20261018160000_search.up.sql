@@ -0,0 +1,40 @@
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS search_vector TSVECTOR;
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS search_vector TSVECTOR;
+
+-- Titles weigh more than descriptions and descriptions more than the content
+CREATE OR REPLACE FUNCTION posts_search_vector_update() RETURNS TRIGGER AS $$
+BEGIN
+  NEW.search_vector :=
+    setweight(to_tsvector('english', coalesce(NEW.title, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(NEW.description, '')), 'B') ||
+    setweight(to_tsvector('english', coalesce(NEW.content, '')), 'C');
+
+  RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION projects_search_vector_update() RETURNS TRIGGER AS $$
+BEGIN
+  NEW.search_vector :=
+    setweight(to_tsvector('english', coalesce(NEW.name, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(NEW.description, '')), 'B') ||
+    setweight(to_tsvector('english', coalesce(array_to_string(NEW.tags, ' '), '')), 'C');
+
+  RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER posts_search_vector_update
+  BEFORE INSERT OR UPDATE OF title, description, content ON posts
+  FOR EACH ROW EXECUTE FUNCTION posts_search_vector_update();
+
+CREATE TRIGGER projects_search_vector_update
+  BEFORE INSERT OR UPDATE OF name, description, tags ON projects
+  FOR EACH ROW EXECUTE FUNCTION projects_search_vector_update();
+
+-- Fire the triggers for the existing rows
+UPDATE posts SET title = title;
+UPDATE projects SET name = name;
+
+CREATE INDEX IF NOT EXISTS posts_search_vector_idx ON posts USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS projects_search_vector_idx ON projects USING GIN (search_vector);
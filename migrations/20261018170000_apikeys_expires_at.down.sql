@@ -0,0 +1 @@
+ALTER TABLE apikeys DROP COLUMN IF EXISTS expires_at;
@@ -0,0 +1 @@
+ALTER TABLE apikeys ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP DEFAULT NULL;
@@ -0,0 +1,4 @@
+ALTER TABLE apikeys ADD COLUMN IF NOT EXISTS scopes VARCHAR[] NOT NULL DEFAULT '{}';
+
+-- Keys created before scopes existed could do everything
+UPDATE apikeys SET scopes = ARRAY['posts:write', 'projects:write', 'chikitos:write', 'keys:admin'];
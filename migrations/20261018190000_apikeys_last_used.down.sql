@@ -0,0 +1,2 @@
+ALTER TABLE apikeys DROP COLUMN IF EXISTS last_used_ip;
+ALTER TABLE apikeys DROP COLUMN IF EXISTS last_used_at;
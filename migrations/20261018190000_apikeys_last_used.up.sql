@@ -0,0 +1,2 @@
+ALTER TABLE apikeys ADD COLUMN IF NOT EXISTS last_used_at TIMESTAMP DEFAULT NULL;
+ALTER TABLE apikeys ADD COLUMN IF NOT EXISTS last_used_ip VARCHAR(45) DEFAULT NULL;
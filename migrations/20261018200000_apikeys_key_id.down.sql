@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS apikeys_key_id_idx;
+
+ALTER TABLE apikeys DROP COLUMN IF EXISTS key_id;
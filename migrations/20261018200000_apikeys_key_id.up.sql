@@ -0,0 +1,8 @@
+-- The key id is the salt segment of the key, so a key can be found without hashing it first
+ALTER TABLE apikeys ADD COLUMN IF NOT EXISTS key_id VARCHAR(64);
+
+UPDATE apikeys SET key_id = split_part(key, '.', 1);
+
+ALTER TABLE apikeys ALTER COLUMN key_id SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS apikeys_key_id_idx ON apikeys (key_id);
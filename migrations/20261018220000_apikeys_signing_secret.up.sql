@@ -0,0 +1,2 @@
+-- Encrypted secret used to sign requests with HMAC. Keys created before request signing have none
+ALTER TABLE apikeys ADD COLUMN IF NOT EXISTS signing_secret BYTEA;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS rate_limits;
+
+ALTER TABLE apikeys DROP COLUMN IF EXISTS rate_limit;
@@ -0,0 +1,10 @@
+-- Requests per minute allowed to the key. NULL keeps the limits of the route groups
+ALTER TABLE apikeys ADD COLUMN IF NOT EXISTS rate_limit INTEGER;
+
+-- Counters of the rate limiter, shared by all the instances. They are unlogged as losing them
+-- only resets the current windows
+CREATE UNLOGGED TABLE IF NOT EXISTS rate_limits (
+  key VARCHAR(255) PRIMARY KEY,
+  window_start TIMESTAMP NOT NULL,
+  hits INTEGER NOT NULL
+);
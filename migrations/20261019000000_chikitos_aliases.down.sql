@@ -0,0 +1,3 @@
+ALTER TABLE chikitos DROP CONSTRAINT IF EXISTS chikitos_public_id_lower_check;
+
+ALTER TABLE chikitos ALTER COLUMN public_id TYPE VARCHAR(15);
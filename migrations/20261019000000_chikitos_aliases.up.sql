@@ -0,0 +1,5 @@
+-- Aliases chosen by the user are stored as the public id. They are lowercased, which makes them
+-- unique regardless of case
+ALTER TABLE chikitos ALTER COLUMN public_id TYPE VARCHAR(64);
+
+ALTER TABLE chikitos ADD CONSTRAINT chikitos_public_id_lower_check CHECK (public_id = lower(public_id));
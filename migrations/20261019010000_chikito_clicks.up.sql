@@ -0,0 +1,12 @@
+-- Redirects to the chikitos. The IPs are truncated to their network and hashed, so visitors can't
+-- be identified from them
+CREATE TABLE IF NOT EXISTS chikito_clicks (
+  id BIGSERIAL PRIMARY KEY,
+  chikito_id INTEGER NOT NULL REFERENCES chikitos (id) ON DELETE CASCADE,
+  clicked_at TIMESTAMP NOT NULL,
+  referrer_host VARCHAR(255) NOT NULL DEFAULT '',
+  user_agent_family VARCHAR(32) NOT NULL,
+  ip_hash VARCHAR(16) NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS chikito_clicks_chikito_id_clicked_at_idx ON chikito_clicks (chikito_id, clicked_at);
@@ -0,0 +1,4 @@
+ALTER TABLE chikitos DROP COLUMN IF EXISTS clicks;
+ALTER TABLE chikitos DROP COLUMN IF EXISTS disabled;
+ALTER TABLE chikitos DROP COLUMN IF EXISTS max_clicks;
+ALTER TABLE chikitos DROP COLUMN IF EXISTS expires_at;
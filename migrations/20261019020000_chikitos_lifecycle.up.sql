@@ -0,0 +1,9 @@
+-- A chikito stops redirecting once it expires, is disabled or reaches its max clicks. NULL
+-- expires_at and max_clicks never stop it
+ALTER TABLE chikitos ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP;
+ALTER TABLE chikitos ADD COLUMN IF NOT EXISTS max_clicks INTEGER;
+ALTER TABLE chikitos ADD COLUMN IF NOT EXISTS disabled BOOLEAN NOT NULL DEFAULT false;
+
+-- Redirects counted against max_clicks. They are only counted for the chikitos with max_clicks,
+-- the clicks of the rest are in chikito_clicks
+ALTER TABLE chikitos ADD COLUMN IF NOT EXISTS clicks INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+ALTER TABLE chikitos DROP COLUMN IF EXISTS utm;
+ALTER TABLE chikitos DROP COLUMN IF EXISTS forward_query;
+ALTER TABLE chikitos DROP COLUMN IF EXISTS redirect_code;
@@ -0,0 +1,9 @@
+-- Existing chikitos move to a temporary redirect too, as browsers cache permanent ones forever
+ALTER TABLE chikitos ADD COLUMN IF NOT EXISTS redirect_code INTEGER NOT NULL DEFAULT 307
+  CONSTRAINT chikitos_redirect_code_check CHECK (redirect_code IN (301, 302, 307, 308));
+
+-- Whether the query of the short link is forwarded to the destination
+ALTER TABLE chikitos ADD COLUMN IF NOT EXISTS forward_query BOOLEAN NOT NULL DEFAULT false;
+
+-- UTM parameters added to the destination, like {"source": "newsletter"}
+ALTER TABLE chikitos ADD COLUMN IF NOT EXISTS utm JSONB NOT NULL DEFAULT '{}';
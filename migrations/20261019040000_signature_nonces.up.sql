@@ -0,0 +1,10 @@
+-- Nonces of the signed requests, shared by all the instances to reject replays. They are kept
+-- until the timestamp of their request is outside the replay window
+CREATE TABLE IF NOT EXISTS signature_nonces (
+  key_id VARCHAR(64) NOT NULL,
+  nonce VARCHAR(64) NOT NULL,
+  expires_at TIMESTAMP NOT NULL,
+  PRIMARY KEY (key_id, nonce)
+);
+
+CREATE INDEX IF NOT EXISTS signature_nonces_expires_at_idx ON signature_nonces (expires_at);